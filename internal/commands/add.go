@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var flagAddUnique bool
+
+func init() {
+	addCmd.Flags().BoolVarP(&flagAddUnique, "unique", "u", false, "skip values already in the array")
+	rootCmd.AddCommand(addCmd)
+}
+
+var addCmd = &cobra.Command{
+	Use:   "add <category.key> <value>...",
+	Short: "Append values to an array field",
+	Long: `Append one or more values to an array field without retyping the
+whole array. A missing field is created as a new array.
+
+When the file being written does not have the field yet but the merged
+view does (for example a global array appended to with --local), the
+merged array is copied before appending.
+
+Examples:
+  deets add identity.aka "Alex T."
+  deets add academic.topics "ml" "statistics"
+  deets add -u identity.aka "Alex Towell"   # no-op if already present`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		cat, key, err := parsePath(path)
+		if err != nil {
+			return err
+		}
+		filePath, err := targetFile()
+		if err != nil {
+			return err
+		}
+		items, err := arrayField(filePath, path, true)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range args[1:] {
+			if flagAddUnique && containsItem(items, v) {
+				continue
+			}
+			items = append(items, v)
+		}
+		return store.SetValue(filePath, cat, key, model.FormatValueTOML(items))
+	},
+}
+
+// arrayField returns the elements of the array at path in filePath. When
+// the file does not define the field, the merged view is consulted if
+// inherit is set; a field found nowhere yields an empty array. A field
+// holding anything other than an array is an error.
+func arrayField(filePath, path string, inherit bool) ([]interface{}, error) {
+	var (
+		f     model.Field
+		found bool
+	)
+	if fileExists(filePath) {
+		db, err := store.LoadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		f, found = db.GetField(path)
+	}
+	if !found && inherit {
+		if db, err := loadDB(); err == nil {
+			f, found = db.GetField(path)
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	switch v := f.Value.(type) {
+	case []interface{}:
+		return append([]interface{}(nil), v...), nil
+	case []map[string]interface{}:
+		items := make([]interface{}, len(v))
+		for i, rec := range v {
+			items[i] = rec
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("%s is not an array (use 'deets set' to replace it)", path)
+}
+
+// containsItem reports whether items holds an element that formats as s.
+func containsItem(items []interface{}, s string) bool {
+	for _, item := range items {
+		if model.FormatValue(item) == s {
+			return true
+		}
+	}
+	return false
+}
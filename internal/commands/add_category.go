@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var flagAddCategoryDescriptions string
+
+func init() {
+	addCategoryCmd.Flags().StringVar(&flagAddCategoryDescriptions, "descriptions", "", "JSON or TOML file of category.key = description to draw keys from")
+	rootCmd.AddCommand(addCategoryCmd)
+}
+
+var addCategoryCmd = &cobra.Command{
+	Use:   "add-category <name>",
+	Short: "Append a new category with commented-out starter keys",
+	Long: `Append a [name] section to the target file, scaffolded the way the
+'deets init' template is: each well-known key of that category is listed
+commented out, followed by a *_desc line holding its description. Remove
+the "# " from the keys you want and fill in their values.
+
+Keys and descriptions come from the built-in defaults, plus those for
+this category in a --descriptions file (the format describe --import
+reads), which take precedence. A category with neither gets a generic
+example.
+
+Examples:
+  deets add-category academic
+  deets add-category cooking --descriptions team-descs.toml
+  deets add-category --local web`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if !bareName(name) {
+			return fmt.Errorf("invalid category name %q: use letters, digits, _ and -", name)
+		}
+
+		fields := store.DefaultFields()
+		if flagAddCategoryDescriptions != "" {
+			descs, err := readDescriptions(flagAddCategoryDescriptions)
+			if err != nil {
+				return err
+			}
+			fields = mergeDescriptionFields(fields, descs)
+		}
+
+		filePath, err := targetFile()
+		if err != nil {
+			return err
+		}
+		if err := store.AddCategory(filePath, name, categoryScaffold(name, fields)); err != nil {
+			return err
+		}
+		if !flagQuiet {
+			fmt.Printf("Added [%s] to %s\n", name, filePath)
+		}
+		return nil
+	},
+}
+
+// mergeDescriptionFields overrides the descriptions of fields with descs,
+// adding the paths fields lacks as string fields in sorted order.
+func mergeDescriptionFields(fields []store.DefaultField, descs map[string]string) []store.DefaultField {
+	seen := make(map[string]bool)
+	for i, f := range fields {
+		path := f.Category + "." + f.Key
+		if d, ok := descs[path]; ok {
+			fields[i].Desc = d
+		}
+		seen[path] = true
+	}
+	paths := make([]string, 0, len(descs))
+	for path := range descs {
+		if !seen[path] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		cat, key, _ := strings.Cut(path, ".")
+		fields = append(fields, store.DefaultField{Category: cat, Key: key, Desc: descs[path], Kind: "string"})
+	}
+	return fields
+}
+
+// categoryScaffold returns the commented-out body of a new [category]:
+// each of its fields as an empty placeholder with a *_desc line, and each
+// array of tables as a commented [[category.key]] header.
+func categoryScaffold(category string, fields []store.DefaultField) []string {
+	var lines, records []string
+	for _, f := range fields {
+		if f.Category != category {
+			continue
+		}
+		desc := "# " + f.Key + "_desc = " + model.TOMLString(f.Desc)
+		switch f.Kind {
+		case "list":
+			lines = append(lines, "# "+f.Key+" = []", desc)
+		case "records":
+			records = append(records, desc, fmt.Sprintf("# [[%s.%s]]", category, f.Key))
+		default:
+			lines = append(lines, "# "+f.Key+` = ""`, desc)
+		}
+	}
+	if len(lines) > 0 && len(records) > 0 {
+		lines = append(lines, "#")
+	}
+	lines = append(lines, records...)
+	if len(lines) == 0 {
+		lines = []string{`# key = "value"`, `# key_desc = "What this key holds"`}
+	}
+	return lines
+}
+
+// bareName reports whether name can be written as a bare TOML key.
+func bareName(name string) bool {
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddCategory(t *testing.T) {
+	home := setupTestEnv(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+
+	if _, _, err := executeCommand("add-category", "web", "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{"[web]\n", "# github = \"\"\n# github_desc = \"GitHub username\"\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in:\n%s", want, data)
+		}
+	}
+
+	if _, _, err := executeCommand("add-category", "employment", "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), "[employment]\n# positions_desc = \"Employment history with organization and dates\"\n# [[employment.positions]]\n") {
+		t.Errorf("expected a commented array-of-tables header, got:\n%s", data)
+	}
+
+	if _, _, err := executeCommand("add-category", "web"); err == nil {
+		t.Error("expected error for an existing category")
+	}
+	if _, _, err := executeCommand("add-category", "bad.name"); err == nil {
+		t.Error("expected error for an invalid name")
+	}
+}
+
+func TestAddCategory_Descriptions(t *testing.T) {
+	home := setupTestEnv(t)
+	descs := filepath.Join(home, "descs.toml")
+	os.WriteFile(descs, []byte("[cooking]\ncuisine = \"Favourite cuisine\"\n\n[web]\ngithub = \"Team GitHub handle\"\n"), 0644)
+
+	if _, _, err := executeCommand("add-category", "cooking", "--descriptions", descs, "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := executeCommand("add-category", "hobbies", "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	want := "[cooking]\n# cuisine = \"\"\n# cuisine_desc = \"Favourite cuisine\"\n\n[hobbies]\n# key = \"value\"\n# key_desc = \"What this key holds\"\n"
+	if string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAdd_Appends(t *testing.T) {
+	home := setupTestDB(t)
+	if _, _, err := executeCommand("add", "identity.aka", "A. Towell"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := executeCommand("add", "contact.pgp", "ABCD"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	for _, want := range []string{
+		`aka = ["Alex Towell", "Alex T", "A. Towell"]`,
+		`pgp = ["ABCD"]`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in file:\n%s", want, data)
+		}
+	}
+}
+
+func TestAdd_Unique(t *testing.T) {
+	home := setupTestDB(t)
+	if _, _, err := executeCommand("add", "-u", "academic.topics", "statistics", "ml"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if !strings.Contains(string(data), `topics = ["statistics", "machine learning", "ml"]`) {
+		t.Errorf("expected deduplicated append, got:\n%s", data)
+	}
+}
+
+func TestAdd_LocalInheritsGlobal(t *testing.T) {
+	home := setupTestDB(t)
+	workDir := filepath.Join(home, "project")
+	os.MkdirAll(workDir, 0755)
+	os.Chdir(workDir)
+
+	if _, _, err := executeCommand("add", "--local", "identity.aka", "AT"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(workDir, ".deets", "me.toml"))
+	if !strings.Contains(string(data), `aka = ["Alex Towell", "Alex T", "AT"]`) {
+		t.Errorf("expected global array copied locally, got:\n%s", data)
+	}
+}
+
+func TestAdd_NotArray(t *testing.T) {
+	setupTestDB(t)
+	if _, _, err := executeCommand("add", "contact.email", "x@example.com"); err == nil {
+		t.Error("expected error appending to a string field")
+	}
+}
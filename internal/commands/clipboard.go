@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// clipboardTools lists clipboard programs in order of preference. The first
+// one on PATH that runs successfully is used.
+var clipboardTools = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// openTTY opens the controlling terminal for OSC 52 output. Tests replace it.
+var openTTY = func() (io.WriteCloser, error) {
+	return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+}
+
+// copyToClipboard places text on the system clipboard. Over SSH, or when no
+// clipboard program works, it falls back to the OSC 52 escape sequence,
+// which asks the user's terminal emulator to set its clipboard.
+func copyToClipboard(text string) error {
+	if os.Getenv("SSH_TTY") == "" && os.Getenv("SSH_CONNECTION") == "" {
+		for _, tool := range clipboardTools {
+			path, err := exec.LookPath(tool[0])
+			if err != nil {
+				continue
+			}
+			c := exec.Command(path, tool[1:]...)
+			c.Stdin = strings.NewReader(text)
+			if c.Run() == nil {
+				return nil
+			}
+		}
+	}
+	return writeOSC52(text)
+}
+
+// writeOSC52 writes an OSC 52 clipboard sequence for text to the terminal,
+// wrapped in a DCS passthrough when running inside tmux.
+func writeOSC52(text string) error {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+	if os.Getenv("TMUX") != "" {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+
+	tty, err := openTTY()
+	if err != nil {
+		return fmt.Errorf("copying to clipboard: no clipboard program found and no terminal for OSC 52: %w", err)
+	}
+	defer tty.Close()
+	if _, err := io.WriteString(tty, seq); err != nil {
+		return fmt.Errorf("copying to clipboard: %w", err)
+	}
+	return nil
+}
+
+// copyOrWarn copies text as copyToClipboard does, but only warns on stderr
+// when that fails: the copy is a convenience on top of output that has
+// already been printed, and should not turn it into a failure.
+func copyOrWarn(text string) {
+	if err := copyToClipboard(text); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// nopCloser adapts a bytes.Buffer to io.WriteCloser.
+type nopCloser struct{ *bytes.Buffer }
+
+func (nopCloser) Close() error { return nil }
+
+// captureTTY replaces openTTY with a buffer for the duration of the test.
+func captureTTY(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	orig := openTTY
+	openTTY = func() (io.WriteCloser, error) { return nopCloser{&buf}, nil }
+	t.Cleanup(func() { openTTY = orig })
+	return &buf
+}
+
+func TestCopyToClipboard_Tool(t *testing.T) {
+	bin := t.TempDir()
+	out := filepath.Join(bin, "clipboard.txt")
+	script := "#!/bin/sh\ncat > " + out + "\n"
+	if err := os.WriteFile(filepath.Join(bin, "pbcopy"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("SSH_TTY", "")
+	t.Setenv("SSH_CONNECTION", "")
+	tty := captureTTY(t)
+
+	if err := copyToClipboard("0000-0001-2345-6789"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("clipboard tool was not run: %v", err)
+	}
+	if string(data) != "0000-0001-2345-6789" {
+		t.Errorf("clipboard got %q", data)
+	}
+	if tty.Len() != 0 {
+		t.Errorf("OSC 52 should not be used when a tool works, got %q", tty.String())
+	}
+}
+
+func TestCopyToClipboard_OSC52(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("SSH_TTY", "/dev/pts/0")
+	t.Setenv("TMUX", "")
+	tty := captureTTY(t)
+
+	if err := copyToClipboard("hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte("hello")) + "\x07"
+	if tty.String() != want {
+		t.Errorf("got %q, want %q", tty.String(), want)
+	}
+
+	tty.Reset()
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	if err := copyToClipboard("hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(tty.Bytes(), []byte("\x1bPtmux;\x1b\x1b]52;c;")) {
+		t.Errorf("expected tmux passthrough, got %q", tty.String())
+	}
+}
+
+func TestGet_Copy(t *testing.T) {
+	setupTestDB(t)
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("SSH_TTY", "/dev/pts/0")
+	t.Setenv("TMUX", "")
+	tty := captureTTY(t)
+	flagFormat = "table"
+
+	stdout, _, err := executeCommand("get", "web.github", "--copy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "queelius\n" {
+		t.Errorf("value should still be printed, got %q", stdout)
+	}
+	want := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte("queelius")) + "\x07"
+	if tty.String() != want {
+		t.Errorf("clipboard sequence = %q, want %q", tty.String(), want)
+	}
+}
+
+func TestGet_CopyFailureWarns(t *testing.T) {
+	setupTestDB(t)
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("SSH_TTY", "/dev/pts/0")
+	orig := openTTY
+	openTTY = func() (io.WriteCloser, error) { return nil, os.ErrNotExist }
+	t.Cleanup(func() { openTTY = orig })
+	flagFormat = "table"
+
+	stdout, stderr, err := executeCommand("get", "web.github", "--copy")
+	if err != nil {
+		t.Fatalf("a failed copy should not fail get: %v", err)
+	}
+	if stdout != "queelius\n" {
+		t.Errorf("value should still be printed, got %q", stdout)
+	}
+	if !strings.Contains(stderr, "warning: copying to clipboard") {
+		t.Errorf("expected a clipboard warning, got %q", stderr)
+	}
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/queelius/deets/internal/model"
+)
+
+// Conflict resolution strategies shared by commands that merge external data
+// into a deets file.
+const (
+	strategyAsk    = "ask"    // prompt per conflicting field
+	strategyMine   = "mine"   // keep the existing value
+	strategyTheirs = "theirs" // take the incoming value
+)
+
+// conflictInput is where interactive answers are read from. Tests replace it.
+var conflictInput io.Reader = os.Stdin
+
+// conflictResolver decides, per conflicting field, whether to keep the
+// existing value, take the incoming one, or use a hand-edited value.
+type conflictResolver struct {
+	strategy string
+	in       *bufio.Reader
+	out      io.Writer
+}
+
+// newConflictResolver returns a resolver for the given strategy. An empty
+// strategy means "ask" when stdin is a terminal and "theirs" otherwise,
+// which preserves the non-interactive overwrite behavior for scripts.
+func newConflictResolver(strategy string) (*conflictResolver, error) {
+	if strategy == "" {
+		strategy = strategyTheirs
+		if isStdinTTY() {
+			strategy = strategyAsk
+		}
+	}
+	switch strategy {
+	case strategyAsk, strategyMine, strategyTheirs:
+	default:
+		return nil, fmt.Errorf("unknown strategy %q: expected ask, mine, or theirs", strategy)
+	}
+	return &conflictResolver{
+		strategy: strategy,
+		in:       bufio.NewReader(conflictInput),
+		out:      os.Stderr,
+	}, nil
+}
+
+// resolve returns the TOML literal to write for path and whether to write it
+// at all. mine and theirs are display values; theirsValue is the incoming
+// value, written as is when it is taken. A hand-edited value is read as
+// editedLiteral reads it: as text when theirsValue is a string, and as a
+// TOML value otherwise.
+func (r *conflictResolver) resolve(path, mine, theirs string, theirsValue interface{}) (string, bool, error) {
+	theirsTOML := model.FormatValueTOML(theirsValue)
+	switch r.strategy {
+	case strategyMine:
+		return "", false, nil
+	case strategyTheirs:
+		return theirsTOML, true, nil
+	}
+
+	fmt.Fprintf(r.out, "\nConflict: %s\n  mine:   %s\n  theirs: %s\n", path, mine, theirs)
+	for {
+		fmt.Fprint(r.out, "Keep [m]ine, take [t]heirs, or [e]dit? ")
+		answer, err := r.readLine()
+		if err != nil {
+			return "", false, err
+		}
+		switch strings.ToLower(answer) {
+		case "m", "mine":
+			return "", false, nil
+		case "t", "theirs":
+			return theirsTOML, true, nil
+		case "e", "edit":
+			fmt.Fprint(r.out, "New value: ")
+			value, err := r.readLine()
+			if err != nil {
+				return "", false, err
+			}
+			literal, err := editedLiteral(theirsValue, value)
+			if err != nil {
+				fmt.Fprintf(r.out, "%v\n", err)
+				continue
+			}
+			return literal, true, nil
+		}
+	}
+}
+
+// readLine reads one trimmed line of input. EOF before any input is an error
+// so a closed stdin cannot silently pick a side.
+func (r *conflictResolver) readLine() (string, error) {
+	line, err := r.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("reading answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newTestResolver(strategy, input string) *conflictResolver {
+	return &conflictResolver{
+		strategy: strategy,
+		in:       bufio.NewReader(strings.NewReader(input)),
+		out:      io.Discard,
+	}
+}
+
+func TestConflictResolver_Strategies(t *testing.T) {
+	if _, take, _ := newTestResolver(strategyMine, "").resolve("a.b", "x", "y", "y"); take {
+		t.Error("mine strategy should keep existing value")
+	}
+	val, take, _ := newTestResolver(strategyTheirs, "").resolve("a.b", "x", "y", "y")
+	if !take || val != `"y"` {
+		t.Errorf("theirs strategy should take incoming value, got %q, %v", val, take)
+	}
+}
+
+func TestConflictResolver_Ask(t *testing.T) {
+	tests := []struct {
+		input string
+		take  bool
+		value string
+	}{
+		{"m\n", false, ""},
+		{"t\n", true, `"y"`},
+		{"theirs\n", true, `"y"`},
+		{"e\nmerged value\n", true, `"merged value"`},
+		{"?\nm\n", false, ""},
+	}
+	for _, tt := range tests {
+		val, take, err := newTestResolver(strategyAsk, tt.input).resolve("a.b", "x", "y", "y")
+		if err != nil {
+			t.Errorf("input %q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if take != tt.take || val != tt.value {
+			t.Errorf("input %q: got (%q, %v), want (%q, %v)", tt.input, val, take, tt.value, tt.take)
+		}
+	}
+}
+
+func TestConflictResolver_EditTyped(t *testing.T) {
+	val, take, err := newTestResolver(strategyAsk, "e\nnot toml\ne\n3\n").resolve("a.b", "1", "2", int64(2))
+	if err != nil || !take || val != "3" {
+		t.Errorf("got (%q, %v, %v), want the edited number after a rejected value", val, take, err)
+	}
+}
+
+func TestConflictResolver_AskEOF(t *testing.T) {
+	if _, _, err := newTestResolver(strategyAsk, "").resolve("a.b", "x", "y", "y"); err == nil {
+		t.Error("expected error when input ends before an answer")
+	}
+}
+
+func TestNewConflictResolver_Invalid(t *testing.T) {
+	if _, err := newConflictResolver("both"); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
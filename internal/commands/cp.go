@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagCpToLocal  bool
+	flagCpToGlobal bool
+	flagCpForce    bool
+)
+
+func init() {
+	cpCmd.Flags().BoolVar(&flagCpToLocal, "to-local", false, "write the copy to the local .deets/me.toml")
+	cpCmd.Flags().BoolVar(&flagCpToGlobal, "to-global", false, "write the copy to the global ~/.deets/me.toml")
+	cpCmd.Flags().BoolVarP(&flagCpForce, "force", "f", false, "overwrite the destination if it exists")
+	rootCmd.AddCommand(cpCmd)
+}
+
+var cpCmd = &cobra.Command{
+	Use:   "cp <src-path> <dst-path>",
+	Short: "Copy a field",
+	Long: `Copy a field's value and description to another path.
+
+The source is read from the merged view, so a global field can be copied
+into the local file and vice versa. The copy is written where set would
+write it, or to the file chosen by --to-local or --to-global. Values keep
+their TOML type, and templates and @refs are copied as written.
+
+Examples:
+  deets cp contact.email billing.email
+  deets cp web.github --to-local web.github     # override it per project
+  deets cp contact.email work.email --force     # overwrite an existing field`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src, dst := args[0], args[1]
+		toCat, toKey, err := parsePath(dst)
+		if err != nil {
+			return err
+		}
+		if flagCpToLocal && flagCpToGlobal {
+			return fmt.Errorf("--to-local and --to-global cannot be combined")
+		}
+
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+		f, ok := db.GetField(src)
+		if !ok {
+			return notFoundError(src)
+		}
+
+		var filePath string
+		switch {
+		case flagCpToLocal:
+			filePath, err = localTarget()
+		case flagCpToGlobal:
+			filePath, err = globalTarget()
+		default:
+			filePath, err = targetFile()
+		}
+		if err != nil {
+			return err
+		}
+
+		if !flagCpForce && fileExists(filePath) {
+			existing, err := store.LoadFile(filePath)
+			if err != nil {
+				return err
+			}
+			if _, ok := existing.GetField(dst); ok {
+				return fmt.Errorf("%s already exists in %s (use --force to overwrite)", dst, filePath)
+			}
+		}
+
+		// Copy the value as its file writes it, so a template or @ref is
+		// copied rather than what it resolves to. Tables are copied as
+		// merged, since one may be spread over several files.
+		value := f.Value
+		if raw, ok := fileField(f.Source, src); ok {
+			if _, table := raw.Value.(map[string]interface{}); !table {
+				value = raw.Value
+			}
+		}
+		if err := store.SetLiteral(filePath, toCat, toKey, model.FormatValueTOML(value)); err != nil {
+			return err
+		}
+		// Built-in default descriptions apply on their own; copy only
+		// descriptions written in a file.
+		if f.Desc != "" && f.Desc != store.DefaultDescriptions[f.Category][f.Key] {
+			return store.SetValue(filePath, toCat, toKey+"_desc", f.Desc)
+		}
+		return nil
+	},
+}
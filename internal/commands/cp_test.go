@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCp_ValueAndDesc(t *testing.T) {
+	home := setupTestDB(t)
+	if _, _, err := executeCommand("cp", "contact.email", "billing.email"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := executeCommand("cp", "academic.gpa", "billing.gpa"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	for _, want := range []string{
+		"[billing]\nemail = \"alex@example.com\"\n",
+		"email_desc = \"Primary email\"\n",
+		"gpa = 3.95\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in file:\n%s", want, data)
+		}
+	}
+	if !strings.Contains(string(data), "[contact]\nemail = ") {
+		t.Error("source field should be kept")
+	}
+}
+
+func TestCp_KeepsComputed(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	data, _ := os.ReadFile(path)
+	data = append(data, "\n[email]\nsignature = \"{{identity.name}}\"\nreply = \"@contact.email\"\nhandle = \"@@alex\"\n"...)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"signature", "reply", "handle"} {
+		if _, _, err := executeCommand("cp", "email."+key, "work."+key); err != nil {
+			t.Fatalf("cp email.%s: %v", key, err)
+		}
+	}
+	data, _ = os.ReadFile(path)
+	want := "[work]\nsignature = \"{{identity.name}}\"\nreply = \"@contact.email\"\nhandle = \"@@alex\"\n"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("expected %q in file:\n%s", want, data)
+	}
+}
+
+func TestCp_ToLocal(t *testing.T) {
+	home := setupTestDB(t)
+	workDir := filepath.Join(home, "project")
+	os.MkdirAll(workDir, 0755)
+	os.Chdir(workDir)
+
+	if _, _, err := executeCommand("cp", "--to-local", "web.github", "web.github"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(workDir, ".deets", "me.toml"))
+	if err != nil || !strings.Contains(string(data), `github = "queelius"`) {
+		t.Errorf("expected local copy, got %q (%v)", data, err)
+	}
+}
+
+func TestCp_Errors(t *testing.T) {
+	setupTestDB(t)
+	if _, _, err := executeCommand("cp", "contact.fax", "billing.fax"); err == nil {
+		t.Error("expected error for missing source")
+	}
+	if _, _, err := executeCommand("cp", "contact.email", "web.github"); err == nil {
+		t.Error("expected error for existing destination")
+	}
+	if _, _, err := executeCommand("cp", "contact.email", "web.github", "--force"); err != nil {
+		t.Errorf("--force should overwrite: %v", err)
+	}
+}
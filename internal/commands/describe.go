@@ -1,15 +1,26 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/queelius/deets/internal/model"
 	"github.com/queelius/deets/internal/store"
 	"github.com/spf13/cobra"
 )
 
+var (
+	flagDescribeMissing bool
+	flagDescribeImport  string
+)
+
 func init() {
+	describeCmd.Flags().BoolVar(&flagDescribeMissing, "missing", false, "list fields that have no description")
+	describeCmd.Flags().StringVar(&flagDescribeImport, "import", "", "set descriptions from a JSON or TOML file of category.key = description")
 	rootCmd.AddCommand(describeCmd)
 }
 
@@ -18,13 +29,34 @@ var describeCmd = &cobra.Command{
 	Short: "Show or set field descriptions",
 	Long: `Show or set field descriptions.
 
+With --missing, list the fields that have no description instead, even
+after the built-in defaults for well-known fields, with their values.
+
+With --import, set many descriptions at once from a JSON or TOML file
+mapping "category.key" to a description, or categories to tables of
+key = description. Every *_desc key is written in a single update;
+descriptions of fields that do not exist are skipped with a warning.
+
 Examples:
   deets describe                          # all descriptions
   deets describe identity                 # descriptions in category
   deets describe academic.orcid           # single field description
-  deets describe web.mastodon "Mastodon handle"  # set a description`,
+  deets describe web.mastodon "Mastodon handle"  # set a description
+  deets describe --missing                # fields still to document
+  deets describe --missing web
+  deets describe --import descriptions.toml`,
 	Args: cobra.MaximumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagDescribeMissing {
+			return describeMissing(args)
+		}
+		if flagDescribeImport != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("--import takes no arguments")
+			}
+			return importDescriptions(flagDescribeImport)
+		}
+
 		// Setting a description
 		if len(args) == 2 {
 			return setDescription(args[0], args[1])
@@ -87,3 +119,142 @@ func setDescription(path, desc string) error {
 
 	return store.SetValue(filePath, cat, key+"_desc", desc)
 }
+
+// describeMissing lists the fields without a description, in the category
+// given by args or everywhere: as a Field/Value table, or as a JSON array
+// of paths.
+func describeMissing(args []string) error {
+	if len(args) > 1 || len(args) == 1 && strings.Contains(args[0], ".") {
+		return fmt.Errorf("--missing takes at most a category name")
+	}
+	db, err := loadDB()
+	if err != nil {
+		return err
+	}
+
+	category := ""
+	if len(args) == 1 {
+		category = args[0]
+		if _, ok := db.GetCategory(category); !ok {
+			return &ExitError{Code: 2, Message: fmt.Sprintf("category not found: %s", category)}
+		}
+	}
+	fields := db.MissingDescriptions(category)
+
+	switch resolveFormat() {
+	case "json":
+		paths := make([]string, 0, len(fields))
+		for _, f := range fields {
+			paths = append(paths, f.Category+"."+f.Key)
+		}
+		data, err := json.MarshalIndent(paths, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	default: // table
+		if len(fields) == 0 {
+			if !flagQuiet {
+				fmt.Fprintln(os.Stderr, "Every field has a description.")
+			}
+			return nil
+		}
+		fmt.Print(model.FormatTableFit(fields, false, false, tableWidth()))
+	}
+	return nil
+}
+
+// importDescriptions writes the descriptions in file, a JSON or TOML map of
+// paths to descriptions, as *_desc keys of the target file. Paths naming
+// no existing field are skipped with a warning, since their *_desc keys
+// would only be orphans for prune-desc to remove.
+func importDescriptions(file string) error {
+	descs, err := readDescriptions(file)
+	if err != nil {
+		return err
+	}
+	if len(descs) == 0 {
+		return fmt.Errorf("%s: no descriptions found", file)
+	}
+
+	paths := make([]string, 0, len(descs))
+	for path := range descs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	db, _ := loadDB()
+	var assignments []store.Assignment
+	for _, path := range paths {
+		cat, key, err := parsePath(path)
+		if err != nil {
+			return err
+		}
+		if db != nil {
+			if _, ok := db.GetField(path); !ok {
+				fmt.Fprintf(os.Stderr, "warning: no field %s; skipping its description\n", path)
+				continue
+			}
+		}
+		assignments = append(assignments, store.Assignment{Category: cat, Key: key + "_desc", Literal: model.TOMLString(descs[path])})
+	}
+
+	if len(assignments) == 0 {
+		return fmt.Errorf("%s: no description names an existing field", file)
+	}
+
+	filePath, err := targetFile()
+	if err != nil {
+		return err
+	}
+	if err := store.SetLiterals(filePath, assignments); err != nil {
+		return err
+	}
+	if !flagQuiet {
+		fmt.Fprintf(os.Stderr, "Set %d description(s) in %s\n", len(assignments), filePath)
+	}
+	return nil
+}
+
+// readDescriptions reads a JSON or TOML file mapping "category.key" paths,
+// or categories holding tables of keys, to descriptions.
+func readDescriptions(file string) (map[string]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", file, err)
+	}
+	var doc map[string]interface{}
+	if strings.HasSuffix(file, ".json") || strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		err = json.Unmarshal(data, &doc)
+	} else {
+		err = toml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+
+	descs := make(map[string]string)
+	if err := flattenDescriptions(doc, "", descs); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return descs, nil
+}
+
+// flattenDescriptions collects the strings in m into descs under their
+// dotted paths, so {"web": {"github": "..."}} and {"web.github": "..."}
+// both yield "web.github".
+func flattenDescriptions(m map[string]interface{}, prefix string, descs map[string]string) error {
+	for k, v := range m {
+		switch v := v.(type) {
+		case string:
+			descs[prefix+k] = v
+		case map[string]interface{}:
+			if err := flattenDescriptions(v, prefix+k+".", descs); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s%s: description must be a string, got %T", prefix, k, v)
+		}
+	}
+	return nil
+}
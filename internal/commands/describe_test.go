@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDescribe_Missing(t *testing.T) {
+	setupTestDB(t)
+
+	stdout, _, err := executeCommand("describe", "--missing", "--format", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, `"academic.gpa"`) || strings.Contains(stdout, `"identity.name"`) {
+		t.Errorf("expected only undescribed fields, got %s", stdout)
+	}
+
+	stdout, _, err = executeCommand("describe", "--missing", "identity", "--format", "table")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(stdout, "identity.name") {
+		t.Errorf("described fields should not be listed, got %q", stdout)
+	}
+
+	if _, _, err := executeCommand("describe", "--missing", "nope"); err == nil {
+		t.Error("expected error for an unknown category")
+	}
+	if _, _, err := executeCommand("describe", "--missing", "identity.name"); err == nil {
+		t.Error("expected error for a field path")
+	}
+}
+
+func TestDescribe_Import(t *testing.T) {
+	home := setupTestDB(t)
+	file := filepath.Join(home, "descs.toml")
+	os.WriteFile(file, []byte("\"academic.gpa\" = \"Graduate GPA\"\n\n[web]\ngithub = \"GitHub handle\"\nmastodon = \"Fediverse handle\"\n"), 0644)
+
+	_, stderr, err := executeCommand("describe", "--import", file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stderr, "Set 2 description(s)") || !strings.Contains(stderr, "warning: no field web.mastodon") {
+		t.Errorf("unexpected stderr %q", stderr)
+	}
+	flagDescribeImport = ""
+	if data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml")); strings.Contains(string(data), "mastodon_desc") {
+		t.Errorf("description of a missing field was written:\n%s", data)
+	}
+
+	for path, want := range map[string]string{"academic.gpa": "Graduate GPA", "web.github": "GitHub handle"} {
+		stdout, _, err := executeCommand("describe", path)
+		if err != nil || strings.TrimSpace(stdout) != want {
+			t.Errorf("describe %s = %q (err %v), want %q", path, stdout, err, want)
+		}
+	}
+
+	orphans := filepath.Join(home, "orphans.toml")
+	os.WriteFile(orphans, []byte("\"nope.x\" = \"Nothing\"\n"), 0644)
+	if _, _, err := executeCommand("describe", "--import", orphans); err == nil {
+		t.Error("expected error when no description names a field")
+	}
+	flagDescribeImport = ""
+
+	jsonFile := filepath.Join(home, "descs.json")
+	os.WriteFile(jsonFile, []byte(`{"contact.email": 42}`), 0644)
+	if _, _, err := executeCommand("describe", "--import", jsonFile); err == nil {
+		t.Error("expected error for a non-string description")
+	}
+}
@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/queelius/deets/internal/config"
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var flagDoctorFix bool
+
+func init() {
+	doctorCmd.Flags().BoolVar(&flagDoctorFix, "fix", false, "repair the problems that can be repaired automatically")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the metadata files for common problems",
+	Long: `Check the files deets reads (fragments, the global ~/.deets/me.toml,
+the host and profile files, and any local .deets/me.toml), or the --file
+target alone, for problems that make deets behave oddly:
+
+  missing      the global file or the selected profile does not exist
+  syntax       a file is not valid TOML
+  permissions  a file holding contact info is readable by other users
+  orphan-desc  a _desc key describes a field that no longer exists
+  duplicate    a local field repeats the global value it overrides
+  stale        a local field overrides a global one that changed since
+  empty        a category has no keys, comments, or sub-tables
+  computed     a {{template}} or @ref in the merged files cannot be evaluated
+
+--fix repairs what it can: it makes files private (chmod go-rwx), removes
+orphaned _desc keys, duplicate local fields, and empty categories. Missing
+files, syntax errors, stale overrides, and computed fields need a decision
+and are only reported.
+
+Exits 1 if any problem remains.
+
+Examples:
+  deets doctor
+  deets doctor --fix
+  deets doctor --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagFile == stdinFile {
+			return fmt.Errorf("doctor checks files on disk; --file - is not supported")
+		}
+		findings := diagnose()
+
+		if flagDoctorFix {
+			for i := range findings {
+				if f := &findings[i]; f.fix != nil {
+					if err := f.fix(); err != nil {
+						return fmt.Errorf("fixing %s: %w", f.Message, err)
+					}
+					f.Fixed = true
+				}
+			}
+		}
+
+		remaining := 0
+		for _, f := range findings {
+			if !f.Fixed {
+				remaining++
+			}
+		}
+
+		switch resolveFormat() {
+		case "json":
+			if findings == nil {
+				findings = []finding{}
+			}
+			data, err := json.MarshalIndent(findings, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		default: // table
+			if len(findings) == 0 {
+				if !flagQuiet {
+					fmt.Println("No problems found.")
+				}
+				return nil
+			}
+			for _, f := range findings {
+				status := f.Severity
+				if f.Fixed {
+					status = "fixed"
+				}
+				line := fmt.Sprintf("%-7s  %-11s  %s", status, f.Check, f.Message)
+				if f.Fixable && !f.Fixed {
+					line += " (--fix)"
+				}
+				fmt.Println(line)
+			}
+		}
+
+		if remaining > 0 {
+			return &ExitError{Code: 1}
+		}
+		return nil
+	},
+}
+
+// finding is one problem reported by doctor.
+type finding struct {
+	Severity string `json:"severity"` // "error" or "warning"
+	Check    string `json:"check"`
+	File     string `json:"file,omitempty"`
+	Message  string `json:"message"`
+	Fixable  bool   `json:"fixable"`
+	Fixed    bool   `json:"fixed"`
+
+	fix func() error
+}
+
+// diagnose runs every check against the files deets would read.
+func diagnose() []finding {
+	var findings []finding
+	add := func(severity, check, file, message string, fix func() error) {
+		findings = append(findings, finding{
+			Severity: severity,
+			Check:    check,
+			File:     file,
+			Message:  message,
+			Fixable:  fix != nil,
+			fix:      fix,
+		})
+	}
+
+	var files []string
+	if flagFile != "" {
+		files = []string{flagFile}
+	} else {
+		candidates, err := candidateFiles()
+		if err != nil {
+			add("error", "syntax", config.ContextsFile(), err.Error(), nil)
+		}
+		for _, c := range candidates {
+			switch {
+			case c.Exists:
+				files = append(files, c.Path)
+			case c.Layer == "global" && c.Note == "":
+				add("error", "missing", c.Path, c.Path+" does not exist; run 'deets init'", nil)
+			case c.Layer == "profile":
+				_, err := profileFile()
+				add("error", "missing", c.Path, err.Error(), nil)
+			}
+		}
+	}
+
+	dbs := map[string]*model.DB{}
+	var loaded []*model.DB
+	for _, path := range files {
+		db, err := store.LoadFile(path)
+		if err != nil {
+			add("error", "syntax", path, err.Error(), nil)
+			continue
+		}
+		dbs[path] = db
+		loaded = append(loaded, db)
+		checkFile(path, db, add)
+	}
+
+	if flagFile == "" && !flagNoGlobal {
+		global, local := dbs[config.GlobalFile()], dbs[config.FindLocalFile()]
+		if global != nil && local != nil {
+			checkLayers(config.GlobalFile(), config.FindLocalFile(), global, local, add)
+		}
+	}
+
+	// Templates and @refs are evaluated on the merged files, as every read
+	// does, so one that fails here fails every command. Resolve changes the
+	// fields it evaluates, which the checks above share, so it runs last.
+	if len(loaded) > 0 {
+		merged := loaded[0]
+		for _, db := range loaded[1:] {
+			merged = store.Merge(merged, db)
+		}
+		if err := merged.Resolve(); err != nil {
+			add("error", "computed", "", err.Error(), nil)
+		}
+	}
+	return findings
+}
+
+// checkFile runs the checks that concern a single valid file.
+func checkFile(path string, db *model.DB, add func(severity, check, file, message string, fix func() error)) {
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0o004 != 0 && hasContactInfo(db) {
+		mode := info.Mode().Perm()
+		add("warning", "permissions", path,
+			fmt.Sprintf("%s holds contact info and is readable by everyone (mode %04o)", path, mode),
+			func() error { return os.Chmod(path, mode&^0o077) })
+	}
+
+	if orphans, err := store.OrphanDescs(path); err == nil {
+		for _, orphan := range orphans {
+			cat, key, _ := parsePath(orphan)
+			add("warning", "orphan-desc", path,
+				fmt.Sprintf("%s in %s describes a field that does not exist", orphan, path),
+				func() error { return store.RemoveValue(path, cat, key) })
+		}
+	}
+
+	if empty, err := store.EmptyCategories(path); err == nil {
+		for _, cat := range empty {
+			add("warning", "empty", path,
+				fmt.Sprintf("[%s] in %s is empty", cat, path),
+				func() error { return store.RemoveCategory(path, cat) })
+		}
+	}
+}
+
+// hasContactInfo reports whether db has any fields in [contact].
+func hasContactInfo(db *model.DB) bool {
+	for _, cat := range db.Categories {
+		if cat.Name == "contact" && len(cat.Fields) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLayers compares the local file against the global one it overrides.
+func checkLayers(globalPath, localPath string, global, local *model.DB, add func(severity, check, file, message string, fix func() error)) {
+	// A global file edited after the local one may have moved on from the
+	// values the local file overrides.
+	globalNewer := false
+	if gi, err := os.Stat(globalPath); err == nil {
+		if li, err := os.Stat(localPath); err == nil {
+			globalNewer = gi.ModTime().After(li.ModTime())
+		}
+	}
+
+	for _, entry := range computeDiff(global, local) {
+		if entry.Status == "override" && globalNewer {
+			add("warning", "stale", localPath,
+				fmt.Sprintf("%s in %s overrides %q, and the global file changed after it", entry.Path, localPath, entry.GlobalVal),
+				nil)
+		}
+	}
+
+	for _, cat := range local.Categories {
+		for _, f := range cat.Fields {
+			if model.IsDescKey(f.Key) {
+				continue // removed along with the field
+			}
+			path := cat.Name + "." + f.Key
+			g, ok := global.GetField(path)
+			if !ok || model.FormatValue(g.Value) != model.FormatValue(f.Value) {
+				continue
+			}
+			category, key := cat.Name, f.Key
+			add("warning", "duplicate", localPath,
+				fmt.Sprintf("%s in %s repeats the global value", path, localPath),
+				func() error {
+					if err := store.RemoveValue(localPath, category, key); err != nil {
+						return err
+					}
+					_ = store.RemoveValue(localPath, category, key+"_desc")
+					return nil
+				})
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupLocal writes content as the local .deets/me.toml of a project
+// directory under home and changes into it.
+func setupLocal(t *testing.T, home, content string) string {
+	t.Helper()
+	workDir := filepath.Join(home, "project")
+	os.MkdirAll(filepath.Join(workDir, ".deets"), 0755)
+	os.Chdir(workDir)
+	path := filepath.Join(workDir, ".deets", "me.toml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDoctor_Clean(t *testing.T) {
+	home := setupTestDB(t)
+	os.Chmod(filepath.Join(home, ".deets", "me.toml"), 0600)
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("doctor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "No problems found") {
+		t.Errorf("expected no problems, got %q", stdout)
+	}
+}
+
+func TestDoctor_MissingGlobal(t *testing.T) {
+	setupTestEnv(t)
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("doctor")
+	if exitErr, ok := err.(*ExitError); !ok || exitErr.Code != 1 {
+		t.Fatalf("expected exit 1, got %v", err)
+	}
+	if !strings.Contains(stdout, "missing") || !strings.Contains(stdout, "deets init") {
+		t.Errorf("expected missing global report, got %q", stdout)
+	}
+}
+
+func TestDoctor_Syntax(t *testing.T) {
+	home := setupTestEnv(t)
+	os.MkdirAll(filepath.Join(home, ".deets"), 0755)
+	os.WriteFile(filepath.Join(home, ".deets", "me.toml"), []byte("[identity\nname = \n"), 0600)
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("doctor")
+	if err == nil {
+		t.Fatal("expected error for malformed TOML")
+	}
+	if !strings.Contains(stdout, "syntax") {
+		t.Errorf("expected syntax report, got %q", stdout)
+	}
+}
+
+func TestDoctor_FixesFileProblems(t *testing.T) {
+	home := setupTestEnv(t)
+	global := filepath.Join(home, ".deets", "me.toml")
+	os.MkdirAll(filepath.Dir(global), 0755)
+	os.WriteFile(global, []byte(`[contact]
+email = "alex@example.com"
+phone_desc = "Mobile number"
+
+[empty]
+`), 0644)
+
+	flagFormat = "json"
+	stdout, _, err := executeCommand("doctor")
+	if err == nil {
+		t.Fatal("expected exit 1 before fixing")
+	}
+	var findings []finding
+	if err := json.Unmarshal([]byte(stdout), &findings); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	checks := map[string]bool{}
+	for _, f := range findings {
+		checks[f.Check] = f.Fixable
+	}
+	for _, check := range []string{"permissions", "orphan-desc", "empty"} {
+		if fixable, ok := checks[check]; !ok || !fixable {
+			t.Errorf("expected fixable %s finding, got %+v", check, findings)
+		}
+	}
+
+	flagDoctorFix = true
+	if _, _, err := executeCommand("doctor"); err != nil {
+		t.Fatalf("fix: %v", err)
+	}
+
+	info, _ := os.Stat(global)
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("mode = %04o, want 0600", perm)
+	}
+	data, _ := os.ReadFile(global)
+	if strings.Contains(string(data), "phone_desc") || strings.Contains(string(data), "[empty]") {
+		t.Errorf("orphan or empty category not removed:\n%s", data)
+	}
+
+	flagDoctorFix = false
+	if _, _, err := executeCommand("doctor"); err != nil {
+		t.Errorf("expected no problems after fixing, got %v", err)
+	}
+}
+
+func TestDoctor_Layers(t *testing.T) {
+	home := setupTestDB(t)
+	global := filepath.Join(home, ".deets", "me.toml")
+	os.Chmod(global, 0600)
+	local := setupLocal(t, home, `[web]
+github = "queelius"
+github_desc = "GitHub username"
+website = "https://other.example.com"
+`)
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(local, old, old)
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("doctor")
+	if err == nil {
+		t.Fatal("expected exit 1")
+	}
+	if !strings.Contains(stdout, "duplicate") || !strings.Contains(stdout, "web.github") {
+		t.Errorf("expected duplicate report, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "stale") || !strings.Contains(stdout, "web.website") {
+		t.Errorf("expected stale report, got %q", stdout)
+	}
+
+	flagDoctorFix = true
+	stdout, _, err = executeCommand("doctor")
+	if err == nil {
+		t.Fatal("stale override is not fixable; expected exit 1")
+	}
+	if !strings.Contains(stdout, "fixed") {
+		t.Errorf("expected fixed duplicate, got %q", stdout)
+	}
+	data, _ := os.ReadFile(local)
+	if strings.Contains(string(data), "github") {
+		t.Errorf("duplicate not removed from local file:\n%s", data)
+	}
+	if !strings.Contains(string(data), "website") {
+		t.Errorf("override removed from local file:\n%s", data)
+	}
+}
+
+func TestDoctor_ProfileAndComputed(t *testing.T) {
+	home := setupTestDB(t)
+	profiles := filepath.Join(home, ".deets", "profiles")
+	os.MkdirAll(profiles, 0755)
+	os.WriteFile(filepath.Join(profiles, "work.toml"), []byte("[work]\na = \"{{work.b}}\"\nb = \"{{work.a}}\"\n"), 0600)
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("doctor", "--profile", "work")
+	if err == nil {
+		t.Fatal("expected exit 1")
+	}
+	if !strings.Contains(stdout, "computed") || !strings.Contains(stdout, "work.a") {
+		t.Errorf("expected computed report for the profile's cycle, got %q", stdout)
+	}
+
+	flagProfile = ""
+	stdout, _, _ = executeCommand("doctor", "--profile", "nope")
+	if !strings.Contains(stdout, "missing") || !strings.Contains(stdout, "profile create nope") {
+		t.Errorf("expected missing profile report, got %q", stdout)
+	}
+	flagProfile = ""
+}
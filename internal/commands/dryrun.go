@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var flagDryRun bool
+
+// addDryRunFlag registers --dry-run on a write command.
+func addDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "show what would change without writing")
+}
+
+// previewWrite runs write against a scratch copy of filePath and prints the
+// fields it would add, change, or remove, leaving filePath untouched.
+func previewWrite(filePath string, write func(path string) error) error {
+	before, after, err := scratchWrite(filePath, write)
+	if err != nil {
+		return err
+	}
+	return printDiffEntries(diffFields(before, after))
+}
+
+// scratchWrite runs write against a scratch copy of filePath and returns
+// the file's contents before and after, leaving filePath untouched.
+func scratchWrite(filePath string, write func(path string) error) (before, after *model.DB, err error) {
+	dir, err := os.MkdirTemp("", "deets-dry-run")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(dir)
+	scratch := filepath.Join(dir, filepath.Base(filePath))
+
+	before = &model.DB{}
+	data, err := os.ReadFile(filePath)
+	if err == nil {
+		if before, err = store.LoadFile(filePath); err != nil {
+			return nil, nil, err
+		}
+		err = os.WriteFile(scratch, data, 0600)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	resume := store.PauseBackups()
+	err = write(scratch)
+	resume()
+	if err != nil {
+		// Report errors against the real file, not the scratch copy.
+		return nil, nil, errors.New(strings.ReplaceAll(err.Error(), scratch, filePath))
+	}
+	if after, err = store.LoadFile(scratch); err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+// diffFields lists the fields that differ between two versions of a file:
+// added and changed fields in after's order, then removed ones.
+func diffFields(before, after *model.DB) []model.DiffEntry {
+	var entries []model.DiffEntry
+	seen := make(map[string]bool)
+	for _, f := range valueFields(after) {
+		path := f.Category + "." + f.Key
+		seen[path] = true
+		newVal := model.FormatValue(f.Value)
+		old, ok := before.GetField(path)
+		switch {
+		case !ok:
+			entries = append(entries, model.DiffEntry{Path: path, Status: "add", LocalVal: newVal})
+		case model.FormatValue(old.Value) != newVal:
+			entries = append(entries, model.DiffEntry{Path: path, Status: "change", GlobalVal: model.FormatValue(old.Value), LocalVal: newVal})
+		}
+	}
+	for _, f := range valueFields(before) {
+		path := f.Category + "." + f.Key
+		if !seen[path] {
+			entries = append(entries, model.DiffEntry{Path: path, Status: "remove", GlobalVal: model.FormatValue(f.Value)})
+		}
+	}
+	return entries
+}
+
+// valueFields returns every field of db except descriptions.
+func valueFields(db *model.DB) []model.Field {
+	var fields []model.Field
+	for _, cat := range db.Categories {
+		for _, f := range cat.Fields {
+			if !model.IsDescKey(f.Key) {
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields
+}
+
+// printDiffEntries prints a change preview as a table or JSON.
+func printDiffEntries(entries []model.DiffEntry) error {
+	if len(entries) == 0 {
+		if !flagQuiet {
+			fmt.Println("No changes to apply.")
+		}
+		return nil
+	}
+
+	switch resolveFormat() {
+	case "json":
+		out, err := model.FormatDiffJSON(entries)
+		if err != nil {
+			return err
+		}
+		fmt.Println(out)
+	default:
+		fmt.Print(model.FormatDiffTable(entries))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/queelius/deets/internal/config"
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(dupesCmd)
+}
+
+var dupesCmd = &cobra.Command{
+	Use:   "dupes",
+	Short: "Find values stored more than once",
+	Long: `Find redundant values in the merged database:
+
+  duplicate           the same string, array, or table under two keys; the
+                      later key can refer to the first with "@category.key"
+  redundant-override  a local field identical to the global field it
+                      overrides, which can simply be removed
+
+Each entry carries a suggested command. Exits 1 if anything is found.
+
+Examples:
+  deets dupes
+  deets dupes --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+
+		// Redundant overrides need the global layer on its own.
+		var global *model.DB
+		if flagFile == "" && !flagNoGlobal && config.FindLocalFile() != "" {
+			if global, err = store.LoadFile(config.GlobalFile()); err != nil {
+				return fmt.Errorf("loading global file: %w", err)
+			}
+		}
+
+		entries := model.FindDupes(db, global)
+
+		switch resolveFormat() {
+		case "json":
+			out, err := model.FormatDupesJSON(entries)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+		default: // table
+			if len(entries) == 0 {
+				if !flagQuiet {
+					fmt.Println("No duplicate values.")
+				}
+				return nil
+			}
+			fmt.Print(model.FormatDupesTable(entries))
+		}
+
+		if len(entries) > 0 {
+			return &ExitError{Code: 1}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDupes_None(t *testing.T) {
+	setupTestDB(t)
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("dupes")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "No duplicate values") {
+		t.Errorf("expected no duplicates, got %q", stdout)
+	}
+}
+
+func TestDupes_Found(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	data, _ := os.ReadFile(path)
+	os.WriteFile(path, []byte(strings.Replace(string(data), "[web]\n", "[web]\ngitlab = \"queelius\"\n", 1)), 0644)
+	setupLocal(t, home, "[contact]\nemail = \"alex@example.com\"\n")
+
+	flagFormat = "json"
+	stdout, _, err := executeCommand("dupes")
+	if exitErr, ok := err.(*ExitError); !ok || exitErr.Code != 1 {
+		t.Fatalf("expected exit 1, got %v", err)
+	}
+	var entries []struct{ Path, Status, SameAs string }
+	if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	statuses := map[string]string{}
+	for _, e := range entries {
+		statuses[e.Path] = e.Status
+	}
+	if statuses["contact.email"] != "redundant-override" || statuses["web.gitlab"] != "duplicate" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
@@ -1,12 +1,16 @@
 package commands
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"strings"
 
-	"github.com/queelius/deets/internal/config"
+	"github.com/BurntSushi/toml"
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/queelius/deets/internal/tui"
 	"github.com/spf13/cobra"
 )
 
@@ -15,38 +19,162 @@ func init() {
 }
 
 var editCmd = &cobra.Command{
-	Use:   "edit",
+	Use:   "edit [category.key]",
 	Short: "Open metadata file in $EDITOR",
-	Long:  "Open ~/.deets/me.toml in $EDITOR, or .deets/me.toml with --local.",
-	Args:  cobra.NoArgs,
+	Long: `Open ~/.deets/me.toml in $EDITOR, or the file set would write to:
+.deets/me.toml with --local, a profile with --profile, or any path with
+--file.
+
+The file is edited as a copy and checked when the editor exits. If it is
+no longer valid TOML you can reopen the editor to fix it; declining
+discards the edit and leaves the file as it was.
+
+Given a field path, only that value is opened. Strings are edited as
+plain text (a final newline is dropped); other values as TOML literals
+such as ["a", "b"] or 42. The value is written back with set's rules.
+
+Examples:
+  deets edit                  # the whole global file
+  deets edit --local
+  deets edit identity.bio     # just one value`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var path string
-		if flagLocal {
-			cwd, err := os.Getwd()
-			if err != nil {
-				return err
-			}
-			path = filepath.Join(cwd, config.DirName, config.FileName)
-		} else {
-			path = config.GlobalFile()
+		if len(args) == 1 {
+			return editField(args[0])
 		}
 
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			return fmt.Errorf("%s does not exist; run 'deets init' first", path)
+		path, err := existingTarget()
+		if err != nil {
+			return err
+		}
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return err
 		}
 
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = os.Getenv("VISUAL")
+		edited, ok, err := editBuffer(original, ".toml", func(data []byte) error {
+			var raw map[string]interface{}
+			return toml.Unmarshal(data, &raw)
+		})
+		if err != nil || !ok || bytes.Equal(edited, original) {
+			return err
 		}
-		if editor == "" {
-			editor = "vi"
+		return store.WriteFile(path, edited)
+	},
+}
+
+// editField opens the value at path in the editor and writes it back to
+// the target file.
+func editField(path string) error {
+	cat, key, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	filePath, err := targetFile()
+	if err != nil {
+		return err
+	}
+
+	// Start from the value as written in the file, so a template or @ref
+	// is edited rather than replaced by what it resolves to. A field the
+	// file does not define starts from its value as currently seen, and a
+	// new field starts empty.
+	var value interface{} = ""
+	if f, ok := fileField(filePath, path); ok {
+		value = f.Value
+	} else if db, err := loadDB(); err == nil {
+		if f, ok := db.GetField(path); ok {
+			value = f.Value
 		}
+	}
+	original := tui.EditText(model.Field{Value: value})
 
-		c := exec.Command(editor, path)
-		c.Stdin = os.Stdin
-		c.Stdout = os.Stdout
-		c.Stderr = os.Stderr
-		return c.Run()
-	},
+	edited, ok, err := editBuffer([]byte(original+"\n"), ".txt", func(data []byte) error {
+		_, err := editedLiteral(value, string(data))
+		return err
+	})
+	if err != nil || !ok {
+		return err
+	}
+	text := strings.TrimSuffix(string(edited), "\n")
+	if text == original {
+		return nil
+	}
+	literal, err := editedLiteral(value, text)
+	if err != nil {
+		return err
+	}
+	return store.SetLiteral(filePath, cat, key, literal)
+}
+
+// editedLiteral turns text edited in place of old into a TOML literal: a
+// string when old was a string, otherwise text must itself be a TOML value.
+func editedLiteral(old interface{}, text string) (string, error) {
+	if _, ok := old.(string); ok {
+		return model.TOMLString(text), nil
+	}
+	text = strings.TrimSpace(text)
+	var probe map[string]interface{}
+	if err := toml.Unmarshal([]byte("v = "+text), &probe); err != nil {
+		return "", fmt.Errorf("not a TOML value: %w", err)
+	}
+	return text, nil
+}
+
+// editBuffer opens content in the user's editor via a temporary file with
+// the given extension and returns the result. When check rejects the
+// result the user may reopen the editor; declining reports ok=false and
+// discards the edit.
+func editBuffer(content []byte, ext string, check func([]byte) error) (edited []byte, ok bool, err error) {
+	f, err := os.CreateTemp("", "deets-*"+ext)
+	if err != nil {
+		return nil, false, err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	_, err = f.Write(content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	for {
+		if err := runEditor(tmp); err != nil {
+			return nil, false, err
+		}
+		edited, err := os.ReadFile(tmp)
+		if err != nil {
+			return nil, false, err
+		}
+		cerr := check(edited)
+		if cerr == nil {
+			return edited, true, nil
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", cerr)
+		if !confirm("Edit again?") {
+			fmt.Fprintln(os.Stderr, "Edit discarded.")
+			return nil, false, nil
+		}
+	}
+}
+
+// runEditor opens path in $EDITOR, falling back to $VISUAL and then vi.
+// The editor setting may include arguments, as in "code --wait".
+func runEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
 }
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeEditor points $EDITOR at a script that replaces the edited file with
+// each of contents in turn, one per invocation.
+func fakeEditor(t *testing.T, contents ...string) {
+	t.Helper()
+	dir := t.TempDir()
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\nn=$(cat " + filepath.Join(dir, "count") + " 2>/dev/null || echo 0)\n")
+	for i, c := range contents {
+		src := filepath.Join(dir, fmt.Sprint("content", i))
+		if err := os.WriteFile(src, []byte(c), 0644); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&script, "[ \"$n\" = %d ] && cp %s \"$1\"\n", i, src)
+	}
+	script.WriteString("echo $((n+1)) > " + filepath.Join(dir, "count") + "\n")
+	path := filepath.Join(dir, "editor")
+	if err := os.WriteFile(path, []byte(script.String()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", path)
+}
+
+func TestEdit_Field(t *testing.T) {
+	home := setupTestDB(t)
+	fakeEditor(t, "line one\nline two\n")
+	if _, _, err := executeCommand("edit", "identity.bio"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fakeEditor(t, `["Alex", "AT"]`+"\n")
+	if _, _, err := executeCommand("edit", "identity.aka"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	for _, want := range []string{"bio = \"\"\"\nline one\nline two\"\"\"", `aka = ["Alex", "AT"]`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in file:\n%s", want, data)
+		}
+	}
+}
+
+func TestEdit_FieldKeepsTemplate(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	data, _ := os.ReadFile(path)
+	data = append(data, "\n[email]\nsignature = \"{{identity.name}}\"\n"...)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	script := filepath.Join(t.TempDir(), "editor")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf '%s, PhD\\n' \"$(cat \"$1\")\" > \"$1\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", script)
+	if _, _, err := executeCommand("edit", "email.signature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), `signature = "{{identity.name}}, PhD"`) {
+		t.Errorf("expected the template to be edited, got:\n%s", data)
+	}
+}
+
+func TestEdit_FieldInvalidDiscarded(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	before, _ := os.ReadFile(path)
+
+	confirmInput = strings.NewReader("n\n")
+	defer func() { confirmInput = os.Stdin }()
+	fakeEditor(t, `["unclosed"`)
+	if _, _, err := executeCommand("edit", "academic.topics"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	after, _ := os.ReadFile(path)
+	if string(after) != string(before) {
+		t.Error("invalid edit should leave the file unchanged")
+	}
+}
+
+func TestEdit_FileRetry(t *testing.T) {
+	home := setupTestDB(t)
+	confirmInput = strings.NewReader("y\n")
+	defer func() { confirmInput = os.Stdin }()
+	fakeEditor(t, "[identity\nname = 1\n", "[identity]\nname = \"Fixed\"\n")
+	if _, _, err := executeCommand("edit"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if string(data) != "[identity]\nname = \"Fixed\"\n" {
+		t.Errorf("expected the corrected file, got:\n%s", data)
+	}
+}
+
+func TestEdit_FileNoGlobal(t *testing.T) {
+	home := setupTestDB(t)
+	fakeEditor(t, "[identity]\nname = \"Changed\"\n")
+	if _, _, err := executeCommand("edit", "--no-global"); err == nil || !strings.Contains(err.Error(), "--no-global") {
+		t.Errorf("err = %v, want --no-global to refuse the global file", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if strings.Contains(string(data), "Changed") {
+		t.Error("edit --no-global wrote the global file")
+	}
+}
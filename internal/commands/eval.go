@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/itchyny/gojq"
+	"github.com/queelius/deets/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagEvalRaw     bool
+	flagEvalCompact bool
+)
+
+func init() {
+	evalCmd.Flags().BoolVarP(&flagEvalRaw, "raw-output", "r", false, "print string results without JSON quotes")
+	evalCmd.Flags().BoolVarP(&flagEvalCompact, "compact-output", "c", false, "print each result on one line")
+	rootCmd.AddCommand(evalCmd)
+}
+
+var evalCmd = &cobra.Command{
+	Use:   "eval <expr>",
+	Short: "Evaluate a jq expression over the merged metadata",
+	Long: `Evaluate a jq expression over the merged metadata, as exported by
+"deets export --format json", and print each result as JSON.
+
+The expression is evaluated by gojq, a Go implementation of jq, so the
+full jq language is available without an external jq binary.
+
+Examples:
+  deets eval '.web | keys'
+  deets eval -r '.identity.name'
+  deets eval '[.. | select(type == "string" and test("@"))]'
+  deets eval '.education.degrees | map(.institution)'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query, err := gojq.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("parsing expression: %w", err)
+		}
+		code, err := gojq.Compile(query)
+		if err != nil {
+			return fmt.Errorf("compiling expression: %w", err)
+		}
+
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+		doc, err := model.FormatJSON(db)
+		if err != nil {
+			return err
+		}
+		var input interface{}
+		if err := json.Unmarshal([]byte(doc), &input); err != nil {
+			return err
+		}
+
+		iter := code.Run(input)
+		for {
+			r, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if err, ok := r.(error); ok {
+				return err
+			}
+			if s, ok := r.(string); ok && flagEvalRaw {
+				fmt.Println(s)
+				continue
+			}
+			var data []byte
+			if flagEvalCompact {
+				data, err = json.Marshal(r)
+			} else {
+				data, err = json.MarshalIndent(r, "", "  ")
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		}
+		return nil
+	},
+}
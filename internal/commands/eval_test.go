@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEval_Keys(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("eval", "-c", ".web | keys")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(stdout) != `["github","website"]` {
+		t.Errorf("got %q", stdout)
+	}
+}
+
+func TestEval_RawOutput(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("eval", "-r", ".identity.name, .identity.aka[0]")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "Alexander Towell\nAlex Towell\n" {
+		t.Errorf("got %q", stdout)
+	}
+}
+
+func TestEval_ParseError(t *testing.T) {
+	setupTestDB(t)
+	_, _, err := executeCommand("eval", ".identity |")
+	if err == nil || !strings.Contains(err.Error(), "parsing expression") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
+
+func TestEval_FullLanguage(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("eval", "-r", `.academic.topics as $t | "\($t | length) topics: \(reduce $t[] as $x (""; . + $x[0:1]))"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "2 topics: sm\n" {
+		t.Errorf("got %q", stdout)
+	}
+}
+
+func TestEval_RuntimeError(t *testing.T) {
+	setupTestDB(t)
+	_, _, err := executeCommand("eval", ".identity.name | keys")
+	if err == nil {
+		t.Error("expected an error for keys on a string")
+	}
+}
@@ -7,7 +7,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	flagExportPrefix string
+	flagExportShell  string
+)
+
 func init() {
+	exportCmd.Flags().StringVar(&flagExportPrefix, "prefix", "", "variable name prefix for --format dotenv (e.g. APP_)")
+	exportCmd.Flags().StringVar(&flagExportShell, "shell", "posix", "shell syntax for --format env: posix, fish, or powershell")
+	exportCmd.Flags().StringArrayVar(&flagExclude, "exclude", nil, excludeUsage)
 	rootCmd.AddCommand(exportCmd)
 }
 
@@ -18,15 +26,32 @@ var exportCmd = &cobra.Command{
 
 Examples:
   deets export --format json    # JSON (default)
-  deets export --format env     # DEETS_IDENTITY_NAME="..." format
+  deets export --format env     # DEETS_IDENTITY_NAME='...' format
+  eval "$(deets export --format env)"            # load into a POSIX shell
+  deets export --format env --shell fish | source
+  deets export --format env --shell powershell | Invoke-Expression
+  deets export --format dotenv > .env            # IDENTITY_NAME='...'
+  deets export --format dotenv --prefix APP_     # APP_IDENTITY_NAME='...'
   deets export --format toml    # raw merged TOML
-  deets export --format yaml    # YAML`,
+  deets export --format yaml    # YAML
+  deets export --format ini     # [category] sections
+  deets export --format hcl     # category "identity" { ... } blocks
+  deets export --format nix     # Nix attribute set
+  deets export --format plist   # macOS XML property list
+  deets export --format vcard > me.vcf           # vCard 4.0 contact card
+  deets export --format jsonresume > resume.json # JSON Resume schema
+  deets export --format jsonld  # schema.org Person JSON-LD
+  deets export --format turtle > foaf.ttl        # FOAF in RDF Turtle
+  deets export --format hcard   # h-card microformat HTML fragment
+  deets export --format csv     # category,key,value,description rows
+  deets export --exclude 'contact.*' --exclude '*.phone'  # omit fields`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		db, err := loadDB()
 		if err != nil {
 			return err
 		}
+		db = db.Without(flagExclude)
 
 		// Export defaults to JSON when resolveFormat() returns "table",
 		// since export is inherently structured output.
@@ -35,20 +60,28 @@ Examples:
 			format = "json"
 		}
 
-		switch format {
-		case "env":
-			fmt.Print(model.FormatEnv(db))
-		case "toml":
-			fmt.Print(model.FormatTOML(db))
-		case "yaml":
-			fmt.Print(model.FormatYAML(db))
-		default: // json
-			out, err := model.FormatJSON(db)
+		if format == "env" {
+			out, err := model.FormatEnv(db, flagExportShell)
 			if err != nil {
 				return err
 			}
-			fmt.Println(out)
+			fmt.Print(out)
+			return nil
+		}
+		if format == "dotenv" {
+			fmt.Print(model.FormatDotenv(db, flagExportPrefix))
+			return nil
+		}
+		if ok, err := printDocument(db, format); ok {
+			return err
+		}
+
+		// json
+		out, err := model.FormatJSON(db)
+		if err != nil {
+			return err
 		}
+		fmt.Println(out)
 		return nil
 	},
 }
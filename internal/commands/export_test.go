@@ -49,6 +49,22 @@ func TestExport_Env(t *testing.T) {
 	}
 }
 
+func TestExport_EnvShell(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "env"
+	stdout, _, err := executeCommand("export", "--shell", "fish")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "set -gx DEETS_IDENTITY_NAME 'Alexander Towell'") {
+		t.Errorf("expected fish syntax, got %q", stdout)
+	}
+
+	if _, _, err := executeCommand("export", "--shell", "csh"); err == nil {
+		t.Error("expected error for unknown shell")
+	}
+}
+
 func TestExport_TOML(t *testing.T) {
 	setupTestDB(t)
 	flagFormat = "toml"
@@ -78,3 +94,81 @@ func TestExport_YAML(t *testing.T) {
 		t.Error("expected name field in YAML")
 	}
 }
+
+func TestExport_Dotenv(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "dotenv"
+	stdout, _, err := executeCommand("export")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "IDENTITY_NAME='Alexander Towell'") {
+		t.Errorf("expected dotenv line, got %q", stdout)
+	}
+
+	stdout, _, err = executeCommand("export", "--prefix", "APP_")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "APP_WEB_GITHUB='queelius'") {
+		t.Errorf("expected prefixed dotenv line, got %q", stdout)
+	}
+}
+
+func TestExport_INI(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "ini"
+	stdout, _, err := executeCommand("export")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "[identity]\n") {
+		t.Error("expected [identity] section")
+	}
+	if !strings.Contains(stdout, "name = Alexander Towell\n") {
+		t.Errorf("expected bare INI value, got %q", stdout)
+	}
+}
+
+func TestExport_VCard(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "vcard"
+	stdout, _, err := executeCommand("export")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(stdout, "BEGIN:VCARD\r\nVERSION:4.0\r\n") {
+		t.Errorf("expected vCard output, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "FN:Alexander Towell\r\n") {
+		t.Errorf("expected FN line, got %q", stdout)
+	}
+}
+
+func TestExport_JSONResumeMapping(t *testing.T) {
+	home := setupTestDB(t)
+	writeSettings(t, home, "[jsonresume]\n\"identity.aka\" = \"basics.aliases\"\n")
+	flagFormat = "jsonresume"
+	stdout, _, err := executeCommand("export")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, `"$schema"`) || !strings.Contains(stdout, `"aliases": [`) {
+		t.Errorf("expected JSON Resume with mapped aliases, got %s", stdout)
+	}
+}
+
+func TestExport_Exclude(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "env"
+	stdout, _, err := executeCommand("export", "--exclude", "contact.*", "--exclude", "*.github")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(stdout, "DEETS_CONTACT_") || strings.Contains(stdout, "DEETS_WEB_GITHUB") {
+		t.Errorf("excluded fields present:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "DEETS_IDENTITY_NAME") {
+		t.Errorf("expected identity.name to remain:\n%s", stdout)
+	}
+}
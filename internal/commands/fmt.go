@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagFmtSort  bool
+	flagFmtCheck bool
+)
+
+func init() {
+	fmtCmd.Flags().BoolVar(&flagFmtSort, "sort", false, "sort keys alphabetically within each section")
+	fmtCmd.Flags().BoolVar(&flagFmtCheck, "check", false, "report whether the file needs formatting without writing it")
+	rootCmd.AddCommand(fmtCmd)
+}
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt",
+	Short: "Rewrite the metadata file in canonical style",
+	Long: `Rewrite ~/.deets/me.toml (or the --local / --file target) in a canonical
+style: no indentation, "key = value" with the equals signs aligned within
+each block of keys, one blank line between sections, and no runs of blank
+lines. Comments are kept next to the key or section below them, and
+values are left exactly as written.
+
+Keys stay in file order unless --sort is given, which sorts them within
+each section, each _desc key directly after the key it describes.
+
+With --check, nothing is written: fmt prints the path and exits 1 if the
+file is not formatted, for use in scripts and hooks.
+
+Examples:
+  deets fmt
+  deets fmt --sort
+  deets fmt --local --check`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := existingTarget()
+		if err != nil {
+			return err
+		}
+
+		changed, err := store.FormatFile(path, flagFmtSort, flagFmtCheck)
+		if err != nil {
+			return err
+		}
+		if flagFmtCheck {
+			if changed {
+				fmt.Println(path)
+				return &ExitError{Code: 1}
+			}
+			return nil
+		}
+		if !flagQuiet {
+			if changed {
+				fmt.Printf("Formatted %s\n", path)
+			} else {
+				fmt.Printf("%s is already formatted\n", path)
+			}
+		}
+		return nil
+	},
+}
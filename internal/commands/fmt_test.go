@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFmt(t *testing.T) {
+	home := setupTestEnv(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte("[web]\n  github=\"queelius\"\nblog = \"b\"\n"), 0644)
+
+	stdout, _, err := executeCommand("fmt", "--check")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 1 {
+		t.Fatalf("expected exit code 1 from --check, got %v", err)
+	}
+	if strings.TrimSpace(stdout) != path {
+		t.Errorf("expected the path reported, got %q", stdout)
+	}
+	flagFmtCheck = false
+
+	if _, _, err := executeCommand("fmt", "--sort", "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if want := "[web]\nblog   = \"b\"\ngithub = \"queelius\"\n"; string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+	flagFmtSort = false
+
+	if _, _, err := executeCommand("fmt", "--check"); err != nil {
+		t.Errorf("formatted file should pass --check, got %v", err)
+	}
+}
+
+func TestFmt_MissingFile(t *testing.T) {
+	setupTestEnv(t)
+	if _, _, err := executeCommand("fmt"); err == nil || !strings.Contains(err.Error(), "deets init") {
+		t.Errorf("expected missing file error, got %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -43,3 +46,81 @@ func TestResolveFormat_EmptyDefaultsBasedOnTTY(t *testing.T) {
 		t.Errorf("resolveFormat() in non-TTY = %q, want %q", got, "json")
 	}
 }
+
+// writeSettings writes a config.toml into the test home's .deets directory.
+func writeSettings(t *testing.T, home, content string) {
+	t.Helper()
+	dir := filepath.Join(home, ".deets")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveFormat_ConfigPerCommand(t *testing.T) {
+	home := setupTestDB(t)
+	writeSettings(t, home, "[formats]\nexport = \"env\"\n")
+
+	stdout, _, err := executeCommand("export")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, `DEETS_IDENTITY_NAME='Alexander Towell'`) {
+		t.Errorf("expected env output from config default, got %q", stdout)
+	}
+
+	// Other commands keep the TTY heuristic.
+	stdout, _, err = executeCommand("keys")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(stdout), "[") {
+		t.Errorf("expected JSON keys output, got %q", stdout)
+	}
+}
+
+func TestResolveFormat_FlagBeatsConfig(t *testing.T) {
+	home := setupTestDB(t)
+	writeSettings(t, home, "[formats]\nexport = \"env\"\n")
+
+	stdout, _, err := executeCommand("export", "--format", "yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "identity:") {
+		t.Errorf("expected YAML output from explicit flag, got %q", stdout)
+	}
+}
+
+func TestResolveFormat_ConfigGetBare(t *testing.T) {
+	home := setupTestDB(t)
+	writeSettings(t, home, "[formats]\nget = \"bare\"\n")
+
+	stdout, _, err := executeCommand("get", "identity.name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(stdout) != "Alexander Towell" {
+		t.Errorf("expected bare value when piped with get = \"bare\", got %q", stdout)
+	}
+
+	// Multiple matches fall back to the TTY heuristic (JSON when piped).
+	stdout, _, err = executeCommand("get", "identity")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(stdout), "{") {
+		t.Errorf("expected JSON for multiple matches, got %q", stdout)
+	}
+}
+
+func TestResolveFormat_ConfigInvalid(t *testing.T) {
+	home := setupTestDB(t)
+	writeSettings(t, home, "[formats]\nshow = \"bare\"\n")
+
+	if _, _, err := executeCommand("show"); err == nil {
+		t.Error("expected error for invalid configured format")
+	}
+}
@@ -0,0 +1,293 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/queelius/deets/internal/config"
+	"github.com/queelius/deets/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagCitationTitle    string
+	flagGenerateTemplate string
+	flagSignatureHTML    bool
+	flagGoPackage        string
+)
+
+func init() {
+	generateCitationCmd.Flags().StringVar(&flagCitationTitle, "title", "", "emit a complete CITATION.cff with this title")
+	generateCmd.AddCommand(generateCitationCmd)
+	generateCmd.AddCommand(generateCodemetaCmd)
+	generateCmd.AddCommand(generateLaTeXCmd)
+	generateHumansCmd.Flags().StringVar(&flagGenerateTemplate, "template", "", "Go text/template file to use instead of the built-in template")
+	generateCmd.AddCommand(generateHumansCmd)
+	generateSignatureCmd.Flags().StringVar(&flagGenerateTemplate, "template", "", "Go template file to use instead of the built-in template")
+	generateSignatureCmd.Flags().BoolVar(&flagSignatureHTML, "html", false, "emit an HTML signature")
+	generateCmd.AddCommand(generateSignatureCmd)
+	generateProfileReadmeCmd.Flags().StringVar(&flagGenerateTemplate, "template", "", "Go text/template file to use instead of the built-in template")
+	generateCmd.AddCommand(generateProfileReadmeCmd)
+	generateGoCmd.Flags().StringVar(&flagGoPackage, "package", "deets", "package name for the generated file")
+	generateCmd.AddCommand(generateGoCmd)
+	generateCmd.AddCommand(generateTSCmd)
+	rootCmd.AddCommand(generateCmd)
+}
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate project files from your metadata",
+}
+
+// renderGenerator renders a template-driven generator. The template is read
+// from --template if given, else from ~/.deets/templates/<name> if that file
+// exists, else the built-in template is used.
+func renderGenerator(db *model.DB, name, builtin string) (string, error) {
+	text := builtin
+	path := flagGenerateTemplate
+	if path == "" {
+		if p := config.TemplateFile(name); p != "" {
+			if _, err := os.Stat(p); err == nil {
+				path = p
+			}
+		}
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("reading template: %w", err)
+		}
+		text = string(data)
+	}
+	out, err := model.RenderTemplate(db, name, text)
+	if err != nil {
+		return "", fmt.Errorf("rendering %s template: %w", name, err)
+	}
+	return out, nil
+}
+
+var generateCitationCmd = &cobra.Command{
+	Use:   "citation-cff",
+	Short: "Print a CITATION.cff authors block",
+	Long: `Print a CITATION.cff authors block built from identity.name,
+contact.email, academic.orcid, and academic.institution.
+
+Examples:
+  deets generate citation-cff                         # authors block to paste
+  deets generate citation-cff --title "My Tool" > CITATION.cff`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+		fmt.Print(model.FormatCitationCFF(db, flagCitationTitle))
+		return nil
+	},
+}
+
+var generateCodemetaCmd = &cobra.Command{
+	Use:   "codemeta [file]",
+	Short: "Generate or update codemeta.json author entries",
+	Long: `Add you to the author and maintainer lists of a codemeta.json file.
+
+Without a file argument, a new minimal codemeta.json is printed. With one,
+the file is updated in place (or created): an existing entry for you,
+matched by ORCID, email, or name, is replaced, co-authors are kept, and all
+other keys are left untouched.
+
+Examples:
+  deets generate codemeta > codemeta.json   # new file
+  deets generate codemeta codemeta.json     # merge into existing file`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			out, err := model.MergeCodemeta(nil, db.Author())
+			if err != nil {
+				return err
+			}
+			fmt.Print(string(out))
+			return nil
+		}
+
+		path := args[0]
+		existing, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		out, err := model.MergeCodemeta(existing, db.Author())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := os.WriteFile(path, out, 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+		if !flagQuiet {
+			fmt.Printf("Updated %s\n", path)
+		}
+		return nil
+	},
+}
+
+var generateLaTeXCmd = &cobra.Command{
+	Use:   "latex",
+	Short: "Print LaTeX author macros",
+	Long: `Print LaTeX macro definitions (\deetsname, \deetsbibname, \deetsemail,
+\deetsorcid, \deetsaffiliation) followed by \author{} and \affiliation{}
+lines, for paper templates to \input. \deetsbibname holds the BibTeX-style
+"Lastname, Firstname" form of identity.name.
+
+Examples:
+  deets generate latex > authors.tex`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+		fmt.Print(model.FormatLaTeX(db))
+		return nil
+	},
+}
+
+var generateHumansCmd = &cobra.Command{
+	Use:   "humans-txt",
+	Short: "Print a humans.txt TEAM section",
+	Long: `Print the TEAM section of a humans.txt file built from identity, contact,
+and web fields.
+
+The output comes from a Go text/template. To customize it, save a template
+as ~/.deets/templates/humans.txt or pass --template. Categories are available
+by name ({{.identity.name}}), along with .Links, .Author, .Degrees, and
+.Positions, and the helpers value, join, items, shield, and title.
+
+Examples:
+  deets generate humans-txt > public/humans.txt
+  deets generate humans-txt --template site/humans.tmpl`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+		out, err := renderGenerator(db, "humans.txt", model.HumansTemplate)
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+var generateSignatureCmd = &cobra.Command{
+	Use:   "signature",
+	Short: "Print an email signature",
+	Long: `Print an email signature built from identity.name, academic.title,
+academic.institution, contact.email, and web links.
+
+Plain text is the default; --html emits an HTML fragment. Customize either by
+saving a template as ~/.deets/templates/signature.txt or signature.html, or
+with --template. HTML templates escape interpolated values automatically.
+
+Examples:
+  deets generate signature
+  deets generate signature --html > signature.html`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+		name, builtin := "signature.txt", model.SignatureTemplate
+		if flagSignatureHTML {
+			name, builtin = "signature.html", model.SignatureHTMLTemplate
+		}
+		out, err := renderGenerator(db, name, builtin)
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+var generateProfileReadmeCmd = &cobra.Command{
+	Use:   "profile-readme",
+	Short: "Print a GitHub profile README",
+	Long: `Print a Markdown GitHub profile README with your name, identity.bio,
+badges for GitHub, Mastodon, and ORCID, and academic.research_interests.
+
+Customize it by saving a template as ~/.deets/templates/profile-readme.md,
+or with --template.
+
+Examples:
+  deets generate profile-readme > README.md`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+		out, err := renderGenerator(db, "profile-readme.md", model.ProfileReadmeTemplate)
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
+
+var generateGoCmd = &cobra.Command{
+	Use:   "go",
+	Short: "Generate typed Go structs for your metadata",
+	Long: `Generate Go source with a Deets struct (one field per category), a struct
+per category with typed fields, and Parse/Load functions that decode the
+output of "deets export --format json". Field types come from the schema:
+string, int64, float64, bool, slices, and item structs for records.
+
+Examples:
+  deets generate go > deets_gen.go
+  deets generate go --package profile > internal/profile/deets.go`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+		src, err := model.FormatGo(db, flagGoPackage)
+		if err != nil {
+			return err
+		}
+		fmt.Print(src)
+		return nil
+	},
+}
+
+var generateTSCmd = &cobra.Command{
+	Use:   "ts",
+	Short: "Generate TypeScript types and values for your metadata",
+	Long: `Generate a TypeScript module with an interface per category, a Deets
+interface combining them, and an exported "deets" const holding the current
+values. Descriptions become JSDoc comments; _desc fields are excluded.
+
+Examples:
+  deets generate ts > src/deets.ts`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+		out, err := model.FormatTypeScript(db)
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	},
+}
@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCitationCFF(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("generate", "citation-cff")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(stdout, "authors:\n  - family-names: Towell\n    given-names: Alexander\n") {
+		t.Errorf("unexpected authors block:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "orcid: \"https://orcid.org/") {
+		t.Errorf("expected ORCID URL, got:\n%s", stdout)
+	}
+}
+
+func TestGenerateCitationCFF_Title(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("generate", "citation-cff", "--title", "deets")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(stdout, "cff-version: 1.2.0\n") || !strings.Contains(stdout, "title: deets\n") {
+		t.Errorf("expected full CITATION.cff, got:\n%s", stdout)
+	}
+}
+
+func TestGenerateCodemeta_Stdout(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("generate", "codemeta")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, `"@type": "SoftwareSourceCode"`) || !strings.Contains(stdout, `"familyName": "Towell"`) {
+		t.Errorf("unexpected codemeta output:\n%s", stdout)
+	}
+}
+
+func TestGenerateCodemeta_MergesFile(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, "codemeta.json")
+	if err := os.WriteFile(path, []byte(`{"name": "tool", "version": "2.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout, _, err := executeCommand("generate", "codemeta", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "Updated "+path) {
+		t.Errorf("expected confirmation, got %q", stdout)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(data)
+	if !strings.HasPrefix(s, "{\n  \"name\": \"tool\",\n  \"version\": \"2.0\",\n  \"author\": [") {
+		t.Errorf("expected existing keys kept and author appended:\n%s", s)
+	}
+}
+
+func TestGenerateCodemeta_InvalidFile(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, "codemeta.json")
+	os.WriteFile(path, []byte("not json"), 0644)
+	if _, _, err := executeCommand("generate", "codemeta", path); err == nil {
+		t.Error("expected error for invalid codemeta.json")
+	}
+}
+
+func TestGenerateLaTeX(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("generate", "latex")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, `\newcommand{\deetsbibname}{Towell, Alexander}`) {
+		t.Errorf("expected BibTeX name macro, got:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, `\orcidlink{`) {
+		t.Errorf("expected orcidlink, got:\n%s", stdout)
+	}
+}
+
+func TestGenerateHumansTxt(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("generate", "humans-txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(stdout, "/* TEAM */\n\tName: Alexander Towell\n") {
+		t.Errorf("unexpected humans.txt:\n%s", stdout)
+	}
+}
+
+func TestGenerateHumansTxt_UserTemplate(t *testing.T) {
+	home := setupTestDB(t)
+	dir := filepath.Join(home, ".deets", "templates")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "humans.txt"), []byte("Dev: {{.identity.name}}\n"), 0644)
+
+	stdout, _, err := executeCommand("generate", "humans-txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "Dev: Alexander Towell\n" {
+		t.Errorf("expected user template output, got %q", stdout)
+	}
+
+	flagPath := filepath.Join(home, "custom.tmpl")
+	os.WriteFile(flagPath, []byte("Custom {{.web.github}}\n"), 0644)
+	stdout, _, err = executeCommand("generate", "humans-txt", "--template", flagPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "Custom queelius\n" {
+		t.Errorf("expected --template to win, got %q", stdout)
+	}
+}
+
+func TestGenerateHumansTxt_BadTemplate(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, "bad.tmpl")
+	os.WriteFile(path, []byte("{{.identity.name"), 0644)
+	_, _, err := executeCommand("generate", "humans-txt", "--template", path)
+	if err == nil || !strings.Contains(err.Error(), "rendering humans.txt template") {
+		t.Errorf("expected template error, got %v", err)
+	}
+}
+
+func TestGenerateSignature(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("generate", "signature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(stdout, "-- \nAlexander Towell\n") {
+		t.Errorf("unexpected signature:\n%s", stdout)
+	}
+
+	stdout, _, err = executeCommand("generate", "signature", "--html")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "<strong>Alexander Towell</strong>") {
+		t.Errorf("expected HTML signature, got:\n%s", stdout)
+	}
+}
+
+func TestGenerateProfileReadme(t *testing.T) {
+	home := setupTestDB(t)
+	stdout, _, err := executeCommand("generate", "profile-readme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(stdout, "# Alexander Towell\n") || !strings.Contains(stdout, "img.shields.io/badge/Github-queelius") {
+		t.Errorf("unexpected profile README:\n%s", stdout)
+	}
+
+	dir := filepath.Join(home, ".deets", "templates")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "profile-readme.md"), []byte("Hello, {{.identity.name}}!\n"), 0644)
+	stdout, _, err = executeCommand("generate", "profile-readme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "Hello, Alexander Towell!\n" {
+		t.Errorf("expected user template, got %q", stdout)
+	}
+}
+
+func TestGenerateGo(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("generate", "go", "--package", "me")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "package me\n") || !strings.Contains(stdout, "type Identity struct {") {
+		t.Errorf("unexpected generated code:\n%s", stdout)
+	}
+}
+
+func TestGenerateTS(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("generate", "ts")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "export interface Identity {") || !strings.Contains(stdout, "export const deets: Deets = {") {
+		t.Errorf("unexpected TypeScript:\n%s", stdout)
+	}
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/queelius/deets/internal/model"
@@ -12,69 +13,165 @@ var (
 	flagGetDefault string
 	flagGetDesc    bool
 	flagGetExists  bool
+	flagGetFirst   bool
+	flagGetRegex   bool
+	flagGetSep     string
+	flagGetNull    bool
+	flagGetCopy    bool
 )
 
 func init() {
+	getCmd.Flags().StringVar(&flagTemplate, "template", "", "format results with a Go text/template")
 	getCmd.Flags().StringVar(&flagGetDefault, "default", "", "fallback value when no match found")
 	getCmd.Flags().BoolVar(&flagGetDesc, "desc", false, "include field descriptions in output")
 	getCmd.Flags().BoolVar(&flagGetExists, "exists", false, "check existence; exit 0 if found, 2 if not (no output)")
+	getCmd.Flags().BoolVar(&flagGetFirst, "first", false, "use only the first path that matches anything (fallback chain)")
+	getCmd.Flags().StringArrayVar(&flagExclude, "exclude", nil, excludeUsage)
+	getCmd.Flags().StringArrayVar(&flagWhere, "where", nil, whereUsage)
+	getCmd.Flags().StringVar(&flagGetSep, "sep", "", `print raw values, one array element per item, separated by this string (escapes like "\n" allowed)`)
+	getCmd.Flags().BoolVarP(&flagGetNull, "null", "0", false, "print raw values separated and terminated by NUL, for xargs -0")
+	getCmd.Flags().BoolVar(&flagGetCopy, "copy", false, "also copy the value to the clipboard (OSC 52 over SSH)")
+	getCmd.Flags().BoolVar(&flagSource, "source", false, sourceUsage)
+	addPagingFlags(getCmd)
+	addSortFlags(getCmd)
+	getCmd.Flags().BoolVar(&flagGetRegex, "regex", false, "treat patterns as regular expressions over category.key paths")
 	rootCmd.AddCommand(getCmd)
 }
 
 var getCmd = &cobra.Command{
-	Use:   "get <path>",
+	Use:   "get <path>...",
 	Short: "Get a metadata value",
 	Long: `Get a metadata value by path. Supports glob patterns.
 
+Several paths may be given; their matches are merged into one result.
+Every path must match something, otherwise get exits with code 2. With
+--first, the paths form a fallback chain instead: the first one that
+matches anything is used and the rest are ignored.
+
+With --regex, each pattern is a regular expression matched against the
+full category.key path instead of a glob.
+
 Examples:
   deets get identity.name          # single value
   deets get academic               # all fields in category
   deets get *.orcid                # find key across categories
   deets get identity.na*           # glob within category
+  deets get '{identity,contact}.email'  # brace alternation
+  deets get '**.institution'       # ** matches across categories and tables
+  deets get identity.aka[0]        # first array element ([-1] for last)
+  deets get identity.name contact.email web.github   # several at once
+  deets get --first contact.work_email contact.email # first that exists
+  deets get --regex '_url$'        # any key ending in _url
+  deets get contact --exclude contact.phone     # everything but the phone
+  deets get '**' --where 'value~olddomain\.com'  # fields mentioning a stale domain
+  deets get academic.topics --sep '\n'         # one array element per line
+  deets get academic.topics -0 | xargs -0 -n1   # NUL-separated for xargs
+  deets get '*.email' --limit 1   # just the first match
+  deets get '**' --sort value      # order results by value
+  deets get academic.orcid --copy # also copy to the clipboard
   deets get identity.name --desc   # include description
+  deets get contact.email --source # which file and line set it
+  deets get identity.name contact.email --template '{{.name}} <{{.email}}>'
   deets get foo.bar --default x    # return "x" if not found
   deets get foo.bar --exists       # exit 0/2, no output`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, pattern := range args {
+			if flagGetRegex {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("invalid regex %q: %w", pattern, err)
+				}
+			} else if err := model.ValidatePattern(pattern); err != nil {
+				return err
+			}
+		}
+		filters, err := parseWhere()
+		if err != nil {
+			return err
+		}
+		if err := checkPaging(); err != nil {
+			return err
+		}
+
 		db, err := loadDB()
 		if err != nil {
 			return err
 		}
 
-		pattern := args[0]
-		fields := db.Query(pattern)
+		var fields []model.Field
+		var missing []string
+		if flagGetFirst {
+			args, fields, missing = queryFirst(db, args, filters)
+		} else {
+			fields, missing = queryAll(db, args, filters)
+		}
 
 		// --exists: pure existence check, no output
 		if flagGetExists {
-			if len(fields) == 0 {
+			if len(missing) > 0 {
 				return &ExitError{Code: 2, Message: ""}
 			}
 			return nil
 		}
 
-		if len(fields) == 0 {
-			// --default: return default value on no match
-			if cmd.Flags().Changed("default") {
-				fmt.Println(flagGetDefault)
-				return nil
-			}
-			if strings.Contains(pattern, ".") && !strings.ContainsAny(pattern, "*?[") {
-				return &ExitError{Code: 2, Message: fmt.Sprintf("field not found: %s", pattern)}
+		if len(fields) == 0 && cmd.Flags().Changed("default") {
+			// --default: return default value when nothing matched
+			fmt.Println(flagGetDefault)
+			return nil
+		}
+		if flagGetFirst && len(missing) > 1 {
+			return &ExitError{Code: 2, Message: fmt.Sprintf("no matches for any of: %s", strings.Join(missing, ", "))}
+		}
+		if len(missing) > 0 {
+			return notFoundError(missing[0])
+		}
+		if fields, err = sortResults(fields); err != nil {
+			return err
+		}
+		fields, total := paginate(fields)
+
+		if flagGetCopy {
+			values := make([]string, 0, len(fields))
+			for _, f := range fields {
+				values = append(values, model.FormatValue(f.Value))
 			}
-			return &ExitError{Code: 2, Message: fmt.Sprintf("no matches for: %s", pattern)}
+			// Copy once the values are printed, so a missing clipboard
+			// only costs the copy.
+			defer copyOrWarn(strings.Join(values, "\n"))
+		}
+
+		if flagTemplate != "" {
+			return printTemplate(fields)
+		}
+		if flagGetNull || cmd.Flags().Changed("sep") {
+			printItems(fields)
+			return nil
 		}
 
-		// Use bare value only for exact field paths (no globs, no category-only)
-		isExactField := strings.Contains(pattern, ".") && !strings.ContainsAny(pattern, "*?[")
+		// Use bare value only for a single exact field path (no globs, no
+		// category-only)
+		isExactField := len(args) == 1 && !flagGetRegex && isExactPath(args[0])
 		format := resolveFormat()
-		if len(fields) == 1 && isExactField && format == "table" {
+		bare := format == "table" || format == "bare"
+		if format == "bare" {
+			// "bare" (config only) forces bare values for exact matches
+			// even when piped; everything else follows the TTY heuristic.
+			format = ttyFormat()
+		}
+		if len(fields) == 1 && isExactField && bare {
+			line := model.FormatValue(fields[0].Value)
 			if flagGetDesc {
-				fmt.Printf("%s\t%s\n", model.FormatValue(fields[0].Value), fields[0].Desc)
-			} else {
-				fmt.Println(model.FormatValue(fields[0].Value))
+				line += "\t" + fields[0].Desc
+			}
+			if flagSource {
+				line += "\t" + fields[0].Origin()
 			}
+			fmt.Println(line)
 			return nil
 		}
+		if flagSource {
+			return printSourced(fields, format, flagGetDesc, total)
+		}
 
 		// Multiple results or explicit format
 		switch format {
@@ -88,23 +185,105 @@ Examples:
 			if err != nil {
 				return err
 			}
-			fmt.Println(out)
-		case "toml":
-			db := model.FieldsToDB(fields)
-			fmt.Print(model.FormatTOML(db))
-		case "yaml":
-			db := model.FieldsToDB(fields)
-			fmt.Print(model.FormatYAML(db))
-		case "env":
-			db := model.FieldsToDB(fields)
-			fmt.Print(model.FormatEnv(db))
-		default: // table
+			return printJSONResult(out, total)
+		case "table":
 			if flagGetDesc {
-				fmt.Print(model.FormatTableWithDesc(fields))
+				fmt.Print(model.FormatTableFit(fields, true, false, tableWidth()))
 			} else {
-				fmt.Print(model.FormatTable(fields))
+				fmt.Print(model.FormatTableFit(fields, false, false, tableWidth()))
 			}
+		default:
+			_, err := printDocument(model.FieldsToDB(fields), format)
+			return err
 		}
 		return nil
 	},
 }
+
+// printItems writes the raw values of fields for --sep and -0. Array values
+// contribute one item per element. With -0 every item is terminated by NUL;
+// with --sep items are joined by the separator and end with a newline.
+func printItems(fields []model.Field) {
+	var items []string
+	for _, f := range fields {
+		items = append(items, model.Items(f.Value)...)
+	}
+	if flagGetNull {
+		for _, item := range items {
+			fmt.Print(item + "\x00")
+		}
+		return
+	}
+	fmt.Println(strings.Join(items, sepEscapes.Replace(flagGetSep)))
+}
+
+// sepEscapes expands the backslash escapes allowed in --sep, so
+// `--sep '\n'` works without shell quoting tricks.
+var sepEscapes = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\t`, "\t", `\r`, "\r", `\0`, "\x00")
+
+// runQuery matches pattern against db as a glob, or as a regular
+// expression with --regex, drops fields matched by --exclude, and keeps
+// only fields that pass every --where filter. Patterns are validated before the database is
+// loaded, so compilation cannot fail here.
+func runQuery(db *model.DB, pattern string, filters []model.Filter) []model.Field {
+	var matches []model.Field
+	if flagGetRegex {
+		matches = db.QueryRegex(regexp.MustCompile(pattern))
+	} else {
+		matches = db.Query(pattern)
+	}
+	return model.FilterFields(db.Exclude(matches, flagExclude), filters)
+}
+
+// queryAll runs each pattern against db and merges the results in order,
+// dropping fields already matched by an earlier pattern. It also returns the
+// patterns that matched nothing.
+func queryAll(db *model.DB, patterns []string, filters []model.Filter) (fields []model.Field, missing []string) {
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches := runQuery(db, pattern, filters)
+		if len(matches) == 0 {
+			missing = append(missing, pattern)
+			continue
+		}
+		for _, f := range matches {
+			path := f.Category + "." + f.Key
+			if !seen[path] {
+				seen[path] = true
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields, missing
+}
+
+// queryFirst returns the first pattern with any matches, as a one-element
+// slice, together with its matches. If no pattern matches, every pattern is
+// reported missing.
+func queryFirst(db *model.DB, patterns []string, filters []model.Filter) ([]string, []model.Field, []string) {
+	for _, pattern := range patterns {
+		if matches := runQuery(db, pattern, filters); len(matches) > 0 {
+			return []string{pattern}, matches, nil
+		}
+	}
+	return patterns, nil, patterns
+}
+
+// arrayIndex matches an array index segment such as "[0]" or "[-1]".
+var arrayIndex = regexp.MustCompile(`\[-?\d+\]`)
+
+// isExactPath reports whether pattern names a single field: it has a
+// category and key and no glob characters, though it may contain array
+// indices such as "education.degrees[1].institution".
+func isExactPath(pattern string) bool {
+	base := arrayIndex.ReplaceAllString(pattern, "")
+	return strings.Contains(base, ".") && !strings.ContainsAny(base, "*?[{")
+}
+
+// notFoundError returns the exit-code-2 error for a pattern with no matches.
+func notFoundError(pattern string) error {
+	if !flagGetRegex && isExactPath(pattern) {
+		return &ExitError{Code: 2, Message: fmt.Sprintf("field not found: %s", pattern)}
+	}
+	return &ExitError{Code: 2, Message: fmt.Sprintf("no matches for: %s", pattern)}
+}
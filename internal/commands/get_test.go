@@ -3,6 +3,8 @@ package commands
 import (
 	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -130,6 +132,54 @@ func TestGet_Desc_BareValue(t *testing.T) {
 	}
 }
 
+func TestGet_Source_BareValue(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	stdout, _, err := executeCommand("get", "contact.email", "--source")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "alex@example.com\tglobal:7\n" {
+		t.Errorf("expected value and origin, got %q", stdout)
+	}
+}
+
+func TestGet_Source_LocalOverride(t *testing.T) {
+	home := setupTestDB(t)
+
+	workDir := filepath.Join(home, "project")
+	os.MkdirAll(filepath.Join(workDir, ".deets"), 0755)
+	os.Chdir(workDir)
+	localPath := filepath.Join(workDir, ".deets", "me.toml")
+	os.WriteFile(localPath, []byte("[contact]\nemail = \"work@example.com\"\n"), 0644)
+
+	flagFormat = "json"
+	stdout, _, err := executeCommand("get", "contact.*", "--source")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	src, _ := parsed["email"]["source"].(map[string]interface{})
+	if src["layer"] != "local" || src["line"] != float64(2) {
+		t.Errorf("expected local:2 source, got %v", src)
+	}
+	if !strings.HasSuffix(src["file"].(string), filepath.Join("project", ".deets", "me.toml")) {
+		t.Errorf("expected local file path, got %v", src["file"])
+	}
+}
+
+func TestGet_Source_UnsupportedFormat(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "yaml"
+	_, _, err := executeCommand("get", "identity", "--source")
+	if err == nil || !strings.Contains(err.Error(), "--source") {
+		t.Errorf("expected --source format error, got %v", err)
+	}
+}
+
 func TestGet_FormatTOML(t *testing.T) {
 	setupTestDB(t)
 	flagFormat = "toml"
@@ -167,7 +217,7 @@ func TestGet_FormatEnv(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(stdout, `DEETS_IDENTITY_NAME="Alexander Towell"`) {
+	if !strings.Contains(stdout, `DEETS_IDENTITY_NAME='Alexander Towell'`) {
 		t.Errorf("expected env format, got %q", stdout)
 	}
 }
@@ -183,3 +233,307 @@ func TestGet_GlobPattern(t *testing.T) {
 		t.Errorf("expected orcid value in output, got %q", stdout)
 	}
 }
+
+func TestGet_FormatMarkdown(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "markdown"
+	stdout, _, err := executeCommand("get", "identity")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(stdout, "| Key | Value |\n| --- | --- |\n") {
+		t.Errorf("expected markdown table header, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "| name | Alexander Towell |") {
+		t.Errorf("expected name row, got %q", stdout)
+	}
+}
+
+func TestGet_MultiplePatterns(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "json"
+	stdout, _, err := executeCommand("get", "identity.name", "web.github", "identity.name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if parsed["identity"]["name"] != "Alexander Towell" || parsed["web"]["github"] != "queelius" {
+		t.Errorf("expected merged results, got %v", parsed)
+	}
+}
+
+func TestGet_MultiplePatterns_Table(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	stdout, _, err := executeCommand("get", "identity.name", "web.github")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(stdout) == "Alexander Towell" {
+		t.Error("multiple exact paths should not use the bare-value path")
+	}
+	if !strings.Contains(stdout, "queelius") || !strings.Contains(stdout, "Alexander Towell") {
+		t.Errorf("expected both values in table, got %q", stdout)
+	}
+}
+
+func TestGet_MultiplePatterns_Missing(t *testing.T) {
+	setupTestDB(t)
+	_, _, err := executeCommand("get", "identity.name", "nope.missing")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("expected exit code 2, got %v", err)
+	}
+	if !strings.Contains(exitErr.Message, "nope.missing") {
+		t.Errorf("expected missing path in message, got %q", exitErr.Message)
+	}
+
+	_, _, err = executeCommand("get", "--exists", "identity.name", "nope.missing")
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Errorf("--exists should require every path, got %v", err)
+	}
+}
+
+func TestGet_First(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	stdout, _, err := executeCommand("get", "--first", "contact.work_email", "identity.name", "web.github")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(stdout) != "Alexander Towell" {
+		t.Errorf("expected first matching path as bare value, got %q", stdout)
+	}
+}
+
+func TestGet_First_NoneMatch(t *testing.T) {
+	setupTestDB(t)
+	_, _, err := executeCommand("get", "--first", "a.b", "c.d")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("expected exit code 2, got %v", err)
+	}
+	if exitErr.Message != "no matches for any of: a.b, c.d" {
+		t.Errorf("unexpected message %q", exitErr.Message)
+	}
+
+	stdout, _, err := executeCommand("get", "--first", "a.b", "c.d", "--default", "none")
+	if err != nil || strings.TrimSpace(stdout) != "none" {
+		t.Errorf("expected default, got %q (%v)", stdout, err)
+	}
+
+	if _, _, err := executeCommand("get", "--first", "--exists", "a.b", "identity.name"); err != nil {
+		t.Errorf("--exists --first should succeed when any path matches, got %v", err)
+	}
+}
+
+func TestGet_ArrayIndex(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	stdout, _, err := executeCommand("get", "identity.aka[-1]")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(stdout) == "" || strings.Contains(stdout, ",") {
+		t.Errorf("expected a single bare element, got %q", stdout)
+	}
+
+	_, _, err = executeCommand("get", "identity.aka[99]")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Errorf("expected exit code 2 for out-of-range index, got %v", err)
+	}
+}
+
+func TestGet_Template(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("get", "identity.name", "contact.email", "--template", "{{.name}} <{{.email}}>")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "Alexander Towell <alex@example.com>\n" {
+		t.Errorf("got %q", stdout)
+	}
+
+	stdout, _, err = executeCommand("show", "identity", "--template", "{{join \"|\" .aka}}")
+	if err != nil || stdout != "Alex Towell|Alex T\n" {
+		t.Errorf("show --template = %q (%v)", stdout, err)
+	}
+
+	stdout, _, err = executeCommand("search", "queelius", "--template", "{{.web.github | upper}}")
+	if err != nil || stdout != "QUEELIUS\n" {
+		t.Errorf("search --template = %q (%v)", stdout, err)
+	}
+
+	stdout, _, err = executeCommand("get", "identity.name", "contact.email", "--template", "{{.name}} {{.missing}}")
+	if err != nil || stdout != "Alexander Towell \n" {
+		t.Errorf("get --template with a missing key = %q (%v), want it empty", stdout, err)
+	}
+
+	_, _, err = executeCommand("get", "identity", "--template", "{{.name")
+	if err == nil || strings.Count(err.Error(), "template: ") != 1 {
+		t.Errorf("expected one template: prefix on the parse error, got %v", err)
+	}
+}
+
+func TestGet_Regex(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "json"
+	stdout, _, err := executeCommand("get", "--regex", `^(identity|contact)\.(name|email)$`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "Alexander Towell") || !strings.Contains(stdout, "alex@example.com") {
+		t.Errorf("expected name and email, got %s", stdout)
+	}
+
+	_, _, err = executeCommand("get", "--regex", `^nothing\.here$`)
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 || !strings.Contains(exitErr.Message, "no matches for") {
+		t.Errorf("expected exit code 2, got %v", err)
+	}
+
+	if _, _, err := executeCommand("get", "--regex", "("); err == nil || !strings.Contains(err.Error(), "invalid regex") {
+		t.Errorf("expected invalid regex error, got %v", err)
+	}
+}
+
+func TestGet_BracesAndGlobstar(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "json"
+	stdout, _, err := executeCommand("get", "{identity,web}.{name,github}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "Alexander Towell") || !strings.Contains(stdout, "queelius") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+
+	stdout, _, err = executeCommand("get", "**.email")
+	if err != nil || !strings.Contains(stdout, "alex@example.com") {
+		t.Errorf("**.email = %q (%v)", stdout, err)
+	}
+
+	if _, _, err := executeCommand("get", "identity.["); err == nil || !strings.Contains(err.Error(), "invalid pattern") {
+		t.Errorf("expected invalid pattern error, got %v", err)
+	}
+}
+
+func TestGet_Exclude(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "json"
+	stdout, _, err := executeCommand("get", "identity", "--exclude", "identity.aka")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(stdout, "Alex T") || !strings.Contains(stdout, "Alexander Towell") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+
+	_, _, err = executeCommand("get", "identity.name", "--exclude", "identity")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Errorf("expected exit code 2 when everything is excluded, got %v", err)
+	}
+}
+
+func TestGet_Where(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "json"
+	stdout, _, err := executeCommand("get", "*", "--where", `value~example\.com`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "alex@example.com") || strings.Contains(stdout, "queelius") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+
+	_, _, err = executeCommand("get", "identity", "--where", "value=nobody")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Errorf("expected exit code 2 when the filter removes everything, got %v", err)
+	}
+
+	if _, _, err := executeCommand("get", "identity", "--where", "bogus"); err == nil {
+		t.Error("expected invalid filter error")
+	}
+
+	stdout, _, err = executeCommand("search", "alex", "--where", "key=email")
+	if err != nil || !strings.Contains(stdout, "alex@example.com") || strings.Contains(stdout, "Alexander") {
+		t.Errorf("search --where = %q (%v)", stdout, err)
+	}
+}
+
+func TestGet_SepAndNull(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("get", "academic.topics", "--sep", `\n`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "statistics\nmachine learning\n" {
+		t.Errorf("--sep output = %q", stdout)
+	}
+
+	stdout, _, err = executeCommand("get", "academic.topics", "identity.name", "-0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "statistics\x00machine learning\x00Alexander Towell\x00" {
+		t.Errorf("-0 output = %q", stdout)
+	}
+
+	flagGetNull = false
+	stdout, _, err = executeCommand("get", "identity.aka", "--sep", " | ")
+	if err != nil || stdout != "Alex Towell | Alex T\n" {
+		t.Errorf("--sep ' | ' output = %q (%v)", stdout, err)
+	}
+}
+
+func TestGet_LimitOffset(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	stdout, _, err := executeCommand("get", "identity", "--limit", "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "aka") || strings.Contains(stdout, "name") {
+		t.Errorf("expected only identity.aka, got:\n%s", stdout)
+	}
+
+	flagLimit = 0
+	flagFormat = "json"
+	stdout, _, err = executeCommand("get", "identity", "--offset", "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var paged struct {
+		Total   int                    `json:"total"`
+		Results map[string]interface{} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &paged); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if paged.Total != 2 || len(paged.Results) != 1 || paged.Results["name"] == nil {
+		t.Errorf("expected identity.name of 2 results, got %s", stdout)
+	}
+}
+
+func TestGet_Sort(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	stdout, _, err := executeCommand("get", "web", "--sort", "value")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// "https://example.com" sorts before "queelius".
+	if strings.Index(stdout, "website") > strings.Index(stdout, "github") {
+		t.Errorf("expected website before github, got:\n%s", stdout)
+	}
+
+	if _, _, err := executeCommand("get", "web", "--sort", "size"); err == nil {
+		t.Error("expected error for unknown sort key")
+	}
+}
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -35,32 +37,253 @@ func parsePath(path string) (category, key string, err error) {
 	return parts[0], parts[1], nil
 }
 
+// stdinFile is the --file value that reads the database from stdin.
+const stdinFile = "-"
+
+// errStdinWrite is returned by commands that would write to --file -.
+var errStdinWrite = fmt.Errorf("--file - reads from stdin and cannot be written; pass a file path")
+
 // loadDB loads the merged metadata database (global + optional local).
+// Each of the two is preceded by the fragments in its conf.d/ directory.
+// Between them sit the host file for this machine and then a profile. With
+// --file, only that file is loaded and global/local resolution is
+// bypassed; --file - reads it from stdin. With --no-global, the global
+// files are skipped and only local ones are read. Environment overrides
+// and --set values are laid on top of whatever is loaded.
 func loadDB() (*model.DB, error) {
-	globalPath := config.GlobalFile()
-	if _, err := os.Stat(globalPath); os.IsNotExist(err) {
+	layers, err := loadLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	if flagFile == stdinFile {
+		return store.LoadReader(os.Stdin, "stdin", layers...)
+	}
+	if flagFile != "" {
+		return store.Load(flagFile, "", layers...)
+	}
+
+	files, err := candidateFiles()
+	if err != nil {
+		return nil, err
+	}
+	var sources []store.Source
+	hasGlobal := false
+	for _, f := range files {
+		if f.Layer == "profile" && !f.Exists {
+			_, err := profileFile()
+			return nil, err
+		}
+		if !f.Exists {
+			continue
+		}
+		sources = append(sources, store.Source{Path: f.Path, Layer: f.Layer})
+		hasGlobal = hasGlobal || f.Layer == "global"
+	}
+	switch {
+	case !flagNoGlobal && !hasGlobal:
 		return nil, fmt.Errorf("no deets found; run 'deets init' first")
+	case flagNoGlobal && len(sources) == 0:
+		return nil, fmt.Errorf("no local .deets/me.toml found (--no-global skips ~/.deets)")
 	}
+	return store.LoadSources(sources, layers...)
+}
 
-	localPath := config.FindLocalFile()
-	return store.Load(globalPath, localPath)
+// candidateFile is a file loadDB considers, whether or not it exists.
+type candidateFile struct {
+	Layer  string
+	Path   string // empty when there is no file to look for
+	Exists bool
+	Note   string // "fragment", or why a profile was chosen
 }
 
-// targetFile returns the TOML file path to write to, based on --local flag.
-func targetFile() (string, error) {
-	if flagLocal {
-		if err := config.EnsureLocalDir(); err != nil {
-			return "", err
+// candidateFiles lists the files loadDB considers when --file is not
+// given, in load order. Fragments are listed only when they exist.
+func candidateFiles() ([]candidateFile, error) {
+	var files []candidateFile
+	add := func(layer, path, note string) {
+		files = append(files, candidateFile{Layer: layer, Path: path, Exists: path != "" && fileExists(path), Note: note})
+	}
+
+	if !flagNoGlobal {
+		for _, f := range config.Fragments(config.GlobalDir()) {
+			add("global", f, "fragment")
 		}
-		cwd, err := os.Getwd()
+		add("global", config.GlobalFile(), "")
+		if host := config.HostFile(); host != "" {
+			add("host", host, "")
+		}
+
+		name, rule, err := activeProfile()
 		if err != nil {
+			return nil, err
+		}
+		switch {
+		case rule != "":
+			add("profile", config.ProfileFile(name), "selected by "+rule)
+		case name != "":
+			add("profile", config.ProfileFile(name), "--profile "+name)
+		}
+	}
+
+	for _, f := range config.Fragments(config.FindLocalDir()) {
+		add("local", f, "fragment")
+	}
+	if local := config.FindLocalFile(); local != "" {
+		add("local", local, "")
+	} else if env := os.Getenv(config.EnvLocalFile); env != "" {
+		add("local", env, "")
+	} else if dir := config.FindLocalDir(); dir != "" {
+		add("local", filepath.Join(dir, config.FileName), "")
+	} else {
+		add("local", "", "no .deets/ found")
+	}
+	return files, nil
+}
+
+// profileFile returns the file of the active profile, or "" when no
+// profile is selected. The profile must exist.
+func profileFile() (string, error) {
+	name, rule, err := activeProfile()
+	if name == "" || err != nil {
+		return "", err
+	}
+	path := config.ProfileFile(name)
+	if !fileExists(path) {
+		if rule != "" {
+			return "", fmt.Errorf("profile %q selected by %s not found; create it with 'deets profile create %s'", name, rule, name)
+		}
+		return "", fmt.Errorf("profile %q not found; create it with 'deets profile create %s'", name, name)
+	}
+	return path, nil
+}
+
+// loadLayers returns the in-memory layers merged over the loaded files:
+// DEETS_* environment variables with --from-env or [env] override = true,
+// then the --set values, which win.
+func loadLayers() ([]store.Layer, error) {
+	var layers []store.Layer
+	if flagFromEnv || settings.Env.Override {
+		layers = append(layers, store.EnvLayer(lookupFieldEnv))
+	}
+	if len(flagOverride) == 0 {
+		return layers, nil
+	}
+	var assignments []store.Assignment
+	for _, s := range flagOverride {
+		a, err := store.ParseAssignment(s)
+		if err != nil {
+			return nil, fmt.Errorf("--set %s: %w", s, err)
+		}
+		assignments = append(assignments, a)
+	}
+	overlay, err := store.Overlay(assignments, "set", "--set")
+	if err != nil {
+		return nil, fmt.Errorf("--set: %w", err)
+	}
+	return append(layers, store.Static(overlay)), nil
+}
+
+// lookupFieldEnv looks up a field override in the environment. The
+// variables that move deets files are never taken for fields.
+func lookupFieldEnv(name string) (string, bool) {
+	switch name {
+	case config.EnvHome, config.EnvGlobalFile, config.EnvLocalFile:
+		return "", false
+	}
+	return os.LookupEnv(name)
+}
+
+// loadSchema reads ~/.deets/schema.toml. It returns nil when there is no
+// schema file, and under --no-global, which keeps deets out of ~/.deets.
+func loadSchema() (*model.Schema, error) {
+	if flagNoGlobal {
+		return nil, nil
+	}
+	return store.LoadSchema(config.SchemaFile())
+}
+
+// fileField returns the field at path as written in file, before computed
+// fields are evaluated, and whether file defines it.
+func fileField(file, path string) (model.Field, bool) {
+	if !fileExists(file) {
+		return model.Field{}, false
+	}
+	db, err := store.LoadFile(file)
+	if err != nil {
+		return model.Field{}, false
+	}
+	return db.GetField(path)
+}
+
+// targetFile returns the TOML file path to write to, based on the --file,
+// --local, and --profile flags.
+func targetFile() (string, error) {
+	if flagFile == stdinFile {
+		return "", errStdinWrite
+	}
+	if flagFile != "" {
+		if err := os.MkdirAll(filepath.Dir(flagFile), 0755); err != nil {
 			return "", err
 		}
-		return filepath.Join(cwd, config.DirName, config.FileName), nil
+		return flagFile, nil
+	}
+
+	if flagLocal {
+		return localTarget()
+	}
+	if flagProfile != "" {
+		return profileFile()
+	}
+
+	if flagNoGlobal {
+		return "", fmt.Errorf("--no-global requires --file or --local for writes")
+	}
+	return globalTarget()
+}
+
+// existingTarget returns the file targetFile chooses, for commands that
+// rewrite a whole file and so need it to exist already.
+func existingTarget() (string, error) {
+	path, err := targetFile()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("%s does not exist; run 'deets init' first", path)
 	}
+	return path, nil
+}
+
+// localTarget returns the .deets/me.toml in the current directory, or
+// $DEETS_LOCAL_FILE, creating its directory if needed.
+func localTarget() (string, error) {
+	if err := config.EnsureLocalDir(); err != nil {
+		return "", err
+	}
+	return config.LocalTargetFile()
+}
 
+// globalTarget returns the global ~/.deets/me.toml, creating ~/.deets if
+// needed.
+func globalTarget() (string, error) {
 	if err := config.EnsureGlobalDir(); err != nil {
 		return "", err
 	}
 	return config.GlobalFile(), nil
 }
+
+// confirmInput is where confirmation answers are read from. Tests replace it.
+var confirmInput io.Reader = os.Stdin
+
+// confirm asks a yes/no question on stderr and reports whether the answer
+// was yes. Anything other than "y" or "yes", including EOF, means no.
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(confirmInput).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,491 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/queelius/deets/internal/config"
+)
+
+func TestFileFlag_InitGetSetWithoutHome(t *testing.T) {
+	home := setupTestEnv(t)
+	path := filepath.Join(t.TempDir(), "ci", "me.toml")
+
+	if _, _, err := executeCommand("init", "--file", path, "--no-global", "-q"); err != nil {
+		t.Fatalf("init --file: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected %s to be created: %v", path, err)
+	}
+
+	if _, _, err := executeCommand("set", "--file", path, "--no-global", "identity.name", "CI Bot"); err != nil {
+		t.Fatalf("set --file: %v", err)
+	}
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("get", "--file", path, "--no-global", "identity.name")
+	if err != nil {
+		t.Fatalf("get --file: %v", err)
+	}
+	if strings.TrimSpace(stdout) != "CI Bot" {
+		t.Errorf("expected 'CI Bot', got %q", stdout)
+	}
+
+	if _, err := os.Stat(filepath.Join(home, ".deets")); !os.IsNotExist(err) {
+		t.Error("expected home directory to be left untouched")
+	}
+}
+
+func TestFileFlag_BypassesGlobal(t *testing.T) {
+	setupTestDB(t)
+	path := filepath.Join(t.TempDir(), "other.toml")
+	if err := os.WriteFile(path, []byte("[web]\ngithub = \"other\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("get", "--file", path, "web.github")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(stdout) != "other" {
+		t.Errorf("expected value from --file, got %q", stdout)
+	}
+
+	if _, _, err := executeCommand("get", "--file", path, "identity.name"); err == nil {
+		t.Error("expected global fields to be invisible with --file")
+	}
+}
+
+func TestFileFlag_ConflictsWithLocal(t *testing.T) {
+	setupTestDB(t)
+	if _, _, err := executeCommand("show", "--file", "x.toml", "--local"); err == nil {
+		t.Error("expected error for --file with --local")
+	}
+}
+
+func TestNoGlobal_LocalOnly(t *testing.T) {
+	home := setupTestDB(t)
+	localDir := filepath.Join(home, "project", ".deets")
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(localDir, "me.toml"), []byte("[web]\ngithub = \"local\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(home, "project")); err != nil {
+		t.Fatal(err)
+	}
+
+	flagFormat = "json"
+	stdout, _, err := executeCommand("show", "--no-global")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(stdout, "Alexander Towell") {
+		t.Errorf("expected global fields to be skipped, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "local") {
+		t.Errorf("expected local fields, got %q", stdout)
+	}
+}
+
+func TestNoGlobal_WriteRequiresTarget(t *testing.T) {
+	setupTestDB(t)
+	if _, _, err := executeCommand("set", "--no-global", "identity.name", "x"); err == nil {
+		t.Error("expected error writing with --no-global and no --file/--local")
+	}
+}
+
+func TestEnvOverrides_ReadAndWrite(t *testing.T) {
+	setupTestEnv(t)
+	tmp := t.TempDir()
+	global := filepath.Join(tmp, "global", "me.toml")
+	local := filepath.Join(tmp, "local", "project.toml")
+	t.Setenv("DEETS_GLOBAL_FILE", global)
+	t.Setenv("DEETS_LOCAL_FILE", local)
+
+	if _, _, err := executeCommand("set", "identity.name", "Global Name", "-q"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, _, err := executeCommand("set", "--local", "identity.name", "Local Name", "-q"); err != nil {
+		t.Fatalf("set --local: %v", err)
+	}
+	for _, path := range []string{global, local} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be written: %v", path, err)
+		}
+	}
+
+	out, _, err := executeCommand("get", "identity.name")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !strings.Contains(out, "Local Name") {
+		t.Errorf("get = %q, want the local override", out)
+	}
+
+	out, _, err = executeCommand("which", "--format", "json")
+	if err != nil {
+		t.Fatalf("which: %v", err)
+	}
+	if !strings.Contains(out, global) || !strings.Contains(out, local) {
+		t.Errorf("which does not report the overridden paths:\n%s", out)
+	}
+}
+
+func TestFileFlag_ReadWriteCommands(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(t.TempDir(), "teammate.toml")
+	content := "[identity]\nname = \"Teammate\"\nname_desc = \"Their name\"\n\n[web]\ngithub = \"mate\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"get", "identity.name"},
+		{"show"},
+		{"describe", "identity.name"},
+		{"export", "--format", "toml"},
+	} {
+		out, _, err := executeCommand(append(args, "--file", path)...)
+		if err != nil {
+			t.Fatalf("%v --file: %v", args, err)
+		}
+		if !strings.Contains(out, "Teammate") && !strings.Contains(out, "Their name") {
+			t.Errorf("%v --file did not read the file:\n%s", args, out)
+		}
+		if strings.Contains(out, "Alexander Towell") {
+			t.Errorf("%v --file read the global file:\n%s", args, out)
+		}
+	}
+
+	if _, _, err := executeCommand("set", "--file", path, "web.gitlab", "mate2", "-q"); err != nil {
+		t.Fatalf("set --file: %v", err)
+	}
+	if _, _, err := executeCommand("rm", "--file", path, "web.github", "-q"); err != nil {
+		t.Fatalf("rm --file: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "gitlab") || strings.Contains(string(data), "github") {
+		t.Errorf("writes did not land in --file:\n%s", data)
+	}
+	global, err := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(global), "gitlab") {
+		t.Error("set --file wrote to the global file")
+	}
+
+	out, _, err := executeCommand("which", "--file", path, "--format", "table")
+	if err != nil {
+		t.Fatalf("which --file: %v", err)
+	}
+	if !strings.Contains(out, path) || strings.Contains(out, "Global:") {
+		t.Errorf("which --file = %q, want only the file", out)
+	}
+}
+
+func TestFileFlag_Stdin(t *testing.T) {
+	setupTestDB(t)
+	content := "[web]\ngithub = \"piped\"\n"
+
+	var out string
+	var err error
+	withStdin(t, content, func() {
+		out, _, err = executeCommand("get", "--file", "-", "web.github", "--format", "table")
+	})
+	if err != nil {
+		t.Fatalf("get --file -: %v", err)
+	}
+	if !strings.Contains(out, "piped") || strings.Contains(out, "queelius") {
+		t.Errorf("get --file - = %q, want the value from stdin", out)
+	}
+
+	for _, args := range [][]string{
+		{"set", "web.github", "x"},
+		{"init"},
+		{"edit"},
+		{"doctor"},
+	} {
+		withStdin(t, content, func() {
+			_, _, err = executeCommand(append(args, "--file", "-")...)
+		})
+		if err == nil {
+			t.Errorf("%v --file - succeeded, want an error", args)
+		}
+	}
+}
+
+func TestSetFlag_OverridesInMemory(t *testing.T) {
+	home := setupTestDB(t)
+	global := filepath.Join(home, ".deets", "me.toml")
+	before, err := os.ReadFile(global)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, err := executeCommand("export", "--format", "json",
+		"--set", "identity.name=Test Person", "--set", "contact.pager=555")
+	if err != nil {
+		t.Fatalf("export --set: %v", err)
+	}
+	for _, want := range []string{"Test Person", "pager", "alex@example.com"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("export --set missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Alexander Towell") {
+		t.Errorf("export --set still shows the file value:\n%s", out)
+	}
+
+	after, err := os.ReadFile(global)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Error("--set modified the global file")
+	}
+
+	if _, _, err := executeCommand("get", "identity.name", "--set", "identity.name"); err == nil {
+		t.Error("--set without a value succeeded, want an error")
+	}
+}
+
+func TestFromEnv_OptIn(t *testing.T) {
+	home := setupTestDB(t)
+	t.Setenv("DEETS_IDENTITY_NAME", "CI Bot")
+
+	out, _, err := executeCommand("get", "identity.name", "--format", "table")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if strings.Contains(out, "CI Bot") {
+		t.Errorf("environment used without opting in: %q", out)
+	}
+
+	out, _, err = executeCommand("get", "identity.name", "--from-env", "--format", "table")
+	if err != nil {
+		t.Fatalf("get --from-env: %v", err)
+	}
+	if !strings.Contains(out, "CI Bot") {
+		t.Errorf("get --from-env = %q, want the environment value", out)
+	}
+
+	// --set beats the environment.
+	out, _, err = executeCommand("get", "identity.name", "--from-env", "--set", "identity.name=Flag", "--format", "table")
+	if err != nil {
+		t.Fatalf("get --from-env --set: %v", err)
+	}
+	if !strings.Contains(out, "Flag") {
+		t.Errorf("get --from-env --set = %q, want the --set value", out)
+	}
+
+	writeSettings(t, home, "[env]\noverride = true\n")
+	flagFromEnv = false
+	flagOverride = nil
+	out, _, err = executeCommand("get", "identity.name", "--format", "table")
+	if err != nil {
+		t.Fatalf("get with [env] override: %v", err)
+	}
+	if !strings.Contains(out, "CI Bot") {
+		t.Errorf("get with [env] override = %q, want the environment value", out)
+	}
+}
+
+func TestConfD_Fragments(t *testing.T) {
+	home := setupTestDB(t)
+	globalConf := filepath.Join(home, ".deets", "conf.d")
+	if err := os.MkdirAll(globalConf, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"academic.toml": "[academic]\nfield = \"Statistics\"\nh_index = 3\n",
+		"social.toml":   "[academic]\nh_index = 5\n\n[social]\nmastodon = \"@alex\"\n",
+		// me.toml is loaded after the fragments and wins.
+		"web.toml": "[web]\ngithub = \"fragment\"\n",
+	} {
+		if err := os.WriteFile(filepath.Join(globalConf, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setupLocal(t, home, "[social]\nmastodon = \"@project\"\n")
+	localConf := filepath.Join(home, "project", ".deets", "conf.d")
+	if err := os.MkdirAll(localConf, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(localConf, "team.toml"), []byte("[team]\nname = \"core\"\n\n[social]\nmastodon = \"@team\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, err := executeCommand("show", "--format", "json")
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	for _, want := range []string{`"Statistics"`, `"h_index": 5`, `"github": "queelius"`, `"@project"`, `"core"`, "Alexander Towell"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("show missing %s:\n%s", want, out)
+		}
+	}
+
+	out, _, err = executeCommand("which", "--format", "table")
+	if err != nil {
+		t.Fatalf("which: %v", err)
+	}
+	order := []string{
+		filepath.Join(globalConf, "academic.toml"),
+		filepath.Join(globalConf, "social.toml"),
+		filepath.Join(globalConf, "web.toml"),
+		filepath.Join(home, ".deets", "me.toml"),
+		filepath.Join(localConf, "team.toml"),
+		filepath.Join(home, "project", ".deets", "me.toml"),
+	}
+	last := -1
+	for _, path := range order {
+		i := strings.Index(out, path+" ")
+		if i <= last {
+			t.Errorf("which does not list %s in load order:\n%s", path, out)
+		}
+		last = i
+	}
+}
+
+func TestConfD_FragmentsWithoutMainFile(t *testing.T) {
+	home := setupTestEnv(t)
+	conf := filepath.Join(home, ".deets", "conf.d")
+	if err := os.MkdirAll(conf, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(conf, "identity.toml"), []byte("[identity]\nname = \"Fragment Only\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, err := executeCommand("get", "identity.name", "--format", "table")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !strings.Contains(out, "Fragment Only") {
+		t.Errorf("get = %q, want the fragment value", out)
+	}
+}
+
+func TestHostFile_OverridesGlobal(t *testing.T) {
+	home := setupTestDB(t)
+	host := config.HostFile()
+	if host == "" {
+		t.Skip("host name unknown")
+	}
+	if err := os.WriteFile(host, []byte("[contact]\nemail = \"alex@laptop.example\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	profiles := filepath.Join(home, ".deets", "profiles")
+	if err := os.MkdirAll(profiles, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(profiles, "work.toml"), []byte("[contact]\nemail = \"alex@work.example\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, err := executeCommand("get", "contact.email", "--format", "table")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !strings.Contains(out, "alex@laptop.example") {
+		t.Errorf("get = %q, want the host value", out)
+	}
+
+	// A profile is more specific than the host.
+	out, _, err = executeCommand("get", "contact.email", "--profile", "work", "--format", "table")
+	if err != nil {
+		t.Fatalf("get --profile: %v", err)
+	}
+	if !strings.Contains(out, "alex@work.example") {
+		t.Errorf("get --profile = %q, want the profile value", out)
+	}
+
+	out, _, err = executeCommand("which", "--format", "table")
+	if err != nil {
+		t.Fatalf("which: %v", err)
+	}
+	if !strings.Contains(out, "Host:   "+host+" (exists)") {
+		t.Errorf("which does not report the host file:\n%s", out)
+	}
+}
+
+func TestWhichExplain(t *testing.T) {
+	home := setupTestDB(t)
+	conf := filepath.Join(home, ".deets", "conf.d")
+	if err := os.MkdirAll(conf, 0755); err != nil {
+		t.Fatal(err)
+	}
+	fragment := filepath.Join(conf, "web.toml")
+	// github is overridden by me.toml, so only mastodon is used.
+	if err := os.WriteFile(fragment, []byte("[web]\ngithub = \"fragment\"\nmastodon = \"@alex\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	local := setupLocal(t, home, "[contact]\nemail = \"alex@project.example\"\n")
+	t.Setenv("DEETS_IDENTITY_NAME", "CI Bot")
+
+	out, _, err := executeCommand("which", "--explain", "--from-env", "--set", "web.website=https://set.example", "--format", "json")
+	if err != nil {
+		t.Fatalf("which --explain: %v", err)
+	}
+	var reports []layerReport
+	if err := json.Unmarshal([]byte(out), &reports); err != nil {
+		t.Fatalf("parsing %s: %v", out, err)
+	}
+	got := map[string]layerReport{}
+	for _, r := range reports {
+		got[r.Layer+" "+r.Source] = r
+	}
+	global := filepath.Join(home, ".deets", "me.toml")
+	for _, want := range []layerReport{
+		{Layer: "global", Source: fragment, Exists: true, Fields: 2, Used: 1, Note: "fragment"},
+		// name is overridden by the environment, website by --set, and
+		// email by the local file.
+		{Layer: "global", Source: global, Exists: true, Fields: 8, Used: 5},
+		{Layer: "local", Source: local, Exists: true, Fields: 1, Used: 1},
+		{Layer: "env", Source: "DEETS_<CATEGORY>_<KEY>", Exists: true, Fields: 1, Used: 1, Note: "--from-env"},
+		{Layer: "set", Source: "--set", Exists: true, Fields: 1, Used: 1},
+	} {
+		if r := got[want.Layer+" "+want.Source]; r != want {
+			t.Errorf("report for %s %s = %+v, want %+v", want.Layer, want.Source, r, want)
+		}
+	}
+	if reports[0].Source != fragment || reports[len(reports)-1].Layer != "set" {
+		t.Errorf("reports not in load order: %+v", reports)
+	}
+
+	flagFromEnv = false
+	flagOverride = nil
+	out, _, err = executeCommand("which", "--explain", "--format", "table")
+	if err != nil {
+		t.Fatalf("which --explain table: %v", err)
+	}
+	for _, want := range []string{"Layer", "Used", "────", global, "enable with --from-env"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("which --explain table missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWhichExplain_MissingProfile(t *testing.T) {
+	setupTestDB(t)
+
+	out, _, err := executeCommand("which", "--explain", "--profile", "work", "--format", "table")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || !strings.Contains(exitErr.Message, `profile "work" not found`) {
+		t.Fatalf("which --explain --profile work error = %v, want profile not found", err)
+	}
+	if !strings.Contains(out, "not found    0") || !strings.Contains(out, "--profile work") {
+		t.Errorf("which --explain does not show the missing profile:\n%s", out)
+	}
+}
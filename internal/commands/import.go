@@ -2,17 +2,20 @@ package commands
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/queelius/deets/internal/model"
 	"github.com/queelius/deets/internal/store"
 	"github.com/spf13/cobra"
 )
 
-var flagImportDryRun bool
+var (
+	flagImportDryRun   bool
+	flagImportStrategy string
+)
 
 func init() {
 	importCmd.Flags().BoolVar(&flagImportDryRun, "dry-run", false, "show what would change without writing")
+	importCmd.Flags().StringVar(&flagImportStrategy, "strategy", "", "conflict resolution: ask, mine, or theirs (default: ask on a TTY, theirs otherwise)")
 	rootCmd.AddCommand(importCmd)
 }
 
@@ -25,10 +28,15 @@ Each field in the import file is written to the target file using
 line-level editing to preserve formatting. Use --dry-run to preview
 changes without writing.
 
+When an imported value conflicts with an existing one, deets asks per field
+whether to keep yours, take theirs, or edit a merged value. When stdin is
+not a terminal it takes theirs; use --strategy to choose explicitly.
+
 Examples:
-  deets import backup.toml             # import into global
-  deets import other.toml --local      # import into local
-  deets import other.toml --dry-run    # preview changes`,
+  deets import backup.toml                   # import into global
+  deets import other.toml --local            # import into local
+  deets import other.toml --dry-run          # preview changes
+  deets import other.toml --strategy mine    # never overwrite existing values`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		importPath := args[0]
@@ -42,42 +50,95 @@ Examples:
 			return importDryRun(importDB)
 		}
 
+		resolver, err := newConflictResolver(flagImportStrategy)
+		if err != nil {
+			return err
+		}
+
 		targetPath, err := targetFile()
 		if err != nil {
 			return err
 		}
 
-		count := 0
+		// Conflicts are judged against the file being written, not the
+		// merged view, since that is what the import will change.
+		var existingDB *model.DB
+		if fileExists(targetPath) {
+			existingDB, err = store.LoadFile(targetPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Values are written as TOML literals so numbers, booleans and dates
+		// keep their types, all in one write of the file. Tables are written
+		// key by key, into the sub-table the target may already have.
+		var assignments []store.Assignment
+		kept := 0
 		for _, cat := range importDB.Categories {
-			for _, f := range cat.Fields {
+			for _, f := range model.FlattenFields(cat.Fields) {
 				if model.IsDescKey(f.Key) {
 					continue
 				}
+				path := cat.Name + "." + f.Key
 				val := model.FormatValueTOML(f.Value)
-				if err := store.SetValue(targetPath, cat.Name, f.Key, val); err != nil {
-					return fmt.Errorf("setting %s.%s: %w", cat.Name, f.Key, err)
+
+				if existingDB != nil {
+					if existing, ok := existingDB.GetField(path); ok {
+						mine, theirs := model.FormatValue(existing.Value), model.FormatValue(f.Value)
+						if mine != theirs {
+							var take bool
+							val, take, err = resolver.resolve(path, mine, theirs, f.Value)
+							if err != nil {
+								return err
+							}
+							if !take {
+								kept++
+								continue
+							}
+						}
+					}
 				}
-				count++
+
+				assignments = append(assignments, store.Assignment{Category: cat.Name, Key: f.Key, Literal: val})
+			}
+		}
+		count := len(assignments)
+		if count > 0 {
+			if err := store.SetLiterals(targetPath, assignments); err != nil {
+				return err
 			}
 		}
 
 		if !flagQuiet {
-			fmt.Printf("Imported %d fields into %s\n", count, targetPath)
+			if kept > 0 {
+				fmt.Printf("Imported %d fields into %s (kept %d existing)\n", count, targetPath, kept)
+			} else {
+				fmt.Printf("Imported %d fields into %s\n", count, targetPath)
+			}
 		}
 		return nil
 	},
 }
 
+// importDryRun lists what importing importDB would change, judged against
+// the file the import would write, as the import itself does.
 func importDryRun(importDB *model.DB) error {
-	// Load existing DB to compare; tolerate missing file but not other errors.
-	existingDB, err := loadDB()
-	if err != nil && !strings.Contains(err.Error(), "no deets found") {
+	targetPath, err := targetFile()
+	if err != nil {
 		return err
 	}
+	var existingDB *model.DB
+	if fileExists(targetPath) {
+		existingDB, err = store.LoadFile(targetPath)
+		if err != nil {
+			return err
+		}
+	}
 
 	var entries []model.DiffEntry
 	for _, cat := range importDB.Categories {
-		for _, f := range cat.Fields {
+		for _, f := range model.FlattenFields(cat.Fields) {
 			if model.IsDescKey(f.Key) {
 				continue
 			}
@@ -109,22 +170,5 @@ func importDryRun(importDB *model.DB) error {
 		}
 	}
 
-	if len(entries) == 0 {
-		if !flagQuiet {
-			fmt.Println("No changes to apply.")
-		}
-		return nil
-	}
-
-	switch resolveFormat() {
-	case "json":
-		out, err := model.FormatDiffJSON(entries)
-		if err != nil {
-			return err
-		}
-		fmt.Println(out)
-	default:
-		fmt.Print(model.FormatDiffTable(entries))
-	}
-	return nil
+	return printDiffEntries(entries)
 }
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
 )
 
 func TestImport_IntoExisting(t *testing.T) {
@@ -107,6 +110,29 @@ nickname = "Lex"
 	}
 }
 
+func TestImport_DryRunAgainstTarget(t *testing.T) {
+	home := setupTestDB(t)
+	setupLocal(t, home, "[web]\ngithub = \"other\"\n")
+
+	importFile := filepath.Join(home, "import.toml")
+	if err := os.WriteFile(importFile, []byte("[identity]\nname = \"Alexander Towell\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	flagFormat = "json"
+	stdout, _, err := executeCommand("import", importFile, "--dry-run", "--local")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var entries []model.DiffEntry
+	if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != "identity.name" || entries[0].Status != "add" {
+		t.Errorf("entries = %+v, want identity.name added to the local file", entries)
+	}
+}
+
 func TestImport_MissingFile(t *testing.T) {
 	setupTestDB(t)
 	_, _, err := executeCommand("import", "/nonexistent/file.toml")
@@ -114,3 +140,166 @@ func TestImport_MissingFile(t *testing.T) {
 		t.Error("expected error for missing import file")
 	}
 }
+
+func writeConflictImport(t *testing.T, home string) string {
+	t.Helper()
+	importFile := filepath.Join(home, "conflict.toml")
+	content := `[identity]
+name = "Different Name"
+nickname = "Lex"
+
+[web]
+github = "someone-else"
+`
+	if err := os.WriteFile(importFile, []byte(content), 0644); err != nil {
+		t.Fatalf("writing import file: %v", err)
+	}
+	return importFile
+}
+
+func TestImport_StrategyMine(t *testing.T) {
+	home := setupTestDB(t)
+	importFile := writeConflictImport(t, home)
+
+	stdout, _, err := executeCommand("import", importFile, "--strategy", "mine")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "kept 2 existing") {
+		t.Errorf("expected kept count in summary, got %q", stdout)
+	}
+
+	flagFormat = "table"
+	stdout, _, _ = executeCommand("get", "identity.name")
+	if strings.TrimSpace(stdout) != "Alexander Towell" {
+		t.Errorf("expected existing name to be kept, got %q", stdout)
+	}
+	stdout, _, _ = executeCommand("get", "identity.nickname")
+	if strings.TrimSpace(stdout) != "Lex" {
+		t.Errorf("expected non-conflicting field to be imported, got %q", stdout)
+	}
+}
+
+func TestImport_StrategyAsk(t *testing.T) {
+	home := setupTestDB(t)
+	importFile := writeConflictImport(t, home)
+
+	orig := conflictInput
+	conflictInput = strings.NewReader("t\ne\nmerged\n")
+	t.Cleanup(func() { conflictInput = orig })
+
+	flagQuiet = true
+	if _, _, err := executeCommand("import", importFile, "--strategy", "ask"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flagFormat = "table"
+	stdout, _, _ := executeCommand("get", "identity.name")
+	if strings.TrimSpace(stdout) != "Different Name" {
+		t.Errorf("expected theirs for identity.name, got %q", stdout)
+	}
+	stdout, _, _ = executeCommand("get", "web.github")
+	if strings.TrimSpace(stdout) != "merged" {
+		t.Errorf("expected edited value for web.github, got %q", stdout)
+	}
+}
+
+func TestImport_DefaultStrategyNonTTYTakesTheirs(t *testing.T) {
+	home := setupTestDB(t)
+	importFile := writeConflictImport(t, home)
+
+	flagQuiet = true
+	if _, _, err := executeCommand("import", importFile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flagFormat = "table"
+	stdout, _, _ := executeCommand("get", "identity.name")
+	if strings.TrimSpace(stdout) != "Different Name" {
+		t.Errorf("expected incoming value when not a TTY, got %q", stdout)
+	}
+}
+
+func TestImport_KeepsValueTypes(t *testing.T) {
+	home := setupTestDB(t)
+
+	mePath := filepath.Join(home, ".deets", "me.toml")
+	existing, _ := os.ReadFile(mePath)
+	existing = append(existing, "\n[education.phd]\nfield = \"Statistics\"\n"...)
+	if err := os.WriteFile(mePath, existing, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	importContent := `[academic]
+gpa = 4.0
+citations = 42
+tenured = true
+
+[education.phd]
+school = "MIT"
+year = 2020
+`
+	importFile := filepath.Join(home, "import.toml")
+	if err := os.WriteFile(importFile, []byte(importContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	flagQuiet = true
+	flagImportStrategy = "theirs"
+	defer func() { flagImportStrategy = "" }()
+	if _, _, err := executeCommand("import", importFile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	db, err := store.LoadFile(mePath)
+	if err != nil {
+		t.Fatalf("reloading: %v", err)
+	}
+	want := map[string]interface{}{
+		"academic.gpa":       4.0,
+		"academic.citations": int64(42),
+		"academic.tenured":   true,
+		"education.phd":      map[string]interface{}{"field": "Statistics", "school": "MIT", "year": int64(2020)},
+	}
+	for path, v := range want {
+		f, ok := db.GetField(path)
+		if !ok {
+			t.Errorf("%s missing after import", path)
+			continue
+		}
+		if !reflect.DeepEqual(f.Value, v) {
+			t.Errorf("%s = %#v, want %#v", path, f.Value, v)
+		}
+	}
+}
+
+func TestImport_KeepsDates(t *testing.T) {
+	home := setupTestDB(t)
+
+	importContent := `[identity]
+birthday = 1990-05-01
+updated = 2024-03-01T12:30:00Z
+`
+	importFile := filepath.Join(home, "import.toml")
+	if err := os.WriteFile(importFile, []byte(importContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	flagQuiet = true
+	if _, _, err := executeCommand("import", importFile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	db, err := store.LoadFile(filepath.Join(home, ".deets", "me.toml"))
+	if err != nil {
+		t.Fatalf("reloading: %v", err)
+	}
+	f, _ := db.GetField("identity.birthday")
+	if d, ok := f.Value.(time.Time); !ok || model.TimeKind(d) != "date" || d.Format("2006-01-02") != "1990-05-01" {
+		t.Errorf("identity.birthday = %#v, want the local date 1990-05-01", f.Value)
+	}
+	f, _ = db.GetField("identity.updated")
+	if d, ok := f.Value.(time.Time); !ok || !d.Equal(time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)) {
+		t.Errorf("identity.updated = %#v, want 2024-03-01T12:30:00Z", f.Value)
+	}
+}
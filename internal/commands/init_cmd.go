@@ -1,73 +1,261 @@
 package commands
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
 
 	"github.com/queelius/deets/internal/config"
+	"github.com/queelius/deets/internal/model"
 	"github.com/queelius/deets/internal/store"
 	"github.com/spf13/cobra"
 )
 
+var (
+	flagInitInteractive bool
+	flagInitFrom        string
+	flagInitTemplate    string
+)
+
 func init() {
+	initCmd.Flags().StringVar(&flagInitTemplate, "template", "", "start from a template file or a named template in ~/.deets/templates/")
+	initCmd.Flags().StringVar(&flagInitFrom, "from", "", "pre-populate from a JSON Resume, vCard, or git config file")
+	initCmd.Flags().BoolVarP(&flagInitInteractive, "interactive", "i", false, "prompt for the well-known fields and write them filled in")
 	rootCmd.AddCommand(initCmd)
 }
 
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Create a new deets metadata file",
-	Long:  "Create ~/.deets/me.toml from a template, or .deets/me.toml with --local.",
+	Long: `Create ~/.deets/me.toml from a template, .deets/me.toml with --local,
+or any path with --file.
+
+With --interactive, deets asks for each well-known field (name, email,
+github, orcid, ...) instead and writes only the answers. Press Enter to
+skip a field; list fields take comma-separated values.
+
+With --from, deets reads an existing profile and writes the fields it
+maps onto: a JSON Resume (.json), a vCard (.vcf), or a git config
+(~/.gitconfig: user.name, user.email, github.user). The format is
+guessed from the file name and contents.
+
+With --template, deets starts from another template instead: a path to
+a TOML file, the name of one in ~/.deets/templates/ (--template academic
+reads academic.toml there), or a built-in: "default" or "local". Teams
+and personas can keep their own starting points this way.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if flagLocal {
-			return initLocal()
+		sources := 0
+		for _, set := range []bool{flagInitInteractive, flagInitFrom != "", flagInitTemplate != ""} {
+			if set {
+				sources++
+			}
+		}
+		if sources > 1 {
+			return fmt.Errorf("--interactive, --from, and --template cannot be used together")
+		}
+
+		path, content, err := initTarget()
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+
+		switch {
+		case flagInitInteractive:
+			if content, err = initWizard(bufio.NewReader(confirmInput), os.Stderr); err != nil {
+				return err
+			}
+		case flagInitFrom != "":
+			if content, err = initFrom(flagInitFrom); err != nil {
+				return err
+			}
+		case flagInitTemplate != "":
+			if content, err = initTemplate(flagInitTemplate); err != nil {
+				return err
+			}
 		}
-		return initGlobal()
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("creating directory for %s: %w", path, err)
+		}
+		if err := store.WriteFile(path, []byte(content)); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+
+		if !flagQuiet {
+			fmt.Printf("Created %s\n", path)
+			if content == store.DefaultTemplate && flagFile == "" {
+				fmt.Println("Edit it to add your personal details.")
+			}
+		}
+		return nil
 	},
 }
 
-func initGlobal() error {
+// initTarget returns the file init creates and its default content: the
+// --file path or the global file with the default template, or the local
+// file with the local template. --file never touches the home directory.
+func initTarget() (path, content string, err error) {
+	switch {
+	case flagFile == stdinFile:
+		return "", "", errStdinWrite
+	case flagFile != "":
+		return flagFile, store.DefaultTemplate, nil
+	case flagLocal:
+		local, err := config.LocalTargetFile()
+		if err != nil {
+			return "", "", err
+		}
+		return local, store.LocalTemplate, nil
+	case flagNoGlobal:
+		return "", "", fmt.Errorf("--no-global requires --file or --local")
+	}
 	if err := config.EnsureGlobalDir(); err != nil {
-		return fmt.Errorf("creating global directory: %w", err)
+		return "", "", fmt.Errorf("creating global directory: %w", err)
 	}
+	return config.GlobalFile(), store.DefaultTemplate, nil
+}
 
-	path := config.GlobalFile()
-	if _, err := os.Stat(path); err == nil {
-		return fmt.Errorf("%s already exists", path)
-	}
+// initWizard prompts on out for each well-known field, reading answers
+// from in, and returns a me.toml holding the answered fields. Arrays of
+// tables are left for later editing.
+func initWizard(in *bufio.Reader, out io.Writer) (string, error) {
+	fmt.Fprintln(out, "Fill in your details. Press Enter to skip a field.")
 
-	if err := os.WriteFile(path, []byte(store.DefaultTemplate), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
-	}
+	var b strings.Builder
+	b.WriteString("# deets — Personal metadata\n")
+	b.WriteString("# Created by 'deets init --interactive'. Any [category] with any key = \"value\" is valid.\n")
 
-	if !flagQuiet {
-		fmt.Printf("Created %s\n", path)
-		fmt.Println("Edit it to add your personal details.")
+	section := ""
+	for _, f := range store.DefaultFields() {
+		if f.Kind == "records" {
+			continue
+		}
+		prompt := f.Desc
+		if f.Kind == "list" {
+			prompt += ", comma-separated"
+		}
+		fmt.Fprintf(out, "%s.%s (%s): ", f.Category, f.Key, prompt)
+
+		answer, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		eof := err == io.EOF
+		answer = strings.TrimSpace(answer)
+
+		if answer != "" {
+			if f.Category != section {
+				section = f.Category
+				fmt.Fprintf(&b, "\n[%s]\n", section)
+			}
+			fmt.Fprintf(&b, "%s = %s\n", f.Key, wizardValue(f.Kind, answer))
+		}
+		if eof {
+			fmt.Fprintln(out)
+			break
+		}
 	}
-	return nil
+	return b.String(), nil
+}
+
+// builtinTemplates are the templates --template accepts by name without a
+// file.
+var builtinTemplates = map[string]string{
+	"default": store.DefaultTemplate,
+	"local":   store.LocalTemplate,
 }
 
-func initLocal() error {
-	if err := config.EnsureLocalDir(); err != nil {
-		return fmt.Errorf("creating local directory: %w", err)
+// initTemplate returns the content of the template named by --template:
+// an existing file, else <name>.toml in ~/.deets/templates/, else a
+// built-in. The content must be valid TOML.
+func initTemplate(name string) (string, error) {
+	path := ""
+	if info, err := os.Stat(name); err == nil && !info.IsDir() {
+		path = name
+	} else if p := config.TemplateFile(strings.TrimSuffix(name, ".toml") + ".toml"); p != "" && !strings.ContainsRune(name, os.PathSeparator) {
+		if _, err := os.Stat(p); err == nil {
+			path = p
+		}
+	}
+
+	if path == "" {
+		content, ok := builtinTemplates[name]
+		if !ok {
+			return "", fmt.Errorf("template %q not found (available: %s)", name, strings.Join(templateNames(), ", "))
+		}
+		return content, nil
 	}
 
-	cwd, err := os.Getwd()
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("reading template: %w", err)
+	}
+	var probe map[string]interface{}
+	if err := toml.Unmarshal(data, &probe); err != nil {
+		return "", fmt.Errorf("template %s is not valid TOML: %w", path, err)
 	}
-	path := filepath.Join(cwd, config.DirName, config.FileName)
+	return string(data), nil
+}
 
-	if _, err := os.Stat(path); err == nil {
-		return fmt.Errorf("%s already exists", path)
+// templateNames lists the templates --template accepts by name: the
+// built-ins and the .toml files in ~/.deets/templates/.
+func templateNames() []string {
+	names := []string{"default", "local"}
+	if matches, err := filepath.Glob(filepath.Join(config.TemplatesDir(), "*.toml")); err == nil && config.TemplatesDir() != "" {
+		for _, m := range matches {
+			name := strings.TrimSuffix(filepath.Base(m), ".toml")
+			if _, builtin := builtinTemplates[name]; !builtin {
+				names = append(names, name)
+			}
+		}
 	}
+	sort.Strings(names)
+	return names
+}
 
-	if err := os.WriteFile(path, []byte(store.LocalTemplate), 0644); err != nil {
-		return fmt.Errorf("writing %s: %w", path, err)
+// initFrom reads an existing profile file and returns a me.toml holding
+// the fields it maps onto.
+func initFrom(source string) (string, error) {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", source, err)
+	}
+	kind := model.SourceKind(source, data)
+	if kind == "" {
+		return "", fmt.Errorf("%s: unrecognized format (expected a JSON Resume, vCard, or git config)", source)
 	}
+	db, err := model.ParseSource(kind, data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", source, err)
+	}
+
+	var b strings.Builder
+	b.WriteString("# deets — Personal metadata\n")
+	fmt.Fprintf(&b, "# Imported from %s by 'deets init --from'. Any [category] with any key = \"value\" is valid.\n\n", filepath.Base(source))
+	b.WriteString(model.FormatTOML(db))
+	return b.String(), nil
+}
 
-	if !flagQuiet {
-		fmt.Printf("Created %s\n", path)
+// wizardValue turns an answer into a TOML literal for a field of kind.
+func wizardValue(kind, answer string) string {
+	if kind != "list" {
+		return model.TOMLString(answer)
+	}
+	var items []interface{}
+	for _, item := range strings.Split(answer, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
-	return nil
+	return model.FormatValueTOML(items)
 }
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInit_Template(t *testing.T) {
+	home := setupTestEnv(t)
+	if _, _, err := executeCommand("init", "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if !strings.Contains(string(data), `# name = "Your Name"`) {
+		t.Errorf("expected the default template, got:\n%s", data)
+	}
+	if _, _, err := executeCommand("init", "-q"); err == nil {
+		t.Error("expected error when the file already exists")
+	}
+}
+
+func TestInit_Interactive(t *testing.T) {
+	home := setupTestEnv(t)
+	// Answers follow DefaultFields: identity.aka, name, pronouns, then
+	// contact.email, contact.phone; input ends before the rest.
+	confirmInput = strings.NewReader("Lex, Alex T\nAlex Towell\n\nalex@example.com\n")
+	defer func() { confirmInput = os.Stdin }()
+
+	if _, _, err := executeCommand("init", "--interactive", "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	want := "\n[identity]\naka = [\"Lex\", \"Alex T\"]\nname = \"Alex Towell\"\n\n[contact]\nemail = \"alex@example.com\"\n"
+	if !strings.HasSuffix(string(data), want) {
+		t.Errorf("expected answers written, got:\n%s", data)
+	}
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("get", "identity.name")
+	if err != nil || strings.TrimSpace(stdout) != "Alex Towell" {
+		t.Errorf("expected a readable file, got %q (%v)", stdout, err)
+	}
+}
+
+func TestInit_From(t *testing.T) {
+	home := setupTestEnv(t)
+	resume := filepath.Join(home, "resume.json")
+	os.WriteFile(resume, []byte(`{
+	  "basics": {"name": "Alex Towell", "email": "alex@example.com",
+	    "profiles": [{"network": "GitHub", "username": "queelius"}]},
+	  "education": [{"institution": "SIUE", "studyType": "MS", "endDate": "2019"}]
+	}`), 0644)
+
+	if _, _, err := executeCommand("init", "--from", resume, "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, _, err := executeCommand("get", "web.github")
+	if err != nil || !strings.Contains(out, `"queelius"`) {
+		t.Errorf("expected imported github, got %q (err %v)", out, err)
+	}
+	out, _, err = executeCommand("get", "education.degrees", "--format", "json")
+	if err != nil || !strings.Contains(out, "SIUE") {
+		t.Errorf("expected imported degrees, got %q (err %v)", out, err)
+	}
+}
+
+func TestInit_FromErrors(t *testing.T) {
+	home := setupTestEnv(t)
+	notes := filepath.Join(home, "notes.txt")
+	os.WriteFile(notes, []byte("hello"), 0644)
+
+	if _, _, err := executeCommand("init", "--from", notes); err == nil || !strings.Contains(err.Error(), "unrecognized format") {
+		t.Errorf("expected unrecognized format error, got %v", err)
+	}
+	if _, _, err := executeCommand("init", "--from", notes, "--interactive"); err == nil {
+		t.Error("expected error combining --from and --interactive")
+	}
+	if _, err := os.Stat(filepath.Join(home, ".deets", "me.toml")); err == nil {
+		t.Error("failed init should not create me.toml")
+	}
+}
+
+func TestInit_TemplateFlag(t *testing.T) {
+	home := setupTestEnv(t)
+	dir := filepath.Join(home, ".deets", "templates")
+	os.MkdirAll(dir, 0755)
+	academic := "# Lab starter\n[academic]\ninstitution = \"SIUE\"\n"
+	os.WriteFile(filepath.Join(dir, "academic.toml"), []byte(academic), 0644)
+
+	if _, _, err := executeCommand("init", "--template", "academic", "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if string(data) != academic {
+		t.Errorf("expected the named template, got:\n%s", data)
+	}
+
+	// A path works too, and built-ins are available by name.
+	file := filepath.Join(home, "team.toml")
+	os.WriteFile(file, []byte("[web]\ngithub = \"team\"\n"), 0644)
+	out := filepath.Join(home, "a.toml")
+	if _, _, err := executeCommand("init", "--file", out, "--template", file, "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(out); !strings.Contains(string(data), `github = "team"`) {
+		t.Errorf("expected the template file, got:\n%s", data)
+	}
+	flagFile = ""
+	out = filepath.Join(home, "b.toml")
+	if _, _, err := executeCommand("init", "--file", out, "--template", "local", "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(out); !strings.Contains(string(data), "Local project overrides") {
+		t.Errorf("expected the built-in local template, got:\n%s", data)
+	}
+}
+
+func TestInit_TemplateErrors(t *testing.T) {
+	home := setupTestEnv(t)
+	dir := filepath.Join(home, ".deets", "templates")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "broken.toml"), []byte("[academic\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "developer.toml"), []byte("[web]\n"), 0644)
+
+	_, _, err := executeCommand("init", "--template", "nope")
+	if err == nil || !strings.Contains(err.Error(), "available: broken, default, developer, local") {
+		t.Errorf("expected not-found error listing templates, got %v", err)
+	}
+	if _, _, err := executeCommand("init", "--template", "broken"); err == nil || !strings.Contains(err.Error(), "not valid TOML") {
+		t.Errorf("expected invalid TOML error, got %v", err)
+	}
+	if _, _, err := executeCommand("init", "--template", "developer", "--interactive"); err == nil {
+		t.Error("expected error combining --template and --interactive")
+	}
+	if _, err := os.Stat(filepath.Join(home, ".deets", "me.toml")); err == nil {
+		t.Error("failed init should not create me.toml")
+	}
+}
@@ -3,11 +3,23 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/queelius/deets/internal/model"
 	"github.com/spf13/cobra"
 )
 
+var (
+	flagKeysTree   bool
+	flagKeysDepth  int
+	flagKeysValues bool
+)
+
 func init() {
+	keysCmd.Flags().BoolVar(&flagKeysTree, "tree", false, "render categories and keys as an indented tree with counts")
+	keysCmd.Flags().BoolVar(&flagKeysValues, "values", false, "print \"category.key = value\" lines for grepping")
+	keysCmd.Flags().IntVar(&flagKeysDepth, "depth", 0, "with --tree, show at most this many levels (0 = unlimited)")
 	rootCmd.AddCommand(keysCmd)
 }
 
@@ -16,17 +28,50 @@ var keysCmd = &cobra.Command{
 	Short: "List all field paths",
 	Long: `List every field path in the database, one per line.
 
+With --tree, categories and keys are shown as an indented tree. Each
+category and nested table is followed by the number of entries under it.
+--depth limits how many levels are expanded (1 = categories only).
+
+With --values, each line is "category.key = value". Nested tables are
+flattened to dotted paths. Both --tree and --values print plain text
+regardless of --format.
+
 Examples:
   deets keys                  # one per line
-  deets keys --format json    # JSON array`,
+  deets keys --format json    # JSON array
+  deets keys --tree           # indented tree with counts
+  deets keys --tree --depth 1 # categories and field counts only
+  deets keys --values | grep example.com`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagKeysDepth < 0 {
+			return fmt.Errorf("--depth must not be negative")
+		}
+		if cmd.Flags().Changed("depth") && !flagKeysTree {
+			return fmt.Errorf("--depth requires --tree")
+		}
+		if flagKeysTree && flagKeysValues {
+			return fmt.Errorf("--tree and --values cannot be combined")
+		}
+
 		db, err := loadDB()
 		if err != nil {
 			return err
 		}
 
+		if flagKeysTree {
+			fmt.Print(formatKeyTree(db, flagKeysDepth))
+			return nil
+		}
+
 		fields := db.AllFields()
+		if flagKeysValues {
+			for _, f := range model.FlattenFields(fields) {
+				fmt.Printf("%s.%s = %s\n", f.Category, f.Key, model.FormatValue(f.Value))
+			}
+			return nil
+		}
+
 		paths := make([]string, 0, len(fields))
 		for _, f := range fields {
 			paths = append(paths, f.Category+"."+f.Key)
@@ -47,3 +92,51 @@ Examples:
 		return nil
 	},
 }
+
+// formatKeyTree renders the categories of db and their keys as an indented
+// tree. Categories and nested tables are annotated with their entry count.
+// A positive depth stops expanding below that level.
+func formatKeyTree(db *model.DB, depth int) string {
+	var b strings.Builder
+	for _, cat := range db.Categories {
+		var keys []string
+		values := make(map[string]interface{})
+		for _, f := range cat.Fields {
+			if !model.IsDescKey(f.Key) {
+				keys = append(keys, f.Key)
+				values[f.Key] = f.Value
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s (%d)\n", cat.Name, len(keys))
+		writeKeyTree(&b, keys, values, 1, depth)
+	}
+	return b.String()
+}
+
+// writeKeyTree writes one tree level of keys at the given indentation level,
+// recursing into table values.
+func writeKeyTree(b *strings.Builder, keys []string, values map[string]interface{}, level, depth int) {
+	if depth > 0 && level >= depth {
+		return
+	}
+	indent := strings.Repeat("  ", level)
+	for _, k := range keys {
+		table, ok := values[k].(map[string]interface{})
+		if !ok {
+			fmt.Fprintf(b, "%s%s\n", indent, k)
+			continue
+		}
+		var sub []string
+		for sk := range table {
+			if !model.IsDescKey(sk) {
+				sub = append(sub, sk)
+			}
+		}
+		sort.Strings(sub)
+		fmt.Fprintf(b, "%s%s (%d)\n", indent, k, len(sub))
+		writeKeyTree(b, sub, table, level+1, depth)
+	}
+}
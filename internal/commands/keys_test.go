@@ -3,6 +3,7 @@ package commands
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -97,3 +98,75 @@ func mkdirAll(path string) error {
 func writeFile(path, content string) error {
 	return os.WriteFile(path, []byte(content), 0644)
 }
+
+func TestKeys_Tree(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	data, _ := os.ReadFile(path)
+	data = append(data, "\n[education.phd]\ninstitution = \"SIUE\"\ninstitution_desc = \"School\"\nyear = 2020\n"...)
+	os.WriteFile(path, data, 0644)
+
+	flagFormat = "json" // --tree is text regardless of format
+	stdout, _, err := executeCommand("keys", "--tree")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"identity (2)\n  aka\n  name\n",
+		"education (1)\n  phd (2)\n    institution\n    year\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in tree, got:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "_desc") {
+		t.Errorf("_desc keys should be excluded:\n%s", stdout)
+	}
+}
+
+func TestKeys_TreeDepth(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("keys", "--tree", "--depth", "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "academic (3)\ncontact (1)\nidentity (2)\nweb (2)\n"
+	if stdout != want {
+		t.Errorf("expected %q, got %q", want, stdout)
+	}
+}
+
+func TestKeys_DepthRequiresTree(t *testing.T) {
+	setupTestDB(t)
+	if _, _, err := executeCommand("keys", "--depth", "2"); err == nil {
+		t.Error("expected error for --depth without --tree")
+	}
+}
+
+func TestKeys_Values(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "json" // --values is text regardless of format
+	stdout, _, err := executeCommand("keys", "--values")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"contact.email = alex@example.com\n",
+		"identity.aka = Alex Towell, Alex T\n",
+		"web.github = queelius\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "_desc") {
+		t.Errorf("_desc keys should be excluded:\n%s", stdout)
+	}
+}
+
+func TestKeys_ValuesWithTree(t *testing.T) {
+	setupTestDB(t)
+	if _, _, err := executeCommand("keys", "--values", "--tree"); err == nil {
+		t.Error("expected error combining --values and --tree")
+	}
+}
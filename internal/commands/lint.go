@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var flagLintDisable []string
+
+func init() {
+	lintCmd.Flags().StringSliceVar(&flagLintDisable, "disable", nil, "rules to skip (repeatable or comma-separated)")
+	rootCmd.AddCommand(lintCmd)
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check me.toml for style problems",
+	Long: `Check ~/.deets/me.toml (or the --local / --file target) against style
+rules and exit 1 if any are broken, for use in CI on a dotfiles repo.
+
+Rules:
+` + lintRuleHelp() + `
+Skip rules with --disable, or for good with disable = [...] under [lint]
+in ~/.deets/config.toml.
+
+Examples:
+  deets lint
+  deets lint --local
+  deets lint --disable long-line,url-scheme
+  deets lint --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		disabled := map[string]bool{}
+		for _, name := range append(append([]string{}, settings.Lint.Disable...), flagLintDisable...) {
+			if !model.IsLintRule(name) {
+				return fmt.Errorf("unknown lint rule %q", name)
+			}
+			disabled[name] = true
+		}
+
+		path, err := existingTarget()
+		if err != nil {
+			return err
+		}
+		db, err := store.LoadFile(path)
+		if err != nil {
+			return err
+		}
+
+		issues := model.Lint(db, disabled)
+		switch resolveFormat() {
+		case "json":
+			out, err := model.FormatLintJSON(issues)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+		default: // table
+			if len(issues) > 0 {
+				fmt.Print(model.FormatLintTable(issues, tableWidth()))
+			} else if !flagQuiet {
+				fmt.Println("No lint issues.")
+			}
+		}
+
+		if len(issues) > 0 {
+			return &ExitError{Code: 1}
+		}
+		return nil
+	},
+}
+
+// lintRuleHelp lists the lint rules for the command's help text.
+func lintRuleHelp() string {
+	var b strings.Builder
+	for _, rule := range model.LintRules {
+		fmt.Fprintf(&b, "  %-16s %s\n", rule.Name, rule.Description)
+	}
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint_Clean(t *testing.T) {
+	setupTestDB(t)
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("lint")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "No lint issues") {
+		t.Errorf("expected no issues, got %q", stdout)
+	}
+}
+
+func TestLint_Issues(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	data, _ := os.ReadFile(path)
+	os.WriteFile(path, append(data, "personalSite = \"www.example.org\"\n"...), 0644)
+
+	flagFormat = "json"
+	stdout, _, err := executeCommand("lint")
+	if exitErr, ok := err.(*ExitError); !ok || exitErr.Code != 1 {
+		t.Fatalf("expected exit 1, got %v", err)
+	}
+	var issues []struct{ Rule, Path string }
+	if err := json.Unmarshal([]byte(stdout), &issues); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if len(issues) != 2 || issues[0].Rule != "key-case" || issues[1].Rule != "url-scheme" {
+		t.Errorf("unexpected issues: %+v", issues)
+	}
+
+	flagLintDisable = []string{"key-case", "url-scheme"}
+	if _, _, err := executeCommand("lint"); err != nil {
+		t.Errorf("expected disabled rules to pass, got %v", err)
+	}
+}
+
+func TestLint_DisabledInSettings(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	data, _ := os.ReadFile(path)
+	os.WriteFile(path, append(data, "personalSite = \"https://example.org\"\n"...), 0644)
+	writeSettings(t, home, "[lint]\ndisable = [\"key-case\"]\n")
+
+	if _, _, err := executeCommand("lint"); err != nil {
+		t.Errorf("expected key-case disabled by settings, got %v", err)
+	}
+}
+
+func TestLint_UnknownRule(t *testing.T) {
+	setupTestDB(t)
+
+	flagLintDisable = []string{"nope"}
+	_, _, err := executeCommand("lint")
+	if err == nil || !strings.Contains(err.Error(), "unknown lint rule") {
+		t.Errorf("expected unknown rule error, got %v", err)
+	}
+}
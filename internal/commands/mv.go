@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(mvCmd)
+}
+
+var mvCmd = &cobra.Command{
+	Use:   "mv <src> <dst>",
+	Short: "Rename a field or category",
+	Long: `Rename or move a field, or rename a category.
+
+The field's _desc companion and the comment lines directly above it move
+with it, and the rest of the file is left untouched. Moving a field to
+another category appends it there, creating the category if needed.
+Renaming a category also renames its sub-tables.
+
+Examples:
+  deets mv identity.nickname identity.aka    # rename a key
+  deets mv contact.work_email work.email     # move to another category
+  deets mv education.phd education.doctorate # rename a [education.phd] table
+  deets mv cooking recipes                   # rename a category
+  deets mv --local web.blog web.site         # in the local .deets/me.toml`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src, dst := args[0], args[1]
+		filePath, err := targetFile()
+		if err != nil {
+			return err
+		}
+
+		srcField, dstField := strings.Contains(src, "."), strings.Contains(dst, ".")
+		if srcField != dstField {
+			return fmt.Errorf("cannot move %q to %q: both must be fields (category.key) or both categories", src, dst)
+		}
+		if !srcField {
+			return store.RenameCategory(filePath, src, dst)
+		}
+
+		fromCat, fromKey, err := parsePath(src)
+		if err != nil {
+			return err
+		}
+		toCat, toKey, err := parsePath(dst)
+		if err != nil {
+			return err
+		}
+		return store.MoveValue(filePath, fromCat, fromKey, toCat, toKey)
+	},
+}
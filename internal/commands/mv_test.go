@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMv_Field(t *testing.T) {
+	setupTestDB(t)
+	if _, _, err := executeCommand("mv", "contact.email", "billing.email"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flagFormat = "json"
+	stdout, _, err := executeCommand("get", "billing.email", "--desc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "alex@example.com") || !strings.Contains(stdout, "Primary email") {
+		t.Errorf("expected value and description moved, got %q", stdout)
+	}
+	if _, _, err := executeCommand("get", "contact.email"); err == nil {
+		t.Error("expected contact.email to be gone")
+	}
+}
+
+func TestMv_Category(t *testing.T) {
+	home := setupTestDB(t)
+	if _, _, err := executeCommand("mv", "web", "online"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if !strings.Contains(string(data), "[online]\ngithub") {
+		t.Errorf("expected [web] renamed to [online], got:\n%s", data)
+	}
+}
+
+func TestMv_MixedShapes(t *testing.T) {
+	setupTestDB(t)
+	if _, _, err := executeCommand("mv", "web", "online.web"); err == nil {
+		t.Error("expected error moving a category onto a field path")
+	}
+}
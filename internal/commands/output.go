@@ -0,0 +1,223 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/queelius/deets/internal/model"
+	"github.com/spf13/cobra"
+)
+
+// printDocument writes db in any of the formats in formatNames that render a
+// whole document. It reports false for table and json, whose shape differs
+// per command and is handled by the caller.
+func printDocument(db *model.DB, format string) (bool, error) {
+	switch format {
+	case "toml":
+		fmt.Print(model.FormatTOML(db))
+	case "yaml":
+		fmt.Print(model.FormatYAML(db))
+	case "ini":
+		fmt.Print(model.FormatINI(db))
+	case "hcl":
+		fmt.Print(model.FormatHCL(db))
+	case "nix":
+		fmt.Print(model.FormatNix(db))
+	case "plist":
+		fmt.Print(model.FormatPlist(db))
+	case "vcard":
+		fmt.Print(model.FormatVCard(db))
+	case "jsonresume":
+		out, err := model.FormatJSONResume(db, settings.JSONResume)
+		if err != nil {
+			return true, err
+		}
+		fmt.Print(out)
+	case "jsonld":
+		out, err := model.FormatJSONLD(db)
+		if err != nil {
+			return true, err
+		}
+		fmt.Print(out)
+	case "turtle":
+		fmt.Print(model.FormatTurtle(db))
+	case "hcard":
+		fmt.Print(model.FormatHCard(db))
+	case "env":
+		out, err := model.FormatEnv(db, "posix")
+		if err != nil {
+			return true, err
+		}
+		fmt.Print(out)
+	case "dotenv":
+		fmt.Print(model.FormatDotenv(db, ""))
+	case "csv":
+		out, err := model.FormatCSV(db.AllFields())
+		if err != nil {
+			return true, err
+		}
+		fmt.Print(out)
+	case "ndjson":
+		out, err := model.FormatNDJSON(db.AllFields())
+		if err != nil {
+			return true, err
+		}
+		fmt.Print(out)
+	case "markdown":
+		fmt.Print(model.FormatMarkdown(db.AllFields()))
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// flagTemplate is the --template flag shared by get, show, and search.
+var flagTemplate string
+
+// flagExclude is the repeatable --exclude flag shared by get, show, and
+// export.
+var flagExclude []string
+
+// flagWhere is the repeatable --where flag shared by get and search.
+var flagWhere []string
+
+// whereUsage is the help text for --where.
+const whereUsage = "keep fields where attr=value, attr!=value, attr~regex, or attr!~regex (attr: key, value, desc, category, path; repeatable)"
+
+// parseWhere parses every --where expression.
+func parseWhere() ([]model.Filter, error) {
+	filters := make([]model.Filter, 0, len(flagWhere))
+	for _, expr := range flagWhere {
+		f, err := model.ParseFilter(expr)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// flagSource is the --source flag shared by get and show.
+var flagSource bool
+
+// sourceUsage is the help text for --source.
+const sourceUsage = "annotate each field with the layer, file, and line it came from"
+
+// printSourced writes fields with their provenance for --source. Only table
+// and json output can carry it. total is the result count before paging.
+func printSourced(fields []model.Field, format string, includeDesc bool, total int) error {
+	switch format {
+	case "json":
+		out, err := model.FormatFieldsJSONWithSource(fields, includeDesc)
+		if err != nil {
+			return err
+		}
+		return printJSONResult(out, total)
+	case "table":
+		fmt.Print(model.FormatTableFit(fields, includeDesc, true, tableWidth()))
+	default:
+		return fmt.Errorf("--source is only supported with table and json output, not %s", format)
+	}
+	return nil
+}
+
+// flagLimit and flagOffset are the --limit and --offset paging flags shared
+// by get and search. Zero means no limit and no offset.
+var (
+	flagLimit  int
+	flagOffset int
+)
+
+// addPagingFlags registers --limit and --offset on cmd.
+func addPagingFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&flagLimit, "limit", 0, "show at most N results (0 = all)")
+	cmd.Flags().IntVar(&flagOffset, "offset", 0, "skip the first N results")
+}
+
+// checkPaging rejects negative --limit and --offset values.
+func checkPaging() error {
+	if flagLimit < 0 || flagOffset < 0 {
+		return fmt.Errorf("--limit and --offset must not be negative")
+	}
+	return nil
+}
+
+// paging reports whether --limit or --offset is in effect.
+func paging() bool {
+	return flagLimit > 0 || flagOffset > 0
+}
+
+// pageBounds returns the slice bounds of the current page in a result list
+// of length n.
+func pageBounds(n int) (int, int) {
+	start := flagOffset
+	if start > n {
+		start = n
+	}
+	end := n
+	if flagLimit > 0 && start+flagLimit < end {
+		end = start + flagLimit
+	}
+	return start, end
+}
+
+// paginate applies --offset and --limit to fields. It also returns the
+// number of fields before paging.
+func paginate(fields []model.Field) ([]model.Field, int) {
+	start, end := pageBounds(len(fields))
+	return fields[start:end], len(fields)
+}
+
+// printJSONResult prints a JSON document. While paging it is wrapped as
+// {"total": n, "results": ...} so scripts can tell how many results exist.
+func printJSONResult(out string, total int) error {
+	if !paging() {
+		fmt.Println(out)
+		return nil
+	}
+	data, err := json.MarshalIndent(struct {
+		Total   int             `json:"total"`
+		Results json.RawMessage `json:"results"`
+	}{total, json.RawMessage(out)}, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// flagSort and flagReverse are the --sort and --reverse flags shared by
+// get, show, and search.
+var (
+	flagSort    string
+	flagReverse bool
+)
+
+// addSortFlags registers --sort and --reverse on cmd.
+func addSortFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flagSort, "sort", "", "order results by "+strings.Join(model.SortKeys, ", ")+" (table and json output)")
+	cmd.Flags().BoolVar(&flagReverse, "reverse", false, "reverse the result order")
+}
+
+// sortResults orders fields according to --sort and --reverse.
+func sortResults(fields []model.Field) ([]model.Field, error) {
+	return model.SortFields(fields, flagSort, flagReverse)
+}
+
+// excludeUsage is the help text for --exclude.
+const excludeUsage = "drop fields matching this pattern from the results (repeatable)"
+
+// printTemplate renders fields through the --template text, adding a
+// trailing newline if the template does not end with one.
+func printTemplate(fields []model.Field) error {
+	out, err := model.RenderFields(fields, flagTemplate)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	fmt.Print(out)
+	return nil
+}
@@ -0,0 +1,211 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/queelius/deets/internal/config"
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileShowCmd)
+	rootCmd.AddCommand(profileCmd)
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named profiles layered between global and local",
+	Long: `A profile is a TOML file under ~/.deets/profiles/ holding the fields
+that differ for one identity, such as work or personal. With --profile
+<name>, its fields override the global file and are overridden in turn by
+a local .deets/me.toml, and writes go to the profile unless --local or
+--file is given.
+
+Rules in ~/.deets/contexts.toml pick a profile automatically by working
+directory or git remote; the first matching rule wins and --profile
+overrides them. A profile picked by a rule is read but not written to.
+
+  [[rule]]
+  dir = "~/work"
+  profile = "work"
+
+  [[rule]]
+  remote = "github.com/acme/*"
+  profile = "work"
+
+Examples:
+  deets profile create work
+  deets --profile work set contact.email alex@work.example
+  deets --profile work get contact.email
+  deets profile list`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := profileNames()
+		if err != nil {
+			return err
+		}
+
+		switch resolveFormat() {
+		case "json":
+			type entry struct {
+				Name string `json:"name"`
+				File string `json:"file"`
+			}
+			entries := make([]entry, 0, len(names))
+			for _, name := range names {
+				entries = append(entries, entry{name, config.ProfileFile(name)})
+			}
+			data, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		default: // table
+			for _, name := range names {
+				fmt.Println(name)
+			}
+		}
+		return nil
+	},
+}
+
+// activeProfile returns the profile in effect: --profile, or else the
+// profile of the first rule in ~/.deets/contexts.toml that matches the
+// working directory, with rule describing that rule. Rules are not
+// consulted with --file or --no-global.
+func activeProfile() (name, rule string, err error) {
+	if flagProfile != "" {
+		return flagProfile, "", nil
+	}
+	if flagFile != "" || flagNoGlobal {
+		return "", "", nil
+	}
+	file := config.ContextsFile()
+	rules, err := config.LoadContexts(file)
+	if err != nil || len(rules) == 0 {
+		return "", "", err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+	i := config.MatchContext(rules, cwd, gitRemotes)
+	if i < 0 {
+		return "", "", nil
+	}
+	return rules[i].Profile, fmt.Sprintf("%s rule %d (%s)", filepath.Base(file), i+1, rules[i]), nil
+}
+
+// gitRemotes lists the remote URLs of the git repository holding the
+// working directory. It returns none outside a repository or without git.
+func gitRemotes() []string {
+	out, err := exec.Command("git", "config", "--local", "--get-regexp", `^remote\..*\.url$`).Output()
+	if err != nil {
+		return nil
+	}
+	var urls []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if _, url, ok := strings.Cut(line, " "); ok {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// profileNames lists the profiles in ~/.deets/profiles/, sorted by name. A
+// missing directory has none.
+func profileNames() ([]string, error) {
+	entries, err := os.ReadDir(config.ProfilesDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".toml"); ok && !e.IsDir() && config.CheckProfileName(name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an empty profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := config.CheckProfileName(name); err != nil {
+			return err
+		}
+		path := config.ProfileFile(name)
+		if fileExists(path) {
+			return fmt.Errorf("profile %q already exists", name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("creating directory for %s: %w", path, err)
+		}
+		content := fmt.Sprintf("# deets profile %q: fields here override ~/.deets/me.toml\n# when deets runs with --profile %s.\n", name, name)
+		if err := store.WriteFile(path, []byte(content)); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+		if !flagQuiet {
+			fmt.Printf("Created %s\n", path)
+		}
+		return nil
+	},
+}
+
+var profileShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show the fields a profile overrides",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := config.CheckProfileName(name); err != nil {
+			return err
+		}
+		path := config.ProfileFile(name)
+		if !fileExists(path) {
+			return &ExitError{Code: 2, Message: fmt.Sprintf("profile %q not found", name)}
+		}
+		db, err := store.Load(path, "")
+		if err != nil {
+			return err
+		}
+
+		format := resolveFormat()
+		switch format {
+		case "json":
+			out, err := model.FormatJSON(db)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+		case "table":
+			fmt.Print(model.FormatTableFit(db.AllFields(), false, false, tableWidth()))
+		default:
+			_, err := printDocument(db, format)
+			return err
+		}
+		return nil
+	},
+}
@@ -0,0 +1,239 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfile_CreateSetGet(t *testing.T) {
+	home := setupTestDB(t)
+
+	if _, _, err := executeCommand("profile", "create", "work", "-q"); err != nil {
+		t.Fatalf("profile create: %v", err)
+	}
+	profile := filepath.Join(home, ".deets", "profiles", "work.toml")
+	if _, err := os.Stat(profile); err != nil {
+		t.Fatalf("expected %s: %v", profile, err)
+	}
+	if _, _, err := executeCommand("profile", "create", "work"); err == nil {
+		t.Error("creating an existing profile succeeded, want an error")
+	}
+
+	if _, _, err := executeCommand("--profile", "work", "set", "contact.email", "alex@work.example", "-q"); err != nil {
+		t.Fatalf("set --profile: %v", err)
+	}
+	data, err := os.ReadFile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "alex@work.example") {
+		t.Errorf("set --profile did not write the profile:\n%s", data)
+	}
+
+	out, _, err := executeCommand("--profile", "work", "get", "contact.email", "--format", "table")
+	if err != nil {
+		t.Fatalf("get --profile: %v", err)
+	}
+	if !strings.Contains(out, "alex@work.example") {
+		t.Errorf("get --profile = %q, want the profile value", out)
+	}
+
+	flagProfile = ""
+	out, _, err = executeCommand("get", "contact.email", "--format", "table")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !strings.Contains(out, "alex@example.com") {
+		t.Errorf("get without --profile = %q, want the global value", out)
+	}
+}
+
+func TestProfile_LocalBeatsProfile(t *testing.T) {
+	home := setupTestDB(t)
+	profiles := filepath.Join(home, ".deets", "profiles")
+	if err := os.MkdirAll(profiles, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "[contact]\nemail = \"work@example.com\"\n\n[web]\ngithub = \"work-gh\"\n"
+	if err := os.WriteFile(filepath.Join(profiles, "work.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setupLocal(t, home, "[contact]\nemail = \"project@example.com\"\n")
+
+	out, _, err := executeCommand("--profile", "work", "show", "--format", "json")
+	if err != nil {
+		t.Fatalf("show --profile: %v", err)
+	}
+	for _, want := range []string{"project@example.com", "work-gh", "Alexander Towell"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("show --profile missing %q:\n%s", want, out)
+		}
+	}
+
+	out, _, err = executeCommand("--profile", "work", "which", "--format", "table")
+	if err != nil {
+		t.Fatalf("which --profile: %v", err)
+	}
+	if !strings.Contains(out, "Profile: "+filepath.Join(profiles, "work.toml")) {
+		t.Errorf("which --profile does not report the profile:\n%s", out)
+	}
+}
+
+func TestProfile_ListAndShow(t *testing.T) {
+	home := setupTestDB(t)
+
+	out, _, err := executeCommand("profile", "list", "--format", "table")
+	if err != nil {
+		t.Fatalf("profile list: %v", err)
+	}
+	if out != "" {
+		t.Errorf("profile list with no profiles = %q, want nothing", out)
+	}
+
+	profiles := filepath.Join(home, ".deets", "profiles")
+	if err := os.MkdirAll(profiles, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"work.toml":     "[contact]\nemail = \"work@example.com\"\n",
+		"personal.toml": "",
+		"notes.txt":     "",
+	} {
+		if err := os.WriteFile(filepath.Join(profiles, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, _, err = executeCommand("profile", "list", "--format", "table")
+	if err != nil {
+		t.Fatalf("profile list: %v", err)
+	}
+	if out != "personal\nwork\n" {
+		t.Errorf("profile list = %q, want personal and work", out)
+	}
+
+	out, _, err = executeCommand("profile", "show", "work", "--format", "table")
+	if err != nil {
+		t.Fatalf("profile show: %v", err)
+	}
+	if !strings.Contains(out, "work@example.com") || strings.Contains(out, "Alexander") {
+		t.Errorf("profile show = %q, want only the profile's fields", out)
+	}
+
+	_, _, err = executeCommand("profile", "show", "missing")
+	if exitErr, ok := err.(*ExitError); !ok || exitErr.Code != 2 {
+		t.Errorf("profile show missing: err = %v, want exit code 2", err)
+	}
+}
+
+func TestProfile_Errors(t *testing.T) {
+	setupTestDB(t)
+
+	if _, _, err := executeCommand("--profile", "nope", "get", "identity.name"); err == nil || !strings.Contains(err.Error(), "profile create nope") {
+		t.Errorf("missing profile: err = %v, want a hint to create it", err)
+	}
+	flagProfile = ""
+	if _, _, err := executeCommand("--profile", "../evil", "get", "identity.name"); err == nil {
+		t.Error("--profile with a path succeeded, want an error")
+	}
+	flagProfile = ""
+	if _, _, err := executeCommand("--profile", "work", "--no-global", "get", "identity.name"); err == nil {
+		t.Error("--profile with --no-global succeeded, want an error")
+	}
+}
+
+// writeProfileRules writes a work profile and contexts.toml under home.
+func writeProfileRules(t *testing.T, home, rules string) {
+	t.Helper()
+	profiles := filepath.Join(home, ".deets", "profiles")
+	if err := os.MkdirAll(profiles, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(profiles, "work.toml"), []byte("[contact]\nemail = \"work@example.com\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".deets", "contexts.toml"), []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProfile_ContextDirRule(t *testing.T) {
+	home := setupTestDB(t)
+	writeProfileRules(t, home, "[[rule]]\ndir = \"~/work\"\nprofile = \"work\"\n")
+
+	out, _, err := executeCommand("get", "contact.email", "--format", "table")
+	if err != nil {
+		t.Fatalf("get outside ~/work: %v", err)
+	}
+	if !strings.Contains(out, "alex@example.com") {
+		t.Errorf("get outside ~/work = %q, want the global value", out)
+	}
+
+	dir := filepath.Join(home, "work", "app")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, err = executeCommand("get", "contact.email", "--format", "table")
+	if err != nil {
+		t.Fatalf("get in ~/work: %v", err)
+	}
+	if !strings.Contains(out, "work@example.com") {
+		t.Errorf("get in ~/work = %q, want the profile value", out)
+	}
+
+	out, _, err = executeCommand("which", "--format", "table")
+	if err != nil {
+		t.Fatalf("which: %v", err)
+	}
+	if !strings.Contains(out, "work.toml") || !strings.Contains(out, `contexts.toml rule 1 (dir = "~/work")`) {
+		t.Errorf("which does not report the rule:\n%s", out)
+	}
+
+	// Writes go to the global file unless --profile is explicit.
+	if _, _, err := executeCommand("set", "web.blog", "https://blog.example.com", "-q"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "blog.example.com") {
+		t.Error("set under a context rule did not write the global file")
+	}
+}
+
+func TestProfile_ContextRemoteRule(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	home := setupTestDB(t)
+	writeProfileRules(t, home, "[[rule]]\nremote = \"github.com/acme/*\"\nprofile = \"work\"\n")
+
+	repo := filepath.Join(t.TempDir(), "app")
+	for _, args := range [][]string{
+		{"init", "-q", repo},
+		{"-C", repo, "remote", "add", "origin", "git@github.com:acme/app.git"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, err := executeCommand("get", "contact.email", "--format", "table")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !strings.Contains(out, "work@example.com") {
+		t.Errorf("get in an acme repository = %q, want the profile value", out)
+	}
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addDryRunFlag(pruneDescCmd)
+	rootCmd.AddCommand(pruneDescCmd)
+}
+
+var pruneDescCmd = &cobra.Command{
+	Use:   "prune-desc",
+	Short: "Remove _desc keys whose field no longer exists",
+	Long: `Find the _desc keys in ~/.deets/me.toml (or the --local / --file target)
+that describe a key the same table no longer has, and remove them. Such
+orphans are left behind when a field is removed or renamed by hand, and
+nothing reads them.
+
+Each removed key is printed; --dry-run only lists them.
+
+Examples:
+  deets prune-desc --dry-run
+  deets prune-desc --local`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := existingTarget()
+		if err != nil {
+			return err
+		}
+
+		orphans, err := store.OrphanDescs(path)
+		if err != nil {
+			return err
+		}
+		for _, orphan := range orphans {
+			if flagDryRun {
+				fmt.Printf("would remove %s\n", orphan)
+				continue
+			}
+			cat, key, err := parsePath(orphan)
+			if err != nil {
+				return err
+			}
+			if err := store.RemoveValue(path, cat, key); err != nil {
+				return err
+			}
+			if !flagQuiet {
+				fmt.Printf("removed %s\n", orphan)
+			}
+		}
+		if len(orphans) == 0 && !flagQuiet {
+			fmt.Println("No orphaned _desc keys.")
+		}
+		return nil
+	},
+}
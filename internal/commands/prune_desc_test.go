@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPruneDesc(t *testing.T) {
+	home := setupTestEnv(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	content := "[contact]\nemail = \"a@b.c\"\nemail_desc = \"Primary email\"\nphone_desc = \"Mobile\"\n\n[web]\ngithub_desc = \"GitHub username\"\n"
+	os.WriteFile(path, []byte(content), 0644)
+
+	stdout, _, err := executeCommand("prune-desc", "--dry-run")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "would remove contact.phone_desc\nwould remove web.github_desc\n" {
+		t.Errorf("unexpected dry-run output %q", stdout)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Error("--dry-run should not write the file")
+	}
+	flagDryRun = false
+
+	stdout, _, err = executeCommand("prune-desc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "removed web.github_desc") {
+		t.Errorf("expected removals listed, got %q", stdout)
+	}
+	data, _ := os.ReadFile(path)
+	if want := "[contact]\nemail = \"a@b.c\"\nemail_desc = \"Primary email\""; strings.TrimSpace(string(data)) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+
+	stdout, _, _ = executeCommand("prune-desc")
+	if !strings.Contains(stdout, "No orphaned") {
+		t.Errorf("expected nothing left to prune, got %q", stdout)
+	}
+}
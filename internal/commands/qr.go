@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/qr"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagQROutput string
+	flagQRScale  int
+)
+
+func init() {
+	qrCmd.Flags().StringVarP(&flagQROutput, "output", "o", "", "write a PNG image to this file instead of printing")
+	qrCmd.Flags().IntVar(&flagQRScale, "scale", 8, "pixels per module for --output")
+	rootCmd.AddCommand(qrCmd)
+}
+
+var qrCmd = &cobra.Command{
+	Use:   "qr [pattern]",
+	Short: "Show contact info as a QR code",
+	Long: `Encode your details as a QR code, drawn in the terminal with Unicode
+blocks or written as a PNG with --output.
+
+With no pattern, the code holds your vCard (see export --format vcard).
+A pattern matching a single field encodes just that value; a pattern
+matching several fields encodes a vCard built from them.
+
+Examples:
+  deets qr                         # vCard in the terminal
+  deets qr web.website             # a single URL
+  deets qr contact                 # vCard with contact fields only
+  deets qr -o card.png             # vCard as a PNG`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+
+		payload := model.FormatVCard(db)
+		if len(args) == 1 {
+			pattern := args[0]
+			fields := db.Query(pattern)
+			switch {
+			case len(fields) == 0:
+				return notFoundError(pattern)
+			case len(fields) == 1:
+				payload = model.FormatValue(fields[0].Value)
+			default:
+				payload = model.FormatVCard(model.FieldsToDB(fields))
+			}
+		}
+
+		code, err := qr.Encode([]byte(payload), qr.Medium)
+		if err != nil {
+			return err
+		}
+
+		if flagQROutput != "" {
+			data, err := code.PNG(flagQRScale)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(flagQROutput, data, 0644); err != nil {
+				return fmt.Errorf("writing %s: %w", flagQROutput, err)
+			}
+			if !flagQuiet {
+				fmt.Printf("Wrote %s\n", flagQROutput)
+			}
+			return nil
+		}
+
+		fmt.Print(code.Blocks(isTTY()))
+		return nil
+	},
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestQR_Terminal(t *testing.T) {
+	setupTestDB(t)
+	stdout, _, err := executeCommand("qr")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.ContainsAny(stdout, "█▀▄") {
+		t.Errorf("expected block characters, got %q", stdout)
+	}
+	if strings.Contains(stdout, "\x1b[") {
+		t.Error("non-TTY output should not contain escape sequences")
+	}
+}
+
+func TestQR_SingleValueIsSmaller(t *testing.T) {
+	setupTestDB(t)
+	card, _, err := executeCommand("qr")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	value, _, err := executeCommand("qr", "web.github")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(value, "\n") >= strings.Count(card, "\n") {
+		t.Error("a single value should produce a smaller code than the full vCard")
+	}
+}
+
+func TestQR_NotFound(t *testing.T) {
+	setupTestDB(t)
+	_, _, err := executeCommand("qr", "nope.nothing")
+	exitErr, ok := err.(*ExitError)
+	if !ok || exitErr.Code != 2 {
+		t.Errorf("expected exit code 2, got %v", err)
+	}
+}
+
+func TestQR_PNG(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, "card.png")
+	stdout, _, err := executeCommand("qr", "--output", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "Wrote "+path) {
+		t.Errorf("expected confirmation, got %q", stdout)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "\x89PNG") {
+		t.Error("expected PNG signature")
+	}
+}
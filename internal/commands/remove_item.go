@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var flagRemoveItemIndex []int
+
+func init() {
+	removeItemCmd.Flags().IntSliceVar(&flagRemoveItemIndex, "index", nil, "remove the element at this 0-based index (repeatable; negative counts from the end)")
+	rootCmd.AddCommand(removeItemCmd)
+}
+
+var removeItemCmd = &cobra.Command{
+	Use:   "remove-item <category.key> [value]...",
+	Short: "Remove elements from an array field",
+	Long: `Remove elements from an array field in place, by value or by index.
+Every element equal to a given value is removed. Other elements keep
+their order and type.
+
+Examples:
+  deets remove-item academic.topics "statistics"
+  deets remove-item identity.aka --index 0
+  deets remove-item identity.aka --index -1     # last element`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, values := args[0], args[1:]
+		cat, key, err := parsePath(path)
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 && len(flagRemoveItemIndex) == 0 {
+			return fmt.Errorf("give values to remove or --index")
+		}
+
+		filePath, err := targetFile()
+		if err != nil {
+			return err
+		}
+		items, err := arrayField(filePath, path, false)
+		if err != nil {
+			return err
+		}
+		if items == nil {
+			return &ExitError{Code: 2, Message: fmt.Sprintf("field not found in %s: %s", filePath, path)}
+		}
+
+		drop := make([]bool, len(items))
+		for _, i := range flagRemoveItemIndex {
+			if i < 0 {
+				i += len(items)
+			}
+			if i < 0 || i >= len(items) {
+				return fmt.Errorf("index out of range for %s (%d elements)", path, len(items))
+			}
+			drop[i] = true
+		}
+		for _, v := range values {
+			if !containsItem(items, v) {
+				return &ExitError{Code: 2, Message: fmt.Sprintf("%q not found in %s", v, path)}
+			}
+			for i, item := range items {
+				if model.FormatValue(item) == v {
+					drop[i] = true
+				}
+			}
+		}
+
+		kept := []interface{}{}
+		for i, item := range items {
+			if !drop[i] {
+				kept = append(kept, item)
+			}
+		}
+		return store.SetValue(filePath, cat, key, model.FormatValueTOML(kept))
+	},
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRemoveItem_ByValue(t *testing.T) {
+	home := setupTestDB(t)
+	if _, _, err := executeCommand("remove-item", "academic.topics", "statistics"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if !strings.Contains(string(data), `topics = ["machine learning"]`) {
+		t.Errorf("expected statistics removed, got:\n%s", data)
+	}
+}
+
+func TestRemoveItem_ByIndex(t *testing.T) {
+	home := setupTestDB(t)
+	if _, _, err := executeCommand("remove-item", "identity.aka", "--index", "-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if !strings.Contains(string(data), `aka = ["Alex Towell"]`) {
+		t.Errorf("expected last alias removed, got:\n%s", data)
+	}
+}
+
+func TestRemoveItem_Errors(t *testing.T) {
+	setupTestDB(t)
+	_, _, err := executeCommand("remove-item", "academic.topics", "cooking")
+	if exitErr, ok := err.(*ExitError); !ok || exitErr.Code != 2 {
+		t.Errorf("expected exit code 2 for missing value, got %v", err)
+	}
+	if _, _, err := executeCommand("remove-item", "identity.aka", "--index", "5"); err == nil {
+		t.Error("expected error for out-of-range index")
+	}
+	flagRemoveItemIndex = nil
+	if _, _, err := executeCommand("remove-item", "contact.email", "x"); err == nil {
+		t.Error("expected error for non-array field")
+	}
+	if _, _, err := executeCommand("remove-item", "academic.topics"); err == nil {
+		t.Error("expected error with nothing to remove")
+	}
+}
@@ -1,24 +1,39 @@
 package commands
 
 import (
+	"fmt"
+	"os"
 	"strings"
 
+	"github.com/queelius/deets/internal/model"
 	"github.com/queelius/deets/internal/store"
 	"github.com/spf13/cobra"
 )
 
+var flagRmForce bool
+
 func init() {
+	rmCmd.Flags().BoolVarP(&flagRmForce, "force", "f", false, "remove without asking for confirmation")
+	addDryRunFlag(rmCmd)
 	rootCmd.AddCommand(rmCmd)
 }
 
 var rmCmd = &cobra.Command{
-	Use:   "rm <path>",
+	Use:   "rm <category.key|category>",
 	Short: "Remove a field or category",
-	Long: `Remove a field or entire category.
+	Long: `Remove a field or entire category. Removing a field also removes its
+_desc companion; removing a category also removes its sub-tables.
+
+When stdin is a terminal, rm asks for confirmation first. --force skips
+the prompt; scripts (non-interactive stdin) are never prompted. --dry-run
+lists the fields that would be removed without asking or writing.
 
 Examples:
   deets rm contact.phone     # remove a field
-  deets rm cooking           # remove entire category`,
+  deets rm cooking           # remove entire category
+  deets rm --local web.blog  # remove from the local .deets/me.toml
+  deets rm -f cooking        # no confirmation
+  deets rm --dry-run cooking # preview`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		path := args[0]
@@ -27,14 +42,67 @@ Examples:
 			return err
 		}
 
-		if strings.Contains(path, ".") {
-			cat, key, err := parsePath(path)
+		if flagDryRun {
+			return previewWrite(filePath, func(p string) error {
+				return remove(p, path)
+			})
+		}
+
+		if !flagRmForce && isStdinTTY() {
+			question, err := removalQuestion(filePath, path)
 			if err != nil {
 				return err
 			}
-			return store.RemoveValue(filePath, cat, key)
+			if !confirm(question) {
+				fmt.Fprintln(os.Stderr, "Aborted.")
+				return nil
+			}
 		}
 
-		return store.RemoveCategory(filePath, path)
+		return remove(filePath, path)
 	},
 }
+
+// remove deletes a field, with its _desc companion, or a whole category
+// from filePath.
+func remove(filePath, path string) error {
+	if strings.Contains(path, ".") {
+		cat, key, err := parsePath(path)
+		if err != nil {
+			return err
+		}
+		if err := store.RemoveValue(filePath, cat, key); err != nil {
+			return err
+		}
+		// The description may not exist; only the value is required.
+		_ = store.RemoveValue(filePath, cat, key+"_desc")
+		return nil
+	}
+
+	return store.RemoveCategory(filePath, path)
+}
+
+// removalQuestion describes what removing path from filePath would delete,
+// so the prompt shows the value or field count at stake.
+func removalQuestion(filePath, path string) (string, error) {
+	db, err := store.LoadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(path, ".") {
+		f, ok := db.GetField(path)
+		if !ok {
+			return "", fmt.Errorf("field not found: %s", path)
+		}
+		return fmt.Sprintf("Remove %s (%s) from %s?", path, model.FormatValue(f.Value), filePath), nil
+	}
+	fields := db.Query(path)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("category not found: %s", path)
+	}
+	noun := "fields"
+	if len(fields) == 1 {
+		noun = "field"
+	}
+	return fmt.Sprintf("Remove category %s (%d %s) from %s?", path, len(fields), noun, filePath), nil
+}
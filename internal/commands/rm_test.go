@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRm_FieldRemovesDesc(t *testing.T) {
+	home := setupTestDB(t)
+	if _, _, err := executeCommand("rm", "--force", "contact.email"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if strings.Contains(string(data), "email") {
+		t.Errorf("expected email and email_desc removed, got:\n%s", data)
+	}
+}
+
+func TestRm_Category(t *testing.T) {
+	home := setupTestDB(t)
+	if _, _, err := executeCommand("rm", "-f", "web"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if strings.Contains(string(data), "[web]") || !strings.Contains(string(data), "[contact]") {
+		t.Errorf("expected only [web] removed, got:\n%s", data)
+	}
+}
+
+func TestRm_NotFound(t *testing.T) {
+	setupTestDB(t)
+	if _, _, err := executeCommand("rm", "-f", "contact.fax"); err == nil {
+		t.Error("expected error for missing field")
+	}
+}
+
+func TestRemovalQuestion(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+
+	q, err := removalQuestion(path, "contact.email")
+	if err != nil || !strings.Contains(q, "contact.email (alex@example.com)") {
+		t.Errorf("field question = %q (%v)", q, err)
+	}
+	q, err = removalQuestion(path, "identity")
+	if err != nil || !strings.Contains(q, "category identity (2 fields)") {
+		t.Errorf("category question = %q (%v)", q, err)
+	}
+	if _, err := removalQuestion(path, "nonexistent"); err == nil {
+		t.Error("expected error for missing category")
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	orig := confirmInput
+	t.Cleanup(func() { confirmInput = orig })
+
+	for answer, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false, "": false} {
+		confirmInput = strings.NewReader(answer)
+		if got := confirm("Remove?"); got != want {
+			t.Errorf("confirm with %q = %v, want %v", answer, got, want)
+		}
+	}
+}
+
+func TestRm_DryRun(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	before, _ := os.ReadFile(path)
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("rm", "--dry-run", "web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "web.github") || !strings.Contains(stdout, "web.website") || !strings.Contains(stdout, "remove") {
+		t.Errorf("expected removal preview, got:\n%s", stdout)
+	}
+	after, _ := os.ReadFile(path)
+	if string(after) != string(before) {
+		t.Error("--dry-run should not write")
+	}
+
+	_, _, err = executeCommand("rm", "--dry-run", "web.nope")
+	if err == nil || strings.Contains(err.Error(), "deets-dry-run") {
+		t.Errorf("expected error naming the real file, got %v", err)
+	}
+}
@@ -3,24 +3,46 @@ package commands
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/queelius/deets/internal/config"
+	"github.com/queelius/deets/internal/store"
+	"github.com/queelius/deets/internal/tui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagFormat string
-	flagLocal  bool
-	flagQuiet  bool
+	flagFormat   string
+	flagLocal    bool
+	flagQuiet    bool
+	flagFile     string
+	flagNoGlobal bool
+	flagMaxWidth int
+	flagFull     bool
+	flagOverride []string
+	flagFromEnv  bool
+	flagProfile  string
 )
 
-// validFormats lists all recognized output format names.
-var validFormats = map[string]bool{
-	"table": true,
-	"json":  true,
-	"toml":  true,
-	"yaml":  true,
-	"env":   true,
-}
+// settings holds preferences from ~/.deets/config.toml, and activeCommand
+// the name of the command being run. Both are set before each command runs.
+var (
+	settings      config.Settings
+	activeCommand string
+)
+
+// formatNames lists all recognized output format names in display order.
+var formatNames = []string{"table", "json", "toml", "yaml", "ini", "hcl", "nix", "plist", "env", "dotenv", "csv", "ndjson", "markdown", "vcard", "jsonresume", "jsonld", "turtle", "hcard"}
+
+// validFormats indexes formatNames for lookup.
+var validFormats = func() map[string]bool {
+	m := make(map[string]bool, len(formatNames))
+	for _, name := range formatNames {
+		m[name] = true
+	}
+	return m
+}()
 
 var rootCmd = &cobra.Command{
 	Use:           "deets",
@@ -29,14 +51,47 @@ var rootCmd = &cobra.Command{
 	SilenceErrors: true,
 	SilenceUsage:  true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		return validateFormat()
+		if err := validateFormat(); err != nil {
+			return err
+		}
+		if flagMaxWidth < 0 {
+			return fmt.Errorf("--max-width must not be negative")
+		}
+		if flagFile != "" && flagLocal {
+			return fmt.Errorf("--file and --local cannot be used together")
+		}
+		if flagProfile != "" {
+			if flagFile != "" || flagNoGlobal {
+				return fmt.Errorf("--profile cannot be used with --file or --no-global")
+			}
+			if err := config.CheckProfileName(flagProfile); err != nil {
+				return err
+			}
+		}
+		if err := loadSettings(cmd); err != nil {
+			return err
+		}
+		// --no-global keeps deets out of ~/.deets, so it also skips backups.
+		if flagNoGlobal {
+			store.EnableBackups("")
+		} else {
+			store.EnableBackups(config.BackupsDir())
+		}
+		return nil
 	},
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVar(&flagFormat, "format", "", "output format: table, json, toml, yaml, env")
+	rootCmd.PersistentFlags().StringVar(&flagFormat, "format", "", "output format: "+strings.Join(formatNames, ", "))
 	rootCmd.PersistentFlags().BoolVar(&flagLocal, "local", false, "operate on local .deets/me.toml")
 	rootCmd.PersistentFlags().BoolVarP(&flagQuiet, "quiet", "q", false, "suppress informational messages")
+	rootCmd.PersistentFlags().StringVar(&flagFile, "file", "", "operate on this TOML file instead of global/local resolution")
+	rootCmd.PersistentFlags().BoolVar(&flagNoGlobal, "no-global", false, "never read ~/.deets (global file and config.toml)")
+	rootCmd.PersistentFlags().IntVar(&flagMaxWidth, "max-width", 0, "cut table values to fit this many columns (default: terminal width)")
+	rootCmd.PersistentFlags().BoolVar(&flagFull, "full", false, "never cut table values to fit the terminal")
+	rootCmd.PersistentFlags().StringArrayVar(&flagOverride, "set", nil, "override category.key=value in memory for this run (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&flagProfile, "profile", "", "layer ~/.deets/profiles/<name>.toml between global and local, and write to it")
+	rootCmd.PersistentFlags().BoolVar(&flagFromEnv, "from-env", false, "override loaded fields with DEETS_<CATEGORY>_<KEY> environment variables")
 }
 
 // Execute runs the root command.
@@ -45,12 +100,21 @@ func Execute() error {
 }
 
 // resolveFormat returns the effective output format for the current invocation.
-// If --format was explicitly set, that value is returned. Otherwise, TTY
-// detection drives the default: "table" on a terminal, "json" when piped.
+// If --format was explicitly set, that value is returned. Otherwise a
+// per-command default from config.toml is used if present, and failing that
+// TTY detection drives the default: "table" on a terminal, "json" when piped.
 func resolveFormat() string {
 	if flagFormat != "" {
 		return flagFormat
 	}
+	if f := settings.FormatFor(activeCommand); f != "" {
+		return f
+	}
+	return ttyFormat()
+}
+
+// ttyFormat returns "table" on a terminal and "json" when piped.
+func ttyFormat() string {
 	if isTTY() {
 		return "table"
 	}
@@ -63,11 +127,67 @@ func validateFormat() error {
 		return nil
 	}
 	if !validFormats[flagFormat] {
-		return fmt.Errorf("unknown format %q: expected table, json, toml, yaml, or env", flagFormat)
+		return fmt.Errorf("unknown format %q: expected one of %s", flagFormat, strings.Join(formatNames, ", "))
+	}
+	return nil
+}
+
+// loadSettings reads config.toml and records the running command so that
+// resolveFormat can apply per-command defaults. Configured formats are
+// validated up front so a typo surfaces immediately.
+func loadSettings(cmd *cobra.Command) error {
+	activeCommand = cmd.Name()
+	if flagNoGlobal {
+		settings = config.Settings{}
+		return nil
+	}
+	s, err := config.LoadSettings()
+	if err != nil {
+		return err
+	}
+	for name, format := range s.Formats {
+		if validFormats[format] || (name == "get" && format == "bare") {
+			continue
+		}
+		return fmt.Errorf("%s: unknown format %q for command %q", config.SettingsFile(), format, name)
 	}
+	settings = s
 	return nil
 }
 
+// isStdinTTY reports whether stdin is connected to a terminal. /dev/null is
+// also a character device, so it is ruled out explicitly; otherwise
+// `deets ... < /dev/null` would be treated as interactive.
+func isStdinTTY() bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(fi, null) {
+		return false
+	}
+	return true
+}
+
+// tableWidth returns the width table output is fitted to: --max-width, or
+// the terminal's width when stdout is a terminal. 0 means no limit, as
+// with --full or when output is piped.
+func tableWidth() int {
+	switch {
+	case flagFull:
+		return 0
+	case flagMaxWidth > 0:
+		return flagMaxWidth
+	case !isTTY():
+		return 0
+	}
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	width, _ := tui.TermSize(os.Stdout)
+	return width
+}
+
 // isTTY reports whether stdout is connected to a terminal.
 func isTTY() bool {
 	fi, err := os.Stdout.Stat()
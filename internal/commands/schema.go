@@ -7,7 +7,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var flagSchemaJSONSchema bool
+
 func init() {
+	schemaCmd.Flags().BoolVar(&flagSchemaJSONSchema, "json-schema", false, "emit a JSON Schema document for 'deets export --format json' output")
 	rootCmd.AddCommand(schemaCmd)
 }
 
@@ -17,9 +20,14 @@ var schemaCmd = &cobra.Command{
 	Long: `Display the schema of all fields: category, key, inferred type,
 description, and example value.
 
+With --json-schema, print a JSON Schema (draft 2020-12) document that
+describes the JSON export of the database instead, for editors and CI
+that validate exported JSON or YAML documents.
+
 Examples:
   deets schema                  # table output
-  deets schema --format json    # JSON array`,
+  deets schema --format json    # JSON array
+  deets schema --json-schema > deets.schema.json`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		db, err := loadDB()
@@ -27,6 +35,15 @@ Examples:
 			return err
 		}
 
+		if flagSchemaJSONSchema {
+			out, err := model.FormatJSONSchema(db)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+			return nil
+		}
+
 		entries := model.BuildSchema(db)
 
 		switch resolveFormat() {
@@ -87,3 +87,31 @@ func TestSchema_HasDescriptions(t *testing.T) {
 	}
 	t.Error("identity.name not found in schema entries")
 }
+
+func TestSchema_JSONSchema(t *testing.T) {
+	setupTestDB(t)
+
+	flagSchemaJSONSchema = true
+	stdout, _, err := executeCommand("schema")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc struct {
+		Schema     string `json:"$schema"`
+		Properties map[string]struct {
+			Properties map[string]map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if doc.Schema == "" {
+		t.Error("missing $schema")
+	}
+	if gpa := doc.Properties["academic"].Properties["gpa"]; gpa["type"] != "number" {
+		t.Errorf("unexpected gpa schema: %v", gpa)
+	}
+	if email := doc.Properties["contact"].Properties["email"]; email["description"] != "Primary email" {
+		t.Errorf("unexpected email schema: %v", email)
+	}
+}
@@ -2,48 +2,155 @@ package commands
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/queelius/deets/internal/model"
 	"github.com/spf13/cobra"
 )
 
+var (
+	flagSearchRegex      bool
+	flagSearchIgnoreCase bool
+	flagSearchIn         []string
+	flagSearchFuzzy      bool
+	flagSearchCategory   []string
+)
+
 func init() {
+	searchCmd.Flags().BoolVar(&flagSearchRegex, "regex", false, "treat the query as a regular expression (case-sensitive unless -i or (?i))")
+	searchCmd.Flags().BoolVarP(&flagSearchIgnoreCase, "ignore-case", "i", false, "match case-insensitively (always the case without --regex)")
+	searchCmd.Flags().BoolVar(&flagSearchFuzzy, "fuzzy", false, "fuzzy subsequence match, best results first (JSON includes the score)")
+	searchCmd.Flags().StringSliceVar(&flagSearchIn, "in", nil, "search only keys, values, or descriptions (repeatable or comma-separated)")
+	searchCmd.Flags().StringSliceVar(&flagSearchCategory, "category", nil, "search only these categories (comma-separated or repeatable; globs allowed)")
+	addPagingFlags(searchCmd)
+	addSortFlags(searchCmd)
+	searchCmd.Flags().StringVar(&flagTemplate, "template", "", "format results with a Go text/template")
+	searchCmd.Flags().StringArrayVar(&flagWhere, "where", nil, whereUsage)
 	rootCmd.AddCommand(searchCmd)
 }
 
 var searchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search keys, values, and descriptions",
-	Args:  cobra.ExactArgs(1),
+	Long: `Search keys, values, and descriptions.
+
+By default the query is a case-insensitive substring. With --regex it is a
+Go regular expression, which is case-sensitive unless -i is given or the
+expression starts with (?i). With --fuzzy the query's characters only need
+to appear in order ("mastdon" finds "mastodon"), and results are ranked by
+match quality; JSON output is then a ranked array carrying each score.
+--in restricts which parts of each field are
+searched; the default is all three. --category limits the search to the
+named categories. --limit and --offset page through the results; JSON
+output then becomes {"total": n, "results": ...}. --sort and --reverse
+reorder the results, overriding the fuzzy ranking.
+
+Examples:
+  deets search towell
+  deets search --regex '^https?://'
+  deets search --regex -i 'mast(o|a)don'
+  deets search --fuzzy mastdon
+  deets search --in descriptions orcid
+  deets search --in keys,values email
+  deets search --category web,contact email
+  deets search --fuzzy mastdon --limit 1`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		filters, err := parseWhere()
+		if err != nil {
+			return err
+		}
+		if err := checkPaging(); err != nil {
+			return err
+		}
+		scope := model.ScopeAll
+		if len(flagSearchIn) > 0 {
+			scope = 0
+			for _, name := range flagSearchIn {
+				s, err := model.ParseSearchScope(name)
+				if err != nil {
+					return err
+				}
+				scope |= s
+			}
+		}
+		if flagSearchRegex && flagSearchFuzzy {
+			return fmt.Errorf("--regex and --fuzzy cannot be combined")
+		}
+		var re *regexp.Regexp
+		if flagSearchRegex {
+			expr := args[0]
+			if flagSearchIgnoreCase {
+				expr = "(?i)" + expr
+			}
+			if re, err = regexp.Compile(expr); err != nil {
+				return fmt.Errorf("invalid regex %q: %w", args[0], err)
+			}
+		}
 		db, err := loadDB()
 		if err != nil {
 			return err
 		}
+		db = db.Only(flagSearchCategory)
 
-		fields := db.Search(args[0])
+		var fields []model.Field
+		var scored []model.ScoredField
+		switch {
+		case flagSearchFuzzy:
+			for _, r := range db.SearchFuzzy(args[0], scope) {
+				if model.MatchAll(r.Field, filters) {
+					scored = append(scored, r)
+					fields = append(fields, r.Field)
+				}
+			}
+		case re != nil:
+			fields = model.FilterFields(db.SearchRegex(re, scope), filters)
+		default:
+			fields = model.FilterFields(db.Search(args[0], scope), filters)
+		}
 		if len(fields) == 0 {
 			return &ExitError{Code: 2, Message: fmt.Sprintf("no matches for: %s", args[0])}
 		}
+		if fields, err = sortResults(fields); err != nil {
+			return err
+		}
+		if scored != nil {
+			// Keep each score with its field in the new order.
+			scores := make(map[string]int, len(scored))
+			for _, r := range scored {
+				scores[r.Category+"."+r.Key] = r.Score
+			}
+			for i, f := range fields {
+				scored[i] = model.ScoredField{Field: f, Score: scores[f.Category+"."+f.Key]}
+			}
+		}
+		total := len(fields)
+		start, end := pageBounds(total)
+		fields = fields[start:end]
+		if scored != nil {
+			scored = scored[start:end]
+		}
+		if flagTemplate != "" {
+			return printTemplate(fields)
+		}
 
-		switch resolveFormat() {
+		switch format := resolveFormat(); format {
 		case "json":
-			out, err := model.FormatFieldsJSON(fields)
+			var out string
+			if flagSearchFuzzy {
+				out, err = model.FormatScoredJSON(scored)
+			} else {
+				out, err = model.FormatFieldsJSON(fields)
+			}
 			if err != nil {
 				return err
 			}
-			fmt.Println(out)
-		case "toml":
-			db := model.FieldsToDB(fields)
-			fmt.Print(model.FormatTOML(db))
-		case "yaml":
-			db := model.FieldsToDB(fields)
-			fmt.Print(model.FormatYAML(db))
-		case "env":
-			db := model.FieldsToDB(fields)
-			fmt.Print(model.FormatEnv(db))
-		default: // table
-			fmt.Print(model.FormatTable(fields))
+			return printJSONResult(out, total)
+		case "table":
+			fmt.Print(model.FormatTableFit(fields, false, false, tableWidth()))
+		default:
+			_, err := printDocument(model.FieldsToDB(fields), format)
+			return err
 		}
 		return nil
 	},
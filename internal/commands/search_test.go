@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSearch_Regex(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	stdout, _, err := executeCommand("search", "--regex", `^https?://`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "website") || strings.Contains(stdout, "github") {
+		t.Errorf("expected only web.website, got:\n%s", stdout)
+	}
+}
+
+func TestSearch_RegexCase(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	if _, _, err := executeCommand("search", "--regex", "^QUEELIUS$"); err == nil {
+		t.Error("expected no match for case-sensitive regex")
+	}
+
+	stdout, _, err := executeCommand("search", "--regex", "-i", "^QUEELIUS$")
+	if err != nil || !strings.Contains(stdout, "queelius") {
+		t.Errorf("search --regex -i = %q (%v)", stdout, err)
+	}
+}
+
+func TestSearch_RegexInvalid(t *testing.T) {
+	setupTestDB(t)
+	_, _, err := executeCommand("search", "--regex", "(")
+	if err == nil || !strings.Contains(err.Error(), "invalid regex") {
+		t.Errorf("expected invalid regex error, got %v", err)
+	}
+}
+
+func TestSearch_In(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	// "alex" appears in identity values and in contact.email.
+	stdout, _, err := executeCommand("search", "alex", "--in", "keys")
+	if err == nil {
+		t.Errorf("expected no key matches, got:\n%s", stdout)
+	}
+
+	flagSearchIn = nil
+	stdout, _, err = executeCommand("search", "username", "--in", "descriptions")
+	if err != nil || !strings.Contains(stdout, "queelius") {
+		t.Errorf("search --in descriptions = %q (%v)", stdout, err)
+	}
+
+	flagSearchIn = nil
+	stdout, _, err = executeCommand("search", "email", "--in", "keys,values")
+	if err != nil || !strings.Contains(stdout, "alex@example.com") {
+		t.Errorf("search --in keys,values = %q (%v)", stdout, err)
+	}
+
+	flagSearchIn = nil
+	if _, _, err := executeCommand("search", "x", "--in", "paths"); err == nil {
+		t.Error("expected error for unknown scope")
+	}
+}
+
+func TestSearch_Fuzzy(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "json"
+	stdout, _, err := executeCommand("search", "--fuzzy", "gthb")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed []map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if len(parsed) == 0 || parsed[0]["key"] != "github" {
+		t.Fatalf("expected github ranked first, got %s", stdout)
+	}
+	if _, ok := parsed[0]["score"].(float64); !ok {
+		t.Errorf("expected numeric score, got %v", parsed[0]["score"])
+	}
+
+	if _, _, err := executeCommand("search", "--fuzzy", "--regex", "x"); err == nil {
+		t.Error("expected error combining --fuzzy and --regex")
+	}
+}
+
+func TestSearch_Category(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	stdout, _, err := executeCommand("search", "alex", "--category", "web,contact")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "alex@example.com") || strings.Contains(stdout, "Alexander") {
+		t.Errorf("expected only contact.email, got:\n%s", stdout)
+	}
+
+	flagSearchCategory = nil
+	if _, _, err := executeCommand("search", "alex", "--category", "web"); err == nil {
+		t.Error("expected no matches in web")
+	}
+}
+
+func TestSearch_LimitOffset(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "json"
+	// "alex" matches identity.aka, identity.name, and contact.email.
+	stdout, _, err := executeCommand("search", "alex", "--fuzzy", "--limit", "1", "--offset", "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var paged struct {
+		Total   int                      `json:"total"`
+		Results []map[string]interface{} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &paged); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if paged.Total < 3 || len(paged.Results) != 1 {
+		t.Errorf("expected 1 of at least 3 results, got %s", stdout)
+	}
+
+	if _, _, err := executeCommand("search", "alex", "--limit", "-1"); err == nil {
+		t.Error("expected error for negative --limit")
+	}
+}
+
+func TestSearch_SortReverse(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "json"
+	stdout, _, err := executeCommand("search", "alex", "--fuzzy", "--sort", "key", "--reverse")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed []map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	for i := 1; i < len(parsed); i++ {
+		if parsed[i]["key"].(string) > parsed[i-1]["key"].(string) {
+			t.Errorf("expected descending keys, got %s", stdout)
+		}
+	}
+	if len(parsed) > 0 && parsed[0]["score"] == float64(0) {
+		t.Errorf("score lost after sorting: %s", stdout)
+	}
+}
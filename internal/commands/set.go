@@ -1,21 +1,40 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/queelius/deets/internal/config"
+	"github.com/queelius/deets/internal/model"
 	"github.com/queelius/deets/internal/store"
 	"github.com/spf13/cobra"
 )
 
+var (
+	flagSetJSON     bool
+	flagSetType     string
+	flagSetBatch    bool
+	flagSetNoVerify bool
+)
+
 func init() {
+	setCmd.Flags().BoolVar(&flagSetJSON, "json", false, "parse the value as JSON and store it as the matching TOML type")
+	setCmd.Flags().StringVar(&flagSetType, "type", "", "store the value as this TOML type: string, integer, float, boolean, date, or datetime")
+	setCmd.Flags().BoolVar(&flagSetBatch, "batch", false, "read many \"category.key = value\" lines (or a JSON object) from stdin")
+	setCmd.Flags().BoolVar(&flagSetNoVerify, "no-verify", false, "write even if ~/.deets/schema.toml or a placeholder check rejects the value, with a warning")
+	addDryRunFlag(setCmd)
 	rootCmd.AddCommand(setCmd)
 }
 
 var setCmd = &cobra.Command{
-	Use:   "set <category.key> [value]",
+	Use:   "set <category.key> [value] | --batch",
 	Short: "Set a metadata value",
 	Long: `Set a metadata value. Creates the category if it doesn't exist.
 
@@ -27,9 +46,46 @@ Examples:
   deets set cooking.fav "lasagna"          # creates [cooking]
   deets set identity.aka '["Alex Towell"]' # array value
   echo "piped" | deets set identity.name   # value from stdin
-  cat file.txt | deets set identity.bio -  # explicit stdin`,
-	Args: cobra.RangeArgs(1, 2),
+  cat file.txt | deets set identity.bio -  # explicit stdin
+
+With --json the value is parsed as JSON, so arrays, objects (written as
+inline tables), numbers, and booleans keep their type:
+  deets set --json identity.aka '["Alex","Lex"]'
+  deets set --json academic.advisor '{"name":"Bob","dept":"Math"}'
+
+With --type the value is checked and stored unquoted as that TOML type.
+Dates are YYYY-MM-DD; datetimes are RFC 3339, with the offset optional:
+  deets set --type date identity.birthday 1990-05-01
+  deets set --type integer academic.year 2021
+
+--dry-run prints the fields that would be added or changed, without
+writing anything.
+
+If ~/.deets/schema.toml exists (see 'deets validate --help'), values it
+rejects are refused. So are strings with a {{category.key}} placeholder
+naming no field, since they would break every later read; write \{{ for
+literal braces. --no-verify writes either anyway with a warning.
+
+With --batch, stdin holds many assignments that are applied with a single
+write: either "category.key = value" lines, where values that are valid
+TOML (2021, true, "quoted", ["a","b"]) keep their type and anything else
+is a string, or a JSON object shaped like 'deets export --format json'
+output (dotted "category.key" names work too):
+  deets set --batch < provision.txt
+  deets export --format json | ssh newbox deets set --batch`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if flagSetBatch {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.RangeArgs(1, 2)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagSetBatch {
+			if flagSetJSON || flagSetType != "" {
+				return fmt.Errorf("--batch cannot be combined with --json or --type")
+			}
+			return setBatch()
+		}
 		path := args[0]
 
 		cat, key, err := parsePath(path)
@@ -60,11 +116,304 @@ Examples:
 			value = strings.TrimRight(string(data), "\n")
 		}
 
+		if flagSetJSON && flagSetType != "" {
+			return fmt.Errorf("--json and --type cannot be combined")
+		}
+
 		filePath, err := targetFile()
 		if err != nil {
 			return err
 		}
 
-		return store.SetValue(filePath, cat, key, value)
+		write := func(path string) error {
+			return store.SetValue(path, cat, key, value)
+		}
+		if flagSetType != "" || flagSetJSON {
+			var literal string
+			if flagSetType != "" {
+				literal, err = typedLiteral(flagSetType, value)
+			} else {
+				literal, err = jsonToTOML(value)
+			}
+			if err != nil {
+				return err
+			}
+			write = func(path string) error {
+				return store.SetLiteral(path, cat, key, literal)
+			}
+		}
+
+		if err := verifyTemplates(filePath, write); err != nil {
+			return err
+		}
+		if err := verifySchema(filePath, write); err != nil {
+			return err
+		}
+		if flagDryRun {
+			return previewWrite(filePath, write)
+		}
+		return write(filePath)
 	},
 }
+
+// verifySchema checks the fields write would add or change in filePath
+// against ~/.deets/schema.toml, using a scratch copy. A violation is an
+// error, or with --no-verify a warning.
+func verifySchema(filePath string, write func(path string) error) error {
+	schema, err := loadSchema()
+	if err != nil || schema == nil {
+		return err
+	}
+	before, after, err := scratchWrite(filePath, write)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, entry := range diffFields(before, after) {
+		if entry.Status == "remove" {
+			continue
+		}
+		f, _ := after.GetField(entry.Path)
+		for _, msg := range schema.CheckField(f.Category, f.Key, f.Value) {
+			problems = append(problems, entry.Path+": "+msg)
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	if flagSetNoVerify {
+		for _, p := range problems {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", p)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s rejects this change (use --no-verify to write anyway):\n  %s",
+		config.SchemaFile(), strings.Join(problems, "\n  "))
+}
+
+// verifyTemplates checks that every {{category.key}} placeholder in the
+// strings write would add or change in filePath names a field, either in
+// the changed file or in the merged view, using a scratch copy. An unknown
+// placeholder would make every later read fail, so it is an error, or with
+// --no-verify a warning.
+func verifyTemplates(filePath string, write func(path string) error) error {
+	before, after, err := scratchWrite(filePath, write)
+	if err != nil {
+		return err
+	}
+	merged, _ := loadDB() // best effort: the current view may itself be broken
+	known := func(path string) bool {
+		if _, ok := after.GetField(path); ok {
+			return true
+		}
+		if merged == nil {
+			return false
+		}
+		_, ok := merged.GetField(path)
+		return ok
+	}
+
+	var problems []string
+	for _, entry := range diffFields(before, after) {
+		if entry.Status == "remove" {
+			continue
+		}
+		f, _ := after.GetField(entry.Path)
+		for _, ref := range model.TemplateRefs(f.Value) {
+			if !known(ref) {
+				problems = append(problems, fmt.Sprintf("%s: {{%s}} names no field (write \\{{ for literal braces)", entry.Path, ref))
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	if flagSetNoVerify {
+		for _, p := range problems {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", p)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown placeholder (use --no-verify to write anyway):\n  %s", strings.Join(problems, "\n  "))
+}
+
+// setBatch applies the assignments read from stdin in one write.
+func setBatch() error {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("reading stdin: %w", err)
+	}
+	var assignments []store.Assignment
+	if text := strings.TrimSpace(string(data)); strings.HasPrefix(text, "{") {
+		assignments, err = jsonAssignments(text)
+	} else {
+		assignments, err = store.ParseAssignments(string(data))
+	}
+	if err != nil {
+		return err
+	}
+	if len(assignments) == 0 {
+		return fmt.Errorf("no assignments on stdin")
+	}
+
+	filePath, err := targetFile()
+	if err != nil {
+		return err
+	}
+	write := func(path string) error {
+		return store.SetLiterals(path, assignments)
+	}
+	if err := verifyTemplates(filePath, write); err != nil {
+		return err
+	}
+	if err := verifySchema(filePath, write); err != nil {
+		return err
+	}
+	if flagDryRun {
+		return previewWrite(filePath, write)
+	}
+	if err := write(filePath); err != nil {
+		return err
+	}
+	if !flagQuiet {
+		fmt.Fprintf(os.Stderr, "Set %d field(s) in %s\n", len(assignments), filePath)
+	}
+	return nil
+}
+
+// jsonAssignments turns a JSON object into assignments. Top-level names
+// are either "category.key" paths or categories holding an object of keys.
+// Keys are applied in sorted order so the result does not depend on map
+// iteration.
+func jsonAssignments(text string) ([]store.Assignment, error) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var out []store.Assignment
+	add := func(cat, key string, v interface{}) error {
+		v, err := fromJSON(v)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", cat, key, err)
+		}
+		out = append(out, store.Assignment{Category: cat, Key: key, Literal: model.FormatValueTOML(v)})
+		return nil
+	}
+	for _, name := range sortedKeys(doc) {
+		if strings.Contains(name, ".") {
+			cat, key, err := parsePath(name)
+			if err != nil {
+				return nil, err
+			}
+			if err := add(cat, key, doc[name]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		fields, ok := doc[name].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%q: expected an object of fields or a category.key name", name)
+		}
+		for _, key := range sortedKeys(fields) {
+			if err := add(name, key, fields[key]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return out, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// typedLiteral validates value as the named TOML type and returns it as a
+// TOML literal.
+func typedLiteral(kind, value string) (string, error) {
+	switch kind {
+	case "string":
+		return model.TOMLString(value), nil
+	case "integer":
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid integer %q", value)
+		}
+		return strconv.FormatInt(i, 10), nil
+	case "float":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return "", fmt.Errorf("invalid float %q", value)
+		}
+		return model.FormatValueTOML(f), nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid boolean %q", value)
+		}
+		return strconv.FormatBool(b), nil
+	case "date", "datetime":
+		return model.ParseTime(kind, value)
+	}
+	return "", fmt.Errorf("unknown type %q: use string, integer, float, boolean, date, or datetime", kind)
+}
+
+// jsonToTOML converts a JSON document to the equivalent TOML value literal.
+// Integral numbers become TOML integers; null has no TOML counterpart and
+// is rejected.
+func jsonToTOML(s string) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", fmt.Errorf("invalid JSON value: %w", err)
+	}
+	if dec.More() {
+		return "", fmt.Errorf("invalid JSON value: trailing data")
+	}
+	v, err := fromJSON(v)
+	if err != nil {
+		return "", err
+	}
+	return model.FormatValueTOML(v), nil
+}
+
+// fromJSON maps decoded JSON onto the Go types the TOML decoder produces.
+func fromJSON(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, fmt.Errorf("JSON null cannot be stored in TOML")
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i, nil
+		}
+		return val.Float64()
+	case []interface{}:
+		for i, item := range val {
+			conv, err := fromJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = conv
+		}
+		return val, nil
+	case map[string]interface{}:
+		for k, item := range val {
+			conv, err := fromJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			val[k] = conv
+		}
+		return val, nil
+	}
+	return v, nil
+}
@@ -26,6 +26,40 @@ func TestSet_BasicValue(t *testing.T) {
 	}
 }
 
+func TestSet_ArrayOfTablesElement(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	if _, _, err := executeCommand("set", "education.degrees[0].degree", "PhD"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := executeCommand("set", "education.degrees[1].degree", "BS"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := executeCommand("set", "--type", "integer", "education.degrees[0].year", "2020"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	flagSetType = ""
+	stdout, _, err := executeCommand("get", "education.degrees[1].degree")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(stdout) != "BS" {
+		t.Errorf("expected BS, got %q", stdout)
+	}
+
+	flagRmForce = true
+	if _, _, err := executeCommand("rm", "education.degrees[0]"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stdout, _, err = executeCommand("get", "education.degrees[0].degree")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(stdout) != "BS" {
+		t.Errorf("expected BS to move to index 0, got %q", stdout)
+	}
+}
+
 func TestSet_CreateCategory(t *testing.T) {
 	setupTestDB(t)
 	_, _, err := executeCommand("set", "cooking.favorite", "lasagna")
@@ -75,3 +109,300 @@ func TestSet_Local(t *testing.T) {
 		t.Errorf("expected 'Local Name' in local file, got %q", string(data))
 	}
 }
+
+func TestSet_JSON(t *testing.T) {
+	home := setupTestDB(t)
+	if _, _, err := executeCommand("set", "--json", "identity.aka", `["Alex","Lex"]`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := executeCommand("set", "--json", "academic.advisor", `{"name":"Bob","year":2019,"gpa":3.5}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	for _, want := range []string{
+		`aka = ["Alex", "Lex"]`,
+		`advisor = { year = 2019, gpa = 3.5, name = "Bob" }`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in file:\n%s", want, data)
+		}
+	}
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("get", "academic.advisor.year")
+	if err != nil || strings.TrimSpace(stdout) != "2019" {
+		t.Errorf("expected nested value readable, got %q (%v)", stdout, err)
+	}
+}
+
+func TestSet_JSONInvalid(t *testing.T) {
+	setupTestDB(t)
+	for _, v := range []string{`["a",`, `null`, `[1, null]`, `1 2`} {
+		if _, _, err := executeCommand("set", "--json", "identity.aka", v); err == nil {
+			t.Errorf("expected error for %s", v)
+		}
+	}
+}
+
+func TestSet_Type(t *testing.T) {
+	home := setupTestDB(t)
+	cases := [][]string{
+		{"date", "identity.birthday", "1990-05-01"},
+		{"integer", "academic.year", "2021"},
+		{"float", "academic.gpa", "4"},
+		{"boolean", "contact.public", "true"},
+	}
+	for _, c := range cases {
+		if _, _, err := executeCommand("set", "--type", c[0], c[1], c[2]); err != nil {
+			t.Fatalf("set --type %s: %v", c[0], err)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	for _, want := range []string{"birthday = 1990-05-01\n", "year = 2021\n", "gpa = 4.0\n", "public = true\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in file:\n%s", want, data)
+		}
+	}
+
+	flagFormat = "json"
+	stdout, _, err := executeCommand("get", "identity.birthday")
+	if err != nil || !strings.Contains(stdout, `"1990-05-01"`) {
+		t.Errorf("expected ISO date in JSON, got %q (%v)", stdout, err)
+	}
+}
+
+func TestSet_TypeInvalid(t *testing.T) {
+	setupTestDB(t)
+	for _, c := range [][]string{{"date", "yesterday"}, {"integer", "1.5"}, {"color", "red"}} {
+		if _, _, err := executeCommand("set", "--type", c[0], "identity.x", c[1]); err == nil {
+			t.Errorf("expected error for --type %s %q", c[0], c[1])
+		}
+	}
+}
+
+// withStdin runs fn with os.Stdin reading input.
+func withStdin(t *testing.T, input string, fn func()) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	orig := os.Stdin
+	os.Stdin = f
+	defer func() { os.Stdin = orig }()
+	fn()
+}
+
+func TestSet_BatchLines(t *testing.T) {
+	home := setupTestDB(t)
+	var err error
+	withStdin(t, "identity.nickname = Lex\nacademic.year = 2021\n\n# done\n", func() {
+		_, _, err = executeCommand("set", "--batch")
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	for _, want := range []string{`nickname = "Lex"`, "year = 2021\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in file:\n%s", want, data)
+		}
+	}
+}
+
+func TestSet_BatchQuiet(t *testing.T) {
+	setupTestDB(t)
+	var stderr string
+	var err error
+	withStdin(t, "identity.nickname = Lex\n", func() {
+		_, stderr, err = executeCommand("set", "--batch", "--quiet")
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stderr != "" {
+		t.Errorf("expected no summary with --quiet, got %q", stderr)
+	}
+}
+
+func TestSet_BatchJSON(t *testing.T) {
+	home := setupTestDB(t)
+	var err error
+	withStdin(t, `{"cooking": {"fav": "lasagna", "rating": 5}, "web.blog": "https://b.example"}`, func() {
+		_, _, err = executeCommand("set", "--batch")
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	for _, want := range []string{"[cooking]\nfav = \"lasagna\"\nrating = 5\n", `blog = "https://b.example"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in file:\n%s", want, data)
+		}
+	}
+}
+
+func TestSet_BatchErrors(t *testing.T) {
+	setupTestDB(t)
+	var err error
+	if _, _, err = executeCommand("set", "--batch", "identity.name"); err == nil {
+		t.Error("expected error for positional args with --batch")
+	}
+	flagSetBatch = false
+	withStdin(t, `{"cooking": "lasagna"}`, func() {
+		_, _, err = executeCommand("set", "--batch")
+	})
+	if err == nil {
+		t.Error("expected error for a category that is not an object")
+	}
+	flagSetBatch = false
+	withStdin(t, "", func() {
+		_, _, err = executeCommand("set", "--batch")
+	})
+	if err == nil {
+		t.Error("expected error for empty input")
+	}
+}
+
+func TestSet_DryRun(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	before, _ := os.ReadFile(path)
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("set", "--dry-run", "contact.email", "new@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "contact.email") || !strings.Contains(stdout, "change") || !strings.Contains(stdout, "new@example.com") {
+		t.Errorf("expected change preview, got:\n%s", stdout)
+	}
+	after, _ := os.ReadFile(path)
+	if string(after) != string(before) {
+		t.Error("--dry-run should not write")
+	}
+
+	flagFormat = "json"
+	stdout, _, err = executeCommand("set", "--dry-run", "cooking.fav", "lasagna")
+	if err != nil || !strings.Contains(stdout, `"status": "add"`) {
+		t.Errorf("expected add entry in JSON, got %q (%v)", stdout, err)
+	}
+}
+
+func TestSet_SchemaRefuses(t *testing.T) {
+	home := setupTestDB(t)
+	writeSchema(t, home, `strict = true
+
+[identity]
+
+[contact.email]
+pattern = '^[^@ ]+@[^@ ]+$'
+
+[web]
+[academic]
+`)
+	flagSetType = ""
+	flagSetJSON = false
+
+	_, _, err := executeCommand("set", "contact.email", "not-an-email")
+	if err == nil || !strings.Contains(err.Error(), "does not match") || !strings.Contains(err.Error(), "--no-verify") {
+		t.Fatalf("expected schema refusal, got %v", err)
+	}
+	if _, _, err := executeCommand("set", "hobbies.chess", "yes"); err == nil || !strings.Contains(err.Error(), "not in the schema") {
+		t.Fatalf("expected undeclared category refusal, got %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if strings.Contains(string(data), "not-an-email") || strings.Contains(string(data), "hobbies") {
+		t.Fatalf("refused values were written:\n%s", data)
+	}
+
+	if _, _, err := executeCommand("set", "contact.email", "alex@new.example.com"); err != nil {
+		t.Fatalf("valid value refused: %v", err)
+	}
+
+	flagSetNoVerify = true
+	_, stderr, err := executeCommand("set", "contact.email", "not-an-email")
+	if err != nil {
+		t.Fatalf("--no-verify: %v", err)
+	}
+	if !strings.Contains(stderr, "warning") {
+		t.Errorf("expected warning on stderr, got %q", stderr)
+	}
+	data, _ = os.ReadFile(filepath.Join(home, ".deets", "me.toml"))
+	if !strings.Contains(string(data), "not-an-email") {
+		t.Errorf("--no-verify did not write:\n%s", data)
+	}
+}
+
+func TestSet_TemplatePlaceholders(t *testing.T) {
+	home := setupTestDB(t)
+	file := filepath.Join(home, ".deets", "me.toml")
+
+	// Braces around anything but a field path are not placeholders.
+	if _, _, err := executeCommand("set", "identity.note", "Use {{.Name}} in go templates"); err != nil {
+		t.Fatalf("set go template text: %v", err)
+	}
+	out, _, err := executeCommand("get", "identity.note", "--format", "table")
+	if err != nil {
+		t.Fatalf("get after go template text: %v", err)
+	}
+	if !strings.Contains(out, "Use {{.Name}} in go templates") {
+		t.Errorf("get = %q, want the text unchanged", out)
+	}
+
+	if _, _, err := executeCommand("set", "identity.sig", "{{identity.name}} <{{contact.email}}>"); err != nil {
+		t.Fatalf("set template naming known fields: %v", err)
+	}
+
+	_, _, err = executeCommand("set", "identity.broken", "{{identity.nickname}}")
+	if err == nil || !strings.Contains(err.Error(), "identity.nickname") || !strings.Contains(err.Error(), "--no-verify") {
+		t.Fatalf("expected unknown placeholder refusal, got %v", err)
+	}
+	data, _ := os.ReadFile(file)
+	if strings.Contains(string(data), "nickname") {
+		t.Fatalf("refused value was written:\n%s", data)
+	}
+
+	// An escaped placeholder is literal text.
+	if _, _, err := executeCommand("set", "identity.howto", `\{{identity.nickname}}`); err != nil {
+		t.Fatalf("set escaped placeholder: %v", err)
+	}
+	out, _, err = executeCommand("get", "identity.howto", "--format", "table")
+	if err != nil {
+		t.Fatalf("get escaped placeholder: %v", err)
+	}
+	if !strings.Contains(out, "{{identity.nickname}}") || strings.Contains(out, `\{{`) {
+		t.Errorf("get = %q, want literal braces", out)
+	}
+
+	flagSetNoVerify = true
+	_, stderr, err := executeCommand("set", "identity.broken", "{{identity.nickname}}")
+	if err != nil {
+		t.Fatalf("--no-verify: %v", err)
+	}
+	if !strings.Contains(stderr, "warning") {
+		t.Errorf("expected warning on stderr, got %q", stderr)
+	}
+}
+
+func TestSet_NestedPlaceholder(t *testing.T) {
+	setupTestDB(t)
+	if _, _, err := executeCommand("set", "education.phd.institution", "SIUE"); err != nil {
+		t.Fatalf("set nested field: %v", err)
+	}
+	if _, _, err := executeCommand("set", "identity.sig", "{{identity.name}}, {{education.phd.institution}}"); err != nil {
+		t.Fatalf("set nested placeholder: %v", err)
+	}
+	out, _, err := executeCommand("get", "identity.sig", "--format", "table")
+	if err != nil {
+		t.Fatalf("get after nested placeholder: %v", err)
+	}
+	if !strings.Contains(out, "Alexander Towell, SIUE") {
+		t.Errorf("get = %q, want the nested placeholder resolved", out)
+	}
+}
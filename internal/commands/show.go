@@ -2,87 +2,155 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/queelius/deets/internal/model"
 	"github.com/spf13/cobra"
 )
 
 func init() {
+	showCmd.Flags().StringVar(&flagTemplate, "template", "", "format results with a Go text/template")
+	showCmd.Flags().StringArrayVar(&flagExclude, "exclude", nil, excludeUsage)
+	showCmd.Flags().BoolVar(&flagSource, "source", false, sourceUsage)
+	addSortFlags(showCmd)
 	rootCmd.AddCommand(showCmd)
 }
 
 var showCmd = &cobra.Command{
-	Use:   "show [category]",
+	Use:   "show [category|pattern]",
 	Short: "Display metadata",
-	Long: `Display all metadata, or a single category.
+	Long: `Display all metadata, a single category, or the fields matching a
+pattern. Patterns take the same forms as get.
 
 Examples:
   deets show                    # all categories as table
   deets show identity           # single category
+  deets show 'a*'               # every category starting with "a"
+  deets show '*.email'          # one key across categories
   deets show --format json      # full JSON dump
   deets show --format toml      # raw merged TOML
-  deets show --format yaml      # YAML output`,
+  deets show --format yaml      # YAML output
+  deets show --format csv       # category,key,value,description rows
+  deets show --format ndjson    # one JSON object per field, per line
+  deets show --format markdown  # GitHub-flavored Markdown table
+  deets show contact --template '{{.email}}'
+  deets show --exclude contact --exclude '*.phone'
+  deets show --source           # which file and line each value came from
+  deets show --sort value --reverse`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		db, err := loadDB()
 		if err != nil {
 			return err
 		}
+		db = db.Without(flagExclude)
 
 		format := resolveFormat()
 
-		// Single category
 		if len(args) == 1 {
 			cat, ok := db.GetCategory(args[0])
 			if !ok {
-				return fmt.Errorf("category not found: %s", args[0])
+				return showPattern(db, args[0], format)
+			}
+
+			// Single category
+			if flagTemplate != "" {
+				return printTemplate(cat.Fields)
+			}
+			fields := make([]model.Field, 0, len(cat.Fields))
+			for _, f := range cat.Fields {
+				if !model.IsDescKey(f.Key) {
+					fields = append(fields, f)
+				}
+			}
+			if fields, err = sortResults(fields); err != nil {
+				return err
+			}
+			if flagSource {
+				return printSourced(fields, format, false, len(fields))
 			}
 
 			switch format {
 			case "json":
-				out, err := model.FormatCategoryJSON(cat)
+				out, err := model.FormatCategoryJSON(model.Category{Name: cat.Name, Fields: fields})
 				if err != nil {
 					return err
 				}
 				fmt.Println(out)
-			case "toml":
-				catDB := &model.DB{Categories: []model.Category{cat}}
-				fmt.Print(model.FormatTOML(catDB))
-			case "yaml":
-				catDB := &model.DB{Categories: []model.Category{cat}}
-				fmt.Print(model.FormatYAML(catDB))
-			case "env":
+			case "table":
+				fmt.Print(model.FormatTableFit(fields, false, false, tableWidth()))
+			default:
 				catDB := &model.DB{Categories: []model.Category{cat}}
-				fmt.Print(model.FormatEnv(catDB))
-			default: // table
-				fields := make([]model.Field, 0, len(cat.Fields))
-				for _, f := range cat.Fields {
-					if !model.IsDescKey(f.Key) {
-						fields = append(fields, f)
-					}
-				}
-				fmt.Print(model.FormatTable(fields))
+				_, err := printDocument(catDB, format)
+				return err
 			}
 			return nil
 		}
 
 		// All categories
+		if flagTemplate != "" {
+			return printTemplate(db.AllFields())
+		}
+		fields, err := sortResults(db.AllFields())
+		if err != nil {
+			return err
+		}
+		if flagSource {
+			return printSourced(fields, format, false, len(fields))
+		}
 		switch format {
 		case "json":
-			out, err := model.FormatJSON(db)
+			out, err := model.FormatJSON(model.FieldsToDB(fields))
 			if err != nil {
 				return err
 			}
 			fmt.Println(out)
-		case "toml":
-			fmt.Print(model.FormatTOML(db))
-		case "yaml":
-			fmt.Print(model.FormatYAML(db))
-		case "env":
-			fmt.Print(model.FormatEnv(db))
-		default: // table
-			fmt.Print(model.FormatTable(db.AllFields()))
+		case "table":
+			fmt.Print(model.FormatTableFit(fields, false, false, tableWidth()))
+		default:
+			_, err := printDocument(db, format)
+			return err
 		}
 		return nil
 	},
 }
+
+// showPattern displays the fields matching a get-style pattern. A plain word
+// that names no category keeps the "category not found" error.
+func showPattern(db *model.DB, pattern, format string) error {
+	if err := model.ValidatePattern(pattern); err != nil {
+		return err
+	}
+	fields := db.Query(pattern)
+	if len(fields) == 0 {
+		if !strings.Contains(pattern, ".") && isExactPath(pattern) {
+			return fmt.Errorf("category not found: %s", pattern)
+		}
+		return notFoundError(pattern)
+	}
+	fields, err := sortResults(fields)
+	if err != nil {
+		return err
+	}
+
+	if flagTemplate != "" {
+		return printTemplate(fields)
+	}
+	if flagSource {
+		return printSourced(fields, format, false, len(fields))
+	}
+	switch format {
+	case "json":
+		out, err := model.FormatFieldsJSON(fields)
+		if err != nil {
+			return err
+		}
+		fmt.Println(out)
+	case "table":
+		fmt.Print(model.FormatTableFit(fields, false, false, tableWidth()))
+	default:
+		_, err := printDocument(model.FieldsToDB(fields), format)
+		return err
+	}
+	return nil
+}
@@ -2,8 +2,11 @@ package commands
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
+
+	"github.com/queelius/deets/internal/model"
 )
 
 func TestShow_Table(t *testing.T) {
@@ -125,3 +128,143 @@ func TestShow_SingleCategory_NotFound(t *testing.T) {
 		t.Error("expected error for nonexistent category")
 	}
 }
+
+func TestShow_FormatCSV(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "csv"
+	stdout, _, err := executeCommand("show")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(stdout, "category,key,value,description\n") {
+		t.Errorf("expected CSV header, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "identity,name,Alexander Towell,Full legal name") {
+		t.Errorf("expected identity.name row, got %q", stdout)
+	}
+	if !strings.Contains(stdout, `"Alex Towell, Alex T"`) {
+		t.Errorf("expected quoted array value, got %q", stdout)
+	}
+}
+
+func TestShow_FormatNDJSON(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "ndjson"
+	stdout, _, err := executeCommand("show", "web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(stdout, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines for web category, got %d: %q", len(lines), stdout)
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &obj); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+	if obj["key"] != "github" || obj["value"] != "queelius" {
+		t.Errorf("unexpected first object: %v", obj)
+	}
+}
+
+func TestShow_Source(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	stdout, _, err := executeCommand("show", "web", "--source")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Source", "global:11", "global:13"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestShow_CategoryGlob(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "json"
+	stdout, _, err := executeCommand("show", "[ac]*")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if len(parsed) != 2 || parsed["academic"] == nil || parsed["contact"] == nil {
+		t.Errorf("expected academic and contact, got %v", parsed)
+	}
+}
+
+func TestShow_KeyGlob(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	stdout, _, err := executeCommand("show", "*.email")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "alex@example.com") {
+		t.Errorf("expected email in output, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "queelius") {
+		t.Errorf("unexpected non-email field in output:\n%s", stdout)
+	}
+}
+
+func TestShow_PatternNoMatch(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	_, _, err := executeCommand("show", "*.nonexistent")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Errorf("expected exit code 2, got %v", err)
+	}
+}
+
+func TestShow_SortReverse(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "json"
+	stdout, _, err := executeCommand("show", "identity", "--reverse")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Index(stdout, `"name"`) > strings.Index(stdout, `"aka"`) {
+		t.Errorf("expected name before aka, got:\n%s", stdout)
+	}
+
+	flagReverse = false
+	flagFormat = "table"
+	stdout, _, err = executeCommand("show", "--sort", "category", "--reverse")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Index(stdout, "web") > strings.Index(stdout, "academic") {
+		t.Errorf("expected web before academic, got:\n%s", stdout)
+	}
+}
+
+func TestShow_MaxWidth(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+
+	flagMaxWidth = 30
+	stdout, _, err := executeCommand("show", "academic")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "…") {
+		t.Errorf("expected cut values at --max-width 30, got:\n%s", stdout)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(stdout, "\n"), "\n") {
+		if w := model.DisplayWidth(line); w > 30 {
+			t.Errorf("line is %d columns wide: %q", w, line)
+		}
+	}
+
+	flagFull = true
+	stdout, _, _ = executeCommand("show", "academic")
+	if strings.Contains(stdout, "…") {
+		t.Errorf("--full should not cut values, got:\n%s", stdout)
+	}
+}
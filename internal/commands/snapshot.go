@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/queelius/deets/internal/config"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var flagSnapshotForce bool
+
+func init() {
+	snapshotSaveCmd.Flags().BoolVarP(&flagSnapshotForce, "force", "f", false, "replace an existing snapshot of the same name")
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and restore named copies of your metadata files",
+	Long: `Keep named copies of the global and local metadata files under
+~/.deets/snapshots/, for example before a large import or a schema
+change. Unlike the automatic backups used by undo, snapshots are only
+made and removed on request.`,
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save [name]",
+	Short: "Save a snapshot of the global and local files",
+	Long: `Save a copy of ~/.deets/me.toml and the local .deets/me.toml, if there
+is one, under the given name (default: the current date and time). With
+--local only the local file is saved; with --file, only that file.
+
+Examples:
+  deets snapshot save before-import
+  deets snapshot save --local`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := time.Now().Format("2006-01-02T150405")
+		if len(args) == 1 {
+			name = args[0]
+		}
+		files, err := snapshotFiles()
+		if err != nil {
+			return err
+		}
+
+		snap, err := store.SaveSnapshot(config.SnapshotsDir(), name, files, flagSnapshotForce)
+		if err != nil {
+			return err
+		}
+		if !flagQuiet {
+			fmt.Printf("Saved snapshot %s (%s)\n", snap.Name, strings.Join(snap.Files, ", "))
+		}
+		return nil
+	},
+}
+
+// snapshotFiles returns the files snapshot save copies: --file, or the
+// local file with --local, or the global file and any local one.
+func snapshotFiles() ([]string, error) {
+	switch {
+	case flagFile == stdinFile:
+		return nil, errStdinWrite
+	case flagFile != "":
+		return []string{flagFile}, nil
+	case flagLocal:
+		local, err := config.LocalTargetFile()
+		if err != nil {
+			return nil, err
+		}
+		return []string{local}, nil
+	case flagNoGlobal:
+		return nil, fmt.Errorf("--no-global requires --file or --local")
+	}
+	files := []string{config.GlobalFile()}
+	if local := config.FindLocalFile(); local != "" {
+		files = append(files, local)
+	}
+	return files, nil
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List snapshots, oldest first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snaps, err := store.Snapshots(config.SnapshotsDir())
+		if err != nil {
+			return err
+		}
+
+		switch resolveFormat() {
+		case "json":
+			type entry struct {
+				Name  string   `json:"name"`
+				Time  string   `json:"time"`
+				Files []string `json:"files"`
+			}
+			entries := make([]entry, 0, len(snaps))
+			for _, s := range snaps {
+				entries = append(entries, entry{s.Name, s.Time.Format(time.RFC3339), s.Files})
+			}
+			data, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		default: // table
+			for _, s := range snaps {
+				fmt.Printf("%s  %s  %s\n", s.Time.Format("2006-01-02 15:04"), s.Name, strings.Join(s.Files, ", "))
+			}
+		}
+		return nil
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Restore the files saved in a snapshot",
+	Long: `Write every file saved in the snapshot back to where it was taken from.
+The files being replaced are backed up first, so 'deets undo' can revert
+a restore, one file per undo.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snap, err := store.RestoreSnapshot(config.SnapshotsDir(), args[0])
+		if err != nil {
+			return err
+		}
+		if !flagQuiet {
+			fmt.Printf("Restored snapshot %s (%s)\n", snap.Name, strings.Join(snap.Files, ", "))
+		}
+		return nil
+	},
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	home := setupTestDB(t)
+	global := filepath.Join(home, ".deets", "me.toml")
+	workDir := filepath.Join(home, "project")
+	os.MkdirAll(filepath.Join(workDir, ".deets"), 0755)
+	local := filepath.Join(workDir, ".deets", "me.toml")
+	os.WriteFile(local, []byte("[contact]\nemail = \"project@example.com\"\n"), 0644)
+	os.Chdir(workDir)
+	globalBefore, _ := os.ReadFile(global)
+
+	if _, _, err := executeCommand("snapshot", "save", "before", "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	executeCommand("set", "web.github", "someone-else")
+	executeCommand("set", "--local", "contact.email", "changed@example.com")
+	flagLocal = false
+
+	stdout, _, err := executeCommand("snapshot", "list", "--format", "table")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "before") || !strings.Contains(stdout, local) {
+		t.Errorf("expected the snapshot listed with both files, got %q", stdout)
+	}
+
+	if _, _, err := executeCommand("snapshot", "restore", "before", "-q"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(global); string(data) != string(globalBefore) {
+		t.Errorf("global file not restored:\n%s", data)
+	}
+	if data, _ := os.ReadFile(local); !strings.Contains(string(data), "project@example.com") {
+		t.Errorf("local file not restored:\n%s", data)
+	}
+
+	if _, _, err := executeCommand("snapshot", "restore", "missing"); err == nil {
+		t.Error("expected error for an unknown snapshot")
+	}
+}
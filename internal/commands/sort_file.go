@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var flagSortFileBy string
+
+func init() {
+	sortFileCmd.Flags().StringVar(&flagSortFileBy, "by", "alpha", "order: alpha or template")
+	rootCmd.AddCommand(sortFileCmd)
+}
+
+var sortFileCmd = &cobra.Command{
+	Use:   "sort-file",
+	Short: "Reorder the sections and keys of the metadata file",
+	Long: `Physically reorder ~/.deets/me.toml (or the --local / --file target):
+sections and the keys within them are sorted, and the file is written in
+fmt's canonical style.
+
+--by alpha (the default) sorts alphabetically. --by template follows the
+order of the template 'deets init' writes (identity, contact, web,
+academic, education, employment, and their well-known keys), with
+anything else after it alphabetically.
+
+Each _desc key is placed right after the key it describes, comments move
+with the key or section below them, and sub-tables such as
+[[education.degrees]] stay after their category in their original order.
+
+Examples:
+  deets sort-file
+  deets sort-file --by template
+  deets sort-file --local`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := existingTarget()
+		if err != nil {
+			return err
+		}
+
+		changed, err := store.SortFile(path, flagSortFileBy)
+		if err != nil {
+			return err
+		}
+		if !flagQuiet {
+			if changed {
+				fmt.Printf("Sorted %s\n", path)
+			} else {
+				fmt.Printf("%s is already sorted\n", path)
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSortFile(t *testing.T) {
+	home := setupTestEnv(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte("[web]\ngithub = \"queelius\"\n\n[contact]\nemail = \"a@b.c\"\n\n[identity]\nname = \"A\"\n"), 0644)
+
+	stdout, _, err := executeCommand("sort-file", "--by", "template")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "Sorted") {
+		t.Errorf("expected confirmation, got %q", stdout)
+	}
+	data, _ := os.ReadFile(path)
+	if want := "[identity]\nname = \"A\"\n\n[contact]\nemail = \"a@b.c\"\n\n[web]\ngithub = \"queelius\"\n"; string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+
+	if _, _, err := executeCommand("sort-file", "--by", "size"); err == nil {
+		t.Error("expected error for an unknown order")
+	}
+}
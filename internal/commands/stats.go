@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/queelius/deets/internal/config"
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize the database",
+	Long: `Report field counts per category, the distribution of value types,
+how many fields have descriptions, and how many fields come from (and
+override) a local .deets/me.toml.
+
+Examples:
+  deets stats                  # table output
+  deets stats --format json    # JSON object`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+
+		// Overrides are local fields that also exist globally, which only
+		// applies when both layers are loaded.
+		var global *model.DB
+		if flagFile == "" && !flagNoGlobal && config.FindLocalFile() != "" {
+			if global, err = store.LoadFile(config.GlobalFile()); err != nil {
+				return fmt.Errorf("loading global file: %w", err)
+			}
+		}
+
+		stats := model.ComputeStats(db, global)
+
+		switch resolveFormat() {
+		case "json":
+			out, err := model.FormatStatsJSON(stats)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+		default: // table
+			fmt.Print(model.FormatStatsTable(stats))
+		}
+		return nil
+	},
+}
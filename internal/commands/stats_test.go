@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStats_Table(t *testing.T) {
+	setupTestDB(t)
+	flagFormat = "table"
+	stdout, _, err := executeCommand("stats")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"academic", "Fields:     8 (6 described, 0 computed)", "Local:      0 (0 overrides)"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestStats_JSONWithLocal(t *testing.T) {
+	home := setupTestDB(t)
+
+	workDir := filepath.Join(home, "project")
+	os.MkdirAll(filepath.Join(workDir, ".deets"), 0755)
+	os.Chdir(workDir)
+	local := "[contact]\nemail = \"work@example.com\"\nslack = \"@alex\"\n"
+	os.WriteFile(filepath.Join(workDir, ".deets", "me.toml"), []byte(local), 0644)
+
+	flagFormat = "json"
+	stdout, _, err := executeCommand("stats")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed struct {
+		Fields    int            `json:"fields"`
+		Local     int            `json:"local"`
+		Overrides int            `json:"overrides"`
+		Types     map[string]int `json:"types"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if parsed.Fields != 9 || parsed.Local != 2 || parsed.Overrides != 1 {
+		t.Errorf("fields/local/overrides = %d/%d/%d, want 9/2/1", parsed.Fields, parsed.Local, parsed.Overrides)
+	}
+	if parsed.Types["float"] != 1 {
+		t.Errorf("unexpected types: %v", parsed.Types)
+	}
+}
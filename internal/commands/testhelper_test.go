@@ -7,6 +7,10 @@ import (
 	"path/filepath"
 	"sync"
 	"testing"
+
+	"github.com/queelius/deets/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // executeCommand runs a cobra command with the given args and captures output.
@@ -56,6 +60,10 @@ func setupTestEnv(t *testing.T) string {
 	t.Helper()
 	home := t.TempDir()
 	t.Setenv("HOME", home)
+	// Ignore any overrides from the environment running the tests.
+	t.Setenv(config.EnvHome, "")
+	t.Setenv(config.EnvGlobalFile, "")
+	t.Setenv(config.EnvLocalFile, "")
 
 	// Change CWD into the temp home so FindLocalDir() doesn't
 	// walk into the real user's ~/.deets/.
@@ -72,14 +80,98 @@ func setupTestEnv(t *testing.T) string {
 	flagFormat = ""
 	flagLocal = false
 	flagQuiet = false
+	flagFile = ""
+	flagNoGlobal = false
+	flagMaxWidth = 0
+	flagFull = false
+	flagOverride = nil
+	flagFromEnv = false
+	flagProfile = ""
 	flagGetDefault = ""
 	flagGetDesc = false
 	flagGetExists = false
+	flagGetFirst = false
+	flagGetRegex = false
+	flagGetSep = ""
+	flagGetNull = false
+	flagGetCopy = false
+	flagEvalRaw = false
+	flagEvalCompact = false
+	flagTemplate = ""
+	flagExclude = nil
+	flagSource = false
+	flagKeysTree = false
+	flagKeysDepth = 0
+	flagKeysValues = false
+	flagSearchRegex = false
+	flagSearchIgnoreCase = false
+	flagSearchIn = nil
+	flagSearchFuzzy = false
+	flagSearchCategory = nil
+	flagLimit = 0
+	flagOffset = 0
+	flagSort = ""
+	flagReverse = false
+	flagRmForce = false
+	flagCpToLocal = false
+	flagCpToGlobal = false
+	flagCpForce = false
+	flagAddUnique = false
+	flagRemoveItemIndex = nil
+	flagSetJSON = false
+	flagSetType = ""
+	flagSetBatch = false
+	flagSetNoVerify = false
+	flagDryRun = false
+	flagInitInteractive = false
+	flagInitFrom = ""
+	flagInitTemplate = ""
+	flagFmtSort = false
+	flagFmtCheck = false
+	flagSortFileBy = "alpha"
+	flagUndoList = false
+	flagSnapshotForce = false
+	flagDescribeMissing = false
+	flagDescribeImport = ""
+	flagAddCategoryDescriptions = ""
+	flagWhere = nil
 	flagImportDryRun = false
+	flagExportPrefix = ""
+	flagExportShell = "posix"
+	flagCitationTitle = ""
+	flagGenerateTemplate = ""
+	flagSignatureHTML = false
+	flagGoPackage = "deets"
+	flagQROutput = ""
+	flagQRScale = 8
+	flagImportStrategy = ""
+	flagDoctorFix = false
+	flagLintDisable = nil
+	flagSchemaJSONSchema = false
+	flagWhichExplain = false
+	settings = config.Settings{}
+	activeCommand = ""
+	resetChangedFlags(rootCmd)
+	t.Cleanup(func() {
+		settings = config.Settings{}
+		activeCommand = ""
+	})
 
 	return home
 }
 
+// resetChangedFlags clears the Changed marker on every flag of cmd and its
+// subcommands, since cobra keeps it across Execute calls and commands such
+// as get --default rely on it.
+func resetChangedFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) { f.Changed = false }
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetChangedFlags(c)
+	}
+}
+
 // setupTestDB creates an isolated test environment and initializes a
 // deets database with sample data. Returns the home directory path.
 func setupTestDB(t *testing.T) string {
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
+	"github.com/queelius/deets/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse and edit fields interactively",
+	Long: `Open an interactive browser in the terminal.
+
+Keys:
+  ↑/↓ or j/k      move             ←/→, h/l, tab   switch category
+  /               fuzzy filter     esc             clear the filter
+  enter or e      edit the value   q or ctrl-c     quit
+
+Edits are written immediately, to the file set would write to (--local
+and --file apply). A field is edited as that file writes it, so templates
+stay templates. Strings are edited as text; other values as TOML literals
+such as ["a", "b"] or 42.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !isStdinTTY() || !isTTY() {
+			return fmt.Errorf("tui needs an interactive terminal")
+		}
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+		filePath, err := targetFile()
+		if err != nil {
+			return err
+		}
+
+		b := tui.NewBrowser(db)
+		b.Save = func(f model.Field, input string) error {
+			literal, err := editedLiteral(f.Value, input)
+			if err != nil {
+				return err
+			}
+			return store.SetLiteral(filePath, f.Category, f.Key, literal)
+		}
+		b.Load = loadDB
+		b.Raw = func(f model.Field) (model.Field, bool) {
+			return fileField(filePath, f.Category+"."+f.Key)
+		}
+		return tui.Run(b)
+	},
+}
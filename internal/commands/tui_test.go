@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTUI_RequiresTerminal(t *testing.T) {
+	setupTestDB(t)
+	_, _, err := executeCommand("tui")
+	if err == nil || !strings.Contains(err.Error(), "interactive terminal") {
+		t.Errorf("expected terminal error, got %v", err)
+	}
+}
+
+func TestEditedLiteral(t *testing.T) {
+	tests := []struct {
+		old  interface{}
+		text string
+		want string
+	}{
+		{"Alex", "Alexander", `"Alexander"`},
+		{"x", "42", `"42"`},
+		{int64(1), " 42 ", "42"},
+		{[]interface{}{"a"}, `["a", "b"]`, `["a", "b"]`},
+	}
+	for _, tt := range tests {
+		got, err := editedLiteral(tt.old, tt.text)
+		if err != nil || got != tt.want {
+			t.Errorf("editedLiteral(%v, %q) = %q, %v; want %q", tt.old, tt.text, got, err, tt.want)
+		}
+	}
+	if _, err := editedLiteral(int64(1), "forty-two"); err == nil {
+		t.Error("expected error for a non-TOML value")
+	}
+}
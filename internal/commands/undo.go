@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/queelius/deets/internal/config"
+	"github.com/queelius/deets/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var flagUndoList bool
+
+func init() {
+	undoCmd.Flags().BoolVar(&flagUndoList, "list", false, "list the backups undo can restore, newest first")
+	rootCmd.AddCommand(undoCmd)
+}
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Restore the file changed by the last write",
+	Long: `Every command that changes a metadata file (set, rm, import, describe,
+edit, fmt, ...) first copies it to ~/.deets/backups/. undo restores the
+most recent backup over the file it came from and deletes the backup, so
+running undo again steps further back. The newest 50 backups are kept.
+--no-global disables backups.
+
+Examples:
+  deets undo
+  deets undo --list`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := config.BackupsDir()
+		if flagUndoList {
+			backups, err := store.Backups(dir)
+			if err != nil {
+				return err
+			}
+			for _, b := range backups {
+				fmt.Printf("%s  %s\n", b.Time.Local().Format("2006-01-02 15:04:05"), b.Path)
+			}
+			return nil
+		}
+
+		b, err := store.Undo(dir)
+		if err != nil {
+			return err
+		}
+		if !flagQuiet {
+			fmt.Printf("Restored %s as of %s\n", b.Path, b.Time.Local().Format("2006-01-02 15:04:05"))
+		}
+		return nil
+	},
+}
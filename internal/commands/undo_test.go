@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUndo(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	original, _ := os.ReadFile(path)
+
+	if _, _, err := executeCommand("set", "contact.email", "new@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := executeCommand("rm", "-f", "web"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stdout, _, err := executeCommand("undo", "--list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(stdout), "\n"); len(lines) != 2 || !strings.HasSuffix(lines[0], path) {
+		t.Errorf("expected two backups of %s, got %q", path, stdout)
+	}
+	flagUndoList = false
+
+	if _, _, err := executeCommand("undo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stdout, _, _ = executeCommand("get", "web.github")
+	if !strings.Contains(stdout, "queelius") {
+		t.Errorf("expected web restored, got %q", stdout)
+	}
+
+	stdout, _, err = executeCommand("undo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "Restored "+path) {
+		t.Errorf("expected restore message, got %q", stdout)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(original) {
+		t.Errorf("expected the original file back, got:\n%s", data)
+	}
+
+	if _, _, err := executeCommand("undo"); err == nil {
+		t.Error("expected error with nothing left to undo")
+	}
+}
+
+func TestUndo_DryRunMakesNoBackup(t *testing.T) {
+	home := setupTestDB(t)
+	if _, _, err := executeCommand("set", "--dry-run", "contact.email", "new@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".deets", "backups")); !os.IsNotExist(err) {
+		t.Error("--dry-run should not back anything up")
+	}
+}
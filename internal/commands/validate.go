@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/queelius/deets/internal/model"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check values with the built-in validators",
+	Long: `Check the values of the merged database (or the --file target) with
+validators chosen by category and key name, and exit 1 if any fail.
+
+Validators:
+` + validatorHelp() + `
+If ~/.deets/schema.toml exists, the database is also checked against it:
+
+  strict = true          # reject categories and keys not declared below
+
+  [identity.name]
+  type = "string"        # string, integer, float, boolean, date, datetime,
+  required = true        # time, array, records, or table
+
+  [contact.email]
+  pattern = '^[^@ ]+@[^@ ]+$'
+
+  [cooking]              # a category whose keys are not constrained
+
+deets set refuses values the schema rejects unless given --no-verify.
+
+Examples:
+  deets validate
+  deets validate --format json
+  deets validate --file other.toml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := loadDB()
+		if err != nil {
+			return err
+		}
+
+		failures := model.Validate(db)
+		schema, err := loadSchema()
+		if err != nil {
+			return err
+		}
+		if schema != nil {
+			failures = append(failures, schema.Check(db)...)
+		}
+		switch resolveFormat() {
+		case "json":
+			out, err := model.FormatValidationJSON(failures)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+		default: // table
+			if len(failures) > 0 {
+				fmt.Print(model.FormatValidationTable(failures, tableWidth()))
+			} else if !flagQuiet {
+				fmt.Println("All values are valid.")
+			}
+		}
+
+		if len(failures) > 0 {
+			return &ExitError{Code: 1}
+		}
+		return nil
+	},
+}
+
+// validatorHelp lists the validators for the command's help text.
+func validatorHelp() string {
+	var b strings.Builder
+	for _, v := range model.Validators {
+		fmt.Fprintf(&b, "  %-6s %s\n", v.Name, v.Description)
+	}
+	return b.String()
+}
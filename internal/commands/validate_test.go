@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate_Valid(t *testing.T) {
+	setupTestDB(t)
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("validate")
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, stdout)
+	}
+	if !strings.Contains(stdout, "All values are valid") {
+		t.Errorf("expected all valid, got %q", stdout)
+	}
+}
+
+func TestValidate_Failures(t *testing.T) {
+	home := setupTestDB(t)
+	path := filepath.Join(home, ".deets", "me.toml")
+	data, _ := os.ReadFile(path)
+	content := strings.Replace(string(data), `email = "alex@example.com"`, `email = "alex at example"`, 1)
+	os.WriteFile(path, []byte(content), 0644)
+
+	flagFormat = "json"
+	stdout, _, err := executeCommand("validate")
+	if exitErr, ok := err.(*ExitError); !ok || exitErr.Code != 1 {
+		t.Fatalf("expected exit 1, got %v", err)
+	}
+	var failures []struct{ Validator, Path, Value string }
+	if err := json.Unmarshal([]byte(stdout), &failures); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if len(failures) != 1 || failures[0].Path != "contact.email" || failures[0].Value != "alex at example" {
+		t.Errorf("unexpected failures: %+v", failures)
+	}
+}
+
+// writeSchema writes a schema.toml into the test home's .deets directory.
+func writeSchema(t *testing.T, home, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(home, ".deets", "schema.toml"), []byte(content), 0644); err != nil {
+		t.Fatalf("writing schema: %v", err)
+	}
+}
+
+func TestValidate_Schema(t *testing.T) {
+	home := setupTestDB(t)
+	writeSchema(t, home, `[identity.name]
+type = "string"
+
+[identity.pronouns]
+required = true
+
+[academic.gpa]
+type = "integer"
+`)
+
+	flagFormat = "table"
+	stdout, _, err := executeCommand("validate")
+	if err == nil {
+		t.Fatal("expected exit 1")
+	}
+	if !strings.Contains(stdout, "identity.pronouns") || !strings.Contains(stdout, "required field is missing") {
+		t.Errorf("expected missing required field, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "academic.gpa") || !strings.Contains(stdout, "schema requires integer") {
+		t.Errorf("expected type violation, got %q", stdout)
+	}
+}
@@ -6,38 +6,97 @@ import (
 	"os"
 
 	"github.com/queelius/deets/internal/config"
+	"github.com/queelius/deets/internal/model"
+	"github.com/queelius/deets/internal/store"
 	"github.com/spf13/cobra"
 )
 
+var flagWhichExplain bool
+
 func init() {
+	whichCmd.Flags().BoolVar(&flagWhichExplain, "explain", false, "list every layer considered and the fields each contributes")
 	rootCmd.AddCommand(whichCmd)
 }
 
 var whichCmd = &cobra.Command{
 	Use:   "which",
 	Short: "Show resolved file paths and merge status",
-	Args:  cobra.NoArgs,
+	Long: `Show the files deets reads, in the order they are loaded. Each file
+overrides the ones listed before it:
+
+  ~/.deets/conf.d/*.toml          global fragments, in lexical order
+  ~/.deets/me.toml                the global file
+  ~/.deets/me.<host>.toml         this machine's overrides
+  ~/.deets/profiles/<name>.toml   with --profile or a contexts.toml rule
+  .deets/conf.d/*.toml            local fragments, in lexical order
+  .deets/me.toml                  the local file, found by walking up from cwd
+
+DEETS_<CATEGORY>_<KEY> variables (with --from-env) and then --set values
+override all of them. <host> is the first label of the host name in lower
+case.
+
+With --explain, deets lists every layer it considered, whether each was
+found, how many fields it defines, and how many of those survive into the
+merged result (USED); a field overridden by a later layer counts only
+there.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagWhichExplain {
+			return explainLayers()
+		}
+		if flagFile != "" {
+			return printWhichFile(flagFile)
+		}
+
 		paths, err := config.ResolvePaths()
 		if err != nil {
 			return err
 		}
+		name, rule, err := activeProfile()
+		if err != nil {
+			return err
+		}
+		var profile string
+		if name != "" {
+			profile = config.ProfileFile(name)
+		}
 
 		switch resolveFormat() {
 		case "json":
-			data, err := json.MarshalIndent(map[string]interface{}{
+			info := map[string]interface{}{
 				"global_dir":    paths.GlobalDir,
 				"global_file":   paths.GlobalFile,
 				"local_dir":     paths.LocalDir,
 				"local_file":    paths.LocalFile,
 				"has_local":     paths.HasLocal,
 				"global_exists": fileExists(paths.GlobalFile),
-			}, "", "  ")
+				"host_file":     paths.HostFile,
+				"has_host":      paths.HasHost,
+			}
+			if paths.GlobalFragments != nil {
+				info["global_fragments"] = paths.GlobalFragments
+			}
+			if paths.LocalFragments != nil {
+				info["local_fragments"] = paths.LocalFragments
+			}
+			if profile != "" {
+				info["profile"] = name
+				info["profile_file"] = profile
+				info["profile_exists"] = fileExists(profile)
+				if rule != "" {
+					info["profile_rule"] = rule
+				}
+			}
+			data, err := json.MarshalIndent(info, "", "  ")
 			if err != nil {
 				return err
 			}
 			fmt.Println(string(data))
 		default: // table
+			// Files are listed in the order they are loaded.
+			for _, f := range paths.GlobalFragments {
+				fmt.Printf("Global: %s (fragment)\n", f)
+			}
 			fmt.Printf("Global: %s", paths.GlobalFile)
 			if fileExists(paths.GlobalFile) {
 				fmt.Println(" (exists)")
@@ -45,6 +104,30 @@ var whichCmd = &cobra.Command{
 				fmt.Println(" (not found)")
 			}
 
+			if paths.HostFile != "" {
+				fmt.Printf("Host:   %s", paths.HostFile)
+				if paths.HasHost {
+					fmt.Println(" (exists)")
+				} else {
+					fmt.Println(" (not found)")
+				}
+			}
+
+			if profile != "" {
+				fmt.Printf("Profile: %s", profile)
+				if fileExists(profile) {
+					fmt.Println(" (exists)")
+				} else {
+					fmt.Println(" (not found)")
+				}
+				if rule != "" {
+					fmt.Printf("         selected by %s\n", rule)
+				}
+			}
+
+			for _, f := range paths.LocalFragments {
+				fmt.Printf("Local:  %s (fragment)\n", f)
+			}
 			if paths.HasLocal {
 				fmt.Printf("Local:  %s (active override)\n", paths.LocalFile)
 			} else if paths.LocalDir != "" {
@@ -62,3 +145,139 @@ func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
+
+// printWhichFile reports the single file --file selects in place of
+// global/local resolution.
+func printWhichFile(path string) error {
+	if path == stdinFile {
+		path = "stdin"
+	}
+	switch resolveFormat() {
+	case "json":
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"file":        path,
+			"file_exists": fileExists(path),
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	default: // table
+		fmt.Printf("File:   %s", path)
+		if fileExists(path) {
+			fmt.Println(" (exists, global/local not used)")
+		} else {
+			fmt.Println(" (not found)")
+		}
+	}
+	return nil
+}
+
+// layerReport is one row of which --explain.
+type layerReport struct {
+	Layer  string `json:"layer"`
+	Source string `json:"source"`
+	Exists bool   `json:"exists"`
+	Fields int    `json:"fields"` // fields the layer defines
+	Used   int    `json:"used"`   // fields of the merged result it supplies
+	Note   string `json:"note,omitempty"`
+}
+
+// explainLayers prints a layerReport for each file loadDB considers, in
+// load order, followed by the environment and --set layers. A load error
+// is reported after the rows so the trace still shows where it came from.
+func explainLayers() error {
+	var files []candidateFile
+	switch {
+	case flagFile == stdinFile:
+		files = []candidateFile{{Layer: "file", Path: "stdin", Exists: true}}
+	case flagFile != "":
+		files = []candidateFile{{Layer: "file", Path: flagFile, Exists: fileExists(flagFile)}}
+	default:
+		var err error
+		if files, err = candidateFiles(); err != nil {
+			return err
+		}
+	}
+
+	db, loadErr := loadDB()
+	bySource := map[string]int{}
+	byLayer := map[string]int{}
+	if db != nil {
+		for _, f := range db.AllFields() {
+			bySource[f.Source]++
+			byLayer[f.Layer]++
+		}
+	}
+
+	var reports []layerReport
+	for _, f := range files {
+		r := layerReport{Layer: f.Layer, Source: f.Path, Exists: f.Exists, Used: bySource[f.Path], Note: f.Note}
+		switch {
+		case f.Path == "stdin":
+			// stdin has been read; what loaded is all there is.
+			r.Fields = r.Used
+		case f.Exists:
+			if fdb, err := store.LoadFile(f.Path); err != nil {
+				r.Note = err.Error()
+			} else {
+				r.Fields = len(fdb.AllFields())
+			}
+		}
+		reports = append(reports, r)
+	}
+
+	env := layerReport{Layer: "env", Source: "DEETS_<CATEGORY>_<KEY>", Fields: byLayer["env"], Used: byLayer["env"]}
+	switch {
+	case flagFromEnv:
+		env.Exists, env.Note = true, "--from-env"
+	case settings.Env.Override:
+		env.Exists, env.Note = true, "[env] override = true"
+	default:
+		env.Note = "enable with --from-env"
+	}
+	set := layerReport{Layer: "set", Source: "--set", Exists: len(flagOverride) > 0, Fields: len(flagOverride), Used: byLayer["set"]}
+	reports = append(reports, env, set)
+
+	switch resolveFormat() {
+	case "json":
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	default: // table
+		fmt.Print(formatLayerReports(reports))
+	}
+
+	if loadErr != nil {
+		return &ExitError{Code: 1, Message: loadErr.Error()}
+	}
+	return nil
+}
+
+// formatLayerReports renders reports as a table fitted to the terminal.
+func formatLayerReports(reports []layerReport) string {
+	cols := []model.Column{
+		{Header: "Layer"}, {Header: "Source"}, {Header: "Status"},
+		{Header: "Fields"}, {Header: "Used"}, {Header: "Note", Shrink: true},
+	}
+	var rows [][]string
+	for _, r := range reports {
+		source, status := r.Source, "loaded"
+		if source == "" {
+			source = "-"
+		}
+		switch {
+		case r.Layer == "env" || r.Layer == "set":
+			status = "on"
+			if !r.Exists {
+				status = "off"
+			}
+		case !r.Exists:
+			status = "not found"
+		}
+		rows = append(rows, []string{r.Layer, source, status, fmt.Sprint(r.Fields), fmt.Sprint(r.Used), r.Note})
+	}
+	return model.FormatColumns(cols, rows, tableWidth())
+}
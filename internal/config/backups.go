@@ -0,0 +1,16 @@
+package config
+
+import "path/filepath"
+
+// BackupsDirName is the name of the automatic backup directory inside the
+// global deets directory.
+const BackupsDirName = "backups"
+
+// BackupsDir returns the path to ~/.deets/backups/.
+func BackupsDir() string {
+	dir := GlobalDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, BackupsDirName)
+}
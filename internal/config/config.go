@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const (
@@ -11,28 +12,53 @@ const (
 
 	// FileName is the name of the data file.
 	FileName = "me.toml"
+
+	// ConfDirName is the name of the directory of fragment files inside a
+	// global or local deets directory.
+	ConfDirName = "conf.d"
+
+	// EnvHome overrides the global directory.
+	EnvHome = "DEETS_HOME"
+
+	// EnvGlobalFile overrides the global data file.
+	EnvGlobalFile = "DEETS_GLOBAL_FILE"
+
+	// EnvLocalFile overrides the local data file, replacing the walk up
+	// from the working directory.
+	EnvLocalFile = "DEETS_LOCAL_FILE"
 )
 
 // Paths holds the resolved paths for global and local deets directories.
 type Paths struct {
 	GlobalDir  string // path to ~/.deets/
 	GlobalFile string // path to ~/.deets/me.toml
+	HostFile   string // path to ~/.deets/me.<host>.toml (empty if the host is unknown)
+	HasHost    bool   // whether the host file exists
 	LocalDir   string // path to local .deets/ (empty if not found)
 	LocalFile  string // path to local .deets/me.toml (empty if not found)
 	HasLocal   bool   // whether a local override exists
+
+	GlobalFragments []string // ~/.deets/conf.d/*.toml, in load order
+	LocalFragments  []string // local .deets/conf.d/*.toml, in load order
 }
 
-// GlobalDir returns the path to ~/.deets/.
+// GlobalDir returns the path to the global deets directory: ~/.deets/, or
+// on Windows %APPDATA%\deets\ unless a %USERPROFILE%\.deets\ from an earlier
+// version exists. $DEETS_HOME overrides it when set. It returns an empty
+// string if it cannot be determined.
 func GlobalDir() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return ""
+	if dir := os.Getenv(EnvHome); dir != "" {
+		return dir
 	}
-	return filepath.Join(home, DirName)
+	return globalDir()
 }
 
-// GlobalFile returns the path to ~/.deets/me.toml.
+// GlobalFile returns the path to ~/.deets/me.toml, or $DEETS_GLOBAL_FILE
+// when set.
 func GlobalFile() string {
+	if file := os.Getenv(EnvGlobalFile); file != "" {
+		return file
+	}
 	dir := GlobalDir()
 	if dir == "" {
 		return ""
@@ -40,13 +66,48 @@ func GlobalFile() string {
 	return filepath.Join(dir, FileName)
 }
 
+// HostName returns the name of this machine as used in host file names:
+// the first label of its host name, in lower case, such as "laptop" for
+// Laptop.local. It returns an empty string if the host name is unknown.
+func HostName() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	name, _, _ = strings.Cut(name, ".")
+	return strings.ToLower(name)
+}
+
+// HostFile returns the path to the host-specific file ~/.deets/me.<host>.toml,
+// which overrides the global file on this machine. It returns an empty
+// string if the host name or global directory is unknown.
+func HostFile() string {
+	host, dir := HostName(), GlobalDir()
+	if host == "" || dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, strings.TrimSuffix(FileName, ".toml")+"."+host+".toml")
+}
+
 // FindLocalDir walks up from the current working directory looking for a
 // .deets/ directory. It stops at the user's home directory or the filesystem
-// root. Returns an empty string if no .deets/ directory is found.
+// root, which on Windows is a drive root such as C:\ or a UNC share root
+// such as \\server\share\. If no home directory is set (e.g., in
+// containers), the walk continues to the root. The global directory is
+// never taken for a local one. Returns an empty string if no .deets/
+// directory is found. When $DEETS_LOCAL_FILE is set there is no walk: the
+// directory containing that file is returned if it exists.
 func FindLocalDir() string {
+	if file := os.Getenv(EnvLocalFile); file != "" {
+		if dir := filepath.Dir(file); isDir(dir) {
+			return dir
+		}
+		return ""
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return ""
+		home = ""
 	}
 
 	cwd, err := os.Getwd()
@@ -57,16 +118,16 @@ func FindLocalDir() string {
 	dir := cwd
 	for {
 		// Stop before checking the home directory — ~/.deets/ is the global store.
-		if dir == home {
+		if home != "" && samePath(dir, home, foldCase) {
 			break
 		}
 
 		candidate := filepath.Join(dir, DirName)
-		info, err := os.Stat(candidate)
-		if err == nil && info.IsDir() {
+		if isDir(candidate) && !samePath(candidate, GlobalDir(), foldCase) {
 			return candidate
 		}
 
+		// filepath.Dir leaves a drive or UNC share root unchanged.
 		parent := filepath.Dir(dir)
 		if parent == dir {
 			// Reached filesystem root.
@@ -80,14 +141,18 @@ func FindLocalDir() string {
 
 // FindLocalFile returns the path to me.toml inside the local .deets/ directory
 // found by FindLocalDir. Returns an empty string if no local directory is found
-// or if me.toml does not exist inside it.
+// or if me.toml does not exist inside it. When $DEETS_LOCAL_FILE is set, it
+// is returned if it exists.
 func FindLocalFile() string {
-	localDir := FindLocalDir()
-	if localDir == "" {
-		return ""
+	file := os.Getenv(EnvLocalFile)
+	if file == "" {
+		localDir := FindLocalDir()
+		if localDir == "" {
+			return ""
+		}
+		file = filepath.Join(localDir, FileName)
 	}
 
-	file := filepath.Join(localDir, FileName)
 	info, err := os.Stat(file)
 	if err != nil || info.IsDir() {
 		return ""
@@ -95,44 +160,86 @@ func FindLocalFile() string {
 	return file
 }
 
+// Fragments returns the *.toml files in the conf.d/ directory inside the
+// deets directory dir, in lexical order, which is the order they are
+// loaded in. It returns nil when dir is empty or has no fragments.
+func Fragments(dir string) []string {
+	if dir == "" {
+		return nil
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, ConfDirName, "*.toml"))
+	var files []string
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil && !info.IsDir() {
+			files = append(files, m)
+		}
+	}
+	return files
+}
+
 // ResolvePaths resolves all deets paths and populates a Paths struct.
-// Returns an error only if the home directory cannot be determined.
+// Returns an error only if the global file cannot be determined because no
+// home directory is set and neither $DEETS_HOME nor $DEETS_GLOBAL_FILE is.
 func ResolvePaths() (Paths, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
+	if GlobalFile() == "" {
+		_, err := os.UserHomeDir()
 		return Paths{}, err
 	}
 
 	p := Paths{
-		GlobalDir:  filepath.Join(home, DirName),
-		GlobalFile: filepath.Join(home, DirName, FileName),
+		GlobalDir:       GlobalDir(),
+		GlobalFile:      GlobalFile(),
+		HostFile:        HostFile(),
+		GlobalFragments: Fragments(GlobalDir()),
+	}
+	if info, err := os.Stat(p.HostFile); err == nil && !info.IsDir() {
+		p.HasHost = true
 	}
 
 	p.LocalDir = FindLocalDir()
 	if p.LocalDir != "" {
 		p.LocalFile = FindLocalFile()
 		p.HasLocal = p.LocalFile != ""
+		p.LocalFragments = Fragments(p.LocalDir)
 	}
 
 	return p, nil
 }
 
-// EnsureGlobalDir creates ~/.deets/ if it does not already exist.
+// EnsureGlobalDir creates the global directory, and the directory holding
+// $DEETS_GLOBAL_FILE if that is elsewhere, if they do not already exist.
 func EnsureGlobalDir() error {
 	dir := GlobalDir()
 	if dir == "" {
 		_, err := os.UserHomeDir()
 		return err
 	}
-	return os.MkdirAll(dir, 0755)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.MkdirAll(filepath.Dir(GlobalFile()), 0755)
 }
 
-// EnsureLocalDir creates .deets/ in the current working directory if it does
+// LocalTargetFile returns the local file that local writes go to:
+// $DEETS_LOCAL_FILE when set, otherwise .deets/me.toml in the current
+// working directory.
+func LocalTargetFile() (string, error) {
+	if file := os.Getenv(EnvLocalFile); file != "" {
+		return file, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cwd, DirName, FileName), nil
+}
+
+// EnsureLocalDir creates the directory holding LocalTargetFile if it does
 // not already exist.
 func EnsureLocalDir() error {
-	cwd, err := os.Getwd()
+	file, err := LocalTargetFile()
 	if err != nil {
 		return err
 	}
-	return os.MkdirAll(filepath.Join(cwd, DirName), 0755)
+	return os.MkdirAll(filepath.Dir(file), 0755)
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -185,6 +186,25 @@ func TestFindLocalDir_StopsAtHomeDir(t *testing.T) {
 	}
 }
 
+func TestFindLocalDir_NoHome(t *testing.T) {
+	tmp := t.TempDir()
+	deetsDir := filepath.Join(tmp, DirName)
+	if err := os.Mkdir(deetsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(tmp, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, sub)
+	t.Setenv("HOME", "")
+
+	got := FindLocalDir()
+	if got != deetsDir {
+		t.Errorf("FindLocalDir() without HOME = %q, want %q", got, deetsDir)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // FindLocalFile
 // ---------------------------------------------------------------------------
@@ -391,6 +411,182 @@ func TestEnsureLocalDir_Idempotent(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// HostFile
+// ---------------------------------------------------------------------------
+
+func TestHostFile(t *testing.T) {
+	host := HostName()
+	if host == "" {
+		t.Skip("host name unknown")
+	}
+	if strings.ContainsAny(host, ".ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		t.Errorf("HostName() = %q, want a lower-case first label", host)
+	}
+
+	dir := t.TempDir()
+	t.Setenv(EnvHome, dir)
+	t.Setenv(EnvGlobalFile, "")
+	want := filepath.Join(dir, "me."+host+".toml")
+	if got := HostFile(); got != want {
+		t.Errorf("HostFile() = %q, want %q", got, want)
+	}
+
+	p, err := ResolvePaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.HostFile != want || p.HasHost {
+		t.Errorf("ResolvePaths() host = %q, %v; want %q, false", p.HostFile, p.HasHost, want)
+	}
+	if err := os.WriteFile(want, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := ResolvePaths(); !p.HasHost {
+		t.Error("ResolvePaths() HasHost = false after creating the host file")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Fragments
+// ---------------------------------------------------------------------------
+
+func TestFragments(t *testing.T) {
+	dir := t.TempDir()
+	if got := Fragments(dir); got != nil {
+		t.Errorf("Fragments() without conf.d = %v, want nil", got)
+	}
+	if got := Fragments(""); got != nil {
+		t.Errorf("Fragments(\"\") = %v, want nil", got)
+	}
+
+	conf := filepath.Join(dir, ConfDirName)
+	if err := os.MkdirAll(filepath.Join(conf, "nested.toml"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"social.toml", "academic.toml", "10-base.toml", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(conf, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := Fragments(dir)
+	want := []string{
+		filepath.Join(conf, "10-base.toml"),
+		filepath.Join(conf, "academic.toml"),
+		filepath.Join(conf, "social.toml"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Fragments() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Fragments()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Environment overrides
+// ---------------------------------------------------------------------------
+
+func TestEnvHome_OverridesGlobalPaths(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	t.Setenv(EnvHome, dir)
+	t.Setenv(EnvGlobalFile, "")
+
+	if got := GlobalDir(); got != dir {
+		t.Errorf("GlobalDir() = %q, want %q", got, dir)
+	}
+	if got, want := GlobalFile(), filepath.Join(dir, FileName); got != want {
+		t.Errorf("GlobalFile() = %q, want %q", got, want)
+	}
+	if got, want := SettingsFile(), filepath.Join(dir, SettingsFileName); got != want {
+		t.Errorf("SettingsFile() = %q, want %q", got, want)
+	}
+}
+
+func TestEnvGlobalFile_OverridesGlobalFile(t *testing.T) {
+	tmp := t.TempDir()
+	home := filepath.Join(tmp, "home")
+	file := filepath.Join(tmp, "elsewhere", "me.toml")
+	t.Setenv(EnvHome, home)
+	t.Setenv(EnvGlobalFile, file)
+
+	if got := GlobalFile(); got != file {
+		t.Errorf("GlobalFile() = %q, want %q", got, file)
+	}
+	if got := GlobalDir(); got != home {
+		t.Errorf("GlobalDir() = %q, want %q", got, home)
+	}
+
+	if err := EnsureGlobalDir(); err != nil {
+		t.Fatalf("EnsureGlobalDir() error: %v", err)
+	}
+	for _, dir := range []string{home, filepath.Dir(file)} {
+		if !isDir(dir) {
+			t.Errorf("%q was not created by EnsureGlobalDir", dir)
+		}
+	}
+}
+
+func TestEnvLocalFile_ReplacesWalk(t *testing.T) {
+	tmp := t.TempDir()
+	// A .deets/ in the working directory is ignored in favour of the override.
+	if err := os.Mkdir(filepath.Join(tmp, DirName), 0755); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, tmp)
+
+	dir := filepath.Join(tmp, "ci")
+	file := filepath.Join(dir, "project.toml")
+	t.Setenv(EnvLocalFile, file)
+
+	if got := FindLocalDir(); got != "" {
+		t.Errorf("FindLocalDir() = %q, want empty before %q exists", got, dir)
+	}
+	if got, err := LocalTargetFile(); err != nil || got != file {
+		t.Errorf("LocalTargetFile() = %q, %v; want %q", got, err, file)
+	}
+
+	if err := EnsureLocalDir(); err != nil {
+		t.Fatalf("EnsureLocalDir() error: %v", err)
+	}
+	if got := FindLocalDir(); got != dir {
+		t.Errorf("FindLocalDir() = %q, want %q", got, dir)
+	}
+	if got := FindLocalFile(); got != "" {
+		t.Errorf("FindLocalFile() = %q, want empty before the file exists", got)
+	}
+
+	if err := os.WriteFile(file, []byte("# test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := ResolvePaths()
+	if err != nil {
+		t.Fatalf("ResolvePaths() error: %v", err)
+	}
+	if !p.HasLocal || p.LocalFile != file || p.LocalDir != dir {
+		t.Errorf("ResolvePaths() = %+v, want local file %q", p, file)
+	}
+}
+
+func TestResolvePaths_NoHomeWithEnvHome(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", "")
+	t.Setenv("USERPROFILE", "")
+	t.Setenv(EnvHome, dir)
+	t.Setenv(EnvGlobalFile, "")
+
+	p, err := ResolvePaths()
+	if err != nil {
+		t.Fatalf("ResolvePaths() error: %v", err)
+	}
+	if want := filepath.Join(dir, FileName); p.GlobalFile != want {
+		t.Errorf("GlobalFile = %q, want %q", p.GlobalFile, want)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Helpers
 // ---------------------------------------------------------------------------
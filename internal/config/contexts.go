@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ContextsFileName is the name of the file inside the global deets
+// directory that picks a profile by working directory or git remote.
+const ContextsFileName = "contexts.toml"
+
+// ContextsFile returns the path to ~/.deets/contexts.toml.
+func ContextsFile() string {
+	dir := GlobalDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, ContextsFileName)
+}
+
+// ContextRule selects Profile when deets runs inside Dir, or in a git
+// repository with a remote matching Remote. Each rule sets exactly one of
+// Dir and Remote.
+//
+// Example:
+//
+//	[[rule]]
+//	dir = "~/work"
+//	profile = "work"
+//
+//	[[rule]]
+//	remote = "github.com/acme/*"
+//	profile = "work"
+type ContextRule struct {
+	// Dir is a directory; the rule matches in it and below. A leading "~"
+	// stands for the home directory.
+	Dir string `toml:"dir"`
+
+	// Remote is a path.Match pattern for a git remote URL, written without
+	// scheme, user, or ".git" suffix, e.g. "github.com/acme/*".
+	Remote string `toml:"remote"`
+
+	// Profile names the profile to use.
+	Profile string `toml:"profile"`
+}
+
+// String describes the rule's condition, e.g. `dir = "~/work"`.
+func (r ContextRule) String() string {
+	if r.Dir != "" {
+		return fmt.Sprintf("dir = %q", r.Dir)
+	}
+	return fmt.Sprintf("remote = %q", r.Remote)
+}
+
+// LoadContexts reads the rules of a contexts file, in order. A missing
+// file has none.
+func LoadContexts(file string) ([]ContextRule, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", file, err)
+	}
+	var doc struct {
+		Rules []ContextRule `toml:"rule"`
+	}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+	for i, r := range doc.Rules {
+		if (r.Dir == "") == (r.Remote == "") {
+			return nil, fmt.Errorf("%s: rule %d: set exactly one of dir and remote", file, i+1)
+		}
+		if err := CheckProfileName(r.Profile); err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", file, i+1, err)
+		}
+		if _, err := path.Match(r.Remote, ""); err != nil {
+			return nil, fmt.Errorf("%s: rule %d: bad remote pattern %q", file, i+1, r.Remote)
+		}
+	}
+	return doc.Rules, nil
+}
+
+// MatchContext returns the index of the first rule matching the working
+// directory dir, or -1 if none does. remotes lists the git remote URLs of
+// dir; it is only called if a remote rule is reached.
+func MatchContext(rules []ContextRule, dir string, remotes func() []string) int {
+	home, _ := os.UserHomeDir()
+	var urls []string
+	fetched := false
+	for i, r := range rules {
+		if r.Dir != "" {
+			if underDir(dir, expandHome(r.Dir, home)) {
+				return i
+			}
+			continue
+		}
+		if !fetched {
+			urls, fetched = remotes(), true
+		}
+		for _, u := range urls {
+			if ok, _ := path.Match(r.Remote, NormalizeRemote(u)); ok {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// expandHome replaces a leading "~" in dir with home.
+func expandHome(dir, home string) string {
+	if home != "" && (dir == "~" || strings.HasPrefix(dir, "~/") || strings.HasPrefix(dir, `~\`)) {
+		return filepath.Join(home, dir[1:])
+	}
+	return dir
+}
+
+// underDir reports whether dir is root or inside it.
+func underDir(dir, root string) bool {
+	dir, root = filepath.Clean(dir), filepath.Clean(root)
+	for {
+		if samePath(dir, root, foldCase) {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// NormalizeRemote reduces a git remote URL to host/path form, so
+// "git@github.com:acme/app.git" and "https://user@github.com/acme/app"
+// both become "github.com/acme/app".
+func NormalizeRemote(url string) string {
+	if _, rest, ok := strings.Cut(url, "://"); ok {
+		url = rest
+	} else if host, rest, ok := strings.Cut(url, ":"); ok && !strings.Contains(host, "/") {
+		// scp-like syntax: [user@]host:path
+		url = host + "/" + rest
+	}
+	if at := strings.LastIndex(url, "@"); at >= 0 && at < strings.Index(url+"/", "/") {
+		url = url[at+1:]
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeRemote(t *testing.T) {
+	cases := map[string]string{
+		"git@github.com:acme/app.git":          "github.com/acme/app",
+		"https://github.com/acme/app.git":      "github.com/acme/app",
+		"https://user@github.com/acme/app/":    "github.com/acme/app",
+		"ssh://git@gitlab.example:22/team/app": "gitlab.example:22/team/app",
+		"/srv/git/app.git":                     "/srv/git/app",
+	}
+	for in, want := range cases {
+		if got := NormalizeRemote(in); got != want {
+			t.Errorf("NormalizeRemote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMatchContext(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	rules := []ContextRule{
+		{Dir: "~/work", Profile: "work"},
+		{Remote: "github.com/acme/*", Profile: "acme"},
+		{Dir: filepath.Join(home, "personal"), Profile: "personal"},
+	}
+	remotes := func(urls ...string) func() []string {
+		return func() []string { return urls }
+	}
+
+	cases := []struct {
+		name string
+		dir  string
+		urls []string
+		want int
+	}{
+		{"dir itself", filepath.Join(home, "work"), nil, 0},
+		{"below dir", filepath.Join(home, "work", "app", "src"), nil, 0},
+		{"sibling prefix", filepath.Join(home, "workshop"), nil, -1},
+		{"remote", filepath.Join(home, "src"), []string{"git@github.com:acme/app.git"}, 1},
+		{"other remote", filepath.Join(home, "src"), []string{"git@github.com:other/app.git"}, -1},
+		{"absolute dir", filepath.Join(home, "personal", "notes"), nil, 2},
+		{"first rule wins", filepath.Join(home, "work"), []string{"git@github.com:acme/app.git"}, 0},
+	}
+	for _, c := range cases {
+		if got := MatchContext(rules, c.dir, remotes(c.urls...)); got != c.want {
+			t.Errorf("%s: MatchContext = %d, want %d", c.name, got, c.want)
+		}
+	}
+}
+
+func TestMatchContext_RemotesOnlyWhenNeeded(t *testing.T) {
+	called := false
+	remotes := func() []string {
+		called = true
+		return nil
+	}
+	dir := t.TempDir()
+	MatchContext([]ContextRule{{Dir: dir, Profile: "work"}, {Remote: "*", Profile: "x"}}, dir, remotes)
+	if called {
+		t.Error("remotes were listed although a dir rule matched first")
+	}
+}
+
+func TestLoadContexts(t *testing.T) {
+	dir := t.TempDir()
+	if rules, err := LoadContexts(filepath.Join(dir, "missing.toml")); err != nil || rules != nil {
+		t.Errorf("missing file: rules = %v, err = %v; want none", rules, err)
+	}
+
+	write := func(content string) string {
+		path := filepath.Join(dir, ContextsFileName)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	rules, err := LoadContexts(write("[[rule]]\ndir = \"~/work\"\nprofile = \"work\"\n\n[[rule]]\nremote = \"github.com/acme/*\"\nprofile = \"acme\"\n"))
+	if err != nil {
+		t.Fatalf("LoadContexts: %v", err)
+	}
+	if len(rules) != 2 || rules[0].Profile != "work" || rules[1].Remote != "github.com/acme/*" {
+		t.Errorf("LoadContexts = %+v", rules)
+	}
+	if got := rules[0].String(); got != `dir = "~/work"` {
+		t.Errorf("String() = %q", got)
+	}
+
+	for content, want := range map[string]string{
+		"[[rule]]\nprofile = \"work\"\n":                               "exactly one",
+		"[[rule]]\ndir = \"/a\"\nremote = \"b\"\nprofile = \"work\"\n": "exactly one",
+		"[[rule]]\ndir = \"/a\"\nprofile = \"../x\"\n":                 "invalid profile",
+		"[[rule]]\nremote = \"[\"\nprofile = \"work\"\n":               "bad remote",
+		"[[rule]\n": "parsing",
+	} {
+		if _, err := LoadContexts(write(content)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadContexts(%q): err = %v, want %q", content, err, want)
+		}
+	}
+}
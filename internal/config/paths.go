@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WindowsDirName is the name of the global deets directory inside
+// %APPDATA% on Windows.
+const WindowsDirName = "deets"
+
+// windowsGlobalDir picks the global directory on Windows: a legacy
+// %USERPROFILE%\.deets that already exists keeps being used, and otherwise
+// %APPDATA%\deets, falling back to the home directory when APPDATA is
+// unset. isDir reports whether a path is an existing directory.
+func windowsGlobalDir(home, appData string, isDir func(string) bool) string {
+	legacy := ""
+	if home != "" {
+		legacy = filepath.Join(home, DirName)
+		if isDir(legacy) {
+			return legacy
+		}
+	}
+	if appData != "" {
+		return filepath.Join(appData, WindowsDirName)
+	}
+	return legacy
+}
+
+// samePath reports whether a and b name the same directory once cleaned,
+// ignoring case where the file system does (foldCase).
+func samePath(a, b string, foldCase bool) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if foldCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// isDir reports whether path is an existing directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// foldCase is false because paths are compared byte for byte.
+const foldCase = false
+
+// globalDir returns ~/.deets.
+func globalDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, DirName)
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWindowsGlobalDir(t *testing.T) {
+	home := filepath.Join("Users", "alex")
+	appData := filepath.Join("Users", "alex", "AppData", "Roaming")
+	none := func(string) bool { return false }
+	legacy := func(p string) bool { return p == filepath.Join(home, DirName) }
+
+	cases := []struct {
+		name          string
+		home, appData string
+		isDir         func(string) bool
+		want          string
+	}{
+		{"appdata", home, appData, none, filepath.Join(appData, WindowsDirName)},
+		{"legacy dir honored", home, appData, legacy, filepath.Join(home, DirName)},
+		{"no appdata", home, "", none, filepath.Join(home, DirName)},
+		{"no home", "", appData, none, filepath.Join(appData, WindowsDirName)},
+		{"neither", "", "", none, ""},
+	}
+	for _, c := range cases {
+		if got := windowsGlobalDir(c.home, c.appData, c.isDir); got != c.want {
+			t.Errorf("%s: windowsGlobalDir = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestSamePath(t *testing.T) {
+	if !samePath("/home/alex/", "/home/alex", false) {
+		t.Error("trailing separator should not matter")
+	}
+	if samePath("/home/Alex", "/home/alex", false) {
+		t.Error("case should matter without foldCase")
+	}
+	if !samePath("/home/Alex", "/home/alex", true) {
+		t.Error("case should not matter with foldCase")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package config
+
+import "os"
+
+// foldCase is true because Windows paths are case-insensitive.
+const foldCase = true
+
+// globalDir returns %APPDATA%\deets, or %USERPROFILE%\.deets when that
+// directory already exists from an earlier version.
+func globalDir() string {
+	home, _ := os.UserHomeDir()
+	return windowsGlobalDir(home, os.Getenv("APPDATA"), isDir)
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ProfilesDirName is the name of the profile directory inside the global
+// deets directory.
+const ProfilesDirName = "profiles"
+
+// ProfilesDir returns the path to ~/.deets/profiles/.
+func ProfilesDir() string {
+	dir := GlobalDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, ProfilesDirName)
+}
+
+// ProfileFile returns the path of the named profile, e.g.
+// ~/.deets/profiles/work.toml.
+func ProfileFile(name string) string {
+	dir := ProfilesDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, name+".toml")
+}
+
+// CheckProfileName rejects profile names that would escape the profile
+// directory or be hidden in it.
+func CheckProfileName(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// SettingsFileName is the name of the CLI settings file inside the global
+// deets directory. It holds preferences, not metadata.
+const SettingsFileName = "config.toml"
+
+// SchemaFileName is the name of the optional file inside the global deets
+// directory that constrains the categories, keys, and values of me.toml.
+const SchemaFileName = "schema.toml"
+
+// Settings holds user preferences read from ~/.deets/config.toml.
+//
+// Example:
+//
+//	[formats]
+//	export = "env"
+//	show = "table"
+//	get = "bare"
+//
+//	[jsonresume]
+//	"identity.city" = "basics.location.city"
+//
+//	[lint]
+//	disable = ["long-line"]
+//
+//	[env]
+//	override = true
+type Settings struct {
+	// Formats maps a command name to its default output format. It overrides
+	// the TTY heuristic but not an explicit --format flag.
+	Formats map[string]string `toml:"formats"`
+
+	// JSONResume maps extra "category.key" paths to dotted JSON Resume paths
+	// for --format jsonresume.
+	JSONResume map[string]string `toml:"jsonresume"`
+
+	// Lint configures deets lint.
+	Lint LintSettings `toml:"lint"`
+
+	// Env configures overrides from DEETS_* environment variables.
+	Env EnvSettings `toml:"env"`
+}
+
+// LintSettings holds the [lint] table of the settings file.
+type LintSettings struct {
+	// Disable names lint rules that are never run.
+	Disable []string `toml:"disable"`
+}
+
+// EnvSettings holds the [env] table of the settings file.
+type EnvSettings struct {
+	// Override makes DEETS_<CATEGORY>_<KEY> environment variables override
+	// loaded fields, as --from-env does.
+	Override bool `toml:"override"`
+}
+
+// SettingsFile returns the path to ~/.deets/config.toml.
+func SettingsFile() string {
+	dir := GlobalDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, SettingsFileName)
+}
+
+// SchemaFile returns the path to ~/.deets/schema.toml.
+func SchemaFile() string {
+	dir := GlobalDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, SchemaFileName)
+}
+
+// LoadSettings reads the settings file. A missing file is not an error and
+// yields zero-value Settings.
+func LoadSettings() (Settings, error) {
+	var s Settings
+	path := SettingsFile()
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := toml.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// FormatFor returns the configured default format for the named command,
+// or an empty string if none is set.
+func (s Settings) FormatFor(command string) string {
+	return s.Formats[command]
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSettingsFile_InGlobalDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	expected := filepath.Join(home, DirName, SettingsFileName)
+	if got := SettingsFile(); got != expected {
+		t.Errorf("SettingsFile() = %q, want %q", got, expected)
+	}
+}
+
+func TestLoadSettings_MissingFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	s, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings() error: %v", err)
+	}
+	if s.FormatFor("export") != "" {
+		t.Errorf("expected no format for missing settings, got %q", s.FormatFor("export"))
+	}
+}
+
+func TestLoadSettings_Formats(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeSettings(t, home, "[formats]\nexport = \"env\"\nget = \"bare\"\n")
+
+	s, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings() error: %v", err)
+	}
+	if s.FormatFor("export") != "env" {
+		t.Errorf("FormatFor(export) = %q, want env", s.FormatFor("export"))
+	}
+	if s.FormatFor("get") != "bare" {
+		t.Errorf("FormatFor(get) = %q, want bare", s.FormatFor("get"))
+	}
+	if s.FormatFor("show") != "" {
+		t.Errorf("FormatFor(show) = %q, want empty", s.FormatFor("show"))
+	}
+}
+
+func TestLoadSettings_Malformed(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeSettings(t, home, "[formats\n")
+
+	if _, err := LoadSettings(); err == nil {
+		t.Error("expected error for malformed settings file")
+	}
+}
+
+// writeSettings writes content to the settings file under home.
+func writeSettings(t *testing.T, home, content string) {
+	t.Helper()
+	dir := filepath.Join(home, DirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, SettingsFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSettings_Lint(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeSettings(t, home, "[lint]\ndisable = [\"long-line\"]\n")
+
+	s, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings() error: %v", err)
+	}
+	if len(s.Lint.Disable) != 1 || s.Lint.Disable[0] != "long-line" {
+		t.Errorf("Lint.Disable = %v, want [long-line]", s.Lint.Disable)
+	}
+}
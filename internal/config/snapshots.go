@@ -0,0 +1,16 @@
+package config
+
+import "path/filepath"
+
+// SnapshotsDirName is the name of the named snapshot directory inside the
+// global deets directory.
+const SnapshotsDirName = "snapshots"
+
+// SnapshotsDir returns the path to ~/.deets/snapshots/.
+func SnapshotsDir() string {
+	dir := GlobalDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, SnapshotsDirName)
+}
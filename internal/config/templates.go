@@ -0,0 +1,26 @@
+package config
+
+import "path/filepath"
+
+// TemplatesDirName is the name of the user template directory inside the
+// global deets directory.
+const TemplatesDirName = "templates"
+
+// TemplatesDir returns the path to ~/.deets/templates/.
+func TemplatesDir() string {
+	dir := GlobalDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, TemplatesDirName)
+}
+
+// TemplateFile returns the path of the user template with the given name,
+// e.g. ~/.deets/templates/humans.txt.
+func TemplateFile(name string) string {
+	dir := TemplatesDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, name)
+}
@@ -0,0 +1,153 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Author is the person described by the database, in the shape used by
+// software citation formats such as CITATION.cff and codemeta.json.
+type Author struct {
+	GivenNames  string
+	FamilyNames string
+	Email       string
+	ORCID       string // full https://orcid.org/ URL
+	Affiliation string
+}
+
+// Author builds an Author from identity.name, contact.email, academic.orcid,
+// and academic.institution, or when that is unset the organization of the
+// first current employment.positions record (one with no end, or an end of
+// "present"). The last word of the name is taken as the family name and the
+// rest as given names.
+func (db *DB) Author() Author {
+	var a Author
+	a.GivenNames, a.FamilyNames = splitName(fieldString(db, "identity.name"))
+	a.Email = fieldString(db, "contact.email")
+	if orcid := fieldString(db, "academic.orcid"); orcid != "" {
+		if !isURL(orcid) {
+			orcid = "https://orcid.org/" + orcid
+		}
+		a.ORCID = orcid
+	}
+	a.Affiliation = fieldString(db, "academic.institution")
+	if a.Affiliation == "" {
+		for _, p := range db.Positions() {
+			if p.Organization != "" && (p.End == "" || strings.EqualFold(p.End, "present")) {
+				a.Affiliation = p.Organization
+				break
+			}
+		}
+	}
+	return a
+}
+
+// splitName splits a display name into given names and a family name.
+// A single-word name is returned as the family name.
+func splitName(name string) (given, family string) {
+	parts := strings.Fields(name)
+	switch len(parts) {
+	case 0:
+		return "", ""
+	case 1:
+		return "", parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], " "), parts[len(parts)-1]
+}
+
+// ORCIDID returns the bare ORCID identifier, without the https://orcid.org/
+// prefix.
+func (a Author) ORCIDID() string {
+	return strings.TrimPrefix(a.ORCID, "https://orcid.org/")
+}
+
+// FormatCitationCFF renders the database's Author as a CITATION.cff authors
+// block. If title is non-empty, a complete minimal CITATION.cff is produced
+// instead, with cff-version, message, and title preceding the authors.
+//
+//	authors:
+//	  - family-names: Towell
+//	    given-names: Alexander
+//	    orcid: "https://orcid.org/0000-0001-2345-6789"
+func FormatCitationCFF(db *DB, title string) string {
+	var b strings.Builder
+	if title != "" {
+		b.WriteString("cff-version: 1.2.0\n")
+		b.WriteString("message: \"If you use this software, please cite it as below.\"\n")
+		fmt.Fprintf(&b, "title: %s\n", yamlScalar(title))
+	}
+	a := db.Author()
+	b.WriteString("authors:\n")
+	marker := "  - "
+	line := func(key, value string) {
+		if value == "" {
+			return
+		}
+		fmt.Fprintf(&b, "%s%s: %s\n", marker, key, yamlScalar(value))
+		marker = "    "
+	}
+	line("family-names", a.FamilyNames)
+	line("given-names", a.GivenNames)
+	line("email", a.Email)
+	line("orcid", a.ORCID)
+	line("affiliation", a.Affiliation)
+	if marker == "  - " {
+		b.WriteString("  - name: \"\"\n")
+	}
+	return b.String()
+}
+
+// BibName returns the author's name in BibTeX "Family, Given" form.
+func (a Author) BibName() string {
+	if a.GivenNames == "" {
+		return a.FamilyNames
+	}
+	return a.FamilyNames + ", " + a.GivenNames
+}
+
+// FormatLaTeX renders the database's Author as a LaTeX fragment meant to be
+// \input from a paper template. It defines \deets* macros for each piece of
+// author metadata, including \deetsbibname in BibTeX "Family, Given" form,
+// followed by ready-made \author{} (with \orcidlink{}) and \affiliation{}
+// lines. Values are escaped for LaTeX.
+func FormatLaTeX(db *DB) string {
+	a := db.Author()
+	name := strings.TrimSpace(a.GivenNames + " " + a.FamilyNames)
+	orcid := a.ORCIDID()
+
+	var b strings.Builder
+	b.WriteString("% Generated by deets generate latex\n")
+	macro := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "\\newcommand{\\%s}{%s}\n", name, latexEscape(value))
+		}
+	}
+	macro("deetsname", name)
+	macro("deetsbibname", a.BibName())
+	macro("deetsemail", a.Email)
+	macro("deetsorcid", orcid)
+	macro("deetsaffiliation", a.Affiliation)
+
+	if name != "" {
+		b.WriteString("\n\\author{" + latexEscape(name))
+		if orcid != "" {
+			b.WriteString("\\,\\orcidlink{" + latexEscape(orcid) + "}")
+		}
+		b.WriteString("}\n")
+	}
+	if a.Affiliation != "" {
+		fmt.Fprintf(&b, "\\affiliation{%s}\n", latexEscape(a.Affiliation))
+	}
+	return b.String()
+}
+
+// latexEscape escapes the LaTeX special characters in s.
+func latexEscape(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\textbackslash{}`,
+		"&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`,
+		"{", `\{`, "}", `\}`,
+		"~", `\textasciitilde{}`, "^", `\textasciicircum{}`,
+	)
+	return r.Replace(s)
+}
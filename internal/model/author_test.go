@@ -0,0 +1,119 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func newAuthorDB() *DB {
+	return &DB{Categories: []Category{
+		{Name: "identity", Fields: []Field{{Key: "name", Value: "Alexander Towell", Category: "identity"}}},
+		{Name: "contact", Fields: []Field{{Key: "email", Value: "alex@example.com", Category: "contact"}}},
+		{Name: "academic", Fields: []Field{
+			{Key: "orcid", Value: "0000-0001-2345-6789", Category: "academic"},
+			{Key: "institution", Value: "SIUE", Category: "academic"},
+		}},
+	}}
+}
+
+func TestAuthor(t *testing.T) {
+	a := newAuthorDB().Author()
+	want := Author{
+		GivenNames:  "Alexander",
+		FamilyNames: "Towell",
+		Email:       "alex@example.com",
+		ORCID:       "https://orcid.org/0000-0001-2345-6789",
+		Affiliation: "SIUE",
+	}
+	if a != want {
+		t.Errorf("Author() = %+v, want %+v", a, want)
+	}
+}
+
+func TestAuthor_AffiliationFromPosition(t *testing.T) {
+	db := &DB{Categories: []Category{
+		{Name: "identity", Fields: []Field{{Key: "name", Value: "Alex Towell", Category: "identity"}}},
+		{Name: "employment", Fields: []Field{{Key: "positions", Category: "employment", Value: []map[string]interface{}{
+			{"title": "Intern", "organization": "Old Corp", "start": "2015", "end": "2016"},
+			{"title": "Engineer", "organization": "Example Corp", "start": "2019"},
+		}}}},
+	}}
+	if got := db.Author().Affiliation; got != "Example Corp" {
+		t.Errorf("Affiliation = %q, want the current position's organization", got)
+	}
+	if out := FormatCitationCFF(db, ""); !strings.Contains(out, "    affiliation: Example Corp\n") {
+		t.Errorf("expected affiliation in CITATION.cff, got:\n%s", out)
+	}
+}
+
+func TestSplitName(t *testing.T) {
+	tests := []struct{ in, given, family string }{
+		{"", "", ""},
+		{"Cher", "", "Cher"},
+		{"Mary Ann Evans", "Mary Ann", "Evans"},
+	}
+	for _, tt := range tests {
+		given, family := splitName(tt.in)
+		if given != tt.given || family != tt.family {
+			t.Errorf("splitName(%q) = %q, %q; want %q, %q", tt.in, given, family, tt.given, tt.family)
+		}
+	}
+}
+
+func TestFormatCitationCFF_AuthorsBlock(t *testing.T) {
+	out := FormatCitationCFF(newAuthorDB(), "")
+	want := `authors:
+  - family-names: Towell
+    given-names: Alexander
+    email: "alex@example.com"
+    orcid: "https://orcid.org/0000-0001-2345-6789"
+    affiliation: SIUE
+`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestFormatCitationCFF_WithTitle(t *testing.T) {
+	out := FormatCitationCFF(newAuthorDB(), "deets: personal metadata")
+	if !strings.HasPrefix(out, "cff-version: 1.2.0\nmessage: ") {
+		t.Errorf("expected cff-version header, got:\n%s", out)
+	}
+	if !strings.Contains(out, "title: \"deets: personal metadata\"\nauthors:\n") {
+		t.Errorf("expected quoted title before authors, got:\n%s", out)
+	}
+}
+
+func TestBibName(t *testing.T) {
+	if got := (Author{GivenNames: "Mary Ann", FamilyNames: "Evans"}).BibName(); got != "Evans, Mary Ann" {
+		t.Errorf("BibName() = %q", got)
+	}
+	if got := (Author{FamilyNames: "Cher"}).BibName(); got != "Cher" {
+		t.Errorf("BibName() = %q", got)
+	}
+}
+
+func TestFormatLaTeX(t *testing.T) {
+	out := FormatLaTeX(newAuthorDB())
+	want := `% Generated by deets generate latex
+\newcommand{\deetsname}{Alexander Towell}
+\newcommand{\deetsbibname}{Towell, Alexander}
+\newcommand{\deetsemail}{alex@example.com}
+\newcommand{\deetsorcid}{0000-0001-2345-6789}
+\newcommand{\deetsaffiliation}{SIUE}
+
+\author{Alexander Towell\,\orcidlink{0000-0001-2345-6789}}
+\affiliation{SIUE}
+`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestLatexEscape(t *testing.T) {
+	got := latexEscape(`R&D_lab 100% {x} ~a^b \z`)
+	want := `R\&D\_lab 100\% \{x\} \textasciitilde{}a\textasciicircum{}b \textbackslash{}z`
+	if got != want {
+		t.Errorf("latexEscape = %q, want %q", got, want)
+	}
+}
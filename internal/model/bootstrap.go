@@ -0,0 +1,352 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Readers for `deets init --from`. Each maps another tool's profile data
+// onto the well-known deets fields, mirroring the FormatJSONResume and
+// FormatVCard mappings in reverse.
+
+// SourceKind guesses the format of an existing profile file from its name
+// and content: "jsonresume", "vcard", "gitconfig", or "" if unknown.
+func SourceKind(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "jsonresume"
+	case ".vcf", ".vcard":
+		return "vcard"
+	}
+	if base := filepath.Base(path); base == ".gitconfig" || base == "config" && filepath.Base(filepath.Dir(path)) == "git" {
+		return "gitconfig"
+	}
+
+	text := strings.TrimSpace(string(data))
+	switch {
+	case strings.HasPrefix(text, "{"):
+		return "jsonresume"
+	case strings.HasPrefix(strings.ToUpper(text), "BEGIN:VCARD"):
+		return "vcard"
+	case strings.Contains(text, "[user]"):
+		return "gitconfig"
+	}
+	return ""
+}
+
+// ParseSource reads data of the given kind (see SourceKind) into a DB.
+func ParseSource(kind string, data []byte) (*DB, error) {
+	switch kind {
+	case "jsonresume":
+		return ParseJSONResume(data)
+	case "vcard":
+		return ParseVCard(data)
+	case "gitconfig":
+		return ParseGitConfig(data)
+	}
+	return nil, fmt.Errorf("unknown source format %q: expected jsonresume, vcard, or gitconfig", kind)
+}
+
+// dbBuilder accumulates fields in insertion order, creating categories as
+// they are first used and skipping empty values.
+type dbBuilder struct {
+	db *DB
+}
+
+func (b *dbBuilder) set(category, key string, v interface{}) {
+	switch val := v.(type) {
+	case string:
+		if val == "" {
+			return
+		}
+	case []interface{}:
+		if len(val) == 0 {
+			return
+		}
+	case []map[string]interface{}:
+		if len(val) == 0 {
+			return
+		}
+	}
+	for i := range b.db.Categories {
+		c := &b.db.Categories[i]
+		if c.Name != category {
+			continue
+		}
+		for _, f := range c.Fields {
+			if f.Key == key {
+				return // first value wins
+			}
+		}
+		c.Fields = append(c.Fields, Field{Category: category, Key: key, Value: v})
+		return
+	}
+	b.db.Categories = append(b.db.Categories, Category{
+		Name:   category,
+		Fields: []Field{{Category: category, Key: key, Value: v}},
+	})
+}
+
+// setLink stores a profile under [web], as a bare username for networks
+// deets knows how to link (see profileURLs) and as given otherwise. ORCID
+// iDs go to academic.orcid.
+func (b *dbBuilder) setLink(network, username, url string) {
+	network = strings.ToLower(strings.TrimSpace(network))
+	if network == "orcid" || strings.Contains(url, "orcid.org/") {
+		if username == "" {
+			username = url[strings.LastIndex(url, "/")+1:]
+		}
+		b.set("academic", "orcid", username)
+		return
+	}
+	if network == "" {
+		network, username = networkFromURL(url)
+	}
+	if network == "" {
+		b.set("web", "website", url)
+		return
+	}
+	if username == "" && url != "" {
+		if n, u := networkFromURL(url); n == network {
+			username = u
+		}
+	}
+	if username != "" {
+		b.set("web", network, username)
+	} else {
+		b.set("web", network, url)
+	}
+}
+
+// networkFromURL recognizes profile URLs of the networks in profileURLs.
+func networkFromURL(url string) (network, username string) {
+	trimmed := strings.TrimSuffix(strings.Replace(url, "http://", "https://", 1), "/")
+	for n, prefix := range profileURLs {
+		if strings.HasPrefix(trimmed, prefix) && len(trimmed) > len(prefix) {
+			return n, trimmed[len(prefix):]
+		}
+	}
+	return "", ""
+}
+
+// ParseJSONResume reads a JSON Resume document (https://jsonresume.org):
+// basics to identity, contact, and web fields, education to
+// education.degrees, work to employment.positions, and interests to
+// academic.research_interests.
+func ParseJSONResume(data []byte) (*DB, error) {
+	type profile struct {
+		Network  string `json:"network"`
+		Username string `json:"username"`
+		URL      string `json:"url"`
+	}
+	var doc struct {
+		Basics struct {
+			Name     string    `json:"name"`
+			Label    string    `json:"label"`
+			Email    string    `json:"email"`
+			Phone    string    `json:"phone"`
+			URL      string    `json:"url"`
+			Summary  string    `json:"summary"`
+			Profiles []profile `json:"profiles"`
+		} `json:"basics"`
+		Education []struct {
+			Institution string `json:"institution"`
+			Area        string `json:"area"`
+			StudyType   string `json:"studyType"`
+			EndDate     string `json:"endDate"`
+		} `json:"education"`
+		Work []struct {
+			Name      string `json:"name"`
+			Position  string `json:"position"`
+			StartDate string `json:"startDate"`
+			EndDate   string `json:"endDate"`
+		} `json:"work"`
+		Interests []struct {
+			Name string `json:"name"`
+		} `json:"interests"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing JSON Resume: %w", err)
+	}
+
+	b := &dbBuilder{db: &DB{}}
+	b.set("identity", "name", doc.Basics.Name)
+	b.set("identity", "summary", doc.Basics.Summary)
+	b.set("contact", "email", doc.Basics.Email)
+	b.set("contact", "phone", doc.Basics.Phone)
+	b.set("web", "website", doc.Basics.URL)
+	for _, p := range doc.Basics.Profiles {
+		b.setLink(p.Network, p.Username, p.URL)
+	}
+	b.set("academic", "title", doc.Basics.Label)
+
+	var interests []interface{}
+	for _, i := range doc.Interests {
+		if i.Name != "" {
+			interests = append(interests, i.Name)
+		}
+	}
+	b.set("academic", "research_interests", interests)
+
+	var degrees []map[string]interface{}
+	for _, e := range doc.Education {
+		degrees = append(degrees, record("degree", e.StudyType, "field", e.Area, "institution", e.Institution, "year", e.EndDate))
+	}
+	b.set("education", "degrees", degrees)
+
+	var positions []map[string]interface{}
+	for _, w := range doc.Work {
+		positions = append(positions, record("title", w.Position, "organization", w.Name, "start", w.StartDate, "end", w.EndDate))
+	}
+	b.set("employment", "positions", positions)
+
+	return b.db, nil
+}
+
+// record builds a table from key/value pairs, leaving out empty values.
+func record(pairs ...string) map[string]interface{} {
+	rec := map[string]interface{}{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			rec[pairs[i]] = pairs[i+1]
+		}
+	}
+	return rec
+}
+
+// ParseVCard reads the first card of a vCard file (RFC 6350, or the older
+// 3.0): FN to identity.name, NICKNAME to identity.aka, EMAIL and TEL to
+// contact.email and contact.phone (then email_2, phone_2, ...), URL to
+// web fields or academic.orcid, and ORG and TITLE to academic.institution
+// and academic.title.
+func ParseVCard(data []byte) (*DB, error) {
+	// Unfold continuation lines, which begin with a space or tab.
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.NewReplacer("\n ", "", "\n\t", "").Replace(text)
+
+	b := &dbBuilder{db: &DB{}}
+	counts := map[string]int{}
+	numbered := func(category, key, value string) {
+		counts[key]++
+		if counts[key] > 1 {
+			key = fmt.Sprintf("%s_%d", key, counts[key])
+		}
+		b.set(category, key, value)
+	}
+
+	inCard := false
+	for _, line := range strings.Split(text, "\n") {
+		nameParams, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(nameParams, ";")
+		// Drop a group prefix such as "item1.EMAIL".
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		name = strings.ToUpper(name)
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			inCard = true
+			continue
+		case name == "END" && strings.EqualFold(value, "VCARD"):
+			if inCard {
+				return b.db, nil
+			}
+		}
+		if !inCard {
+			continue
+		}
+
+		switch name {
+		case "FN":
+			b.set("identity", "name", vcardUnescape(value))
+		case "NICKNAME":
+			var aka []interface{}
+			for _, nick := range vcardSplit(value, ',') {
+				if nick != "" {
+					aka = append(aka, nick)
+				}
+			}
+			b.set("identity", "aka", aka)
+		case "EMAIL":
+			numbered("contact", "email", vcardUnescape(value))
+		case "TEL":
+			numbered("contact", "phone", strings.TrimPrefix(vcardUnescape(value), "tel:"))
+		case "URL":
+			b.setLink("", "", vcardUnescape(value))
+		case "ORG":
+			b.set("academic", "institution", vcardSplit(value, ';')[0])
+		case "TITLE":
+			b.set("academic", "title", vcardUnescape(value))
+		}
+	}
+	if !inCard {
+		return nil, fmt.Errorf("parsing vCard: no BEGIN:VCARD found")
+	}
+	return b.db, nil
+}
+
+// vcardSplit splits a vCard value on unescaped sep and unescapes each part.
+func vcardSplit(value string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, vcardUnescape(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, vcardUnescape(value[start:]))
+}
+
+// vcardUnescape reverses vcardText.
+func vcardUnescape(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+// ParseGitConfig reads a git config file: user.name and user.email to
+// identity.name and contact.email, and github.user to web.github.
+func ParseGitConfig(data []byte) (*DB, error) {
+	b := &dbBuilder{db: &DB{}}
+	section := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			section = strings.ToLower(strings.Trim(line, "[] \t"))
+			continue
+		}
+		key, value, _ := strings.Cut(line, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if i := strings.IndexAny(value, "#;"); i >= 0 && !strings.HasPrefix(value, `"`) {
+			value = strings.TrimSpace(value[:i])
+		}
+		value = strings.Trim(value, `"`)
+
+		switch section + "." + key {
+		case "user.name":
+			b.set("identity", "name", value)
+		case "user.email":
+			b.set("contact", "email", value)
+		case "github.user":
+			b.set("web", "github", value)
+		case "gitlab.user":
+			b.set("web", "gitlab", value)
+		}
+	}
+	if len(b.db.Categories) == 0 {
+		return nil, fmt.Errorf("parsing git config: no user.name, user.email, or github.user found")
+	}
+	return b.db, nil
+}
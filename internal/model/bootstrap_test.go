@@ -0,0 +1,133 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSourceKind(t *testing.T) {
+	tests := []struct {
+		path, data, want string
+	}{
+		{"resume.json", "", "jsonresume"},
+		{"me.vcf", "", "vcard"},
+		{"/home/a/.gitconfig", "", "gitconfig"},
+		{"/home/a/.config/git/config", "", "gitconfig"},
+		{"export", "  {\"basics\": {}}", "jsonresume"},
+		{"export", "BEGIN:VCARD\r\nEND:VCARD", "vcard"},
+		{"export", "[user]\n\tname = A", "gitconfig"},
+		{"notes.txt", "hello", ""},
+	}
+	for _, tt := range tests {
+		if got := SourceKind(tt.path, []byte(tt.data)); got != tt.want {
+			t.Errorf("SourceKind(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseJSONResume(t *testing.T) {
+	db, err := ParseJSONResume([]byte(`{
+	  "basics": {
+	    "name": "Alex Towell", "label": "PhD Student", "email": "alex@example.com",
+	    "url": "https://example.com",
+	    "profiles": [
+	      {"network": "GitHub", "username": "queelius"},
+	      {"network": "ORCID", "url": "https://orcid.org/0000-0001-6443-9897"},
+	      {"network": "Mastodon", "url": "https://fosstodon.org/@alex"}
+	    ]
+	  },
+	  "education": [{"institution": "SIUE", "area": "Statistics", "studyType": "MS", "endDate": "2019"}],
+	  "work": [{"name": "SIUE", "position": "TA", "startDate": "2017"}],
+	  "interests": [{"name": "statistics"}, {"name": "ml"}]
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checks := map[string]interface{}{
+		"identity.name":               "Alex Towell",
+		"contact.email":               "alex@example.com",
+		"web.website":                 "https://example.com",
+		"web.github":                  "queelius",
+		"web.mastodon":                "https://fosstodon.org/@alex",
+		"academic.orcid":              "0000-0001-6443-9897",
+		"academic.title":              "PhD Student",
+		"academic.research_interests": []interface{}{"statistics", "ml"},
+		"education.degrees":           []map[string]interface{}{{"degree": "MS", "field": "Statistics", "institution": "SIUE", "year": "2019"}},
+		"employment.positions":        []map[string]interface{}{{"title": "TA", "organization": "SIUE", "start": "2017"}},
+	}
+	for path, want := range checks {
+		f, ok := db.GetField(path)
+		if !ok {
+			t.Errorf("%s missing", path)
+			continue
+		}
+		if !reflect.DeepEqual(f.Value, want) {
+			t.Errorf("%s = %#v, want %#v", path, f.Value, want)
+		}
+	}
+	if f, ok := db.GetField("contact.phone"); ok {
+		t.Errorf("empty fields should be skipped, got contact.phone = %v", f.Value)
+	}
+
+	if _, err := ParseJSONResume([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestParseVCard(t *testing.T) {
+	card := "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex Towell\r\nNICKNAME:Lex,Alex T\r\n" +
+		"EMAIL;TYPE=work:alex@example.com\r\nitem1.EMAIL:alt@example.com\r\nTEL:tel:+1-555-0100\r\n" +
+		"URL:https://github.com/queelius\r\nURL:https://orcid.org/0000-0001-6443-9897\r\n" +
+		"URL:https://example.com\r\nORG:Southern Illinois\\, Edwardsville;Statistics\r\n" +
+		"TITLE:Graduate Researcher with a very long\r\n  title\r\nEND:VCARD\r\nBEGIN:VCARD\r\nFN:Other\r\nEND:VCARD\r\n"
+	db, err := ParseVCard([]byte(card))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checks := map[string]interface{}{
+		"identity.name":        "Alex Towell",
+		"identity.aka":         []interface{}{"Lex", "Alex T"},
+		"contact.email":        "alex@example.com",
+		"contact.email_2":      "alt@example.com",
+		"contact.phone":        "+1-555-0100",
+		"web.github":           "queelius",
+		"web.website":          "https://example.com",
+		"academic.orcid":       "0000-0001-6443-9897",
+		"academic.institution": "Southern Illinois, Edwardsville",
+		"academic.title":       "Graduate Researcher with a very long title",
+	}
+	for path, want := range checks {
+		f, ok := db.GetField(path)
+		if !ok {
+			t.Errorf("%s missing", path)
+			continue
+		}
+		if !reflect.DeepEqual(f.Value, want) {
+			t.Errorf("%s = %#v, want %#v", path, f.Value, want)
+		}
+	}
+
+	if _, err := ParseVCard([]byte("FN:Nobody")); err == nil {
+		t.Error("expected error without BEGIN:VCARD")
+	}
+}
+
+func TestParseGitConfig(t *testing.T) {
+	db, err := ParseGitConfig([]byte("[core]\n\teditor = vim\n[user]\n\tname = \"Alex Towell\"\n\temail = alex@example.com ; work\n[github]\n\tuser = queelius\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for path, want := range map[string]string{
+		"identity.name": "Alex Towell",
+		"contact.email": "alex@example.com",
+		"web.github":    "queelius",
+	} {
+		if f, _ := db.GetField(path); f.Value != want {
+			t.Errorf("%s = %v, want %q", path, f.Value, want)
+		}
+	}
+
+	if _, err := ParseGitConfig([]byte("[core]\n\teditor = vim\n")); err == nil {
+		t.Error("expected error when no identity is configured")
+	}
+}
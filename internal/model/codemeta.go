@@ -0,0 +1,149 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// CodemetaContext is the JSON-LD context written to new codemeta.json files.
+const CodemetaContext = "https://w3id.org/codemeta/3.0"
+
+// CodemetaPerson renders an Author as a schema.org Person node as used in
+// codemeta.json author and maintainer lists.
+func CodemetaPerson(a Author) map[string]interface{} {
+	p := map[string]interface{}{"@type": "Person"}
+	putString(p, "@id", a.ORCID)
+	putString(p, "givenName", a.GivenNames)
+	putString(p, "familyName", a.FamilyNames)
+	putString(p, "email", a.Email)
+	if a.Affiliation != "" {
+		p["affiliation"] = organization(a.Affiliation)
+	}
+	return p
+}
+
+// MergeCodemeta adds the Author to the author and maintainer lists of a
+// codemeta.json document and returns the updated document. An existing entry
+// for the same person (matched by @id, email, or name) is replaced in place;
+// otherwise the person is appended. All other keys, and their order, are
+// preserved. If existing is empty, a new minimal document is created.
+func MergeCodemeta(existing []byte, a Author) ([]byte, error) {
+	person := CodemetaPerson(a)
+
+	var keys []string
+	values := map[string]json.RawMessage{}
+	if len(bytes.TrimSpace(existing)) == 0 {
+		keys = []string{"@context", "@type"}
+		values["@context"], _ = json.Marshal(CodemetaContext)
+		values["@type"], _ = json.Marshal("SoftwareSourceCode")
+	} else {
+		var err error
+		keys, values, err = decodeOrderedObject(existing)
+		if err != nil {
+			return nil, fmt.Errorf("parsing codemeta.json: %w", err)
+		}
+	}
+
+	for _, key := range []string{"author", "maintainer"} {
+		var list []interface{}
+		if raw, ok := values[key]; ok {
+			var v interface{}
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("parsing codemeta.json %s: %w", key, err)
+			}
+			switch val := v.(type) {
+			case []interface{}:
+				list = val
+			case nil:
+			default:
+				list = []interface{}{val}
+			}
+		} else {
+			keys = append(keys, key)
+		}
+		list = upsertPerson(list, person)
+		raw, err := json.Marshal(list)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = raw
+	}
+
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		name, _ := json.Marshal(k)
+		b.Write(name)
+		b.WriteString(":")
+		b.Write(values[k])
+	}
+	b.WriteString("}")
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, b.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteString("\n")
+	return out.Bytes(), nil
+}
+
+// upsertPerson replaces the entry in list describing the same person as p,
+// or appends p if there is none.
+func upsertPerson(list []interface{}, p map[string]interface{}) []interface{} {
+	for i, item := range list {
+		m, ok := item.(map[string]interface{})
+		if ok && samePerson(m, p) {
+			list[i] = p
+			return list
+		}
+	}
+	return append(list, p)
+}
+
+// samePerson reports whether two Person nodes share an @id, an email, or a
+// full name.
+func samePerson(a, b map[string]interface{}) bool {
+	for _, key := range []string{"@id", "email"} {
+		if av, ok := a[key].(string); ok && av != "" && av == b[key] {
+			return true
+		}
+	}
+	ag, _ := a["givenName"].(string)
+	af, _ := a["familyName"].(string)
+	return af != "" && ag == b["givenName"] && af == b["familyName"]
+}
+
+// decodeOrderedObject decodes a JSON object into its keys, in document
+// order, and their raw values.
+func decodeOrderedObject(data []byte) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return nil, nil, fmt.Errorf("expected a JSON object")
+	}
+	var keys []string
+	values := map[string]json.RawMessage{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		if _, dup := values[key]; !dup {
+			keys = append(keys, key)
+		}
+		values[key] = raw
+	}
+	return keys, values, nil
+}
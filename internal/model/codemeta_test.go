@@ -0,0 +1,95 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMergeCodemeta_New(t *testing.T) {
+	out, err := MergeCodemeta(nil, newAuthorDB().Author())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if doc["@context"] != CodemetaContext || doc["@type"] != "SoftwareSourceCode" {
+		t.Errorf("unexpected header: %v", doc)
+	}
+	authors := doc["author"].([]interface{})
+	p := authors[0].(map[string]interface{})
+	if p["@id"] != "https://orcid.org/0000-0001-2345-6789" || p["familyName"] != "Towell" {
+		t.Errorf("unexpected author: %v", p)
+	}
+	if aff := p["affiliation"].(map[string]interface{}); aff["name"] != "SIUE" {
+		t.Errorf("unexpected affiliation: %v", aff)
+	}
+	if len(doc["maintainer"].([]interface{})) != 1 {
+		t.Error("expected one maintainer")
+	}
+}
+
+func TestMergeCodemeta_PreservesOtherKeys(t *testing.T) {
+	existing := `{
+  "name": "deets",
+  "version": "1.0",
+  "author": [
+    {"@type": "Person", "givenName": "Ada", "familyName": "Lovelace"},
+    {"@type": "Person", "email": "alex@example.com", "givenName": "Old"}
+  ],
+  "keywords": ["metadata"]
+}`
+	out, err := MergeCodemeta([]byte(existing), newAuthorDB().Author())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := string(out)
+	if strings.Index(s, `"name"`) > strings.Index(s, `"version"`) || strings.Index(s, `"author"`) > strings.Index(s, `"keywords"`) {
+		t.Errorf("expected key order to be preserved:\n%s", s)
+	}
+	if !strings.HasSuffix(s, "\"maintainer\": [\n    {\n      \"@id\": \"https://orcid.org/0000-0001-2345-6789\",\n      \"@type\": \"Person\",\n      \"affiliation\": {\n        \"@type\": \"Organization\",\n        \"name\": \"SIUE\"\n      },\n      \"email\": \"alex@example.com\",\n      \"familyName\": \"Towell\",\n      \"givenName\": \"Alexander\"\n    }\n  ]\n}\n") {
+		t.Errorf("expected maintainer appended at the end:\n%s", s)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	authors := doc["author"].([]interface{})
+	if len(authors) != 2 {
+		t.Fatalf("expected co-author kept and matching author replaced, got %v", authors)
+	}
+	if authors[0].(map[string]interface{})["givenName"] != "Ada" {
+		t.Error("co-author should be untouched")
+	}
+	if authors[1].(map[string]interface{})["givenName"] != "Alexander" {
+		t.Error("matching author should be replaced")
+	}
+	if doc["version"] != "1.0" {
+		t.Error("non-author keys should be preserved")
+	}
+}
+
+func TestMergeCodemeta_SingleAuthorObject(t *testing.T) {
+	existing := `{"author": {"@type": "Person", "givenName": "Ada", "familyName": "Lovelace"}}`
+	out, err := MergeCodemeta([]byte(existing), newAuthorDB().Author())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc map[string]interface{}
+	json.Unmarshal(out, &doc)
+	if len(doc["author"].([]interface{})) != 2 {
+		t.Errorf("expected single author object to become a list of two, got %v", doc["author"])
+	}
+}
+
+func TestMergeCodemeta_Invalid(t *testing.T) {
+	if _, err := MergeCodemeta([]byte(`[1, 2]`), Author{}); err == nil {
+		t.Error("expected error for non-object document")
+	}
+	if _, err := MergeCodemeta([]byte(`{"author": `), Author{}); err == nil {
+		t.Error("expected error for truncated document")
+	}
+}
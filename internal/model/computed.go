@@ -0,0 +1,229 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// refPath matches the field path in a placeholder or reference: a category,
+// then dotted keys that may index an array, as in "education.degrees[0].year".
+const refPath = `[A-Za-z0-9_-]+\.[A-Za-z0-9_.-]+(?:\[-?[0-9]+\][A-Za-z0-9_.-]*)*`
+
+// templateRef matches a {{category.key}} placeholder inside a string value.
+// Whitespace around the path is allowed: {{ identity.name }}. Braces around
+// anything else, such as a Go template's {{.Name}}, are left alone. A
+// leading backslash, captured as the first group, escapes the placeholder.
+var templateRef = regexp.MustCompile(`(\\?)\{\{\s*(` + refPath + `)\s*\}\}`)
+
+// fieldRef matches a whole-value reference such as "@contact.email".
+var fieldRef = regexp.MustCompile(`^@(` + refPath + `)$`)
+
+// RefTarget returns the "category.key" path named by a reference value such
+// as "@contact.email", and false if v is not a reference.
+func RefTarget(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return "", false
+	}
+	m := fieldRef.FindStringSubmatch(s)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// IsTemplate reports whether a value contains at least one {{category.key}}
+// placeholder, escaped or not, and should therefore be evaluated at read
+// time.
+func IsTemplate(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && templateRef.MatchString(s)
+}
+
+// TemplateRefs returns the paths named by the unescaped placeholders in a
+// string value, in order.
+func TemplateRefs(v interface{}) []string {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	var refs []string
+	for _, m := range templateRef.FindAllStringSubmatch(s, -1) {
+		if m[1] == "" {
+			refs = append(refs, m[2])
+		}
+	}
+	return refs
+}
+
+// Resolve evaluates every computed field in the database in place. A computed
+// field is either:
+//
+//   - a string value containing {{category.key}} placeholders, each of which
+//     is replaced with the formatted value of the referenced field, or
+//   - a reference of the form "@category.key", which takes on the referenced
+//     field's value unchanged (arrays stay arrays).
+//
+// Resolved fields have Computed set to true. A reference whose target does
+// not exist is left as a literal string, so handles like "@someone" are
+// unaffected; a leading "@@" escapes a literal "@", and "\{{" a literal
+// "{{".
+//
+// Computed fields may refer to other computed fields; evaluation follows the
+// chain and returns an error if a cycle is detected or a placeholder names a
+// field that does not exist.
+func (db *DB) Resolve() error {
+	r := &resolver{
+		db:       db,
+		done:     make(map[string]bool),
+		visiting: make(map[string]bool),
+	}
+	for ci := range db.Categories {
+		for fi := range db.Categories[ci].Fields {
+			f := db.Categories[ci].Fields[fi]
+			if IsDescKey(f.Key) {
+				continue
+			}
+			if err := r.resolve(f.Category + "." + f.Key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolver carries the evaluation state for DB.Resolve.
+type resolver struct {
+	db       *DB
+	done     map[string]bool
+	visiting map[string]bool
+	stack    []string
+}
+
+// field returns a pointer to the field at path so it can be updated in place.
+func (r *resolver) field(path string) *Field {
+	parts := strings.SplitN(path, ".", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	for ci := range r.db.Categories {
+		cat := &r.db.Categories[ci]
+		if cat.Name != parts[0] {
+			continue
+		}
+		for fi := range cat.Fields {
+			if cat.Fields[fi].Key == parts[1] {
+				return &cat.Fields[fi]
+			}
+		}
+	}
+	return nil
+}
+
+// lookup resolves the top-level field holding path and then reads path
+// through DB.GetField, so references reach into nested tables and array
+// elements ("education.phd.institution", "web.links[0]") exactly as get
+// does. Description keys are never valid targets.
+func (r *resolver) lookup(path string) (Field, bool, error) {
+	if cat, key, ok := strings.Cut(path, "."); ok {
+		if i := strings.IndexAny(key, ".["); i != -1 {
+			key = key[:i]
+		}
+		if err := r.resolve(cat + "." + key); err != nil {
+			return Field{}, false, err
+		}
+	}
+	f, ok := r.db.GetField(path)
+	if !ok || IsDescKey(f.Key) {
+		return Field{}, false, nil
+	}
+	return f, true, nil
+}
+
+// resolve evaluates the field at path, first resolving any fields it depends on.
+func (r *resolver) resolve(path string) error {
+	if r.done[path] {
+		return nil
+	}
+	if r.visiting[path] {
+		cycle := append(r.stack[indexOf(r.stack, path):], path)
+		return fmt.Errorf("cycle in computed fields: %s", strings.Join(cycle, " -> "))
+	}
+
+	f := r.field(path)
+	if f == nil {
+		return nil
+	}
+
+	r.visiting[path] = true
+	r.stack = append(r.stack, path)
+	defer func() {
+		r.stack = r.stack[:len(r.stack)-1]
+		delete(r.visiting, path)
+		r.done[path] = true
+	}()
+
+	if s, ok := f.Value.(string); ok && strings.HasPrefix(s, "@@") {
+		f.Value = s[1:]
+		return nil
+	}
+
+	if ref, ok := RefTarget(f.Value); ok {
+		target, ok, err := r.lookup(ref)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		f.Value = target.Value
+		f.Computed = true
+		return nil
+	}
+
+	if !IsTemplate(f.Value) {
+		return nil
+	}
+
+	var evalErr error
+	computed := false
+	out := templateRef.ReplaceAllStringFunc(f.Value.(string), func(m string) string {
+		if evalErr != nil {
+			return m
+		}
+		sub := templateRef.FindStringSubmatch(m)
+		if sub[1] != "" {
+			return m[len(sub[1]):]
+		}
+		ref := sub[2]
+		target, ok, err := r.lookup(ref)
+		if err != nil {
+			evalErr = err
+			return m
+		}
+		if !ok {
+			evalErr = fmt.Errorf("computed field %s: unknown field %q", path, ref)
+			return m
+		}
+		computed = true
+		return FormatValue(target.Value)
+	})
+	if evalErr != nil {
+		return evalErr
+	}
+
+	f.Value = out
+	f.Computed = computed
+	return nil
+}
+
+// indexOf returns the position of s in list, or 0 if it is absent.
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return 0
+}
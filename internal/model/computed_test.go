@@ -0,0 +1,320 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsTemplate(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected bool
+	}{
+		{"{{identity.name}}", true},
+		{"{{ identity.name }} <{{contact.email}}>", true},
+		{"plain string", false},
+		{"{not a template}", false},
+		{"Use {{.Name}} in go templates", false},
+		{"{{ value .identity.name }}", false},
+		{`\{{identity.name}}`, true},
+		{int64(42), false},
+		{[]interface{}{"{{identity.name}}"}, false},
+	}
+	for _, tt := range tests {
+		if got := IsTemplate(tt.value); got != tt.expected {
+			t.Errorf("IsTemplate(%v) = %v, want %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestResolve_Template(t *testing.T) {
+	db := newTestDB()
+	db.Categories[0].Fields = append(db.Categories[0].Fields, Field{
+		Key: "signature", Value: "{{identity.name}} <{{ web.website }}>", Category: "identity",
+	})
+
+	if err := db.Resolve(); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+
+	f, ok := db.GetField("identity.signature")
+	if !ok {
+		t.Fatal("expected identity.signature to exist")
+	}
+	if f.Value != "Alexander Towell <https://example.com>" {
+		t.Errorf("unexpected resolved value: %q", f.Value)
+	}
+	if !f.Computed {
+		t.Error("expected resolved field to be marked computed")
+	}
+
+	name, _ := db.GetField("identity.name")
+	if name.Computed {
+		t.Error("plain field should not be marked computed")
+	}
+}
+
+func TestResolve_ArrayReference(t *testing.T) {
+	db := newTestDB()
+	db.Categories[0].Fields = append(db.Categories[0].Fields, Field{
+		Key: "aliases", Value: "aka: {{identity.aka}}", Category: "identity",
+	})
+
+	if err := db.Resolve(); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	f, _ := db.GetField("identity.aliases")
+	if f.Value != "aka: Alex Towell, Alex T" {
+		t.Errorf("unexpected resolved value: %q", f.Value)
+	}
+}
+
+func TestResolve_Chained(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "x",
+		Fields: []Field{
+			{Key: "a", Value: "[{{x.b}}]", Category: "x"},
+			{Key: "b", Value: "<{{x.c}}>", Category: "x"},
+			{Key: "c", Value: "leaf", Category: "x"},
+		},
+	}}}
+
+	if err := db.Resolve(); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	f, _ := db.GetField("x.a")
+	if f.Value != "[<leaf>]" {
+		t.Errorf("expected chained resolution, got %q", f.Value)
+	}
+}
+
+func TestResolve_Cycle(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "x",
+		Fields: []Field{
+			{Key: "a", Value: "{{x.b}}", Category: "x"},
+			{Key: "b", Value: "{{x.a}}", Category: "x"},
+		},
+	}}}
+
+	err := db.Resolve()
+	if err == nil {
+		t.Fatal("expected error for cyclic templates")
+	}
+	if !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+}
+
+func TestResolve_SelfReference(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name:   "x",
+		Fields: []Field{{Key: "a", Value: "{{x.a}}", Category: "x"}},
+	}}}
+
+	if err := db.Resolve(); err == nil {
+		t.Fatal("expected error for self-referencing template")
+	}
+}
+
+func TestResolve_UnknownField(t *testing.T) {
+	db := newTestDB()
+	db.Categories[0].Fields = append(db.Categories[0].Fields, Field{
+		Key: "broken", Value: "{{nope.missing}}", Category: "identity",
+	})
+
+	err := db.Resolve()
+	if err == nil {
+		t.Fatal("expected error for unknown template reference")
+	}
+	if !strings.Contains(err.Error(), "nope.missing") {
+		t.Errorf("expected error to name the missing field, got %v", err)
+	}
+}
+
+func TestResolve_NestedTemplate(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "education",
+		Fields: []Field{
+			{Key: "phd", Value: map[string]interface{}{"institution": "SIUE"}, Category: "education"},
+			{Key: "degrees", Value: []interface{}{map[string]interface{}{"year": int64(2015)}}, Category: "education"},
+			{Key: "summary", Value: "PhD, {{education.phd.institution}} ({{education.degrees[0].year}})", Category: "education"},
+		},
+	}}}
+
+	if err := db.Resolve(); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	f, _ := db.GetField("education.summary")
+	if f.Value != "PhD, SIUE (2015)" || !f.Computed {
+		t.Errorf("summary = %+v, want nested placeholders resolved", f)
+	}
+}
+
+func TestResolve_TemplateEscape(t *testing.T) {
+	db := newTestDB()
+	db.Categories[0].Fields = append(db.Categories[0].Fields,
+		Field{Key: "note", Value: "Use {{.Name}} in go templates", Category: "identity"},
+		Field{Key: "howto", Value: `write \{{identity.name}} for {{identity.name}}`, Category: "identity"},
+		Field{Key: "literal", Value: `\{{nope.missing}}`, Category: "identity"},
+	)
+
+	if err := db.Resolve(); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	for path, want := range map[string]string{
+		"identity.note":    "Use {{.Name}} in go templates",
+		"identity.howto":   "write {{identity.name}} for Alexander Towell",
+		"identity.literal": "{{nope.missing}}",
+	} {
+		f, _ := db.GetField(path)
+		if f.Value != want {
+			t.Errorf("%s = %q, want %q", path, f.Value, want)
+		}
+	}
+	if f, _ := db.GetField("identity.note"); f.Computed {
+		t.Error("value without placeholders should not be marked computed")
+	}
+	if f, _ := db.GetField("identity.literal"); f.Computed {
+		t.Error("value with only escaped placeholders should not be marked computed")
+	}
+}
+
+func TestTemplateRefs(t *testing.T) {
+	got := TemplateRefs(`{{identity.name}} {{ contact.email }} \{{web.github}} {{.Name}}`)
+	want := []string{"identity.name", "contact.email"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("TemplateRefs = %v, want %v", got, want)
+	}
+	if got := TemplateRefs(int64(1)); got != nil {
+		t.Errorf("TemplateRefs(non-string) = %v, want nil", got)
+	}
+}
+
+func TestRefTarget(t *testing.T) {
+	tests := []struct {
+		value  interface{}
+		target string
+		ok     bool
+	}{
+		{"@contact.email", "contact.email", true},
+		{"@web.github_url", "web.github_url", true},
+		{"@queelius@mastodon.social", "", false},
+		{"@handle", "", false},
+		{"contact.email", "", false},
+		{int64(1), "", false},
+	}
+	for _, tt := range tests {
+		target, ok := RefTarget(tt.value)
+		if target != tt.target || ok != tt.ok {
+			t.Errorf("RefTarget(%v) = (%q, %v), want (%q, %v)", tt.value, target, ok, tt.target, tt.ok)
+		}
+	}
+}
+
+func TestResolve_Reference(t *testing.T) {
+	db := newTestDB()
+	db.Categories[1].Fields = append(db.Categories[1].Fields,
+		Field{Key: "home", Value: "@web.website", Category: "web"},
+		Field{Key: "names", Value: "@identity.aka", Category: "web"},
+	)
+
+	if err := db.Resolve(); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+
+	home, _ := db.GetField("web.home")
+	if home.Value != "https://example.com" || !home.Computed {
+		t.Errorf("expected resolved computed reference, got %+v", home)
+	}
+
+	names, _ := db.GetField("web.names")
+	arr, ok := names.Value.([]interface{})
+	if !ok || len(arr) != 2 {
+		t.Errorf("expected reference to keep array type, got %#v", names.Value)
+	}
+}
+
+func TestResolve_NestedReference(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "education",
+		Fields: []Field{
+			{Key: "phd", Value: map[string]interface{}{"institution": "SIUE"}, Category: "education"},
+			{Key: "school", Value: "@education.phd.institution", Category: "education"},
+		},
+	}}}
+
+	if err := db.Resolve(); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	f, _ := db.GetField("education.school")
+	if f.Value != "SIUE" || !f.Computed {
+		t.Errorf("school = %+v, want the nested reference resolved", f)
+	}
+}
+
+func TestResolve_ReferenceToTemplate(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "x",
+		Fields: []Field{
+			{Key: "a", Value: "@x.b", Category: "x"},
+			{Key: "b", Value: "hello {{x.c}}", Category: "x"},
+			{Key: "c", Value: "world", Category: "x"},
+		},
+	}}}
+
+	if err := db.Resolve(); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	f, _ := db.GetField("x.a")
+	if f.Value != "hello world" {
+		t.Errorf("expected reference to see resolved template, got %q", f.Value)
+	}
+}
+
+func TestResolve_ReferenceMissingTargetIsLiteral(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name:   "web",
+		Fields: []Field{{Key: "twitter", Value: "@some.handle", Category: "web"}},
+	}}}
+
+	if err := db.Resolve(); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	f, _ := db.GetField("web.twitter")
+	if f.Value != "@some.handle" || f.Computed {
+		t.Errorf("expected literal value to be preserved, got %+v", f)
+	}
+}
+
+func TestResolve_ReferenceEscape(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "x",
+		Fields: []Field{
+			{Key: "a", Value: "@@x.b", Category: "x"},
+			{Key: "b", Value: "target", Category: "x"},
+		},
+	}}}
+
+	if err := db.Resolve(); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	f, _ := db.GetField("x.a")
+	if f.Value != "@x.b" || f.Computed {
+		t.Errorf("expected escaped literal, got %+v", f)
+	}
+}
+
+func TestResolve_ReferenceCycle(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "x",
+		Fields: []Field{
+			{Key: "a", Value: "@x.b", Category: "x"},
+			{Key: "b", Value: "@x.a", Category: "x"},
+		},
+	}}}
+
+	if err := db.Resolve(); err == nil {
+		t.Fatal("expected error for reference cycle")
+	}
+}
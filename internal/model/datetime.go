@@ -0,0 +1,107 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// TOML has four date/time types. The decoder returns all of them as
+// time.Time and marks the local ones with fixed zones named "date-local",
+// "datetime-local" and "time-local" (the zones themselves live in an
+// internal package). TimeKind and FormatTime go by those names to write
+// each value back in its original shape.
+var (
+	localDate     = time.FixedZone("date-local", 0)
+	localDatetime = time.FixedZone("datetime-local", 0)
+)
+
+// TimeKind returns the TOML type of t: "date", "time", or "datetime"
+// (with or without an offset).
+func TimeKind(t time.Time) string {
+	switch t.Location().String() {
+	case "date-local":
+		return "date"
+	case "time-local":
+		return "time"
+	}
+	return "datetime"
+}
+
+// FormatTime renders t as ISO 8601 in its TOML shape: 1990-05-01 for a
+// date, 1990-05-01T08:30:00 for a local datetime, an RFC 3339 timestamp
+// for an offset datetime, and 08:30:00 for a local time. Fractional
+// seconds are kept when present. The result is also a valid TOML literal.
+func FormatTime(t time.Time) string {
+	switch t.Location().String() {
+	case "date-local":
+		return t.Format("2006-01-02")
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// ParseTime parses s as a TOML date ("date") or datetime ("datetime") and
+// returns it in the same form FormatTime would write, so it can be stored
+// as a TOML literal. A datetime may omit its offset to make it local, and a
+// space may stand in for the "T".
+func ParseTime(kind, s string) (string, error) {
+	var layouts []string
+	switch kind {
+	case "date":
+		layouts = []string{"2006-01-02"}
+	case "datetime":
+		layouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05.999999999"}
+	default:
+		return "", fmt.Errorf("unknown time type %q", kind)
+	}
+
+	for i, layout := range layouts {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		switch {
+		case kind == "date":
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, localDate)
+		case i%2 == 1:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), localDatetime)
+		}
+		return FormatTime(t), nil
+	}
+	if kind == "date" {
+		return "", fmt.Errorf("invalid date %q: expected YYYY-MM-DD", s)
+	}
+	return "", fmt.Errorf("invalid datetime %q: expected YYYY-MM-DDTHH:MM:SS with optional offset", s)
+}
+
+// jsonValue prepares a field value for encoding/json: dates and times
+// become ISO 8601 strings (time.Time's own encoding would turn a date into
+// a midnight UTC timestamp), recursively within arrays and tables.
+func jsonValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case time.Time:
+		return FormatTime(val)
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = jsonValue(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = jsonValue(item)
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]interface{}, len(val))
+		for i, rec := range val {
+			out[i] = jsonValue(rec)
+		}
+		return out
+	}
+	return v
+}
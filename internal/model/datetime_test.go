@@ -0,0 +1,95 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func decodeTimes(t *testing.T) map[string]interface{} {
+	t.Helper()
+	var m map[string]interface{}
+	doc := `
+birthday = 1990-05-01
+meeting = 2024-03-01T09:30:00
+deadline = 2024-03-01T09:30:00.5-05:00
+alarm = 07:15:00
+`
+	if _, err := toml.Decode(doc, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestFormatTime(t *testing.T) {
+	m := decodeTimes(t)
+	tests := []struct{ key, kind, want string }{
+		{"birthday", "date", "1990-05-01"},
+		{"meeting", "datetime", "2024-03-01T09:30:00"},
+		{"deadline", "datetime", "2024-03-01T09:30:00.5-05:00"},
+		{"alarm", "time", "07:15:00"},
+	}
+	for _, tt := range tests {
+		if got := InferType(m[tt.key]); got != tt.kind {
+			t.Errorf("InferType(%s) = %q, want %q", tt.key, got, tt.kind)
+		}
+		if got := FormatValue(m[tt.key]); got != tt.want {
+			t.Errorf("FormatValue(%s) = %q, want %q", tt.key, got, tt.want)
+		}
+		if got := FormatValueTOML(m[tt.key]); got != tt.want {
+			t.Errorf("FormatValueTOML(%s) = %q, want unquoted %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTime_Formatters(t *testing.T) {
+	m := decodeTimes(t)
+	db := &DB{Categories: []Category{{Name: "identity", Fields: []Field{
+		{Category: "identity", Key: "birthday", Value: m["birthday"]},
+		{Category: "identity", Key: "dates", Value: []interface{}{m["birthday"], m["meeting"]}},
+	}}}}
+
+	out, err := FormatJSON(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed["identity"]["birthday"]; got != "1990-05-01" {
+		t.Errorf("JSON birthday = %v", got)
+	}
+	if !strings.Contains(out, `"2024-03-01T09:30:00"`) {
+		t.Errorf("JSON should keep nested dates as ISO strings:\n%s", out)
+	}
+	if out := FormatYAML(db); !strings.Contains(out, "birthday: 1990-05-01\n") {
+		t.Errorf("YAML birthday not ISO:\n%s", out)
+	}
+	if out := FormatTOML(db); !strings.Contains(out, "birthday = 1990-05-01\n") {
+		t.Errorf("TOML birthday should be a date literal:\n%s", out)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct{ kind, in, want string }{
+		{"date", "1990-05-01", "1990-05-01"},
+		{"datetime", "2024-03-01T09:30:00", "2024-03-01T09:30:00"},
+		{"datetime", "2024-03-01 09:30:00", "2024-03-01T09:30:00"},
+		{"datetime", "2024-03-01T09:30:00Z", "2024-03-01T09:30:00Z"},
+		{"datetime", "2024-03-01T09:30:00+02:00", "2024-03-01T09:30:00+02:00"},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.kind, tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseTime(%q, %q) = %q, %v; want %q", tt.kind, tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"1990-13-01", "May 1", "2024-03-01T09:30"} {
+		if _, err := ParseTime("date", bad); err == nil {
+			t.Errorf("expected error for date %q", bad)
+		}
+	}
+}
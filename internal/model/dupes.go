@@ -0,0 +1,143 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DupeEntry is one redundant value found by FindDupes.
+type DupeEntry struct {
+	Path       string // "category.key"
+	Status     string // "duplicate" or "redundant-override"
+	Value      string // formatted value
+	SameAs     string // for duplicates, the path that keeps the value
+	Suggestion string // how to remove the redundancy
+}
+
+// FindDupes reports two kinds of redundant values in db:
+//
+//   - duplicates: a string, array, or table stored under more than one key.
+//     The first key in db order is kept, and each later one is reported
+//     with a suggestion to refer to it with "@category.key".
+//   - redundant overrides: a local field whose value is identical, type and
+//     all, to the global field it overrides. global may be nil when no
+//     local layer is in play.
+//
+// Computed fields, descriptions, empty values, numbers, and booleans are
+// not considered, since equal small values are usually a coincidence.
+func FindDupes(db, global *DB) []DupeEntry {
+	var entries []DupeEntry
+
+	first := make(map[string]string) // TOML literal -> first path
+	for _, cat := range db.Categories {
+		for _, f := range cat.Fields {
+			if IsDescKey(f.Key) || f.Computed || !dupeCandidate(f.Value) {
+				continue
+			}
+			path := cat.Name + "." + f.Key
+			literal := FormatValueTOML(f.Value)
+			if f.Layer == "local" && global != nil {
+				if g, ok := global.GetField(path); ok && !g.Computed && FormatValueTOML(g.Value) == literal {
+					entries = append(entries, DupeEntry{
+						Path:       path,
+						Status:     "redundant-override",
+						Value:      FormatValue(f.Value),
+						Suggestion: fmt.Sprintf("deets rm --local %s", path),
+					})
+				}
+			}
+			kept, ok := first[literal]
+			if !ok {
+				first[literal] = path
+				continue
+			}
+			entries = append(entries, DupeEntry{
+				Path:       path,
+				Status:     "duplicate",
+				Value:      FormatValue(f.Value),
+				SameAs:     kept,
+				Suggestion: fmt.Sprintf("deets set %s '@%s'", path, kept),
+			})
+		}
+	}
+	return entries
+}
+
+// dupeCandidate reports whether v is a value worth reporting when it
+// repeats.
+func dupeCandidate(v interface{}) bool {
+	switch val := v.(type) {
+	case string:
+		return strings.TrimSpace(val) != ""
+	case []interface{}:
+		return len(val) > 0
+	case []string:
+		return len(val) > 0
+	case []map[string]interface{}:
+		return len(val) > 0
+	case map[string]interface{}:
+		return len(val) > 0
+	}
+	return false
+}
+
+// FormatDupesTable renders dupe entries as a table.
+func FormatDupesTable(entries []DupeEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+
+	pathWidth := len("Path")
+	statusWidth := len("Status")
+	valueWidth := len("Value")
+	suggestWidth := len("Suggestion")
+
+	for _, e := range entries {
+		pathWidth = max(pathWidth, DisplayWidth(e.Path))
+		statusWidth = max(statusWidth, DisplayWidth(e.Status))
+		valueWidth = max(valueWidth, DisplayWidth(e.Value))
+		suggestWidth = max(suggestWidth, DisplayWidth(e.Suggestion))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s    %s    %s    %s\n",
+		PadRight("Path", pathWidth), PadRight("Status", statusWidth), PadRight("Value", valueWidth), "Suggestion")
+	fmt.Fprintf(&b, "%s    %s    %s    %s\n",
+		repeatRune('\u2500', pathWidth),
+		repeatRune('\u2500', statusWidth),
+		repeatRune('\u2500', valueWidth),
+		repeatRune('\u2500', suggestWidth))
+	for _, e := range entries {
+		fmt.Fprintf(&b, "%s    %s    %s    %s\n",
+			PadRight(e.Path, pathWidth), PadRight(e.Status, statusWidth), PadRight(e.Value, valueWidth), e.Suggestion)
+	}
+	return b.String()
+}
+
+// FormatDupesJSON serializes dupe entries as a JSON array.
+func FormatDupesJSON(entries []DupeEntry) (string, error) {
+	type jsonEntry struct {
+		Path       string `json:"path"`
+		Status     string `json:"status"`
+		Value      string `json:"value"`
+		SameAs     string `json:"same_as,omitempty"`
+		Suggestion string `json:"suggestion"`
+	}
+
+	items := make([]jsonEntry, len(entries))
+	for i, e := range entries {
+		items[i] = jsonEntry{
+			Path:       e.Path,
+			Status:     e.Status,
+			Value:      e.Value,
+			SameAs:     e.SameAs,
+			Suggestion: e.Suggestion,
+		}
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal dupes to JSON: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindDupes(t *testing.T) {
+	global := &DB{Categories: []Category{
+		{Name: "contact", Fields: []Field{{Key: "email", Value: "a@example.com"}}},
+		{Name: "web", Fields: []Field{{Key: "github", Value: "alex"}}},
+	}}
+	db := &DB{Categories: []Category{
+		{Name: "contact", Fields: []Field{
+			{Key: "email", Value: "a@example.com", Layer: "local"},
+			{Key: "email_desc", Value: "a@example.com"},
+			{Key: "phone", Value: ""},
+		}},
+		{Name: "identity", Fields: []Field{
+			{Key: "aka", Value: []interface{}{"Al"}},
+			{Key: "active", Value: true},
+			{Key: "handle", Value: "alex"},
+		}},
+		{Name: "web", Fields: []Field{
+			{Key: "github", Value: "alex"},
+			{Key: "gitlab", Value: "alex"},
+			{Key: "gravatar", Value: "a@example.com", Computed: true},
+			{Key: "nicknames", Value: []interface{}{"Al"}},
+			{Key: "public", Value: true},
+			{Key: "site", Value: ""},
+		}},
+	}}
+
+	var got []string
+	for _, e := range FindDupes(db, global) {
+		got = append(got, e.Status+" "+e.Path+" "+e.SameAs+" | "+e.Suggestion)
+	}
+	want := []string{
+		"redundant-override contact.email  | deets rm --local contact.email",
+		"duplicate web.github identity.handle | deets set web.github '@identity.handle'",
+		"duplicate web.gitlab identity.handle | deets set web.gitlab '@identity.handle'",
+		"duplicate web.nicknames identity.aka | deets set web.nicknames '@identity.aka'",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("FindDupes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if entries := FindDupes(db, nil); len(entries) != 3 {
+		t.Errorf("without global, expected 3 duplicates, got %+v", entries)
+	}
+}
+
+func TestFormatDupes(t *testing.T) {
+	entries := []DupeEntry{{Path: "web.gitlab", Status: "duplicate", Value: "alex", SameAs: "web.github", Suggestion: "deets set web.gitlab '@web.github'"}}
+	table := FormatDupesTable(entries)
+	if !strings.Contains(table, "Suggestion") || !strings.Contains(table, "@web.github") {
+		t.Errorf("unexpected table:\n%s", table)
+	}
+	out, err := FormatDupesJSON(entries)
+	if err != nil || !strings.Contains(out, `"same_as": "web.github"`) {
+		t.Errorf("unexpected JSON (%v):\n%s", err, out)
+	}
+	if FormatDupesTable(nil) != "" {
+		t.Error("expected empty table for no entries")
+	}
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Filter is a condition on one attribute of a field, parsed from a --where
+// expression such as "value~example\.com" or "key=email".
+type Filter struct {
+	// Attr is the attribute tested: key, value, desc, category, or path
+	// ("category.key").
+	Attr string
+	// Op is "=" (equals), "~" (matches a regular expression), or either
+	// negated with a leading "!".
+	Op string
+	// Operand is the comparison string or regular expression.
+	Operand string
+
+	re *regexp.Regexp
+}
+
+// filterAttrs lists the attributes a Filter may test.
+var filterAttrs = []string{"key", "value", "desc", "category", "path"}
+
+// ParseFilter parses a "<attr><op><operand>" expression, where op is one of
+// =, !=, ~, or !~.
+func ParseFilter(expr string) (Filter, error) {
+	i := strings.IndexAny(expr, "=~")
+	if i <= 0 {
+		return Filter{}, fmt.Errorf("invalid filter %q: expected <attr>=<value> or <attr>~<regex>", expr)
+	}
+	f := Filter{Attr: expr[:i], Op: expr[i : i+1], Operand: expr[i+1:]}
+	if strings.HasSuffix(f.Attr, "!") {
+		f.Attr = strings.TrimSuffix(f.Attr, "!")
+		f.Op = "!" + f.Op
+	}
+	f.Attr = strings.TrimSpace(f.Attr)
+
+	known := false
+	for _, a := range filterAttrs {
+		if f.Attr == a {
+			known = true
+		}
+	}
+	if !known {
+		return Filter{}, fmt.Errorf("invalid filter %q: unknown attribute %q (want one of %s)", expr, f.Attr, strings.Join(filterAttrs, ", "))
+	}
+
+	if strings.HasSuffix(f.Op, "~") {
+		re, err := regexp.Compile(f.Operand)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid filter %q: %w", expr, err)
+		}
+		f.re = re
+	}
+	return f, nil
+}
+
+// Match reports whether field satisfies the filter. For "value", an array
+// field equals the operand if its formatted value or any element does.
+func (f Filter) Match(field Field) bool {
+	var candidates []string
+	switch f.Attr {
+	case "key":
+		candidates = []string{field.Key}
+	case "desc":
+		candidates = []string{field.Desc}
+	case "category":
+		candidates = []string{field.Category}
+	case "path":
+		candidates = []string{field.Category + "." + field.Key}
+	case "value":
+		candidates = append([]string{FormatValue(field.Value)}, Items(field.Value)...)
+	}
+
+	matched := false
+	for _, c := range candidates {
+		if f.re != nil && f.re.MatchString(c) || f.re == nil && c == f.Operand {
+			matched = true
+			break
+		}
+	}
+	if strings.HasPrefix(f.Op, "!") {
+		return !matched
+	}
+	return matched
+}
+
+// FilterFields returns the fields that satisfy every filter.
+func FilterFields(fields []Field, filters []Filter) []Field {
+	if len(filters) == 0 {
+		return fields
+	}
+	var results []Field
+	for _, field := range fields {
+		if MatchAll(field, filters) {
+			results = append(results, field)
+		}
+	}
+	return results
+}
+
+// MatchAll reports whether field satisfies every filter.
+func MatchAll(field Field, filters []Filter) bool {
+	for _, f := range filters {
+		if !f.Match(field) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package model
+
+import "testing"
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expr, attr, op, operand string
+	}{
+		{"key=email", "key", "=", "email"},
+		{"value~example\\.com$", "value", "~", "example\\.com$"},
+		{"category!=web", "category", "!=", "web"},
+		{"desc!~^$", "desc", "!~", "^$"},
+		{"path=identity.name", "path", "=", "identity.name"},
+		{"value=a=b", "value", "=", "a=b"},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", tt.expr, err)
+			continue
+		}
+		if f.Attr != tt.attr || f.Op != tt.op || f.Operand != tt.operand {
+			t.Errorf("ParseFilter(%q) = %+v", tt.expr, f)
+		}
+	}
+
+	for _, bad := range []string{"nooperator", "=x", "color=red", "value~("} {
+		if _, err := ParseFilter(bad); err == nil {
+			t.Errorf("ParseFilter(%q): expected error", bad)
+		}
+	}
+}
+
+func TestFilterFields(t *testing.T) {
+	db := newTestDB()
+	parse := func(exprs ...string) []Filter {
+		var out []Filter
+		for _, e := range exprs {
+			f, err := ParseFilter(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, f)
+		}
+		return out
+	}
+
+	tests := []struct {
+		filters []Filter
+		want    int
+	}{
+		{parse("value~example"), 1},
+		{parse("value=Alex T"), 1},
+		{parse("value=statistics"), 1},
+		{parse("key=name"), 1},
+		{parse("category=academic", "desc!~^$"), 2},
+		{parse("path!=identity.name", "category=identity"), 2},
+		{nil, 8},
+	}
+	for i, tt := range tests {
+		if got := FilterFields(db.AllFields(), tt.filters); len(got) != tt.want {
+			t.Errorf("case %d: got %d fields, want %d: %+v", i, len(got), tt.want, got)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package model
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // FormatTable renders a slice of fields as an aligned text table.
@@ -17,7 +20,7 @@ import (
 //	identity    aka       Alex Towell
 //	web         github    queelius
 func FormatTable(fields []Field) string {
-	return renderTable(fields, false)
+	return renderTable(fields, false, false, 0)
 }
 
 // FormatJSON serializes the entire DB as a JSON object grouped by category.
@@ -83,26 +86,87 @@ func FormatFieldsJSON(fields []Field) (string, error) {
 	return string(data), nil
 }
 
-// FormatEnv formats the entire DB as environment variable assignments.
+// FormatEnv formats the entire DB as environment variable assignments for
+// shell to eval: "posix" (sh, bash, zsh), "fish", or "powershell".
 //
-// Key format: DEETS_<CATEGORY>_<KEY> (uppercased).
-// Values are double-quoted. For slice values, elements are comma-separated.
+// Key format: DEETS_<CATEGORY>_<KEY>, uppercased, with characters outside
+// [A-Z0-9_] replaced by "_". Values are single-quoted so the shell takes
+// them literally, quotes and newlines included. For slice values, elements
+// are comma-separated.
 //
 // Example:
 //
-//	DEETS_IDENTITY_NAME="Alexander Towell"
-//	DEETS_WEB_GITHUB="queelius"
-func FormatEnv(db *DB) string {
+//	DEETS_IDENTITY_NAME='Alexander Towell'          # posix
+//	set -gx DEETS_IDENTITY_NAME 'Alexander Towell'  # fish
+//	$env:DEETS_IDENTITY_NAME = 'Alexander Towell'   # powershell
+func FormatEnv(db *DB, shell string) (string, error) {
+	var line func(key, value string) string
+	switch shell {
+	case "posix":
+		line = func(key, value string) string {
+			return key + "=" + posixQuote(value)
+		}
+	case "fish":
+		line = func(key, value string) string {
+			r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+			return "set -gx " + key + " '" + r.Replace(value) + "'"
+		}
+	case "powershell":
+		line = func(key, value string) string {
+			return "$env:" + key + " = '" + strings.ReplaceAll(value, "'", "''") + "'"
+		}
+	default:
+		return "", fmt.Errorf("unknown shell %q: expected posix, fish, or powershell", shell)
+	}
+
 	var b strings.Builder
 	for _, cat := range db.Categories {
-		for _, f := range cat.Fields {
+		for _, f := range FlattenFields(cat.Fields) {
+			if IsDescKey(f.Key) {
+				continue
+			}
+			b.WriteString(line(EnvName(cat.Name, f.Key), FormatValue(f.Value)) + "\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// EnvName returns the environment variable FormatEnv uses for the field
+// at category.key, such as DEETS_IDENTITY_NAME for identity.name.
+func EnvName(category, key string) string {
+	return dotenvKey("DEETS_" + category + "_" + key)
+}
+
+// posixQuote single-quotes s for a POSIX shell. Each ' in s closes the
+// quotes, is written as \', and reopens them.
+func posixQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// FormatDotenv formats the entire DB as a .env file for dotenv loaders
+// (docker compose, node dotenv, python-dotenv).
+//
+// Key format: <PREFIX><CATEGORY>_<KEY>, uppercased, with characters outside
+// [A-Z0-9_] replaced by "_". The prefix is empty by default.
+//
+// Values are single-quoted so loaders take them literally (no $ interpolation).
+// Values containing a single quote or newline are double-quoted with
+// backslash escapes (\\, \", \n) instead, and with $ and ` escaped too so
+// loaders that interpolate inside double quotes keep them literal.
+//
+// Example:
+//
+//	IDENTITY_NAME='Alexander Towell'
+//	WEB_GITHUB='queelius'
+func FormatDotenv(db *DB, prefix string) string {
+	var b strings.Builder
+	for _, cat := range db.Categories {
+		for _, f := range FlattenFields(cat.Fields) {
 			if IsDescKey(f.Key) {
 				continue
 			}
-			envKey := fmt.Sprintf("DEETS_%s_%s",
-				strings.ToUpper(cat.Name),
-				strings.ToUpper(f.Key))
-			b.WriteString(fmt.Sprintf("%s=%q\n", envKey, FormatValue(f.Value)))
+			key := dotenvKey(prefix + cat.Name + "_" + f.Key)
+			fmt.Fprintf(&b, "%s=%s\n", key, dotenvValue(FormatValue(f.Value)))
 		}
 	}
 	return b.String()
@@ -112,42 +176,306 @@ func FormatEnv(db *DB) string {
 //
 // Each category becomes a TOML table header. String values are quoted,
 // arrays are rendered as TOML arrays, and numeric types are unquoted.
-// _desc fields are excluded.
+// Nested tables are written as sub-tables such as [education.phd] after the
+// category's own keys, and arrays of tables as one [[education.degrees]]
+// section per element after those. _desc fields are excluded.
 func FormatTOML(db *DB) string {
+	line := func(k string, v interface{}) string {
+		if s, ok := v.(string); ok {
+			return fmt.Sprintf("%s = %s\n", k, TOMLString(s))
+		}
+		return fmt.Sprintf("%s = %s\n", k, tomlValue(v))
+	}
+
 	var b strings.Builder
 	for i, cat := range db.Categories {
 		if i > 0 {
 			b.WriteString("\n")
 		}
-		fmt.Fprintf(&b, "[%s]\n", cat.Name)
-		for _, f := range cat.Fields {
-			if IsDescKey(f.Key) {
+		keys, vals := categoryEntries(cat)
+		var arrays []int
+		var plainKeys []string
+		var plainVals []interface{}
+		for j, v := range vals {
+			if recs, ok := Records(v); ok && len(recs) > 0 {
+				arrays = append(arrays, j)
 				continue
 			}
-			b.WriteString(fmt.Sprintf("%s = %s\n", f.Key, tomlValue(f.Value)))
+			plainKeys = append(plainKeys, keys[j])
+			plainVals = append(plainVals, v)
+		}
+		writeSection(&b, cat.Name, plainKeys, plainVals, line)
+		for _, j := range arrays {
+			recs, _ := Records(vals[j])
+			for _, rec := range recs {
+				fmt.Fprintf(&b, "\n[[%s.%s]]\n", cat.Name, keys[j])
+				for _, k := range RecordKeys(rec) {
+					b.WriteString(line(tomlKey(k), rec[k]))
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// FormatINI formats the entire DB as an INI document.
+//
+// Each category becomes a [section], and nested tables become dotted
+// sections such as [education.phd]. Values are written bare unless they
+// contain characters INI parsers treat specially (comment markers, "=",
+// quotes, backslashes, newlines, or surrounding whitespace), in which case
+// they are double-quoted with backslash escapes. Array values are joined
+// with ", ". _desc fields are excluded.
+//
+// Example:
+//
+//	[identity]
+//	name = Alexander Towell
+//	motto = "work; play"
+func FormatINI(db *DB) string {
+	var b strings.Builder
+	for i, cat := range db.Categories {
+		if i > 0 {
+			b.WriteString("\n")
 		}
+		keys, vals := categoryEntries(cat)
+		writeSection(&b, cat.Name, keys, vals, func(k string, v interface{}) string {
+			return fmt.Sprintf("%s = %s\n", k, iniValue(FormatValue(v)))
+		})
 	}
 	return b.String()
 }
 
-// FormatYAML formats the entire DB as a YAML document.
+// FormatHCL formats the entire DB as HCL (Terraform) blocks.
 //
-// Each category is a top-level mapping key. String values are unquoted (unless
-// they require quoting), arrays use the flow sequence syntax, and numeric types
-// are rendered directly. _desc fields are excluded.
-func FormatYAML(db *DB) string {
+// Each category becomes a labeled block. Strings are quoted with HCL escapes,
+// including "$${" and "%%{" so values are never interpolated. Arrays become
+// tuples and tables become objects. Attribute names must be identifiers, so
+// keys such as "2fa" or "e.mail" are rewritten with hclName; object keys are
+// quoted instead. _desc fields are excluded.
+//
+// Example:
+//
+//	category "identity" {
+//	  name = "Alexander Towell"
+//	  aka  = ["Alex Towell"]
+//	}
+func FormatHCL(db *DB) string {
 	var b strings.Builder
 	for i, cat := range db.Categories {
 		if i > 0 {
 			b.WriteString("\n")
 		}
-		fmt.Fprintf(&b, "%s:\n", cat.Name)
+		fmt.Fprintf(&b, "category %s {\n", hclString(cat.Name))
+
+		// Keys that are identifiers keep their names; rewritten keys are
+		// numbered if they would collide with one.
+		taken := make(map[string]bool)
+		for _, f := range cat.Fields {
+			taken[f.Key] = isHCLIdent(f.Key)
+		}
+		var names []string
+		var values []interface{}
+		width := 0
+		for _, f := range cat.Fields {
+			if IsDescKey(f.Key) {
+				continue
+			}
+			name := f.Key
+			if !isHCLIdent(name) {
+				name = hclName(f.Key)
+				for n := 2; taken[name]; n++ {
+					name = fmt.Sprintf("%s_%d", hclName(f.Key), n)
+				}
+				taken[name] = true
+			}
+			names = append(names, name)
+			values = append(values, f.Value)
+			width = max(width, DisplayWidth(name))
+		}
+		for i, name := range names {
+			fmt.Fprintf(&b, "  %s = %s\n", PadRight(name, width), hclValue(values[i]))
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// FormatNix formats the entire DB as a Nix attribute set, suitable for
+// `import` from a Nix expression such as a home-manager configuration.
+//
+// Strings, lists, integers, floats, and booleans map to their Nix literals;
+// tables become nested attribute sets. Attribute names that are not valid
+// Nix identifiers are quoted. _desc fields are excluded.
+//
+// Example:
+//
+//	{
+//	  identity = {
+//	    name = "Alexander Towell";
+//	    aka = [ "Alex Towell" ];
+//	  };
+//	}
+func FormatNix(db *DB) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, cat := range db.Categories {
+		fmt.Fprintf(&b, "  %s = {\n", nixName(cat.Name))
+		for _, f := range cat.Fields {
+			if IsDescKey(f.Key) {
+				continue
+			}
+			fmt.Fprintf(&b, "    %s = %s;\n", nixName(f.Key), nixValue(f.Value))
+		}
+		b.WriteString("  };\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// FormatPlist formats the entire DB as an XML property list (macOS plist),
+// with one <dict> per category. Values map to <string>, <integer>, <real>,
+// <true/>/<false/>, <array>, and nested <dict> elements. Text is XML-escaped.
+// _desc fields are excluded.
+func FormatPlist(db *DB) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<plist version="1.0">` + "\n")
+	b.WriteString("<dict>\n")
+	for _, cat := range db.Categories {
+		fmt.Fprintf(&b, "\t<key>%s</key>\n\t<dict>\n", xmlEscape(cat.Name))
 		for _, f := range cat.Fields {
 			if IsDescKey(f.Key) {
 				continue
 			}
-			b.WriteString(fmt.Sprintf("  %s: %s\n", f.Key, yamlValue(f.Value)))
+			fmt.Fprintf(&b, "\t\t<key>%s</key>\n", xmlEscape(f.Key))
+			writePlistValue(&b, f.Value, "\t\t")
 		}
+		b.WriteString("\t</dict>\n")
+	}
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// FormatCSV renders fields as RFC 4180 CSV with a header row.
+//
+// Output example:
+//
+//	category,key,value,description
+//	identity,name,Alexander Towell,Full legal name
+//	identity,aka,"Alex Towell, Alex T",Known aliases
+//
+// Array values are joined with ", " as in FormatValue. _desc fields are excluded.
+func FormatCSV(fields []Field) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write([]string{"category", "key", "value", "description"}); err != nil {
+		return "", fmt.Errorf("write CSV header: %w", err)
+	}
+	for _, f := range FlattenFields(fields) {
+		if IsDescKey(f.Key) {
+			continue
+		}
+		if err := w.Write([]string{f.Category, f.Key, FormatValue(f.Value), f.Desc}); err != nil {
+			return "", fmt.Errorf("write CSV row for %s.%s: %w", f.Category, f.Key, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("flush CSV: %w", err)
+	}
+	return b.String(), nil
+}
+
+// FormatNDJSON renders fields as newline-delimited JSON, one object per
+// field, so output can be streamed through line-oriented tools like jq -c.
+//
+// Output example:
+//
+//	{"category":"identity","key":"name","value":"Alexander Towell","description":"Full legal name"}
+//	{"category":"web","key":"github","value":"queelius","description":""}
+//
+// Values keep their JSON types (arrays stay arrays). _desc fields are excluded.
+func FormatNDJSON(fields []Field) (string, error) {
+	type line struct {
+		Category    string      `json:"category"`
+		Key         string      `json:"key"`
+		Value       interface{} `json:"value"`
+		Description string      `json:"description"`
+	}
+
+	var b strings.Builder
+	for _, f := range fields {
+		if IsDescKey(f.Key) {
+			continue
+		}
+		data, err := json.Marshal(line{f.Category, f.Key, jsonValue(f.Value), f.Desc})
+		if err != nil {
+			return "", fmt.Errorf("marshal %s.%s to JSON: %w", f.Category, f.Key, err)
+		}
+		b.Write(data)
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// FormatScoredJSON serializes ranked search results as a JSON array, best
+// match first, so the order survives:
+//
+//	[{"category": "web", "key": "mastodon", "value": "...", "description": "", "score": 139}]
+func FormatScoredJSON(results []ScoredField) (string, error) {
+	type entry struct {
+		Category    string      `json:"category"`
+		Key         string      `json:"key"`
+		Value       interface{} `json:"value"`
+		Description string      `json:"description"`
+		Score       int         `json:"score"`
+	}
+
+	entries := make([]entry, 0, len(results))
+	for _, r := range results {
+		entries = append(entries, entry{r.Category, r.Key, jsonValue(r.Value), r.Desc, r.Score})
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal search results to JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// FormatMarkdown renders fields as a GitHub-flavored Markdown table. As with
+// FormatTable, the Category column is omitted when all fields share one
+// category. Pipes are escaped and newlines become <br> so every field stays
+// on a single table row.
+//
+// Output example:
+//
+//	| Key | Value |
+//	| --- | --- |
+//	| name | Alexander Towell |
+func FormatMarkdown(fields []Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	multiCat := hasMultipleCategories(fields)
+
+	var b strings.Builder
+	if multiCat {
+		b.WriteString("| Category | Key | Value |\n| --- | --- | --- |\n")
+	} else {
+		b.WriteString("| Key | Value |\n| --- | --- |\n")
+	}
+	for _, f := range FlattenFields(fields) {
+		if IsDescKey(f.Key) {
+			continue
+		}
+		b.WriteString("|")
+		if multiCat {
+			fmt.Fprintf(&b, " %s |", markdownCell(f.Category))
+		}
+		fmt.Fprintf(&b, " %s | %s |\n", markdownCell(f.Key), markdownCell(FormatValue(f.Value)))
 	}
 	return b.String()
 }
@@ -170,22 +498,16 @@ func FormatDescTable(fields []Field) string {
 
 	for _, f := range fields {
 		path := f.Category + "." + f.Key
-		if len(path) > fieldWidth {
-			fieldWidth = len(path)
-		}
-		if len(f.Desc) > descWidth {
-			descWidth = len(f.Desc)
-		}
+		fieldWidth = max(fieldWidth, DisplayWidth(path))
+		descWidth = max(descWidth, DisplayWidth(f.Desc))
 	}
 
 	var b strings.Builder
-	fmt.Fprintf(&b, "%-*s    %s\n", fieldWidth, "Field", "Description")
-	fmt.Fprintf(&b, "%-*s    %s\n",
-		fieldWidth, repeatRune('\u2500', fieldWidth),
-		repeatRune('\u2500', descWidth))
+	fmt.Fprintf(&b, "%s    %s\n", PadRight("Field", fieldWidth), "Description")
+	fmt.Fprintf(&b, "%s    %s\n", repeatRune('\u2500', fieldWidth), repeatRune('\u2500', descWidth))
 	for _, f := range fields {
 		path := f.Category + "." + f.Key
-		fmt.Fprintf(&b, "%-*s    %s\n", fieldWidth, path, f.Desc)
+		fmt.Fprintf(&b, "%s    %s\n", PadRight(path, fieldWidth), f.Desc)
 	}
 	return b.String()
 }
@@ -210,102 +532,158 @@ func FormatDescJSON(fields []Field) (string, error) {
 // Internal helpers
 // ---------------------------------------------------------------------------
 
-// renderTable is the shared implementation for FormatTable and FormatTableWithDesc.
-// When includeDesc is true, a Description column is appended.
-func renderTable(fields []Field, includeDesc bool) string {
+// renderTable is the shared implementation for FormatTable, FormatTableWithDesc,
+// and FormatTableWithSource. When includeDesc is true, a Description column is
+// appended; when includeSource is true, a Source column follows it. A
+// positive maxWidth narrows the Value and Description columns until rows fit
+// in that many columns, cutting longer text with "…".
+func renderTable(fields []Field, includeDesc, includeSource bool, maxWidth int) string {
 	if len(fields) == 0 {
 		return ""
 	}
+	fields = FlattenFields(fields)
 
 	multiCat := hasMultipleCategories(fields)
 
-	catWidth := len("Category")
-	keyWidth := len("Key")
-	valWidth := len("Value")
-	descWidth := len("Description")
+	var cols []Column
+	if multiCat {
+		cols = append(cols, Column{Header: "Category"})
+	}
+	cols = append(cols, Column{Header: "Key"}, Column{Header: "Value", Shrink: true})
+	if includeDesc {
+		cols = append(cols, Column{Header: "Description", Shrink: true})
+	}
+	if includeSource {
+		cols = append(cols, Column{Header: "Source"})
+	}
 
+	rows := make([][]string, 0, len(fields))
 	for _, f := range fields {
-		if multiCat && len(f.Category) > catWidth {
-			catWidth = len(f.Category)
-		}
-		if len(f.Key) > keyWidth {
-			keyWidth = len(f.Key)
+		var vals []string
+		if multiCat {
+			vals = append(vals, f.Category)
 		}
-		v := FormatValue(f.Value)
-		if len(v) > valWidth {
-			valWidth = len(v)
+		vals = append(vals, f.Key, FormatValue(f.Value))
+		if includeDesc {
+			vals = append(vals, f.Desc)
 		}
-		if includeDesc && len(f.Desc) > descWidth {
-			descWidth = len(f.Desc)
+		if includeSource {
+			vals = append(vals, f.Origin())
 		}
+		rows = append(rows, vals)
 	}
+	return FormatColumns(cols, rows, maxWidth)
+}
 
-	var b strings.Builder
+// A Column is one column of a table rendered by FormatColumns.
+type Column struct {
+	Header string
+	// Shrink lets fitting maxWidth narrow the column, down to its header.
+	Shrink bool
+}
 
-	// Build header and separator dynamically based on columns.
-	type col struct {
-		header string
-		width  int
+// FormatColumns renders rows under Title-case column headers and a rule
+// line, with columns separated by four spaces, in the style of every
+// table deets prints. A cell may hold several lines; they continue on the
+// following lines with the other columns left blank. A positive maxWidth
+// narrows the Shrink columns until rows fit in that many columns, cutting
+// longer text with "…".
+func FormatColumns(cols []Column, rows [][]string, maxWidth int) string {
+	widths := make([]int, len(cols))
+	for i, c := range cols {
+		widths[i] = DisplayWidth(c.Header)
 	}
-	var cols []col
-	if multiCat {
-		cols = append(cols, col{"Category", catWidth})
+	for _, row := range rows {
+		for i, v := range row {
+			for _, line := range strings.Split(v, "\n") {
+				widths[i] = max(widths[i], DisplayWidth(line))
+			}
+		}
 	}
-	cols = append(cols, col{"Key", keyWidth})
-	cols = append(cols, col{"Value", valWidth})
-	if includeDesc {
-		cols = append(cols, col{"Description", descWidth})
+
+	if maxWidth > 0 {
+		total := 4 * (len(cols) - 1)
+		for _, w := range widths {
+			total += w
+		}
+		// Narrow the widest shrinkable column, one column at a time, but
+		// not below its header.
+		for ; total > maxWidth; total-- {
+			widest := -1
+			for i, c := range cols {
+				if c.Shrink && widths[i] > DisplayWidth(c.Header) && (widest == -1 || widths[i] > widths[widest]) {
+					widest = i
+				}
+			}
+			if widest == -1 {
+				break
+			}
+			widths[widest]--
+		}
 	}
 
+	var b strings.Builder
+
 	// Header
 	for i, c := range cols {
 		if i > 0 {
 			b.WriteString("    ")
 		}
 		if i < len(cols)-1 {
-			fmt.Fprintf(&b, "%-*s", c.width, c.header)
+			b.WriteString(PadRight(c.Header, widths[i]))
 		} else {
-			b.WriteString(c.header)
+			b.WriteString(c.Header)
 		}
 	}
 	b.WriteString("\n")
 
 	// Separator
-	for i, c := range cols {
+	for i := range cols {
 		if i > 0 {
 			b.WriteString("    ")
 		}
-		if i < len(cols)-1 {
-			fmt.Fprintf(&b, "%-*s", c.width, repeatRune('\u2500', c.width))
-		} else {
-			b.WriteString(repeatRune('\u2500', c.width))
-		}
+		b.WriteString(repeatRune('\u2500', widths[i]))
 	}
 	b.WriteString("\n")
 
 	// Rows
-	for _, f := range fields {
-		var vals []string
-		if multiCat {
-			vals = append(vals, f.Category)
-		}
-		vals = append(vals, f.Key)
-		vals = append(vals, FormatValue(f.Value))
-		if includeDesc {
-			vals = append(vals, f.Desc)
-		}
-
+	for _, vals := range rows {
+		// Multi-line values continue on the following lines, with the
+		// other columns left blank.
+		cells := make([][]string, len(vals))
+		height := 1
 		for i, v := range vals {
-			if i > 0 {
-				b.WriteString("    ")
+			cells[i] = strings.Split(v, "\n")
+			if len(cells[i]) > height {
+				height = len(cells[i])
+			}
+		}
+		for row := 0; row < height; row++ {
+			var line strings.Builder
+			for i, c := range cells {
+				v := ""
+				if row < len(c) {
+					v = c[row]
+				}
+				if cols[i].Shrink {
+					v = Truncate(v, widths[i])
+				}
+				if i > 0 {
+					line.WriteString("    ")
+				}
+				if i < len(cols)-1 {
+					line.WriteString(PadRight(v, widths[i]))
+				} else {
+					line.WriteString(v)
+				}
 			}
-			if i < len(cols)-1 {
-				fmt.Fprintf(&b, "%-*s", cols[i].width, v)
+			if row == 0 {
+				b.WriteString(line.String())
 			} else {
-				b.WriteString(v)
+				b.WriteString(strings.TrimRight(line.String(), " "))
 			}
+			b.WriteString("\n")
 		}
-		b.WriteString("\n")
 	}
 
 	return b.String()
@@ -348,7 +726,7 @@ func (o orderedMap) MarshalJSON() ([]byte, error) {
 		if err != nil {
 			return nil, err
 		}
-		valJSON, err := json.Marshal(o.values[k])
+		valJSON, err := json.Marshal(jsonValue(o.values[k]))
 		if err != nil {
 			return nil, err
 		}
@@ -406,11 +784,24 @@ func groupByCategory(fields []Field) orderedMap {
 	return om
 }
 
-// tomlValue formats a Go value as a TOML value literal.
+// tomlValue formats a Go value as a TOML value literal. Tables are rendered
+// as inline tables, so arrays of tables become arrays of inline tables.
 func tomlValue(v interface{}) string {
 	switch val := v.(type) {
 	case string:
-		return fmt.Sprintf("%q", val)
+		return tomlQuote(val)
+	case map[string]interface{}:
+		parts := make([]string, 0, len(val))
+		for _, k := range RecordKeys(val) {
+			parts = append(parts, fmt.Sprintf("%s = %s", tomlKey(k), tomlValue(val[k])))
+		}
+		return "{ " + strings.Join(parts, ", ") + " }"
+	case []map[string]interface{}:
+		parts := make([]string, 0, len(val))
+		for _, rec := range val {
+			parts = append(parts, tomlValue(rec))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
 	case []interface{}:
 		parts := make([]string, 0, len(val))
 		for _, item := range val {
@@ -420,58 +811,157 @@ func tomlValue(v interface{}) string {
 	case []string:
 		parts := make([]string, 0, len(val))
 		for _, s := range val {
-			parts = append(parts, fmt.Sprintf("%q", s))
+			parts = append(parts, tomlQuote(s))
 		}
 		return "[" + strings.Join(parts, ", ") + "]"
 	case int64:
 		return fmt.Sprint(val)
 	case float64:
-		return fmt.Sprint(val)
+		s := fmt.Sprint(val)
+		if !strings.ContainsAny(s, ".eE") {
+			// TOML would read a bare "4" back as an integer.
+			s += ".0"
+		}
+		return s
 	case bool:
 		return fmt.Sprint(val)
+	case time.Time:
+		return FormatTime(val)
 	default:
-		return fmt.Sprintf("%q", fmt.Sprintf("%v", v))
+		return tomlQuote(fmt.Sprintf("%v", v))
 	}
 }
 
-// yamlValue formats a Go value as a YAML value literal.
-func yamlValue(v interface{}) string {
-	switch val := v.(type) {
-	case string:
-		if yamlNeedsQuoting(val) {
-			return fmt.Sprintf("%q", val)
+// TOMLString formats s as a TOML basic string. Strings containing newlines
+// are written as multi-line """ blocks so they stay readable in the file;
+// everything else is a single-line quoted string.
+func TOMLString(s string) string {
+	if !strings.Contains(s, "\n") {
+		return tomlQuote(s)
+	}
+	var b strings.Builder
+	b.WriteString(`"""` + "\n")
+	quotes := 0
+	for _, r := range s {
+		if r == '"' {
+			// Break up runs that would close the block early.
+			if quotes == 2 {
+				b.WriteString(`\"`)
+				quotes = 0
+				continue
+			}
+			quotes++
+			b.WriteRune(r)
+			continue
 		}
-		return val
-	case []interface{}:
-		if len(val) == 0 {
-			return "[]"
+		quotes = 0
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n' || r == '\t':
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
 		}
-		parts := make([]string, 0, len(val))
-		for _, item := range val {
-			parts = append(parts, yamlValue(item))
+	}
+	if quotes > 0 {
+		// A closing quote right before the delimiter would merge with it.
+		str := b.String()
+		b.Reset()
+		b.WriteString(str[:len(str)-quotes])
+		b.WriteString(strings.Repeat(`\"`, quotes))
+	}
+	b.WriteString(`"""`)
+	return b.String()
+}
+
+// tomlQuote formats s as a single-line TOML basic string. Unlike Go's %q,
+// it never emits escapes TOML lacks, such as \a or \x00.
+func tomlQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
 		}
-		return "[" + strings.Join(parts, ", ") + "]"
-	case []string:
-		if len(val) == 0 {
-			return "[]"
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// tomlKey returns k as a bare TOML key when possible, quoted otherwise.
+func tomlKey(k string) string {
+	if k == "" {
+		return `""`
+	}
+	for _, r := range k {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return tomlQuote(k)
 		}
-		parts := make([]string, 0, len(val))
-		for _, s := range val {
-			if yamlNeedsQuoting(s) {
-				parts = append(parts, fmt.Sprintf("%q", s))
-			} else {
-				parts = append(parts, s)
+	}
+	return k
+}
+
+// categoryEntries returns the keys and values of cat, excluding _desc fields.
+func categoryEntries(cat Category) ([]string, []interface{}) {
+	var keys []string
+	var vals []interface{}
+	for _, f := range cat.Fields {
+		if IsDescKey(f.Key) {
+			continue
+		}
+		keys = append(keys, f.Key)
+		vals = append(vals, f.Value)
+	}
+	return keys, vals
+}
+
+// writeSection writes a [name] section header followed by one line per
+// entry, formatted by line. Entries whose values are non-empty tables are
+// written afterwards as [name.key] sub-sections, recursively.
+func writeSection(b *strings.Builder, name string, keys []string, vals []interface{}, line func(string, interface{}) string) {
+	fmt.Fprintf(b, "[%s]\n", name)
+	var tables []int
+	for i, k := range keys {
+		if m, ok := vals[i].(map[string]interface{}); ok && len(m) > 0 {
+			tables = append(tables, i)
+			continue
+		}
+		b.WriteString(line(k, vals[i]))
+	}
+	for _, i := range tables {
+		m := vals[i].(map[string]interface{})
+		var subKeys []string
+		var subVals []interface{}
+		for _, k := range RecordKeys(m) {
+			if !IsDescKey(k) {
+				subKeys = append(subKeys, k)
+				subVals = append(subVals, m[k])
 			}
 		}
-		return "[" + strings.Join(parts, ", ") + "]"
-	case int64:
-		return fmt.Sprint(val)
-	case float64:
-		return fmt.Sprint(val)
-	case bool:
-		return fmt.Sprint(val)
-	default:
-		return fmt.Sprintf("%v", v)
+		b.WriteString("\n")
+		writeSection(b, name+"."+keys[i], subKeys, subVals, line)
 	}
 }
 
@@ -497,7 +987,22 @@ func FieldsToDB(fields []Field) *DB {
 // FormatTableWithDesc renders a 4-column table: Category, Key, Value, Description.
 // If all fields share the same category, the Category column is omitted.
 func FormatTableWithDesc(fields []Field) string {
-	return renderTable(fields, true)
+	return renderTable(fields, true, false, 0)
+}
+
+// FormatTableWithSource renders a table with a trailing Source column
+// holding each field's Origin, plus a Description column when includeDesc
+// is set.
+func FormatTableWithSource(fields []Field, includeDesc bool) string {
+	return renderTable(fields, includeDesc, true, 0)
+}
+
+// FormatTableFit renders the table FormatTable, FormatTableWithDesc, or
+// FormatTableWithSource would, narrowed to at most maxWidth terminal
+// columns: long values and descriptions are cut short with "…" rather than
+// wrapping and breaking the alignment. maxWidth <= 0 means no limit.
+func FormatTableFit(fields []Field, includeDesc, includeSource bool, maxWidth int) string {
+	return renderTable(fields, includeDesc, includeSource, maxWidth)
 }
 
 // FormatFieldsJSONWithDesc serializes fields as JSON objects including
@@ -508,6 +1013,21 @@ func FormatTableWithDesc(fields []Field) string {
 // If all fields share the same category, a flat object is produced.
 // If fields span multiple categories, they are grouped by category name.
 func FormatFieldsJSONWithDesc(fields []Field) (string, error) {
+	return formatFieldsJSONDetailed(fields, true, false)
+}
+
+// FormatFieldsJSONWithSource serializes fields like FormatFieldsJSONWithDesc,
+// adding each field's provenance, and its description when includeDesc is
+// set:
+//
+//	{"value": ..., "source": {"layer": "global", "file": "...", "line": 3}}
+func FormatFieldsJSONWithSource(fields []Field, includeDesc bool) (string, error) {
+	return formatFieldsJSONDetailed(fields, includeDesc, true)
+}
+
+// formatFieldsJSONDetailed is the shared implementation for
+// FormatFieldsJSONWithDesc and FormatFieldsJSONWithSource.
+func formatFieldsJSONDetailed(fields []Field, includeDesc, includeSource bool) (string, error) {
 	if len(fields) == 0 {
 		data, err := json.MarshalIndent(map[string]interface{}{}, "", "  ")
 		if err != nil {
@@ -522,11 +1042,22 @@ func FormatFieldsJSONWithDesc(fields []Field) (string, error) {
 			if IsDescKey(f.Key) {
 				continue
 			}
-			om.keys = append(om.keys, f.Key)
-			om.values[f.Key] = map[string]interface{}{
-				"value":       f.Value,
-				"description": f.Desc,
+			entry := map[string]interface{}{"value": f.Value}
+			if includeDesc {
+				entry["description"] = f.Desc
+			}
+			if includeSource {
+				src := map[string]interface{}{"file": f.Source}
+				if f.Layer != "" {
+					src["layer"] = f.Layer
+				}
+				if f.Line > 0 {
+					src["line"] = f.Line
+				}
+				entry["source"] = src
 			}
+			om.keys = append(om.keys, f.Key)
+			om.values[f.Key] = entry
 		}
 		return om
 	}
@@ -594,29 +1125,23 @@ func FormatDiffTable(entries []DiffEntry) string {
 	localWidth := len("Local")
 
 	for _, e := range entries {
-		if len(e.Path) > pathWidth {
-			pathWidth = len(e.Path)
-		}
-		if len(e.Status) > statusWidth {
-			statusWidth = len(e.Status)
-		}
-		if len(e.GlobalVal) > globalWidth {
-			globalWidth = len(e.GlobalVal)
-		}
-		if len(e.LocalVal) > localWidth {
-			localWidth = len(e.LocalVal)
-		}
+		pathWidth = max(pathWidth, DisplayWidth(e.Path))
+		statusWidth = max(statusWidth, DisplayWidth(e.Status))
+		globalWidth = max(globalWidth, DisplayWidth(e.GlobalVal))
+		localWidth = max(localWidth, DisplayWidth(e.LocalVal))
 	}
 
 	var b strings.Builder
-	fmt.Fprintf(&b, "%-*s    %-*s    %-*s    %s\n", pathWidth, "Path", statusWidth, "Status", globalWidth, "Global", "Local")
-	fmt.Fprintf(&b, "%-*s    %-*s    %-*s    %s\n",
-		pathWidth, repeatRune('\u2500', pathWidth),
-		statusWidth, repeatRune('\u2500', statusWidth),
-		globalWidth, repeatRune('\u2500', globalWidth),
+	fmt.Fprintf(&b, "%s    %s    %s    %s\n",
+		PadRight("Path", pathWidth), PadRight("Status", statusWidth), PadRight("Global", globalWidth), "Local")
+	fmt.Fprintf(&b, "%s    %s    %s    %s\n",
+		repeatRune('\u2500', pathWidth),
+		repeatRune('\u2500', statusWidth),
+		repeatRune('\u2500', globalWidth),
 		repeatRune('\u2500', localWidth))
 	for _, e := range entries {
-		fmt.Fprintf(&b, "%-*s    %-*s    %-*s    %s\n", pathWidth, e.Path, statusWidth, e.Status, globalWidth, e.GlobalVal, e.LocalVal)
+		fmt.Fprintf(&b, "%s    %s    %s    %s\n",
+			PadRight(e.Path, pathWidth), PadRight(e.Status, statusWidth), PadRight(e.GlobalVal, globalWidth), e.LocalVal)
 	}
 	return b.String()
 }
@@ -647,28 +1172,260 @@ func FormatDiffJSON(entries []DiffEntry) (string, error) {
 	return string(data), nil
 }
 
-// yamlNeedsQuoting reports whether a YAML string value requires quoting
-// to avoid ambiguity with YAML special values or characters.
-func yamlNeedsQuoting(s string) bool {
+// dotenvKey uppercases s and replaces characters that are not valid in
+// environment variable names with underscores.
+func dotenvKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// dotenvValue quotes a value for a .env file. See FormatDotenv.
+func dotenvValue(s string) string {
+	if !strings.ContainsAny(s, "'\n\r") {
+		return "'" + s + "'"
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, `$`, `\$`, "`", "\\`")
+	return `"` + r.Replace(s) + `"`
+}
+
+// iniValue quotes a value for an INI file when it would otherwise be
+// misread. See FormatINI.
+func iniValue(s string) string {
+	if s != "" && s == strings.TrimSpace(s) && !strings.ContainsAny(s, ";#=\"'\\\n\r") {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// hclValue formats a Go value as an HCL expression literal.
+func hclValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return hclString(val)
+	case map[string]interface{}:
+		parts := make([]string, 0, len(val))
+		for _, k := range RecordKeys(val) {
+			key := k
+			if !isHCLIdent(k) {
+				key = hclString(k)
+			}
+			parts = append(parts, fmt.Sprintf("%s = %s", key, hclValue(val[k])))
+		}
+		return "{ " + strings.Join(parts, ", ") + " }"
+	case []map[string]interface{}:
+		parts := make([]string, 0, len(val))
+		for _, rec := range val {
+			parts = append(parts, hclValue(rec))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case []interface{}:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, hclValue(item))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case []string:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, hclString(item))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case int64, float64, bool:
+		return fmt.Sprint(val)
+	default:
+		return hclString(FormatValue(v))
+	}
+}
+
+// hclString quotes s as an HCL string literal, escaping template sequences.
+func hclString(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`,
+		"${", "$${", "%{", "%%{",
+	)
+	return `"` + r.Replace(s) + `"`
+}
+
+// isHCLIdent reports whether s is an HCL identifier: an ASCII letter or
+// '_' followed by letters, digits, '_' and '-'.
+func isHCLIdent(s string) bool {
+	for i, r := range s {
+		ok := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(i > 0 && (r == '-' || (r >= '0' && r <= '9')))
+		if !ok {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// hclName returns s as an HCL attribute name. Attribute names cannot be
+// quoted, so characters an identifier cannot hold become '_', and a name
+// that would start with a digit or '-' gets a leading '_'.
+func hclName(s string) string {
+	if isHCLIdent(s) {
+		return s
+	}
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+		case r == '-' || (r >= '0' && r <= '9'):
+			if i == 0 {
+				b.WriteByte('_')
+			}
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// nixValue formats a Go value as a Nix expression literal.
+func nixValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return nixString(val)
+	case map[string]interface{}:
+		parts := make([]string, 0, len(val))
+		for _, k := range RecordKeys(val) {
+			parts = append(parts, fmt.Sprintf("%s = %s;", nixName(k), nixValue(val[k])))
+		}
+		return "{ " + strings.Join(parts, " ") + " }"
+	case []map[string]interface{}:
+		parts := make([]string, 0, len(val))
+		for _, rec := range val {
+			parts = append(parts, nixValue(rec))
+		}
+		return "[ " + strings.Join(parts, " ") + " ]"
+	case []interface{}:
+		if len(val) == 0 {
+			return "[ ]"
+		}
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, nixValue(item))
+		}
+		return "[ " + strings.Join(parts, " ") + " ]"
+	case []string:
+		if len(val) == 0 {
+			return "[ ]"
+		}
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, nixString(item))
+		}
+		return "[ " + strings.Join(parts, " ") + " ]"
+	case int64, bool:
+		return fmt.Sprint(val)
+	case float64:
+		s := fmt.Sprint(val)
+		if !strings.ContainsAny(s, ".eE") {
+			// Nix would read a bare "3" as an integer.
+			s += ".0"
+		}
+		return s
+	default:
+		return nixString(FormatValue(v))
+	}
+}
+
+// nixString quotes s as a Nix string literal, escaping antiquotation.
+func nixString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// nixName returns s as a Nix attribute name, quoting it unless it is a
+// valid identifier.
+func nixName(s string) string {
+	for i, r := range s {
+		ok := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(i > 0 && (r == '-' || r == '\'' || (r >= '0' && r <= '9')))
+		if !ok {
+			return nixString(s)
+		}
+	}
 	if s == "" {
-		return true
-	}
-	// Values that YAML would interpret as special types.
-	lower := strings.ToLower(s)
-	switch lower {
-	case "true", "false", "yes", "no", "on", "off", "null", "~":
-		return true
-	}
-	// If it starts or ends with whitespace, or contains characters that
-	// could confuse a YAML parser.
-	if s[0] == ' ' || s[len(s)-1] == ' ' {
-		return true
-	}
-	for _, c := range s {
-		switch c {
-		case ':', '#', '[', ']', '{', '}', ',', '&', '*', '!', '|', '>', '\'', '"', '%', '@', '`':
-			return true
+		return `""`
+	}
+	return s
+}
+
+// writePlistValue writes v as a plist value element at the given indent.
+func writePlistValue(b *strings.Builder, v interface{}, indent string) {
+	writeArray := func(items []interface{}) {
+		if len(items) == 0 {
+			fmt.Fprintf(b, "%s<array/>\n", indent)
+			return
+		}
+		fmt.Fprintf(b, "%s<array>\n", indent)
+		for _, item := range items {
+			writePlistValue(b, item, indent+"\t")
 		}
+		fmt.Fprintf(b, "%s</array>\n", indent)
 	}
-	return false
+
+	switch val := v.(type) {
+	case string:
+		fmt.Fprintf(b, "%s<string>%s</string>\n", indent, xmlEscape(val))
+	case int64:
+		fmt.Fprintf(b, "%s<integer>%d</integer>\n", indent, val)
+	case float64:
+		fmt.Fprintf(b, "%s<real>%s</real>\n", indent, fmt.Sprint(val))
+	case bool:
+		fmt.Fprintf(b, "%s<%t/>\n", indent, val)
+	case map[string]interface{}:
+		fmt.Fprintf(b, "%s<dict>\n", indent)
+		for _, k := range RecordKeys(val) {
+			fmt.Fprintf(b, "%s\t<key>%s</key>\n", indent, xmlEscape(k))
+			writePlistValue(b, val[k], indent+"\t")
+		}
+		fmt.Fprintf(b, "%s</dict>\n", indent)
+	case []map[string]interface{}:
+		items := make([]interface{}, len(val))
+		for i, rec := range val {
+			items[i] = rec
+		}
+		writeArray(items)
+	case []interface{}:
+		writeArray(val)
+	case []string:
+		items := make([]interface{}, len(val))
+		for i, s := range val {
+			items[i] = s
+		}
+		writeArray(items)
+	case time.Time:
+		fmt.Fprintf(b, "%s<date>%s</date>\n", indent, val.UTC().Format("2006-01-02T15:04:05Z"))
+	default:
+		fmt.Fprintf(b, "%s<string>%s</string>\n", indent, xmlEscape(fmt.Sprintf("%v", v)))
+	}
+}
+
+// xmlEscape escapes s for use as XML character data.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// markdownCell escapes a value for use inside a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
 }
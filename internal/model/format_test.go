@@ -1,9 +1,14 @@
 package model
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 // ---------------------------------------------------------------------------
@@ -299,18 +304,18 @@ func TestFormatFieldsJSON_Empty(t *testing.T) {
 
 func TestFormatEnv(t *testing.T) {
 	db := newTestDB()
-	out := FormatEnv(db)
+	out, _ := FormatEnv(db, "posix")
 
 	// Check expected env var lines.
 	expectedLines := []string{
-		`DEETS_IDENTITY_NAME="Alexander Towell"`,
-		`DEETS_IDENTITY_AKA="Alex Towell, Alex T"`,
-		`DEETS_IDENTITY_AGE="35"`,
-		`DEETS_WEB_GITHUB="queelius"`,
-		`DEETS_WEB_WEBSITE="https://example.com"`,
-		`DEETS_ACADEMIC_ORCID="0000-0001-2345-6789"`,
-		`DEETS_ACADEMIC_GPA="3.95"`,
-		`DEETS_ACADEMIC_TOPICS="statistics, machine learning"`,
+		`DEETS_IDENTITY_NAME='Alexander Towell'`,
+		`DEETS_IDENTITY_AKA='Alex Towell, Alex T'`,
+		`DEETS_IDENTITY_AGE='35'`,
+		`DEETS_WEB_GITHUB='queelius'`,
+		`DEETS_WEB_WEBSITE='https://example.com'`,
+		`DEETS_ACADEMIC_ORCID='0000-0001-2345-6789'`,
+		`DEETS_ACADEMIC_GPA='3.95'`,
+		`DEETS_ACADEMIC_TOPICS='statistics, machine learning'`,
 	}
 
 	for _, line := range expectedLines {
@@ -322,7 +327,7 @@ func TestFormatEnv(t *testing.T) {
 
 func TestFormatEnv_Uppercase(t *testing.T) {
 	db := newTestDB()
-	out := FormatEnv(db)
+	out, _ := FormatEnv(db, "posix")
 
 	lines := strings.Split(strings.TrimSpace(out), "\n")
 	for _, line := range lines {
@@ -343,7 +348,7 @@ func TestFormatEnv_Uppercase(t *testing.T) {
 
 func TestFormatEnv_DescExcluded(t *testing.T) {
 	db := newTestDB()
-	out := FormatEnv(db)
+	out, _ := FormatEnv(db, "posix")
 
 	if strings.Contains(out, "_DESC=") && strings.Contains(out, "NAME_DESC") {
 		t.Error("FormatEnv should exclude _desc fields")
@@ -352,7 +357,7 @@ func TestFormatEnv_DescExcluded(t *testing.T) {
 
 func TestFormatEnv_Quoting(t *testing.T) {
 	db := newTestDB()
-	out := FormatEnv(db)
+	out, _ := FormatEnv(db, "posix")
 
 	// All values should be quoted (surrounded by double-quotes after =)
 	lines := strings.Split(strings.TrimSpace(out), "\n")
@@ -362,15 +367,42 @@ func TestFormatEnv_Quoting(t *testing.T) {
 			continue
 		}
 		val := line[eqIdx+1:]
-		if !strings.HasPrefix(val, `"`) || !strings.HasSuffix(val, `"`) {
-			t.Errorf("env value should be double-quoted: %s", line)
+		if !strings.HasPrefix(val, "'") || !strings.HasSuffix(val, "'") {
+			t.Errorf("env value should be single-quoted: %s", line)
 		}
 	}
 }
 
+func TestFormatEnv_Shells(t *testing.T) {
+	db := &DB{Categories: []Category{{Name: "identity", Fields: []Field{
+		{Category: "identity", Key: "name", Value: `O'Brien "Al" \ $HOME` + "\nline two"},
+		{Category: "identity", Key: "full-name", Value: "x"},
+	}}}}
+	cases := map[string]string{
+		"posix":      `DEETS_IDENTITY_NAME='O'\''Brien "Al" \ $HOME` + "\nline two'\n",
+		"fish":       `set -gx DEETS_IDENTITY_NAME 'O\'Brien "Al" \\ $HOME` + "\nline two'\n",
+		"powershell": `$env:DEETS_IDENTITY_NAME = 'O''Brien "Al" \ $HOME` + "\nline two'\n",
+	}
+	for shell, want := range cases {
+		out, err := FormatEnv(db, shell)
+		if err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		if !strings.HasPrefix(out, want) {
+			t.Errorf("%s: got\n%s\nwant prefix\n%s", shell, out, want)
+		}
+		if !strings.Contains(out, "DEETS_IDENTITY_FULL_NAME") {
+			t.Errorf("%s: expected key sanitized to DEETS_IDENTITY_FULL_NAME:\n%s", shell, out)
+		}
+	}
+	if _, err := FormatEnv(db, "csh"); err == nil {
+		t.Error("expected error for unknown shell")
+	}
+}
+
 func TestFormatEnv_EmptyDB(t *testing.T) {
 	db := &DB{}
-	out := FormatEnv(db)
+	out, _ := FormatEnv(db, "posix")
 	if out != "" {
 		t.Errorf("expected empty string for empty DB, got %q", out)
 	}
@@ -815,7 +847,7 @@ func TestFormatFieldsJSON_WithTestDB(t *testing.T) {
 
 func TestFormatEnv_WithTestDB(t *testing.T) {
 	db := newTestDB()
-	out := FormatEnv(db)
+	out, _ := FormatEnv(db, "posix")
 
 	// Count the number of lines (should match non-desc fields = 8).
 	lines := strings.Split(strings.TrimSpace(out), "\n")
@@ -969,8 +1001,8 @@ func TestFormatYAML_FallbackType(t *testing.T) {
 		},
 	}
 	out := FormatYAML(db)
-	if !strings.Contains(out, "  unknown: {42}") {
-		t.Errorf("YAML should use %%v for fallback, got:\n%s", out)
+	if !strings.Contains(out, `  unknown: "{42}"`) {
+		t.Errorf("YAML should quote the %%v string for fallback, got:\n%s", out)
 	}
 }
 
@@ -1167,6 +1199,75 @@ func TestFormatFieldsJSONWithDesc_Empty(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Provenance (--source)
+// ---------------------------------------------------------------------------
+
+func TestFieldOrigin(t *testing.T) {
+	tests := []struct {
+		f    Field
+		want string
+	}{
+		{Field{Layer: "local", Source: "/p/.deets/me.toml", Line: 12}, "local:12"},
+		{Field{Source: "/tmp/x.toml", Line: 3}, "/tmp/x.toml:3"},
+		{Field{Layer: "global"}, "global"},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Origin(); got != tt.want {
+			t.Errorf("Origin() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestFormatTableWithSource(t *testing.T) {
+	fields := []Field{
+		{Key: "name", Value: "Alexander", Desc: "Full name", Category: "identity", Layer: "global", Line: 2},
+	}
+
+	out := FormatTableWithSource(fields, false)
+	if !strings.Contains(out, "Source") || !strings.Contains(out, "global:2") {
+		t.Errorf("expected Source column with global:2, got:\n%s", out)
+	}
+	if strings.Contains(out, "Description") {
+		t.Error("Description column should only appear with includeDesc")
+	}
+
+	out = FormatTableWithSource(fields, true)
+	if !strings.Contains(out, "Full name") {
+		t.Errorf("expected description with includeDesc, got:\n%s", out)
+	}
+}
+
+func TestFormatFieldsJSONWithSource(t *testing.T) {
+	fields := []Field{
+		{Key: "name", Value: "Alexander", Category: "identity", Source: "/h/.deets/me.toml", Layer: "global", Line: 2},
+	}
+
+	out, err := FormatFieldsJSONWithSource(fields, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	entry := parsed["name"]
+	if entry["value"] != "Alexander" {
+		t.Errorf("unexpected value: %v", entry["value"])
+	}
+	if _, ok := entry["description"]; ok {
+		t.Error("description should be omitted without includeDesc")
+	}
+	src, ok := entry["source"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected source object, got %v", entry["source"])
+	}
+	if src["layer"] != "global" || src["file"] != "/h/.deets/me.toml" || src["line"] != float64(2) {
+		t.Errorf("unexpected source: %v", src)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // FormatValueTOML
 // ---------------------------------------------------------------------------
@@ -1192,6 +1293,39 @@ func TestFormatValueTOML_Array(t *testing.T) {
 	}
 }
 
+func TestTOMLString(t *testing.T) {
+	if got := TOMLString("plain"); got != `"plain"` {
+		t.Errorf("single line: got %q", got)
+	}
+	got := TOMLString("a\n\"\"\"b\"")
+	want := "\"\"\"\na\n\"\"\\\"b\\\"\"\"\""
+	if got != want {
+		t.Errorf("multi-line: got %q, want %q", got, want)
+	}
+	if got := TOMLString("bell\a \"q\" \\ \x7f"); got != `"bell\u0007 \"q\" \\ \u007F"` {
+		t.Errorf("escapes: got %q", got)
+	}
+}
+
+func TestFormatTable_MultiLineValue(t *testing.T) {
+	fields := []Field{
+		{Category: "identity", Key: "bio", Value: "first line\nsecond"},
+		{Category: "identity", Key: "name", Value: "Alex"},
+	}
+	out := FormatTable(fields)
+	want := "bio     first line\n        second\nname    Alex\n"
+	if !strings.Contains(out, want) {
+		t.Errorf("expected continuation line, got:\n%s", out)
+	}
+}
+
+func TestFormatValueTOML_QuotesTableKeys(t *testing.T) {
+	got := FormatValueTOML(map[string]interface{}{"first name": "Alex", "id": int64(1)})
+	if got != `{ "first name" = "Alex", id = 1 }` {
+		t.Errorf("expected quoted key, got %q", got)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Diff formatters
 // ---------------------------------------------------------------------------
@@ -1235,3 +1369,551 @@ func TestFormatDiffJSON(t *testing.T) {
 		t.Error("expected 'override' in JSON output")
 	}
 }
+
+// ---------------------------------------------------------------------------
+// FormatCSV
+// ---------------------------------------------------------------------------
+
+func TestFormatCSV(t *testing.T) {
+	out, err := FormatCSV(newTestDB().AllFields())
+	if err != nil {
+		t.Fatalf("FormatCSV error: %v", err)
+	}
+
+	r := csv.NewReader(strings.NewReader(out))
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v\n%s", err, out)
+	}
+	if strings.Join(records[0], ",") != "category,key,value,description" {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+	// header + 3 identity + 2 web + 3 academic
+	if len(records) != 9 {
+		t.Errorf("expected 9 rows, got %d", len(records))
+	}
+	if strings.Join(records[1], "|") != "identity|name|Alexander Towell|Full legal name" {
+		t.Errorf("unexpected first row: %v", records[1])
+	}
+}
+
+func TestFormatCSV_Quoting(t *testing.T) {
+	fields := []Field{
+		{Key: "aka", Value: []interface{}{"Alex, T", "Lex"}, Category: "identity"},
+		{Key: "quote", Value: `say "hi"`, Category: "identity"},
+		{Key: "bio", Value: "line one\nline two", Category: "identity"},
+	}
+	out, err := FormatCSV(fields)
+	if err != nil {
+		t.Fatalf("FormatCSV error: %v", err)
+	}
+	if !strings.Contains(out, `"Alex, T, Lex"`) {
+		t.Errorf("expected comma-containing value to be quoted, got %q", out)
+	}
+	if !strings.Contains(out, `"say ""hi"""`) {
+		t.Errorf("expected embedded quotes to be doubled, got %q", out)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if records[3][2] != "line one\nline two" {
+		t.Errorf("expected multi-line value to round-trip, got %q", records[3][2])
+	}
+}
+
+func TestFormatCSV_DescExcluded(t *testing.T) {
+	out, err := FormatCSV(newTestDB().Categories[0].Fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "name_desc") {
+		t.Error("CSV output should not contain _desc keys")
+	}
+}
+
+func TestFormatCSV_Empty(t *testing.T) {
+	out, err := FormatCSV(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "category,key,value,description\n" {
+		t.Errorf("expected header only, got %q", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// FormatMarkdown
+// ---------------------------------------------------------------------------
+
+func TestFormatMarkdown_SingleCategory(t *testing.T) {
+	out := FormatMarkdown(newTestDB().Categories[1].Fields)
+	expected := "| Key | Value |\n| --- | --- |\n| github | queelius |\n| website | https://example.com |\n"
+	if out != expected {
+		t.Errorf("unexpected markdown:\n%s", out)
+	}
+}
+
+func TestFormatMarkdown_MultiCategory(t *testing.T) {
+	out := FormatMarkdown(newTestDB().AllFields())
+	if !strings.HasPrefix(out, "| Category | Key | Value |\n| --- | --- | --- |\n") {
+		t.Errorf("expected Category column header, got:\n%s", out)
+	}
+	if !strings.Contains(out, "| identity | aka | Alex Towell, Alex T |") {
+		t.Errorf("expected array row, got:\n%s", out)
+	}
+}
+
+func TestFormatMarkdown_Escaping(t *testing.T) {
+	fields := []Field{
+		{Key: "pipe", Value: "a|b", Category: "x"},
+		{Key: "bio", Value: "line one\nline two", Category: "x"},
+	}
+	out := FormatMarkdown(fields)
+	if !strings.Contains(out, `| pipe | a\|b |`) {
+		t.Errorf("expected escaped pipe, got:\n%s", out)
+	}
+	if !strings.Contains(out, "| bio | line one<br>line two |") {
+		t.Errorf("expected newline as <br>, got:\n%s", out)
+	}
+}
+
+func TestFormatMarkdown_Empty(t *testing.T) {
+	if out := FormatMarkdown(nil); out != "" {
+		t.Errorf("expected empty string, got %q", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// FormatNDJSON
+// ---------------------------------------------------------------------------
+
+func TestFormatNDJSON(t *testing.T) {
+	out, err := FormatNDJSON(newTestDB().AllFields())
+	if err != nil {
+		t.Fatalf("FormatNDJSON error: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("expected 8 lines, got %d:\n%s", len(lines), out)
+	}
+	if lines[0] != `{"category":"identity","key":"name","value":"Alexander Towell","description":"Full legal name"}` {
+		t.Errorf("unexpected first line: %s", lines[0])
+	}
+	for _, l := range lines {
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(l), &obj); err != nil {
+			t.Errorf("line is not valid JSON: %s", l)
+		}
+	}
+}
+
+func TestFormatNDJSON_KeepsTypes(t *testing.T) {
+	out, err := FormatNDJSON(newTestDB().Categories[0].Fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `"value":["Alex Towell","Alex T"]`) {
+		t.Errorf("expected array value to stay an array, got:\n%s", out)
+	}
+	if !strings.Contains(out, `"value":35`) {
+		t.Errorf("expected integer value to stay numeric, got:\n%s", out)
+	}
+	if strings.Contains(out, "name_desc") {
+		t.Error("NDJSON should not contain _desc keys")
+	}
+}
+
+func TestFormatNDJSON_Empty(t *testing.T) {
+	out, err := FormatNDJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "" {
+		t.Errorf("expected empty output, got %q", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// FormatDotenv
+// ---------------------------------------------------------------------------
+
+func TestFormatDotenv(t *testing.T) {
+	out := FormatDotenv(newTestDB(), "")
+	if !strings.Contains(out, "IDENTITY_NAME='Alexander Towell'\n") {
+		t.Errorf("expected unprefixed single-quoted value, got:\n%s", out)
+	}
+	if !strings.Contains(out, "IDENTITY_AKA='Alex Towell, Alex T'\n") {
+		t.Errorf("expected joined array value, got:\n%s", out)
+	}
+	if strings.Contains(out, "DEETS_") {
+		t.Error("dotenv output should have no DEETS_ prefix by default")
+	}
+	if strings.Contains(out, "NAME_DESC") {
+		t.Error("dotenv output should not contain _desc keys")
+	}
+}
+
+func TestFormatDotenv_Prefix(t *testing.T) {
+	out := FormatDotenv(newTestDB(), "APP_")
+	if !strings.Contains(out, "APP_WEB_GITHUB='queelius'\n") {
+		t.Errorf("expected prefixed key, got:\n%s", out)
+	}
+}
+
+func TestFormatDotenv_Escaping(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "my-cat",
+		Fields: []Field{
+			{Key: "quote", Value: "it's", Category: "my-cat"},
+			{Key: "bio", Value: "line one\nsaid \"hi\" \\o/", Category: "my-cat"},
+			{Key: "cost", Value: "$HOME", Category: "my-cat"},
+			{Key: "motto", Value: "it's $HOME `pwd`", Category: "my-cat"},
+		},
+	}}}
+	out := FormatDotenv(db, "")
+	if !strings.Contains(out, `MY_CAT_QUOTE="it's"`) {
+		t.Errorf("expected double-quoted value with sanitized key, got:\n%s", out)
+	}
+	if !strings.Contains(out, `MY_CAT_BIO="line one\nsaid \"hi\" \\o/"`) {
+		t.Errorf("expected escaped multi-line value, got:\n%s", out)
+	}
+	if !strings.Contains(out, `MY_CAT_COST='$HOME'`) {
+		t.Errorf("expected literal single-quoted dollar value, got:\n%s", out)
+	}
+	if !strings.Contains(out, "MY_CAT_MOTTO=\"it's \\$HOME \\`pwd\\`\"") {
+		t.Errorf("expected escaped $ and backticks in a double-quoted value, got:\n%s", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// FormatINI
+// ---------------------------------------------------------------------------
+
+func TestFormatINI(t *testing.T) {
+	out := FormatINI(newTestDB())
+	if !strings.HasPrefix(out, "[identity]\nname = Alexander Towell\n") {
+		t.Errorf("expected identity section first, got:\n%s", out)
+	}
+	if !strings.Contains(out, "\n\n[web]\ngithub = queelius\n") {
+		t.Errorf("expected blank line between sections, got:\n%s", out)
+	}
+	if !strings.Contains(out, "aka = Alex Towell, Alex T\n") {
+		t.Errorf("expected joined array, got:\n%s", out)
+	}
+	if strings.Contains(out, "_desc") {
+		t.Error("INI output should not contain _desc keys")
+	}
+}
+
+func TestFormatINI_Escaping(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "x",
+		Fields: []Field{
+			{Key: "semi", Value: "work; play", Category: "x"},
+			{Key: "hash", Value: "C# dev", Category: "x"},
+			{Key: "eq", Value: "a=b", Category: "x"},
+			{Key: "bio", Value: "one\ntwo \"q\"", Category: "x"},
+			{Key: "pad", Value: " padded ", Category: "x"},
+			{Key: "empty", Value: "", Category: "x"},
+		},
+	}}}
+	out := FormatINI(db)
+	for _, want := range []string{
+		`semi = "work; play"`,
+		`hash = "C# dev"`,
+		`eq = "a=b"`,
+		`bio = "one\ntwo \"q\""`,
+		`pad = " padded "`,
+		`empty = ""`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("expected %s in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatINI_EmptyDB(t *testing.T) {
+	if out := FormatINI(&DB{}); out != "" {
+		t.Errorf("expected empty output, got %q", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// FormatHCL
+// ---------------------------------------------------------------------------
+
+func TestFormatHCL(t *testing.T) {
+	out := FormatHCL(newTestDB())
+	expected := `category "identity" {
+  name = "Alexander Towell"
+  aka  = ["Alex Towell", "Alex T"]
+  age  = 35
+}
+`
+	if !strings.HasPrefix(out, expected) {
+		t.Errorf("unexpected HCL:\n%s", out)
+	}
+	if !strings.Contains(out, "\n\ncategory \"web\" {\n") {
+		t.Errorf("expected web block separated by blank line, got:\n%s", out)
+	}
+	if !strings.Contains(out, "gpa    = 3.95\n") {
+		t.Errorf("expected unquoted float, got:\n%s", out)
+	}
+	if strings.Contains(out, "_desc") {
+		t.Error("HCL output should not contain _desc keys")
+	}
+}
+
+func TestFormatHCL_Escaping(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "x",
+		Fields: []Field{
+			{Key: "tmpl", Value: "${var.x} and %{if}", Category: "x"},
+			{Key: "quote", Value: "say \"hi\"\n", Category: "x"},
+			{Key: "flag", Value: true, Category: "x"},
+		},
+	}}}
+	out := FormatHCL(db)
+	if !strings.Contains(out, `tmpl  = "$${var.x} and %%{if}"`) {
+		t.Errorf("expected template sequences to be escaped, got:\n%s", out)
+	}
+	if !strings.Contains(out, `quote = "say \"hi\"\n"`) {
+		t.Errorf("expected quotes and newline escaped, got:\n%s", out)
+	}
+	if !strings.Contains(out, "flag  = true") {
+		t.Errorf("expected bare bool, got:\n%s", out)
+	}
+}
+
+func TestFormatHCL_KeyNames(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "security",
+		Fields: []Field{
+			{Key: "2fa", Value: true, Category: "security"},
+			{Key: "gpg-key", Value: "ABCD", Category: "security"},
+			{Key: "key.id", Value: "1", Category: "security"},
+			{Key: "key_id", Value: "2", Category: "security"},
+			{Key: "hosts", Value: map[string]interface{}{"1.example": "a", "ok": "b"}, Category: "security"},
+		},
+	}}}
+	out := FormatHCL(db)
+	for _, want := range []string{
+		"  _2fa     = true\n",
+		"  gpg-key  = \"ABCD\"\n",
+		"  key_id_2 = \"1\"\n",
+		"  key_id   = \"2\"\n",
+		`hosts    = { "1.example" = "a", ok = "b" }`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatHCL_Records(t *testing.T) {
+	out := FormatHCL(newRecordsDB())
+	if !strings.Contains(out, `degrees = [{ degree = "PhD", field = "Statistics", institution = "SIUE", year = 2020 }, { degree = "BS", institution = "UIUC" }]`) {
+		t.Errorf("expected records as a tuple of objects, got:\n%s", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// FormatNix
+// ---------------------------------------------------------------------------
+
+func TestFormatNix(t *testing.T) {
+	out := FormatNix(newTestDB())
+	expected := `{
+  identity = {
+    name = "Alexander Towell";
+    aka = [ "Alex Towell" "Alex T" ];
+    age = 35;
+  };
+`
+	if !strings.HasPrefix(out, expected) {
+		t.Errorf("unexpected Nix output:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "  };\n}\n") {
+		t.Errorf("expected closing braces, got:\n%s", out)
+	}
+	if !strings.Contains(out, "gpa = 3.95;") {
+		t.Errorf("expected float literal, got:\n%s", out)
+	}
+	if !strings.Contains(out, `topics = [ "statistics" "machine learning" ];`) {
+		t.Errorf("expected []string list, got:\n%s", out)
+	}
+	if strings.Contains(out, "_desc") {
+		t.Error("Nix output should not contain _desc keys")
+	}
+}
+
+func TestFormatNix_Escaping(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "my cat",
+		Fields: []Field{
+			{Key: "tmpl", Value: "${pkgs.hello} \"q\"", Category: "my cat"},
+			{Key: "flag", Value: false, Category: "my cat"},
+			{Key: "whole", Value: float64(3), Category: "my cat"},
+			{Key: "2fa", Value: "yes", Category: "my cat"},
+		},
+	}}}
+	out := FormatNix(db)
+	for _, want := range []string{
+		`"my cat" = {`,
+		`tmpl = "\${pkgs.hello} \"q\"";`,
+		`flag = false;`,
+		`whole = 3.0;`,
+		`"2fa" = "yes";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatNix_EmptyDB(t *testing.T) {
+	if out := FormatNix(&DB{}); out != "{\n}\n" {
+		t.Errorf("expected empty attrset, got %q", out)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// FormatPlist
+// ---------------------------------------------------------------------------
+
+func TestFormatPlist(t *testing.T) {
+	out := FormatPlist(newTestDB())
+	if !strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8"?>`) {
+		t.Errorf("expected XML declaration, got:\n%s", out)
+	}
+	for _, want := range []string{
+		"\t<key>identity</key>\n\t<dict>\n\t\t<key>name</key>\n\t\t<string>Alexander Towell</string>\n",
+		"\t\t<key>aka</key>\n\t\t<array>\n\t\t\t<string>Alex Towell</string>\n\t\t\t<string>Alex T</string>\n\t\t</array>\n",
+		"\t\t<integer>35</integer>\n",
+		"\t\t<real>3.95</real>\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "_desc") {
+		t.Error("plist output should not contain _desc keys")
+	}
+
+	// The document must be well-formed XML.
+	dec := xml.NewDecoder(strings.NewReader(out))
+	for {
+		if _, err := dec.Token(); err != nil {
+			if err.Error() != "EOF" {
+				t.Fatalf("plist is not well-formed XML: %v", err)
+			}
+			break
+		}
+	}
+}
+
+func TestFormatPlist_EscapingAndTypes(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "x",
+		Fields: []Field{
+			{Key: "html", Value: "<b>Tom & Jerry</b>", Category: "x"},
+			{Key: "on", Value: true, Category: "x"},
+			{Key: "none", Value: []interface{}{}, Category: "x"},
+		},
+	}}}
+	out := FormatPlist(db)
+	for _, want := range []string{
+		"<string>&lt;b&gt;Tom &amp; Jerry&lt;/b&gt;</string>",
+		"<true/>",
+		"<array/>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatPlist_Records(t *testing.T) {
+	out := FormatPlist(newRecordsDB())
+	if !strings.Contains(out, "\t\t<array>\n\t\t\t<dict>\n\t\t\t\t<key>degree</key>\n\t\t\t\t<string>PhD</string>\n") {
+		t.Errorf("expected records as array of dicts, got:\n%s", out)
+	}
+}
+
+func TestFormatTOML_NestedTables(t *testing.T) {
+	out := FormatTOML(newNestedDB())
+	for _, want := range []string{
+		"[education]\nfield = \"Statistics\"\n",
+		"\n[education.phd]\ninstitution = \"SIUE\"\nyear = 2020\n",
+		"\n[education.phd.advisor]\nname = \"Bob\"\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "degrees =") > strings.Index(out, "[education.phd]") {
+		t.Errorf("keys should precede sub-tables:\n%s", out)
+	}
+}
+
+func TestFormatYAML_RoundTrip(t *testing.T) {
+	values := map[string]interface{}{
+		"quote":     `say "hi" and 'bye'`,
+		"multiline": "first line\nsecond: line\n",
+		"colon":     "a: b # c",
+		"numeric":   "0123",
+		"control":   "bell\a",
+		"list":      []interface{}{"a, b", "true", "x\ny"},
+		"float":     float64(4),
+		"nested":    map[string]interface{}{"key: odd": "v"},
+	}
+	db := &DB{Categories: []Category{{Name: "test"}}}
+	for _, k := range RecordKeys(values) {
+		db.Categories[0].Fields = append(db.Categories[0].Fields, Field{Category: "test", Key: k, Value: values[k]})
+	}
+	out := FormatYAML(db)
+
+	var parsed map[string]map[string]interface{}
+	if err := yaml.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, out)
+	}
+	for k, want := range values {
+		if got := parsed["test"][k]; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s: got %#v, want %#v\n%s", k, got, want, out)
+		}
+	}
+	if !strings.Contains(out, "  float: 4.0\n") {
+		t.Errorf("expected whole float as 4.0, got:\n%s", out)
+	}
+	if !strings.Contains(out, "  multiline: |\n    first line\n    second: line\n") {
+		t.Errorf("expected literal block scalar, got:\n%s", out)
+	}
+}
+
+func TestFormatYAML_NestedTables(t *testing.T) {
+	out := FormatYAML(newNestedDB())
+	want := "  phd:\n    institution: SIUE\n    year: 2020\n    advisor:\n      name: Bob\n"
+	if !strings.Contains(out, want) {
+		t.Errorf("output missing nested mapping:\n%s", out)
+	}
+}
+
+func TestFormatEnv_NestedTables(t *testing.T) {
+	out, _ := FormatEnv(newNestedDB(), "posix")
+	if !strings.Contains(out, `DEETS_EDUCATION_PHD_ADVISOR_NAME='Bob'`) {
+		t.Errorf("expected flattened nested key:\n%s", out)
+	}
+	out = FormatDotenv(newNestedDB(), "")
+	if !strings.Contains(out, "EDUCATION_PHD_INSTITUTION='SIUE'") {
+		t.Errorf("expected flattened nested key:\n%s", out)
+	}
+}
+
+func TestFormatTable_NestedTables(t *testing.T) {
+	out := FormatTable(newNestedDB().Categories[0].Fields)
+	if !strings.Contains(out, "phd.institution") || !strings.Contains(out, "SIUE") {
+		t.Errorf("expected dotted nested rows:\n%s", out)
+	}
+}
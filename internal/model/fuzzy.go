@@ -0,0 +1,98 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Fuzzy scoring weights. Every matched character earns fuzzyMatch; matches
+// that continue a run or start a word earn a bonus, and skipped characters
+// between matches cost fuzzyGap each.
+const (
+	fuzzyMatch       = 16
+	fuzzyConsecutive = 8
+	fuzzyBoundary    = 8
+	fuzzyGap         = 3
+)
+
+// ScoredField is a search result ranked by match quality.
+type ScoredField struct {
+	Field
+	Score int
+}
+
+// FuzzyScore reports whether the characters of query appear in s in order
+// (case-insensitively), and how well: consecutive runs and matches at word
+// boundaries score higher, gaps lower. "mastdon" matches "mastodon".
+func FuzzyScore(query, s string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, false
+	}
+	text := []rune(strings.ToLower(s))
+
+	score, qi, last := 0, 0, -1
+	for i, r := range text {
+		if qi == len(q) {
+			break
+		}
+		if r != q[qi] {
+			continue
+		}
+		score += fuzzyMatch
+		switch {
+		case last >= 0 && i == last+1:
+			score += fuzzyConsecutive
+		case last >= 0:
+			score -= fuzzyGap * (i - last - 1)
+		}
+		if i == 0 || !unicode.IsLetter(text[i-1]) && !unicode.IsDigit(text[i-1]) {
+			score += fuzzyBoundary
+		}
+		last = i
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
+
+// SearchFuzzy scores every field against query with FuzzyScore, taking the
+// best score among the parts selected by scope. Fields whose best score is
+// not positive are dropped; the rest are ordered by descending score, ties
+// keeping category order. Results exclude _desc fields.
+func (db *DB) SearchFuzzy(query string, scope SearchScope) []ScoredField {
+	var results []ScoredField
+	for _, cat := range db.Categories {
+		for _, f := range cat.Fields {
+			if IsDescKey(f.Key) {
+				continue
+			}
+			var parts []string
+			if scope&ScopeKeys != 0 {
+				parts = append(parts, f.Key)
+			}
+			if scope&ScopeValues != 0 {
+				parts = append(parts, FormatValue(f.Value))
+			}
+			if scope&ScopeDescriptions != 0 {
+				parts = append(parts, f.Desc)
+			}
+			best, found := 0, false
+			for _, p := range parts {
+				if score, ok := FuzzyScore(query, p); ok && (!found || score > best) {
+					best, found = score, true
+				}
+			}
+			if found && best > 0 {
+				results = append(results, ScoredField{f, best})
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := FuzzyScore("mastdon", "mastodon"); !ok {
+		t.Error("expected mastdon to match mastodon")
+	}
+	if _, ok := FuzzyScore("xyz", "mastodon"); ok {
+		t.Error("expected no match")
+	}
+	if _, ok := FuzzyScore("", "mastodon"); ok {
+		t.Error("empty query should not match")
+	}
+
+	exact, _ := FuzzyScore("git", "github")
+	scattered, _ := FuzzyScore("git", "go into the")
+	if exact <= scattered {
+		t.Errorf("prefix run should outrank scattered match: %d <= %d", exact, scattered)
+	}
+
+	boundary, _ := FuzzyScore("n", "full name")
+	inner, _ := FuzzyScore("n", "ornament")
+	if boundary <= inner {
+		t.Errorf("word start should outrank inner match: %d <= %d", boundary, inner)
+	}
+}
+
+func TestSearchFuzzy(t *testing.T) {
+	db := newTestDB()
+	results := db.SearchFuzzy("gthub", ScopeKeys)
+	if len(results) == 0 || results[0].Key != "github" {
+		t.Fatalf("expected github first, got %v", results)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("results not sorted by score: %v", results)
+		}
+	}
+	for _, r := range results {
+		if IsDescKey(r.Key) {
+			t.Errorf("_desc field in results: %s", r.Key)
+		}
+	}
+}
+
+func TestFormatScoredJSON(t *testing.T) {
+	out, err := FormatScoredJSON([]ScoredField{
+		{Field{Key: "mastodon", Value: "@a@b", Category: "web"}, 139},
+		{Field{Key: "name", Value: "Alex", Category: "identity"}, 20},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(parsed) != 2 || parsed[0]["key"] != "mastodon" || parsed[0]["score"] != float64(139) {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
@@ -0,0 +1,246 @@
+package model
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+)
+
+// goInitialisms are word segments rendered in upper case in Go identifiers.
+var goInitialisms = map[string]bool{
+	"api": true, "id": true, "html": true, "http": true, "json": true,
+	"pgp": true, "ssh": true, "uri": true, "url": true, "gpa": true,
+}
+
+// FormatGo generates Go source declaring a Deets struct with one field per
+// category, a struct type per category with one typed field per key, and
+// Parse/Load functions that decode `deets export --format json` output.
+// Field types follow InferType: string, int64, float64, bool, typed slices
+// for homogeneous arrays, and generated item structs for records.
+//
+// Type names are unique across the package: a category whose name maps to
+// Deets, Parse, Load, or another category's type ("web-site" and
+// "web_site") is numbered, as in Deets2 or WebSite2.
+func FormatGo(db *DB, pkg string) (string, error) {
+	g := &goGen{types: goNamer{"Deets": true, "Parse": true, "Load": true}}
+	typeNames := make([]string, len(db.Categories))
+	for i, cat := range db.Categories {
+		typeNames[i] = g.types.name(cat.Name)
+	}
+	g.line("// Code generated by deets generate go; DO NOT EDIT.")
+	g.line("")
+	g.line("package %s", pkg)
+	g.line("")
+	g.line("import (")
+	g.line("\t\"encoding/json\"")
+	g.line("\t\"os/exec\"")
+	g.line(")")
+	g.line("")
+	g.line("// Deets holds personal metadata from deets, one field per category.")
+	g.line("type Deets struct {")
+	for i, cat := range db.Categories {
+		g.line("\t%s %s `json:%q`", typeNames[i], typeNames[i], cat.Name)
+	}
+	g.line("}")
+
+	for i, cat := range db.Categories {
+		typeName := typeNames[i]
+		g.line("")
+		g.line("// %s holds the [%s] category.", typeName, cat.Name)
+		g.line("type %s struct {", typeName)
+		names := goNamer{}
+		for _, f := range cat.Fields {
+			if IsDescKey(f.Key) {
+				continue
+			}
+			if f.Desc != "" {
+				g.line("\t// %s", strings.ReplaceAll(f.Desc, "\n", " "))
+			}
+			name := names.name(f.Key)
+			g.line("\t%s %s `json:%q`", name, g.goType(typeName+name, f.Value), f.Key+",omitempty")
+		}
+		g.line("}")
+	}
+
+	for _, it := range g.items {
+		g.line("")
+		g.line("// %s is one record in a list of tables.", it.name)
+		g.line("type %s struct {", it.name)
+		names := goNamer{}
+		for _, k := range it.keys {
+			g.line("\t%s %s `json:%q`", names.name(k), it.types[k], k+",omitempty")
+		}
+		g.line("}")
+	}
+
+	g.line("")
+	g.line("// Parse decodes the output of `deets export --format json`.")
+	g.line("func Parse(data []byte) (*Deets, error) {")
+	g.line("\tvar d Deets")
+	g.line("\tif err := json.Unmarshal(data, &d); err != nil {")
+	g.line("\t\treturn nil, err")
+	g.line("\t}")
+	g.line("\treturn &d, nil")
+	g.line("}")
+	g.line("")
+	g.line("// Load runs `deets export --format json` and parses the result.")
+	g.line("func Load() (*Deets, error) {")
+	g.line("\tout, err := exec.Command(\"deets\", \"export\", \"--format\", \"json\").Output()")
+	g.line("\tif err != nil {")
+	g.line("\t\treturn nil, err")
+	g.line("\t}")
+	g.line("\treturn Parse(out)")
+	g.line("}")
+
+	src, err := format.Source([]byte(g.b.String()))
+	if err != nil {
+		return "", fmt.Errorf("formatting generated Go: %w", err)
+	}
+	return string(src), nil
+}
+
+// goGen accumulates generated source and the record item types it needs.
+type goGen struct {
+	b     strings.Builder
+	items []goItem
+	types goNamer // package-level names in use
+}
+
+// goItem is a struct type generated for the records of one field.
+type goItem struct {
+	name  string
+	keys  []string
+	types map[string]string
+}
+
+func (g *goGen) line(format string, args ...interface{}) {
+	fmt.Fprintf(&g.b, format, args...)
+	g.b.WriteString("\n")
+}
+
+// goType returns the Go type for v, registering an item struct named
+// itemName+"Item" if v is a list of records.
+func (g *goGen) goType(itemName string, v interface{}) string {
+	switch InferType(v) {
+	case "string", "date", "datetime", "time": // dates are ISO strings in JSON
+		return "string"
+	case "integer":
+		return "int64"
+	case "float":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + goElemType(stringItemsRaw(v))
+	case "records":
+		recs, _ := Records(v)
+		it := goItem{name: g.types.name(itemName + "Item"), types: map[string]string{}}
+		seen := map[string]bool{}
+		for _, rec := range recs {
+			for _, k := range RecordKeys(rec) {
+				t := goScalarType(rec[k])
+				if !seen[k] {
+					seen[k] = true
+					it.keys = append(it.keys, k)
+					it.types[k] = t
+				} else if it.types[k] != t {
+					it.types[k] = "any"
+				}
+			}
+		}
+		g.items = append(g.items, it)
+		return "[]" + it.name
+	default:
+		return "any"
+	}
+}
+
+// goElemType returns the common Go type of items, or "any" if they differ.
+func goElemType(items []interface{}) string {
+	if len(items) == 0 {
+		return "string"
+	}
+	t := goScalarType(items[0])
+	for _, item := range items[1:] {
+		if goScalarType(item) != t {
+			return "any"
+		}
+	}
+	return t
+}
+
+// goScalarType maps a scalar value to its Go type.
+func goScalarType(v interface{}) string {
+	switch InferType(v) {
+	case "string", "date", "datetime", "time":
+		return "string"
+	case "integer":
+		return "int64"
+	case "float":
+		return "float64"
+	case "boolean":
+		return "bool"
+	default:
+		return "any"
+	}
+}
+
+// stringItemsRaw returns the elements of an array value without formatting.
+func stringItemsRaw(v interface{}) []interface{} {
+	switch val := v.(type) {
+	case []interface{}:
+		return val
+	case []string:
+		out := make([]interface{}, len(val))
+		for i, s := range val {
+			out[i] = s
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// goNamer assigns unique Go names within one scope, a struct's fields or
+// the package's types, numbering keys that map to an identifier already in
+// use (e.g. "web-site" and "web_site").
+type goNamer map[string]bool
+
+func (n goNamer) name(key string) string {
+	base := goName(key)
+	name := base
+	for i := 2; n[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	n[name] = true
+	return name
+}
+
+// goName converts a key such as "research_interests" or "web-site" into an
+// exported Go identifier ("ResearchInterests", "WebSite"), upper-casing
+// common initialisms and prefixing names that would start with a digit.
+func goName(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, w := range words {
+		lw := strings.ToLower(w)
+		if goInitialisms[lw] {
+			b.WriteString(strings.ToUpper(lw))
+			continue
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	name := b.String()
+	if name == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"name":               "Name",
+		"research_interests": "ResearchInterests",
+		"web-site":           "WebSite",
+		"orcid_url":          "OrcidURL",
+		"2fa":                "X2fa",
+		"gpa":                "GPA",
+		"_":                  "X",
+	}
+	for in, want := range tests {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFormatGo(t *testing.T) {
+	db := newTestDB()
+	db.Categories = append(db.Categories, newRecordsDB().Categories...)
+	src, err := FormatGo(db, "me")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "deets.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	// Compare with whitespace collapsed, since gofmt aligns struct fields.
+	flat := strings.Join(strings.Fields(src), " ")
+	for _, want := range []string{
+		"// Code generated by deets generate go; DO NOT EDIT.",
+		"package me ",
+		"Identity Identity `json:\"identity\"`",
+		"// Full legal name Name string `json:\"name,omitempty\"`",
+		"Aka []string `json:\"aka,omitempty\"`",
+		"Age int64 `json:\"age,omitempty\"`",
+		"GPA float64 `json:\"gpa,omitempty\"`",
+		"Topics []string",
+		"Degrees []EducationDegreesItem `json:\"degrees,omitempty\"`",
+		"type EducationDegreesItem struct {",
+		"Year int64 `json:\"year,omitempty\"`",
+		"Positions []EmploymentPositionsItem",
+		"func Parse(data []byte) (*Deets, error) {",
+		"func Load() (*Deets, error) {",
+	} {
+		if !strings.Contains(flat, want) {
+			t.Errorf("expected %q in generated code:\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "NameDesc") {
+		t.Error("_desc fields should be excluded")
+	}
+}
+
+func TestFormatGo_MixedTypesAndCollisions(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "misc",
+		Fields: []Field{
+			{Key: "mixed", Value: []interface{}{"a", int64(1)}, Category: "misc"},
+			{Key: "web-site", Value: "x", Category: "misc"},
+			{Key: "web_site", Value: "y", Category: "misc"},
+		},
+	}}}
+	src, err := FormatGo(db, "deets")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	flat := strings.Join(strings.Fields(src), " ")
+	for _, want := range []string{"Mixed []any", "WebSite string", "WebSite2 string"} {
+		if !strings.Contains(flat, want) {
+			t.Errorf("expected %q in:\n%s", want, src)
+		}
+	}
+}
+
+func TestFormatGo_TypeNameCollisions(t *testing.T) {
+	db := &DB{}
+	for _, name := range []string{"deets", "load", "parse", "web-site", "web_site", "web", "web-site-item"} {
+		db.Categories = append(db.Categories, Category{Name: name, Fields: []Field{{Key: "x", Value: "y", Category: name}}})
+	}
+	db.Categories[5].Fields = append(db.Categories[5].Fields, Field{Key: "site", Value: []map[string]interface{}{{"a": "b"}}, Category: "web"})
+
+	src, err := FormatGo(db, "me")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "deets.go", src, 0)
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("me", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("generated code does not compile: %v\n%s", err, src)
+	}
+	flat := strings.Join(strings.Fields(src), " ")
+	for _, want := range []string{"Deets2 Deets2 `json:\"deets\"`", "Load2 Load2", "Parse2 Parse2", "WebSite WebSite", "WebSite2 WebSite2", "WebSiteItem WebSiteItem", "Site []WebSiteItem2"} {
+		if !strings.Contains(flat, want) {
+			t.Errorf("expected %q in:\n%s", want, src)
+		}
+	}
+}
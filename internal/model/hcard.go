@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// FormatHCard renders the database as an h-card microformat HTML fragment
+// (https://microformats.org/wiki/h-card) for a personal site:
+//
+//   - identity.name → p-name, linked to web.website or web.blog as u-url
+//   - identity.photo or identity.avatar → u-photo
+//   - academic.title → p-job-title, academic.institution → p-org
+//   - contact.email → u-email
+//   - remaining web.* links → rel="me" u-url list items
+//
+// All text and attribute values are HTML-escaped.
+func FormatHCard(db *DB) string {
+	esc := html.EscapeString
+	name := fieldString(db, "identity.name")
+
+	home := ""
+	var links []Link
+	for _, l := range db.Links() {
+		if l.URL == "" {
+			continue
+		}
+		if (l.Network == "website" || l.Network == "blog") && home == "" {
+			home = l.URL
+			continue
+		}
+		links = append(links, l)
+	}
+
+	var b strings.Builder
+	b.WriteString("<div class=\"h-card\">\n")
+	for _, key := range []string{"identity.photo", "identity.avatar"} {
+		if photo := fieldString(db, key); photo != "" {
+			fmt.Fprintf(&b, "  <img class=\"u-photo\" src=\"%s\" alt=\"%s\">\n", esc(photo), esc(name))
+			break
+		}
+	}
+	switch {
+	case name != "" && home != "":
+		fmt.Fprintf(&b, "  <a class=\"p-name u-url\" href=\"%s\">%s</a>\n", esc(home), esc(name))
+	case name != "":
+		fmt.Fprintf(&b, "  <span class=\"p-name\">%s</span>\n", esc(name))
+	case home != "":
+		fmt.Fprintf(&b, "  <a class=\"u-url\" href=\"%s\">%s</a>\n", esc(home), esc(home))
+	}
+	if title := fieldString(db, "academic.title"); title != "" {
+		fmt.Fprintf(&b, "  <span class=\"p-job-title\">%s</span>\n", esc(title))
+	}
+	if org := fieldString(db, "academic.institution"); org != "" {
+		fmt.Fprintf(&b, "  <span class=\"p-org\">%s</span>\n", esc(org))
+	}
+	if email := fieldString(db, "contact.email"); email != "" {
+		fmt.Fprintf(&b, "  <a class=\"u-email\" href=\"mailto:%s\">%s</a>\n", esc(email), esc(email))
+	}
+	if len(links) > 0 {
+		b.WriteString("  <ul>\n")
+		for _, l := range links {
+			fmt.Fprintf(&b, "    <li><a class=\"u-url\" rel=\"me\" href=\"%s\">%s</a></li>\n", esc(l.URL), esc(l.Network))
+		}
+		b.WriteString("  </ul>\n")
+	}
+	b.WriteString("</div>\n")
+	return b.String()
+}
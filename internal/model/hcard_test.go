@@ -0,0 +1,49 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatHCard(t *testing.T) {
+	out := FormatHCard(newTestDB())
+	want := `<div class="h-card">
+  <a class="p-name u-url" href="https://example.com">Alexander Towell</a>
+  <ul>
+    <li><a class="u-url" rel="me" href="https://github.com/queelius">github</a></li>
+  </ul>
+</div>
+`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestFormatHCard_Escaping(t *testing.T) {
+	db := &DB{Categories: []Category{
+		{Name: "identity", Fields: []Field{
+			{Key: "name", Value: `<script>"Bob" & co</script>`, Category: "identity"},
+			{Key: "avatar", Value: `https://example.com/a.png?x=1&y="2"`, Category: "identity"},
+		}},
+		{Name: "academic", Fields: []Field{
+			{Key: "institution", Value: "R&D Lab", Category: "academic"},
+			{Key: "title", Value: "Researcher", Category: "academic"},
+		}},
+		{Name: "contact", Fields: []Field{{Key: "email", Value: "bob@example.com", Category: "contact"}}},
+	}}
+	out := FormatHCard(db)
+	if strings.Contains(out, "<script>") {
+		t.Errorf("name should be escaped:\n%s", out)
+	}
+	for _, want := range []string{
+		`<img class="u-photo" src="https://example.com/a.png?x=1&amp;y=&#34;2&#34;" alt="&lt;script&gt;&#34;Bob&#34; &amp; co&lt;/script&gt;">`,
+		`<span class="p-name">&lt;script&gt;`,
+		`<span class="p-job-title">Researcher</span>`,
+		`<span class="p-org">R&amp;D Lab</span>`,
+		`<a class="u-email" href="mailto:bob@example.com">bob@example.com</a>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
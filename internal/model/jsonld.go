@@ -0,0 +1,83 @@
+package model
+
+import (
+	"encoding/json"
+)
+
+// FormatJSONLD renders the database as a schema.org Person in JSON-LD, ready
+// to embed in a <script type="application/ld+json"> tag:
+//
+//   - identity.name → name, identity.aka → alternateName
+//   - contact.email/phone → email/telephone
+//   - web.website or web.blog → url, all web.* links and academic.orcid → sameAs
+//   - academic.institution → affiliation, academic.title → jobTitle
+//   - education.degrees institutions → alumniOf
+//   - academic.research_interests → knowsAbout
+func FormatJSONLD(db *DB) (string, error) {
+	doc := map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    "Person",
+	}
+	putString(doc, "name", fieldString(db, "identity.name"))
+	if f, ok := db.GetField("identity.aka"); ok {
+		if aka := Items(f.Value); len(aka) > 0 {
+			doc["alternateName"] = aka
+		}
+	}
+	putString(doc, "email", fieldString(db, "contact.email"))
+	putString(doc, "telephone", fieldString(db, "contact.phone"))
+	putString(doc, "jobTitle", fieldString(db, "academic.title"))
+
+	var sameAs []string
+	for _, l := range db.Links() {
+		if l.URL == "" {
+			continue
+		}
+		if (l.Network == "website" || l.Network == "blog") && doc["url"] == nil {
+			doc["url"] = l.URL
+			continue
+		}
+		sameAs = append(sameAs, l.URL)
+	}
+	if orcid := fieldString(db, "academic.orcid"); orcid != "" {
+		if !isURL(orcid) {
+			orcid = "https://orcid.org/" + orcid
+		}
+		sameAs = append(sameAs, orcid)
+	}
+	if len(sameAs) > 0 {
+		doc["sameAs"] = sameAs
+	}
+
+	if inst := fieldString(db, "academic.institution"); inst != "" {
+		doc["affiliation"] = organization(inst)
+	}
+	var alumniOf []interface{}
+	seen := map[string]bool{}
+	for _, d := range db.Degrees() {
+		if d.Institution == "" || seen[d.Institution] {
+			continue
+		}
+		seen[d.Institution] = true
+		alumniOf = append(alumniOf, organization(d.Institution))
+	}
+	if len(alumniOf) > 0 {
+		doc["alumniOf"] = alumniOf
+	}
+	if f, ok := db.GetField("academic.research_interests"); ok {
+		if topics := Items(f.Value); len(topics) > 0 {
+			doc["knowsAbout"] = topics
+		}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// organization returns a schema.org Organization node with the given name.
+func organization(name string) map[string]interface{} {
+	return map[string]interface{}{"@type": "Organization", "name": name}
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFormatJSONLD(t *testing.T) {
+	db := newTestDB()
+	db.Categories[2].Fields = append(db.Categories[2].Fields,
+		Field{Key: "institution", Value: "SIUE", Category: "academic"})
+	db.Categories = append(db.Categories, newRecordsDB().Categories...)
+
+	out, err := FormatJSONLD(db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if doc["@context"] != "https://schema.org" || doc["@type"] != "Person" {
+		t.Errorf("expected schema.org Person, got %v / %v", doc["@context"], doc["@type"])
+	}
+	if doc["name"] != "Alexander Towell" {
+		t.Errorf("unexpected name: %v", doc["name"])
+	}
+	if doc["url"] != "https://example.com" {
+		t.Errorf("expected url from web.website, got %v", doc["url"])
+	}
+	sameAs := doc["sameAs"].([]interface{})
+	if len(sameAs) != 2 || sameAs[0] != "https://github.com/queelius" || sameAs[1] != "https://orcid.org/0000-0001-2345-6789" {
+		t.Errorf("unexpected sameAs: %v", sameAs)
+	}
+	aff := doc["affiliation"].(map[string]interface{})
+	if aff["@type"] != "Organization" || aff["name"] != "SIUE" {
+		t.Errorf("unexpected affiliation: %v", aff)
+	}
+	if alumni := doc["alumniOf"].([]interface{}); len(alumni) != 2 {
+		t.Errorf("expected two alumniOf organizations, got %v", alumni)
+	}
+}
+
+func TestFormatJSONLD_Minimal(t *testing.T) {
+	out, err := FormatJSONLD(&DB{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc) != 2 {
+		t.Errorf("expected only @context and @type, got %v", doc)
+	}
+}
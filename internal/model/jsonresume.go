@@ -0,0 +1,168 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JSONResumeSchema is the schema URL written to the $schema key of JSON
+// Resume documents.
+const JSONResumeSchema = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+// FormatJSONResume renders the database as a JSON Resume document
+// (https://jsonresume.org/schema). Well-known fields are mapped as follows:
+//
+//   - identity.name → basics.name, identity.summary or identity.bio → basics.summary
+//   - academic.title → basics.label, contact.email/phone → basics.email/phone
+//   - web.website or web.blog → basics.url, other web.* links and
+//     academic.orcid → basics.profiles
+//   - education.degrees → education, employment.positions → work
+//   - academic.research_interests → interests
+//
+// extra maps additional "category.key" paths to dotted JSON Resume paths,
+// e.g. "identity.city" → "basics.location.city". Extra mappings are applied
+// after the built-in ones and may overwrite them.
+func FormatJSONResume(db *DB, extra map[string]string) (string, error) {
+	doc := map[string]interface{}{"$schema": JSONResumeSchema}
+
+	basics := map[string]interface{}{}
+	setString := func(m map[string]interface{}, key string, paths ...string) {
+		for _, p := range paths {
+			if v := fieldString(db, p); v != "" {
+				m[key] = v
+				return
+			}
+		}
+	}
+	setString(basics, "name", "identity.name")
+	setString(basics, "label", "academic.title")
+	setString(basics, "email", "contact.email")
+	setString(basics, "phone", "contact.phone")
+	setString(basics, "summary", "identity.summary", "identity.bio")
+
+	var profiles []interface{}
+	for _, l := range db.Links() {
+		if (l.Network == "website" || l.Network == "blog") && basics["url"] == nil && l.URL != "" {
+			basics["url"] = l.URL
+			continue
+		}
+		p := map[string]interface{}{"network": l.Network}
+		if l.Username != "" {
+			p["username"] = l.Username
+		}
+		if l.URL != "" {
+			p["url"] = l.URL
+		}
+		profiles = append(profiles, p)
+	}
+	if orcid := fieldString(db, "academic.orcid"); orcid != "" {
+		p := map[string]interface{}{"network": "ORCID"}
+		if isURL(orcid) {
+			p["url"] = orcid
+		} else {
+			p["username"] = orcid
+			p["url"] = "https://orcid.org/" + orcid
+		}
+		profiles = append(profiles, p)
+	}
+	if len(profiles) > 0 {
+		basics["profiles"] = profiles
+	}
+	if len(basics) > 0 {
+		doc["basics"] = basics
+	}
+
+	var education []interface{}
+	for _, d := range db.Degrees() {
+		e := map[string]interface{}{}
+		putString(e, "studyType", d.Degree)
+		putString(e, "area", d.Field)
+		putString(e, "institution", d.Institution)
+		putString(e, "endDate", d.Year)
+		education = append(education, e)
+	}
+	if len(education) > 0 {
+		doc["education"] = education
+	}
+
+	var work []interface{}
+	for _, p := range db.Positions() {
+		w := map[string]interface{}{}
+		putString(w, "position", p.Title)
+		putString(w, "name", p.Organization)
+		putString(w, "startDate", p.Start)
+		putString(w, "endDate", p.End)
+		work = append(work, w)
+	}
+	if len(work) > 0 {
+		doc["work"] = work
+	}
+
+	if f, ok := db.GetField("academic.research_interests"); ok {
+		var interests []interface{}
+		for _, s := range Items(f.Value) {
+			interests = append(interests, map[string]interface{}{"name": s})
+		}
+		if len(interests) > 0 {
+			doc["interests"] = interests
+		}
+	}
+
+	for _, src := range sortedKeys(extra) {
+		f, ok := db.GetField(src)
+		if !ok {
+			continue
+		}
+		if err := setPath(doc, extra[src], f.Value); err != nil {
+			return "", fmt.Errorf("jsonresume mapping %s: %w", src, err)
+		}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// putString sets m[key] to s unless s is empty.
+func putString(m map[string]interface{}, key, s string) {
+	if s != "" {
+		m[key] = s
+	}
+}
+
+// setPath stores v at a dotted path inside doc, creating intermediate
+// objects as needed.
+func setPath(doc map[string]interface{}, path string, v interface{}) error {
+	parts := strings.Split(path, ".")
+	m := doc
+	for i, p := range parts[:len(parts)-1] {
+		next, ok := m[p]
+		if !ok {
+			child := map[string]interface{}{}
+			m[p] = child
+			m = child
+			continue
+		}
+		child, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s is not an object", strings.Join(parts[:i+1], "."))
+		}
+		m = child
+	}
+	m[parts[len(parts)-1]] = v
+	return nil
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
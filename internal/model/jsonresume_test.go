@@ -0,0 +1,92 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decodeResume(t *testing.T, out string) map[string]interface{} {
+	t.Helper()
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	return doc
+}
+
+func TestFormatJSONResume_Basics(t *testing.T) {
+	out, err := FormatJSONResume(newTestDB(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc := decodeResume(t, out)
+	if doc["$schema"] != JSONResumeSchema {
+		t.Errorf("expected $schema, got %v", doc["$schema"])
+	}
+	basics := doc["basics"].(map[string]interface{})
+	if basics["name"] != "Alexander Towell" {
+		t.Errorf("expected basics.name, got %v", basics["name"])
+	}
+	if basics["url"] != "https://example.com" {
+		t.Errorf("expected basics.url from web.website, got %v", basics["url"])
+	}
+	profiles := basics["profiles"].([]interface{})
+	if len(profiles) != 2 {
+		t.Fatalf("expected github and ORCID profiles, got %v", profiles)
+	}
+	gh := profiles[0].(map[string]interface{})
+	if gh["network"] != "github" || gh["username"] != "queelius" || gh["url"] != "https://github.com/queelius" {
+		t.Errorf("unexpected github profile: %v", gh)
+	}
+	orcid := profiles[1].(map[string]interface{})
+	if orcid["url"] != "https://orcid.org/0000-0001-2345-6789" {
+		t.Errorf("unexpected ORCID profile: %v", orcid)
+	}
+	if _, ok := doc["education"]; ok {
+		t.Error("education should be omitted when there are no degrees")
+	}
+}
+
+func TestFormatJSONResume_EducationAndWork(t *testing.T) {
+	out, err := FormatJSONResume(newRecordsDB(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc := decodeResume(t, out)
+	edu := doc["education"].([]interface{})
+	first := edu[0].(map[string]interface{})
+	if first["studyType"] != "PhD" || first["area"] != "Statistics" || first["institution"] != "SIUE" || first["endDate"] != "2020" {
+		t.Errorf("unexpected education entry: %v", first)
+	}
+	work := doc["work"].([]interface{})
+	current := work[1].(map[string]interface{})
+	if current["position"] != "Researcher" || current["name"] != "Lab" || current["startDate"] != "2021" {
+		t.Errorf("unexpected work entry: %v", current)
+	}
+	if _, ok := current["endDate"]; ok {
+		t.Error("current position should have no endDate")
+	}
+}
+
+func TestFormatJSONResume_ExtraMapping(t *testing.T) {
+	db := newTestDB()
+	out, err := FormatJSONResume(db, map[string]string{
+		"academic.topics": "basics.location.tags",
+		"missing.field":   "basics.ignored",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, `"location": {`) || !strings.Contains(out, `"statistics"`) {
+		t.Errorf("expected mapped field under basics.location, got:\n%s", out)
+	}
+	if strings.Contains(out, "ignored") {
+		t.Error("mappings for missing fields should be skipped")
+	}
+
+	_, err = FormatJSONResume(db, map[string]string{"identity.age": "basics.name.first"})
+	if err == nil || !strings.Contains(err.Error(), "basics.name is not an object") {
+		t.Errorf("expected path conflict error, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect FormatJSONSchema declares.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// FormatJSONSchema describes the shape of 'deets export --format json'
+// output for db as a JSON Schema document, so editors and CI can check
+// exported JSON or YAML against it. Each category is an object property
+// and each field a property of that object, with the type BuildSchema
+// infers, its description, and its current value as an example. Dates and
+// times are strings with a format; nested tables and arrays of tables are
+// described down to their leaves. Computed fields are marked readOnly.
+// Nothing is required and extra properties are allowed, so the schema
+// checks the fields that exist without freezing the set of fields.
+func FormatJSONSchema(db *DB) (string, error) {
+	categories := orderedMap{values: make(map[string]interface{})}
+	fields := make(map[string]orderedMap)
+	for _, e := range BuildSchema(db) {
+		props, ok := fields[e.Category]
+		if !ok {
+			props = orderedMap{values: make(map[string]interface{})}
+			categories.keys = append(categories.keys, e.Category)
+		}
+		f, _ := db.GetField(e.Category + "." + e.Key)
+		prop := jsonSchemaFor(f.Value)
+		if e.Description != "" {
+			prop.set("description", e.Description)
+		}
+		if isJSONScalar(f.Value) {
+			prop.set("examples", []interface{}{f.Value})
+		}
+		if e.Computed {
+			prop.set("readOnly", true)
+		}
+		props.keys = append(props.keys, e.Key)
+		props.values[e.Key] = prop
+		fields[e.Category] = props
+	}
+	for _, name := range categories.keys {
+		categories.values[name] = jsonObjectSchema(fields[name])
+	}
+
+	root := jsonObjectSchema(categories)
+	root.keys = append([]string{"$schema", "title"}, root.keys...)
+	root.values["$schema"] = JSONSchemaDraft
+	root.values["title"] = "deets"
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal JSON Schema: %w", err)
+	}
+	return string(data), nil
+}
+
+// set adds or replaces key in o, keeping the position of an existing key.
+func (o *orderedMap) set(key string, v interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
+
+// jsonObjectSchema returns the schema of an object with the given
+// property schemas.
+func jsonObjectSchema(props orderedMap) orderedMap {
+	s := orderedMap{values: make(map[string]interface{})}
+	s.set("type", "object")
+	s.set("properties", props)
+	return s
+}
+
+// jsonSchemaFor returns the schema of a value as it appears in JSON
+// output.
+func jsonSchemaFor(v interface{}) orderedMap {
+	s := orderedMap{values: make(map[string]interface{})}
+	if recs, ok := Records(v); ok {
+		// Each record may hold a different subset of keys.
+		props := orderedMap{values: make(map[string]interface{})}
+		for _, rec := range recs {
+			for _, k := range RecordKeys(rec) {
+				if _, seen := props.values[k]; !seen {
+					props.set(k, jsonSchemaFor(rec[k]))
+				}
+			}
+		}
+		s.set("type", "array")
+		s.set("items", jsonObjectSchema(props))
+		return s
+	}
+	switch val := v.(type) {
+	case string:
+		s.set("type", "string")
+	case int64:
+		s.set("type", "integer")
+	case float64:
+		s.set("type", "number")
+	case bool:
+		s.set("type", "boolean")
+	case time.Time:
+		s.set("type", "string")
+		s.set("format", map[string]string{"date": "date", "time": "time", "datetime": "date-time"}[TimeKind(val)])
+	case map[string]interface{}:
+		props := orderedMap{values: make(map[string]interface{})}
+		for _, k := range RecordKeys(val) {
+			props.set(k, jsonSchemaFor(val[k]))
+		}
+		return jsonObjectSchema(props)
+	case []string:
+		s.set("type", "array")
+		s.set("items", jsonSchemaFor(""))
+	case []interface{}:
+		s.set("type", "array")
+		// Items are only described when they all share one schema.
+		var items []byte
+		for i, item := range val {
+			b, _ := json.Marshal(jsonSchemaFor(item))
+			if i > 0 && string(b) != string(items) {
+				items = nil
+				break
+			}
+			items = b
+		}
+		if items != nil {
+			s.set("items", json.RawMessage(items))
+		}
+	}
+	return s
+}
+
+// isJSONScalar reports whether v is a single value that makes a useful
+// example, as opposed to an array or table.
+func isJSONScalar(v interface{}) bool {
+	switch v.(type) {
+	case string, int64, float64, bool, time.Time:
+		return true
+	}
+	return false
+}
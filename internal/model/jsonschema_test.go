@@ -0,0 +1,75 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFormatJSONSchema(t *testing.T) {
+	birthday := time.Date(1990, 5, 1, 0, 0, 0, 0, time.FixedZone("date-local", 0))
+	db := &DB{Categories: []Category{
+		{Name: "identity", Fields: []Field{
+			{Key: "name", Value: "Alex", Desc: "Full name"},
+			{Key: "name_desc", Value: "Full name"},
+			{Key: "birthday", Value: birthday},
+			{Key: "aka", Value: []interface{}{"A", "B"}},
+			{Key: "mixed", Value: []interface{}{"a", int64(1)}},
+			{Key: "initials", Value: "AT", Computed: true},
+		}},
+		{Name: "academic", Fields: []Field{
+			{Key: "degrees", Value: []map[string]interface{}{{"name": "PhD", "year": int64(2020)}, {"name": "MS"}}},
+			{Key: "advisor", Value: map[string]interface{}{"name": "Bob"}},
+		}},
+	}}
+
+	out, err := FormatJSONSchema(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Schema     string `json:"$schema"`
+		Type       string
+		Properties map[string]struct {
+			Type       string
+			Properties map[string]map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if doc.Schema != JSONSchemaDraft || doc.Type != "object" {
+		t.Errorf("unexpected root: %+v", doc)
+	}
+
+	identity := doc.Properties["identity"].Properties
+	if _, ok := identity["name_desc"]; ok {
+		t.Error("_desc key included in schema")
+	}
+	name := identity["name"]
+	if name["type"] != "string" || name["description"] != "Full name" || name["examples"].([]interface{})[0] != "Alex" {
+		t.Errorf("unexpected name schema: %v", name)
+	}
+	if b := identity["birthday"]; b["type"] != "string" || b["format"] != "date" {
+		t.Errorf("unexpected birthday schema: %v", b)
+	}
+	if items, ok := identity["aka"]["items"].(map[string]interface{}); !ok || items["type"] != "string" {
+		t.Errorf("unexpected aka schema: %v", identity["aka"])
+	}
+	if _, ok := identity["mixed"]["items"]; ok {
+		t.Errorf("mixed array should not describe items: %v", identity["mixed"])
+	}
+	if identity["initials"]["readOnly"] != true {
+		t.Errorf("computed field not readOnly: %v", identity["initials"])
+	}
+
+	academic := doc.Properties["academic"].Properties
+	items := academic["degrees"]["items"].(map[string]interface{})
+	props := items["properties"].(map[string]interface{})
+	if len(props) != 2 || props["year"].(map[string]interface{})["type"] != "integer" {
+		t.Errorf("unexpected degrees items: %v", items)
+	}
+	if academic["advisor"]["type"] != "object" {
+		t.Errorf("unexpected advisor schema: %v", academic["advisor"])
+	}
+}
@@ -0,0 +1,71 @@
+package model
+
+import "strings"
+
+// Link is a web presence derived from a field in the [web] category.
+type Link struct {
+	Network  string // field key, e.g. "github"
+	Username string // handle, if the value was not already a URL
+	URL      string // absolute profile URL, or "" if it cannot be derived
+}
+
+// profileURLs maps well-known [web] keys to a profile URL prefix for values
+// given as bare usernames.
+var profileURLs = map[string]string{
+	"github":      "https://github.com/",
+	"gitlab":      "https://gitlab.com/",
+	"codeberg":    "https://codeberg.org/",
+	"twitter":     "https://twitter.com/",
+	"x":           "https://x.com/",
+	"linkedin":    "https://www.linkedin.com/in/",
+	"bluesky":     "https://bsky.app/profile/",
+	"keybase":     "https://keybase.io/",
+	"huggingface": "https://huggingface.co/",
+}
+
+// Links returns one Link per scalar field in the [web] category, in file
+// order. Values that are already URLs are used as-is; bare usernames for the
+// well-known networks in profileURLs are expanded, and Mastodon handles of
+// the form "@user@host" become "https://host/@user".
+func (db *DB) Links() []Link {
+	cat, ok := db.GetCategory("web")
+	if !ok {
+		return nil
+	}
+	var out []Link
+	for _, f := range cat.Fields {
+		if IsDescKey(f.Key) {
+			continue
+		}
+		s, ok := f.Value.(string)
+		if !ok || s == "" {
+			continue
+		}
+		out = append(out, linkFor(f.Key, s))
+	}
+	return out
+}
+
+// linkFor builds the Link for a single [web] field.
+func linkFor(network, value string) Link {
+	if isURL(value) {
+		return Link{Network: network, URL: value}
+	}
+	user := strings.TrimPrefix(value, "@")
+	l := Link{Network: network, Username: user}
+	if network == "mastodon" {
+		if name, host, ok := strings.Cut(user, "@"); ok && name != "" && host != "" {
+			l.URL = "https://" + host + "/@" + name
+		}
+		return l
+	}
+	if prefix, ok := profileURLs[network]; ok {
+		l.URL = prefix + user
+	}
+	return l
+}
+
+// isURL reports whether s is an absolute http(s) URL.
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
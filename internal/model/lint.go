@@ -0,0 +1,197 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// LintIssue is one style problem found by Lint.
+type LintIssue struct {
+	Rule    string `json:"rule"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// LintRule checks one value. path is the dotted path of the value and
+// category the table it lives in; key is the last segment of path.
+type LintRule struct {
+	Name        string
+	Description string
+	Check       func(category, path, key string, value interface{}) string
+}
+
+// MaxLintLineLength is the longest single-line string the long-line rule
+// accepts.
+const MaxLintLineLength = 200
+
+var (
+	snakeCase  = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+	emailLike  = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[A-Za-z]{2,}$`)
+	domainLike = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}(/\S*)?$`)
+)
+
+// urlKeys are the key names, or key suffixes after "_", that hold URLs.
+var urlKeys = []string{"url", "website", "homepage", "blog"}
+
+// LintRules lists every rule Lint knows, in the order their issues are
+// reported for a field.
+var LintRules = []LintRule{
+	{
+		Name:        "key-case",
+		Description: "keys are lower snake_case",
+		Check: func(category, path, key string, value interface{}) string {
+			if snakeCase.MatchString(key) {
+				return ""
+			}
+			return fmt.Sprintf("key %q is not lower snake_case", key)
+		},
+	},
+	{
+		Name:        "misplaced-email",
+		Description: "email addresses belong in [contact]",
+		Check: func(category, path, key string, value interface{}) string {
+			s, ok := value.(string)
+			if !ok || category == "contact" || !emailLike.MatchString(s) {
+				return ""
+			}
+			return "looks like an email address but is not in [contact]"
+		},
+	},
+	{
+		Name:        "long-line",
+		Description: fmt.Sprintf("single-line strings are at most %d characters", MaxLintLineLength),
+		Check: func(category, path, key string, value interface{}) string {
+			s, ok := value.(string)
+			if !ok || strings.Contains(s, "\n") {
+				return ""
+			}
+			if n := utf8.RuneCountInString(s); n > MaxLintLineLength {
+				return fmt.Sprintf("%d characters on one line; use a multi-line string", n)
+			}
+			return ""
+		},
+	},
+	{
+		Name:        "url-scheme",
+		Description: "URLs start with a scheme such as https://",
+		Check: func(category, path, key string, value interface{}) string {
+			s, ok := value.(string)
+			if !ok || strings.Contains(s, "://") {
+				return ""
+			}
+			if strings.HasPrefix(s, "www.") || isURLKey(key) && domainLike.MatchString(s) {
+				return fmt.Sprintf("%q looks like a URL without a scheme; write https://%s", s, s)
+			}
+			return ""
+		},
+	},
+}
+
+// isURLKey reports whether key names a URL field, such as "website" or
+// "docs_url".
+func isURLKey(key string) bool {
+	for _, k := range urlKeys {
+		if key == k || strings.HasSuffix(key, "_"+k) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint checks every field of db, and the keys and values nested inside
+// tables, arrays, and arrays of tables, against the rules not named in
+// disabled. Issues follow the order of db. The values of _desc keys are
+// prose and only their casing is checked.
+func Lint(db *DB, disabled map[string]bool) []LintIssue {
+	var issues []LintIssue
+	walkValues(db, func(category, path, key string, value interface{}, item bool) {
+		for _, rule := range LintRules {
+			if disabled[rule.Name] {
+				continue
+			}
+			// Array items have no key of their own.
+			if rule.Name == "key-case" && item || rule.Name != "key-case" && IsDescKey(key) {
+				continue
+			}
+			if msg := rule.Check(category, path, key, value); msg != "" {
+				issues = append(issues, LintIssue{Rule: rule.Name, Path: path, Message: msg})
+			}
+		}
+	})
+	return issues
+}
+
+// walkValues calls fn for every field of db and for every value nested
+// inside its tables, arrays, and arrays of tables. path is the dotted path
+// of the value, as in "education.degrees[0].institution", and key its last
+// key. For the items of an array, path and key are those of the array and
+// item is true.
+func walkValues(db *DB, fn func(category, path, key string, value interface{}, item bool)) {
+	var walk func(category, path, key string, value interface{})
+	walk = func(category, path, key string, value interface{}) {
+		fn(category, path, key, value, false)
+		if recs, ok := Records(value); ok {
+			for i, rec := range recs {
+				for _, k := range RecordKeys(rec) {
+					walk(category, fmt.Sprintf("%s[%d].%s", path, i, k), k, rec[k])
+				}
+			}
+			return
+		}
+		switch val := value.(type) {
+		case map[string]interface{}:
+			for _, k := range RecordKeys(val) {
+				walk(category, path+"."+k, k, val[k])
+			}
+		case []interface{}:
+			for _, v := range val {
+				fn(category, path, key, v, true)
+			}
+		case []string:
+			for _, v := range val {
+				fn(category, path, key, v, true)
+			}
+		}
+	}
+	for _, cat := range db.Categories {
+		for _, f := range cat.Fields {
+			walk(cat.Name, cat.Name+"."+f.Key, f.Key, f.Value)
+		}
+	}
+}
+
+// IsLintRule reports whether name is the name of one of LintRules.
+func IsLintRule(name string) bool {
+	for _, rule := range LintRules {
+		if rule.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatLintTable renders issues as path, rule, and message columns. A
+// positive maxWidth cuts long messages short to fit, as FormatTableFit does.
+func FormatLintTable(issues []LintIssue, maxWidth int) string {
+	cols := []Column{{Header: "Path"}, {Header: "Rule"}, {Header: "Message", Shrink: true}}
+	rows := make([][]string, 0, len(issues))
+	for _, is := range issues {
+		rows = append(rows, []string{is.Path, is.Rule, is.Message})
+	}
+	return FormatColumns(cols, rows, maxWidth)
+}
+
+// FormatLintJSON renders issues as a JSON array.
+func FormatLintJSON(issues []LintIssue) (string, error) {
+	if issues == nil {
+		issues = []LintIssue{}
+	}
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
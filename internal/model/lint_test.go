@@ -0,0 +1,77 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	db := &DB{Categories: []Category{
+		{Name: "identity", Fields: []Field{
+			{Key: "name", Value: "Alex"},
+			{Key: "firstName", Value: "Alex"},
+			{Key: "name_desc", Value: strings.Repeat("x", 300)},
+			{Key: "bio", Value: strings.Repeat("x", MaxLintLineLength+1)},
+			{Key: "essay", Value: strings.Repeat("line\n", 100)},
+		}},
+		{Name: "contact", Fields: []Field{
+			{Key: "email", Value: "alex@example.com"},
+		}},
+		{Name: "web", Fields: []Field{
+			{Key: "backup_email", Value: "alex@example.org"},
+			{Key: "website", Value: "example.com"},
+			{Key: "blog", Value: "https://blog.example.com"},
+			{Key: "mirrors", Value: []interface{}{"www.example.net"}},
+			{Key: "social", Value: map[string]interface{}{"Mastodon-Handle": "@alex"}},
+		}},
+		{Name: "education", Fields: []Field{
+			{Key: "degrees", Value: []map[string]interface{}{{"homepage": "school.edu"}}},
+		}},
+	}}
+
+	var got []string
+	for _, is := range Lint(db, nil) {
+		got = append(got, is.Rule+" "+is.Path)
+	}
+	want := []string{
+		"key-case identity.firstName",
+		"long-line identity.bio",
+		"misplaced-email web.backup_email",
+		"url-scheme web.website",
+		"url-scheme web.mirrors",
+		"key-case web.social.Mastodon-Handle",
+		"url-scheme education.degrees[0].homepage",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Lint issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLint_Disabled(t *testing.T) {
+	db := &DB{Categories: []Category{
+		{Name: "web", Fields: []Field{
+			{Key: "homePage", Value: "www.example.com"},
+		}},
+	}}
+	issues := Lint(db, map[string]bool{"key-case": true})
+	if len(issues) != 1 || issues[0].Rule != "url-scheme" {
+		t.Errorf("expected only url-scheme, got %+v", issues)
+	}
+	if !IsLintRule("long-line") || IsLintRule("nope") {
+		t.Error("IsLintRule mismatch")
+	}
+}
+
+func TestFormatLintTable(t *testing.T) {
+	issues := []LintIssue{{Path: "web.website", Rule: "url-scheme", Message: "URL has no scheme; add https:// so links resolve"}}
+	lines := strings.Split(FormatLintTable(issues, 0), "\n")
+	if !strings.HasPrefix(lines[0], "Path") || !strings.Contains(lines[0], "Rule") || !strings.HasPrefix(lines[1], "───") {
+		t.Errorf("expected the shared table style, got:\n%s", strings.Join(lines, "\n"))
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(FormatLintTable(issues, 40), "\n"), "\n") {
+		if w := DisplayWidth(line); w > 40 {
+			t.Errorf("line wider than 40 (%d): %q", w, line)
+		}
+	}
+}
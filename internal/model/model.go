@@ -6,7 +6,9 @@ package model
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 )
 
 // Field represents a single metadata entry within a category.
@@ -19,6 +21,31 @@ type Field struct {
 	Desc string
 	// Category is the name of the category this field belongs to.
 	Category string
+	// Computed reports whether Value was derived from a template at read time.
+	Computed bool
+	// Descs holds descriptions of keys nested inside a table Value, keyed by
+	// dotted sub-path (e.g. "institution" for [education.phd]).
+	Descs map[string]string
+	// Source is the path of the file the field was read from.
+	Source string
+	// Line is the 1-based line of the field in Source, or 0 if unknown.
+	Line int
+	// Layer names the configuration layer Source belongs to, such as
+	// "global" or "local".
+	Layer string
+}
+
+// Origin describes where a field came from as "layer:line" (or the file
+// path when the layer is unknown), e.g. "local:12".
+func (f Field) Origin() string {
+	origin := f.Layer
+	if origin == "" {
+		origin = f.Source
+	}
+	if f.Line > 0 {
+		origin = fmt.Sprintf("%s:%d", origin, f.Line)
+	}
+	return origin
 }
 
 // Category represents a named group of related fields.
@@ -38,6 +65,9 @@ type DB struct {
 
 // GetField retrieves a single field by its "category.key" path.
 // Returns the field and true if found, or a zero Field and false otherwise.
+// Deeper paths such as "education.phd.institution" reach into nested
+// tables, and an index such as "identity.aka[0]", "academic.topics[-1]", or
+// "education.degrees[1].institution" selects one element of an array.
 func (db *DB) GetField(path string) (Field, bool) {
 	parts := strings.SplitN(path, ".", 2)
 	if len(parts) != 2 {
@@ -52,6 +82,9 @@ func (db *DB) GetField(path string) (Field, bool) {
 					return f, true
 				}
 			}
+			if matches := matchFields(cat.Fields, key, exactKey); len(matches) > 0 {
+				return matches[0], true
+			}
 			return Field{}, false
 		}
 	}
@@ -65,10 +98,40 @@ func (db *DB) GetField(path string) (Field, bool) {
 //   - "category" or "category.*" — all fields in the named category (excluding _desc fields)
 //   - "*.key"           — find a key across all categories
 //   - "category.prefix*" — glob match within a category
+//   - "category.table.key" — a key inside a nested table; every segment may
+//     be a glob, as in "education.*.institution"
+//   - "category.key[0]" — element of an array field ([-1] is the last);
+//     fields that are not arrays or are too short are dropped
+//   - "{identity,contact}.email" — brace alternation, expanded before matching
+//   - "**.email" or "education.**" — "**" matches across categories, keys,
+//     and nested tables
 //
 // The function uses filepath.Match for glob semantics and always excludes
-// _desc fields from results.
+// _desc fields from results. Malformed patterns match nothing; see
+// ValidatePattern.
 func (db *DB) Query(pattern string) []Field {
+	if alts := ExpandBraces(pattern); len(alts) > 1 {
+		var results []Field
+		seen := make(map[string]bool)
+		for _, alt := range alts {
+			for _, f := range db.Query(alt) {
+				path := f.Category + "." + f.Key
+				if !seen[path] {
+					seen[path] = true
+					results = append(results, f)
+				}
+			}
+		}
+		return results
+	}
+	if strings.Contains(pattern, "**") {
+		re, err := globstarRegexp(pattern)
+		if err != nil {
+			return nil
+		}
+		return db.QueryRegex(re)
+	}
+
 	var results []Field
 
 	// If pattern has no dot, treat it as "category" shorthand for "category.*"
@@ -102,31 +165,111 @@ func (db *DB) Query(pattern string) []Field {
 	catPattern, keyPattern := parts[0], parts[1]
 
 	for _, cat := range db.Categories {
-		catMatched, err := filepath.Match(catPattern, cat.Name)
-		if err != nil {
-			catMatched = catPattern == cat.Name
-		}
-		if !catMatched {
+		if !globKey(catPattern, cat.Name) {
 			continue
 		}
+		results = append(results, matchFields(cat.Fields, keyPattern, globKey)...)
+	}
 
-		for _, f := range cat.Fields {
-			if IsDescKey(f.Key) {
-				continue
-			}
-			keyMatched, err := filepath.Match(keyPattern, f.Key)
-			if err != nil {
-				keyMatched = keyPattern == f.Key
-			}
-			if keyMatched {
-				results = append(results, f)
+	return results
+}
+
+// QueryRegex returns every field whose "category.key" path matches re. Keys
+// inside nested tables are tested by their full dotted path, as in
+// "education.phd.institution", when the table itself does not match.
+func (db *DB) QueryRegex(re *regexp.Regexp) []Field {
+	var results []Field
+	for _, f := range db.AllFields() {
+		if re.MatchString(f.Category + "." + f.Key) {
+			results = append(results, f)
+			continue
+		}
+		if _, ok := f.Value.(map[string]interface{}); !ok {
+			continue
+		}
+		for _, leaf := range Leaves(f) {
+			if re.MatchString(leaf.Category + "." + leaf.Key) {
+				results = append(results, leaf)
 			}
 		}
 	}
+	return results
+}
 
+// Exclude returns fields without those matched by any of patterns, using
+// Query semantics. Excluding a table also drops the keys nested inside it.
+func (db *DB) Exclude(fields []Field, patterns []string) []Field {
+	if len(patterns) == 0 {
+		return fields
+	}
+	excluded := make(map[string]bool)
+	for _, pattern := range patterns {
+		for _, f := range db.Query(pattern) {
+			excluded[f.Category+"."+f.Key] = true
+		}
+	}
+	var results []Field
+	for _, f := range fields {
+		if !excludedPath(excluded, f.Category+"."+f.Key) {
+			results = append(results, f)
+		}
+	}
 	return results
 }
 
+// excludedPath reports whether path or any enclosing table path is in
+// excluded.
+func excludedPath(excluded map[string]bool, path string) bool {
+	for {
+		if excluded[path] {
+			return true
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i <= 0 {
+			return false
+		}
+		path = path[:i]
+	}
+}
+
+// Without returns a copy of db without the fields matched by any of
+// patterns. Categories left empty are dropped.
+func (db *DB) Without(patterns []string) *DB {
+	if len(patterns) == 0 {
+		return db
+	}
+	out := &DB{}
+	for _, cat := range db.Categories {
+		fields := db.Exclude(cat.Fields, patterns)
+		for _, f := range fields {
+			if !IsDescKey(f.Key) {
+				out.Categories = append(out.Categories, Category{Name: cat.Name, Fields: fields})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Only returns a copy of db holding just the categories whose names match
+// any of patterns, which may be globs such as "w*". An empty list keeps
+// every category.
+func (db *DB) Only(patterns []string) *DB {
+	if len(patterns) == 0 {
+		return db
+	}
+	out := &DB{}
+	for _, cat := range db.Categories {
+		for _, p := range patterns {
+			if globKey(p, cat.Name) {
+				out.Categories = append(out.Categories, cat)
+				break
+			}
+		}
+	}
+	return out
+}
+
 // GetCategory retrieves a category by name.
 // Returns the category and true if found, or a zero Category and false otherwise.
 func (db *DB) GetCategory(name string) (Category, bool) {
@@ -147,21 +290,62 @@ func (db *DB) CategoryNames() []string {
 	return names
 }
 
-// Search performs a case-insensitive search across all field keys, values,
-// and descriptions, returning every field that contains the query string.
+// SearchScope selects which parts of a field Search and SearchRegex look at.
+// Scopes combine with |.
+type SearchScope uint8
+
+const (
+	ScopeKeys SearchScope = 1 << iota
+	ScopeValues
+	ScopeDescriptions
+
+	// ScopeAll searches keys, values, and descriptions.
+	ScopeAll = ScopeKeys | ScopeValues | ScopeDescriptions
+)
+
+// ParseSearchScope parses a scope name: "keys", "values", or
+// "descriptions". Singular forms and "desc" are also accepted.
+func ParseSearchScope(name string) (SearchScope, error) {
+	switch strings.ToLower(name) {
+	case "key", "keys":
+		return ScopeKeys, nil
+	case "value", "values":
+		return ScopeValues, nil
+	case "desc", "description", "descriptions":
+		return ScopeDescriptions, nil
+	}
+	return 0, fmt.Errorf("unknown search scope %q (want keys, values, or descriptions)", name)
+}
+
+// Search performs a case-insensitive search across the parts of each field
+// selected by scope, returning every field that contains the query string.
 // Results exclude _desc fields.
-func (db *DB) Search(query string) []Field {
-	var results []Field
+func (db *DB) Search(query string, scope SearchScope) []Field {
 	q := strings.ToLower(query)
+	return db.searchFunc(scope, func(s string) bool {
+		return containsLower(s, q)
+	})
+}
+
+// SearchRegex returns every field whose key, value, or description, as
+// selected by scope, matches re. Case sensitivity is up to the expression,
+// e.g. a leading (?i). Results exclude _desc fields.
+func (db *DB) SearchRegex(re *regexp.Regexp, scope SearchScope) []Field {
+	return db.searchFunc(scope, re.MatchString)
+}
 
+// searchFunc returns the fields whose key, formatted value, or description
+// satisfies match, considering only the parts selected by scope.
+func (db *DB) searchFunc(scope SearchScope, match func(string) bool) []Field {
+	var results []Field
 	for _, cat := range db.Categories {
 		for _, f := range cat.Fields {
 			if IsDescKey(f.Key) {
 				continue
 			}
-			if containsLower(f.Key, q) ||
-				containsLower(FormatValue(f.Value), q) ||
-				containsLower(f.Desc, q) {
+			if scope&ScopeKeys != 0 && match(f.Key) ||
+				scope&ScopeValues != 0 && match(FormatValue(f.Value)) ||
+				scope&ScopeDescriptions != 0 && match(f.Desc) {
 				results = append(results, f)
 			}
 		}
@@ -230,22 +414,51 @@ func (db *DB) AllDescriptions() []Field {
 	return results
 }
 
+// MissingDescriptions returns the fields, excluding _desc fields, that have
+// no description, in the named category or, if category is empty, across
+// the entire database.
+func (db *DB) MissingDescriptions(category string) []Field {
+	var results []Field
+	for _, cat := range db.Categories {
+		if category != "" && cat.Name != category {
+			continue
+		}
+		for _, f := range cat.Fields {
+			if !IsDescKey(f.Key) && f.Desc == "" {
+				results = append(results, f)
+			}
+		}
+	}
+	return results
+}
+
 // FormatValue converts a field value to a human-readable string for display.
 //
 // Formatting rules:
 //   - string: returned as-is
+//   - array of tables: each record summarized by FormatRecord, joined with "; "
 //   - []interface{}: elements joined with ", "
 //   - []string: elements joined with ", "
 //   - int64/float64: formatted with fmt.Sprint
 //   - fallback: formatted with fmt.Sprintf("%v", v)
 func FormatValue(v interface{}) string {
+	if recs, ok := Records(v); ok {
+		parts := make([]string, 0, len(recs))
+		for _, rec := range recs {
+			parts = append(parts, FormatRecord(rec))
+		}
+		return strings.Join(parts, "; ")
+	}
+
 	switch val := v.(type) {
 	case string:
 		return val
+	case map[string]interface{}:
+		return FormatRecord(val)
 	case []interface{}:
 		parts := make([]string, 0, len(val))
 		for _, item := range val {
-			parts = append(parts, fmt.Sprintf("%v", item))
+			parts = append(parts, FormatValue(item))
 		}
 		return strings.Join(parts, ", ")
 	case []string:
@@ -254,6 +467,8 @@ func FormatValue(v interface{}) string {
 		return fmt.Sprint(val)
 	case float64:
 		return fmt.Sprint(val)
+	case time.Time:
+		return FormatTime(val)
 	default:
 		return fmt.Sprintf("%v", v)
 	}
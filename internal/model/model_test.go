@@ -1,6 +1,8 @@
 package model
 
 import (
+	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -286,7 +288,7 @@ func TestCategoryNames_Empty(t *testing.T) {
 
 func TestSearch_MatchInKey(t *testing.T) {
 	db := newTestDB()
-	results := db.Search("github")
+	results := db.Search("github", ScopeAll)
 	if len(results) != 1 {
 		t.Fatalf("expected 1 result matching key 'github', got %d", len(results))
 	}
@@ -297,7 +299,7 @@ func TestSearch_MatchInKey(t *testing.T) {
 
 func TestSearch_MatchInValue(t *testing.T) {
 	db := newTestDB()
-	results := db.Search("Alexander")
+	results := db.Search("Alexander", ScopeAll)
 	if len(results) != 1 {
 		t.Fatalf("expected 1 result matching value 'Alexander', got %d", len(results))
 	}
@@ -308,7 +310,7 @@ func TestSearch_MatchInValue(t *testing.T) {
 
 func TestSearch_MatchInDescription(t *testing.T) {
 	db := newTestDB()
-	results := db.Search("ORCID persistent")
+	results := db.Search("ORCID persistent", ScopeAll)
 	if len(results) != 1 {
 		t.Fatalf("expected 1 result matching desc 'ORCID persistent', got %d", len(results))
 	}
@@ -319,7 +321,7 @@ func TestSearch_MatchInDescription(t *testing.T) {
 
 func TestSearch_CaseInsensitive(t *testing.T) {
 	db := newTestDB()
-	results := db.Search("QUEELIUS")
+	results := db.Search("QUEELIUS", ScopeAll)
 	if len(results) != 1 {
 		t.Fatalf("expected 1 result for case-insensitive search, got %d", len(results))
 	}
@@ -330,7 +332,7 @@ func TestSearch_CaseInsensitive(t *testing.T) {
 
 func TestSearch_NoMatches(t *testing.T) {
 	db := newTestDB()
-	results := db.Search("zzznoMatchHere")
+	results := db.Search("zzznoMatchHere", ScopeAll)
 	if len(results) != 0 {
 		t.Errorf("expected 0 results, got %d", len(results))
 	}
@@ -339,7 +341,7 @@ func TestSearch_NoMatches(t *testing.T) {
 func TestSearch_DescFieldsExcluded(t *testing.T) {
 	db := newTestDB()
 	// "companion desc" is the value of a _desc field; search should not return it
-	results := db.Search("companion desc")
+	results := db.Search("companion desc", ScopeAll)
 	for _, f := range results {
 		if IsDescKey(f.Key) {
 			t.Errorf("_desc key %q should be excluded from search results", f.Key)
@@ -350,7 +352,7 @@ func TestSearch_DescFieldsExcluded(t *testing.T) {
 func TestSearch_MatchInArrayValue(t *testing.T) {
 	db := newTestDB()
 	// "Alex Towell" is an element in the aka []interface{} slice
-	results := db.Search("Alex Towell")
+	results := db.Search("Alex Towell", ScopeAll)
 	found := false
 	for _, f := range results {
 		if f.Key == "aka" {
@@ -513,6 +515,40 @@ func TestAllDescriptions_Empty(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// MissingDescriptions
+// ---------------------------------------------------------------------------
+
+func TestMissingDescriptions(t *testing.T) {
+	db := newTestDB()
+	all := db.MissingDescriptions("")
+	described := len(db.AllDescriptions())
+	total := 0
+	for _, cat := range db.Categories {
+		for _, f := range cat.Fields {
+			if !IsDescKey(f.Key) {
+				total++
+			}
+		}
+	}
+	if len(all) != total-described {
+		t.Fatalf("expected %d undescribed fields, got %d", total-described, len(all))
+	}
+	for _, f := range all {
+		if f.Desc != "" || IsDescKey(f.Key) {
+			t.Errorf("field %s.%s should not be listed", f.Category, f.Key)
+		}
+	}
+
+	identity := db.MissingDescriptions("identity")
+	if len(identity) != 1 || identity[0].Key != "age" {
+		t.Errorf("expected identity.age only, got %v", identity)
+	}
+	if fields := db.MissingDescriptions("nonexistent"); fields != nil {
+		t.Errorf("expected nil for nonexistent category, got %v", fields)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // FormatValue
 // ---------------------------------------------------------------------------
@@ -673,3 +709,341 @@ func TestBaseKey(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Array indexing
+// ---------------------------------------------------------------------------
+
+func TestSplitIndex(t *testing.T) {
+	tests := []struct {
+		in    string
+		base  string
+		index int
+		ok    bool
+	}{
+		{"identity.aka[0]", "identity.aka", 0, true},
+		{"academic.topics[-1]", "academic.topics", -1, true},
+		{"identity.aka", "identity.aka", 0, false},
+		{"identity.a[bc]", "identity.a[bc]", 0, false},
+		{"[3]", "[3]", 0, false},
+	}
+	for _, tt := range tests {
+		base, i, ok := SplitIndex(tt.in)
+		if base != tt.base || i != tt.index || ok != tt.ok {
+			t.Errorf("SplitIndex(%q) = %q, %d, %v; want %q, %d, %v", tt.in, base, i, ok, tt.base, tt.index, tt.ok)
+		}
+	}
+}
+
+func TestGetField_Index(t *testing.T) {
+	db := newTestDB()
+	f, ok := db.GetField("identity.aka[0]")
+	if !ok || f.Value != "Alex Towell" || f.Key != "aka[0]" {
+		t.Errorf("unexpected field for aka[0]: %+v, %v", f, ok)
+	}
+	f, ok = db.GetField("academic.topics[-1]")
+	if !ok || f.Value != "machine learning" {
+		t.Errorf("unexpected field for topics[-1]: %+v, %v", f, ok)
+	}
+	for _, path := range []string{"identity.aka[2]", "identity.aka[-3]", "identity.name[0]", "identity.nope[0]"} {
+		if _, ok := db.GetField(path); ok {
+			t.Errorf("expected %s not to be found", path)
+		}
+	}
+}
+
+func TestQuery_Index(t *testing.T) {
+	db := newTestDB()
+	results := db.Query("identity.aka[1]")
+	if len(results) != 1 || results[0].Value != "Alex T" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	// Globs apply the index to every array field and drop the rest.
+	results = db.Query("*.*[0]")
+	if len(results) != 2 {
+		t.Errorf("expected aka[0] and topics[0], got %+v", results)
+	}
+	if results := db.Query("identity.aka[5]"); len(results) != 0 {
+		t.Errorf("out-of-range index should match nothing, got %+v", results)
+	}
+}
+
+func TestFormatValue_Record(t *testing.T) {
+	got := FormatValue(map[string]interface{}{"degree": "PhD", "institution": "SIUE"})
+	if got != "PhD, SIUE" {
+		t.Errorf("FormatValue(record) = %q", got)
+	}
+}
+
+// newNestedDB builds a database with an [education.phd] sub-table, a deeper
+// [education.phd.advisor] table, and an array of tables.
+func newNestedDB() *DB {
+	return &DB{
+		Categories: []Category{
+			{
+				Name: "education",
+				Fields: []Field{
+					{Key: "field", Value: "Statistics", Category: "education"},
+					{Key: "phd", Category: "education",
+						Value: map[string]interface{}{
+							"institution": "SIUE",
+							"year":        int64(2020),
+							"advisor":     map[string]interface{}{"name": "Bob"},
+						},
+						Descs: map[string]string{
+							"institution":  "Where the PhD was earned",
+							"advisor.name": "Dissertation advisor",
+						},
+					},
+					{Key: "degrees", Category: "education",
+						Value: []map[string]interface{}{
+							{"degree": "PhD", "institution": "SIUE"},
+							{"degree": "BS", "institution": "UIUC"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetField_Nested(t *testing.T) {
+	db := newNestedDB()
+	f, ok := db.GetField("education.phd.institution")
+	if !ok {
+		t.Fatal("expected to find education.phd.institution")
+	}
+	if f.Key != "phd.institution" || f.Value != "SIUE" || f.Category != "education" {
+		t.Errorf("unexpected field: %+v", f)
+	}
+	if f.Desc != "Where the PhD was earned" {
+		t.Errorf("Desc = %q", f.Desc)
+	}
+
+	f, ok = db.GetField("education.phd.advisor.name")
+	if !ok || f.Value != "Bob" || f.Desc != "Dissertation advisor" {
+		t.Errorf("deep field = %+v, %v", f, ok)
+	}
+
+	if _, ok := db.GetField("education.phd.missing"); ok {
+		t.Error("expected missing nested key not to be found")
+	}
+	if _, ok := db.GetField("education.field.sub"); ok {
+		t.Error("expected path through a scalar not to be found")
+	}
+}
+
+func TestGetField_IndexThenKey(t *testing.T) {
+	db := newNestedDB()
+	f, ok := db.GetField("education.degrees[1].institution")
+	if !ok {
+		t.Fatal("expected to find education.degrees[1].institution")
+	}
+	if f.Key != "degrees[1].institution" || f.Value != "UIUC" {
+		t.Errorf("unexpected field: %+v", f)
+	}
+}
+
+func TestQuery_NestedGlob(t *testing.T) {
+	db := newNestedDB()
+	results := db.Query("education.phd.*")
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d: %+v", len(results), results)
+	}
+	if results[0].Key != "phd.advisor" || results[1].Key != "phd.institution" || results[2].Key != "phd.year" {
+		t.Errorf("unexpected keys: %s, %s, %s", results[0].Key, results[1].Key, results[2].Key)
+	}
+
+	results = db.Query("education.*.institution")
+	if len(results) != 1 || results[0].Value != "SIUE" {
+		t.Errorf("education.*.institution = %+v", results)
+	}
+}
+
+func TestFlattenFields(t *testing.T) {
+	db := newNestedDB()
+	cat, _ := db.GetCategory("education")
+	flat := FlattenFields(cat.Fields)
+
+	var keys []string
+	for _, f := range flat {
+		keys = append(keys, f.Key)
+	}
+	want := []string{"field", "phd.advisor.name", "phd.institution", "phd.year", "degrees"}
+	if len(keys) != len(want) {
+		t.Fatalf("keys = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("keys[%d] = %q, want %q", i, keys[i], want[i])
+		}
+	}
+	if flat[1].Desc != "Dissertation advisor" {
+		t.Errorf("flattened Desc = %q", flat[1].Desc)
+	}
+}
+
+func TestQueryRegex(t *testing.T) {
+	db := newTestDB()
+	results := db.QueryRegex(regexp.MustCompile(`^(identity|web)\.(name|github)$`))
+	if len(results) != 2 || results[0].Key != "name" || results[1].Key != "github" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if got := db.QueryRegex(regexp.MustCompile(`_desc$`)); len(got) != 0 {
+		t.Errorf("_desc fields should be excluded, got %+v", got)
+	}
+
+	nested := newNestedDB().QueryRegex(regexp.MustCompile(`\.institution$`))
+	if len(nested) != 1 || nested[0].Key != "phd.institution" {
+		t.Errorf("expected nested leaf match, got %+v", nested)
+	}
+}
+
+func TestExpandBraces(t *testing.T) {
+	tests := map[string][]string{
+		"identity.name":            {"identity.name"},
+		"{identity,contact}.email": {"identity.email", "contact.email"},
+		"web.{github,blog{,_url}}": {"web.github", "web.blog", "web.blog_url"},
+		"{a,b}.{c,d}":              {"a.c", "a.d", "b.c", "b.d"},
+		"unbalanced.{a,b":          {"unbalanced.{a,b"},
+	}
+	for in, want := range tests {
+		got := ExpandBraces(in)
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("ExpandBraces(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestQuery_Braces(t *testing.T) {
+	db := newTestDB()
+	results := db.Query("{identity,web}.{name,github}")
+	if len(results) != 2 || results[0].Key != "name" || results[1].Key != "github" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if got := db.Query("{identity,identity}.name"); len(got) != 1 {
+		t.Errorf("duplicate alternatives should be merged, got %d", len(got))
+	}
+}
+
+func TestQuery_Globstar(t *testing.T) {
+	db := newNestedDB()
+	results := db.Query("**.institution")
+	if len(results) != 1 || results[0].Key != "phd.institution" {
+		t.Errorf("**.institution = %+v", results)
+	}
+	if got := db.Query("education.**"); len(got) != 3 {
+		t.Errorf("education.** matched %d fields, want 3", len(got))
+	}
+	if got := newTestDB().Query("**name"); len(got) != 1 || got[0].Key != "name" {
+		t.Errorf("**name = %+v", got)
+	}
+	if got := newTestDB().Query("*name"); len(got) != 0 {
+		t.Errorf("single * must not cross the dot, got %+v", got)
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	for _, ok := range []string{"identity.name", "*.email", "{a,b}.c", "identity.aka[0]", "**.x", "a.[abc]*"} {
+		if err := ValidatePattern(ok); err != nil {
+			t.Errorf("ValidatePattern(%q) = %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"identity.[", "{a,b.c", "a.b}"} {
+		if err := ValidatePattern(bad); err == nil {
+			t.Errorf("ValidatePattern(%q): expected error", bad)
+		}
+	}
+}
+
+func TestExclude(t *testing.T) {
+	db := newTestDB()
+	fields := db.Exclude(db.AllFields(), []string{"web", "*.gpa", "identity.a*"})
+	var paths []string
+	for _, f := range fields {
+		paths = append(paths, f.Category+"."+f.Key)
+	}
+	want := "identity.name academic.orcid academic.topics"
+	if strings.Join(paths, " ") != want {
+		t.Errorf("got %v, want %s", paths, want)
+	}
+
+	nested := newNestedDB()
+	leaves := FlattenFields(nested.AllFields())
+	if got := nested.Exclude(leaves, []string{"education.phd"}); len(got) != 2 {
+		t.Errorf("excluding a table should drop its leaves, got %+v", got)
+	}
+}
+
+func TestWithout(t *testing.T) {
+	db := newTestDB()
+	out := db.Without([]string{"web.*", "identity.aka"})
+	if _, ok := out.GetCategory("web"); ok {
+		t.Error("empty category should be dropped")
+	}
+	if _, ok := out.GetField("identity.aka"); ok {
+		t.Error("identity.aka should be excluded")
+	}
+	if _, ok := out.GetField("identity.name"); !ok {
+		t.Error("identity.name should remain")
+	}
+	if len(db.Categories) != 3 {
+		t.Error("Without must not modify the original DB")
+	}
+}
+
+func TestSearchRegex(t *testing.T) {
+	db := newTestDB()
+	results := db.SearchRegex(regexp.MustCompile(`^queel`), ScopeAll)
+	if len(results) != 1 || results[0].Key != "github" {
+		t.Fatalf("expected web.github, got %v", results)
+	}
+	if got := db.SearchRegex(regexp.MustCompile(`^QUEEL`), ScopeAll); len(got) != 0 {
+		t.Errorf("expected case-sensitive match to fail, got %v", got)
+	}
+	if got := db.SearchRegex(regexp.MustCompile(`(?i)^QUEEL`), ScopeAll); len(got) != 1 {
+		t.Errorf("expected (?i) match, got %v", got)
+	}
+}
+
+func TestSearch_Scope(t *testing.T) {
+	db := newTestDB()
+	// "github" is both a key and part of the description "GitHub username".
+	if got := db.Search("github", ScopeValues); len(got) != 0 {
+		t.Errorf("expected no value matches, got %v", got)
+	}
+	if got := db.Search("github", ScopeKeys); len(got) != 1 {
+		t.Errorf("expected 1 key match, got %v", got)
+	}
+	if got := db.Search("persistent", ScopeKeys|ScopeValues); len(got) != 0 {
+		t.Errorf("description-only match should be excluded, got %v", got)
+	}
+	if got := db.Search("persistent", ScopeDescriptions); len(got) != 1 {
+		t.Errorf("expected 1 description match, got %v", got)
+	}
+}
+
+func TestParseSearchScope(t *testing.T) {
+	for name, want := range map[string]SearchScope{
+		"keys": ScopeKeys, "value": ScopeValues, "desc": ScopeDescriptions, "Descriptions": ScopeDescriptions,
+	} {
+		got, err := ParseSearchScope(name)
+		if err != nil || got != want {
+			t.Errorf("ParseSearchScope(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseSearchScope("paths"); err == nil {
+		t.Error("expected error for unknown scope")
+	}
+}
+
+func TestOnly(t *testing.T) {
+	db := newTestDB()
+	if got := db.Only(nil); got != db {
+		t.Error("expected empty pattern list to return db unchanged")
+	}
+	got := db.Only([]string{"web", "ident*"}).CategoryNames()
+	if strings.Join(got, ",") != "identity,web" {
+		t.Errorf("Only = %v, want [identity web]", got)
+	}
+}
@@ -0,0 +1,294 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// indexSuffix matches a trailing array index such as "[0]" or "[-1]".
+var indexSuffix = regexp.MustCompile(`^(.+)\[(-?\d+)\]$`)
+
+// SplitIndex splits a path with a trailing array index, such as
+// "identity.aka[0]", into the base path and the index. It reports false if
+// the path has no index.
+func SplitIndex(path string) (string, int, bool) {
+	m := indexSuffix.FindStringSubmatch(path)
+	if m == nil {
+		return path, 0, false
+	}
+	i, err := strconv.Atoi(m[2])
+	if err != nil {
+		return path, 0, false
+	}
+	return m[1], i, true
+}
+
+// Element returns element i of an array value. Negative indices count from
+// the end, so -1 is the last element. It reports false if v is not an array
+// or i is out of range.
+func Element(v interface{}, i int) (interface{}, bool) {
+	var items []interface{}
+	switch val := v.(type) {
+	case []interface{}:
+		items = val
+	case []string:
+		items = make([]interface{}, len(val))
+		for j, s := range val {
+			items[j] = s
+		}
+	case []map[string]interface{}:
+		items = make([]interface{}, len(val))
+		for j, rec := range val {
+			items[j] = rec
+		}
+	default:
+		return nil, false
+	}
+	if i < 0 {
+		i += len(items)
+	}
+	if i < 0 || i >= len(items) {
+		return nil, false
+	}
+	return items[i], true
+}
+
+// indexField returns the element field for f at index i, keyed "key[i]".
+func indexField(f Field, i int) (Field, bool) {
+	v, ok := Element(f.Value, i)
+	if !ok {
+		return Field{}, false
+	}
+	f.Key = fmt.Sprintf("%s[%d]", f.Key, i)
+	f.Value = v
+	f.Descs = nil
+	return f, true
+}
+
+// childField returns the field for key k inside the table value of f,
+// keyed "parent.k". Descriptions of deeper keys are carried along.
+func childField(f Field, k string, v interface{}) Field {
+	child := Field{
+		Key:      f.Key + "." + k,
+		Value:    v,
+		Desc:     f.Descs[k],
+		Category: f.Category,
+		Computed: f.Computed,
+		Source:   f.Source,
+		Line:     f.Line,
+		Layer:    f.Layer,
+	}
+	prefix := k + "."
+	for sub, d := range f.Descs {
+		if strings.HasPrefix(sub, prefix) {
+			if child.Descs == nil {
+				child.Descs = make(map[string]string)
+			}
+			child.Descs[strings.TrimPrefix(sub, prefix)] = d
+		}
+	}
+	return child
+}
+
+// keyMatcher reports whether a path segment pattern selects key.
+type keyMatcher func(pattern, key string) bool
+
+// exactKey matches segments literally.
+func exactKey(pattern, key string) bool {
+	return pattern == key
+}
+
+// globKey matches segments with filepath.Match, falling back to a literal
+// comparison for malformed patterns.
+func globKey(pattern, key string) bool {
+	matched, err := filepath.Match(pattern, key)
+	if err != nil {
+		return pattern == key
+	}
+	return matched
+}
+
+// descend walks the remaining path segments into the table value of f and
+// returns every field they select. Each segment may carry an array index,
+// as in "degrees[1]". Fields that are not tables, or indices that are out
+// of range, yield no results.
+func descend(f Field, segs []string, match keyMatcher) []Field {
+	if len(segs) == 0 {
+		return []Field{f}
+	}
+	m, ok := f.Value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	pattern, i, indexed := SplitIndex(segs[0])
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if !IsDescKey(k) && match(pattern, k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var results []Field
+	for _, k := range keys {
+		child := childField(f, k, m[k])
+		if indexed {
+			if child, ok = indexField(child, i); !ok {
+				continue
+			}
+		}
+		results = append(results, descend(child, segs[1:], match)...)
+	}
+	return results
+}
+
+// matchFields applies a key path, split on ".", to the fields of a single
+// category. The first segment selects fields; later segments descend into
+// nested tables.
+func matchFields(fields []Field, keyPath string, match keyMatcher) []Field {
+	segs := strings.Split(keyPath, ".")
+	pattern, i, indexed := SplitIndex(segs[0])
+
+	var results []Field
+	for _, f := range fields {
+		if IsDescKey(f.Key) || !match(pattern, f.Key) {
+			continue
+		}
+		if indexed {
+			var ok bool
+			if f, ok = indexField(f, i); !ok {
+				continue
+			}
+		}
+		results = append(results, descend(f, segs[1:], match)...)
+	}
+	return results
+}
+
+// ExpandBraces expands brace alternations in a pattern, so
+// "{identity,contact}.email" becomes ["identity.email", "contact.email"].
+// Braces may nest. A pattern with unbalanced braces is returned unchanged.
+func ExpandBraces(pattern string) []string {
+	open := strings.IndexByte(pattern, '{')
+	if open == -1 {
+		return []string{pattern}
+	}
+	depth := 0
+	var alts []string
+	start := open + 1
+	for i := open; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alts = append(alts, pattern[start:i])
+				start = i + 1
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				alts = append(alts, pattern[start:i])
+				prefix, suffix := pattern[:open], pattern[i+1:]
+				var out []string
+				for _, alt := range alts {
+					out = append(out, ExpandBraces(prefix+alt+suffix)...)
+				}
+				return out
+			}
+		}
+	}
+	return []string{pattern}
+}
+
+// ValidatePattern reports an error if pattern has unbalanced braces or a
+// segment that filepath.Match cannot parse, such as an unclosed "[".
+func ValidatePattern(pattern string) error {
+	if strings.Count(pattern, "{") != strings.Count(pattern, "}") {
+		return fmt.Errorf("unbalanced braces in pattern %q", pattern)
+	}
+	for _, p := range ExpandBraces(pattern) {
+		for _, seg := range strings.Split(p, ".") {
+			seg, _, _ = SplitIndex(seg)
+			if _, err := filepath.Match(seg, ""); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// globstarRegexp converts a glob containing "**" into a regular expression
+// over full "category.key" paths. "**" matches any run of characters,
+// including dots; "*" and "?" stay within one path segment.
+func globstarRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^.]*")
+			}
+		case '?':
+			b.WriteString("[^.]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid pattern %q: unclosed [", pattern)
+			}
+			b.WriteString(pattern[i : i+end+1])
+			i += end
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// Leaves expands a field whose value is a table into one field per nested
+// scalar or array, keyed by dotted sub-path (e.g. "phd.institution"). Other
+// fields are returned unchanged.
+func Leaves(f Field) []Field {
+	m, ok := f.Value.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return []Field{f}
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if !IsDescKey(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var out []Field
+	for _, k := range keys {
+		out = append(out, Leaves(childField(f, k, m[k]))...)
+	}
+	return out
+}
+
+// FlattenFields applies Leaves to every field, so nested tables render as
+// dotted keys in row-oriented formats.
+func FlattenFields(fields []Field) []Field {
+	var out []Field
+	for _, f := range fields {
+		out = append(out, Leaves(f)...)
+	}
+	return out
+}
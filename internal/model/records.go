@@ -0,0 +1,208 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Degree is a structured education record, stored in me.toml as an array of
+// tables under [[education.degrees]].
+type Degree struct {
+	Degree      string `json:"degree,omitempty"`
+	Field       string `json:"field,omitempty"`
+	Institution string `json:"institution,omitempty"`
+	Year        string `json:"year,omitempty"`
+}
+
+// Position is a structured employment record, stored in me.toml as an array
+// of tables under [[employment.positions]].
+type Position struct {
+	Title        string `json:"title,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Start        string `json:"start,omitempty"`
+	End          string `json:"end,omitempty"`
+}
+
+// recordKeyOrder lists the preferred display order for well-known record
+// keys. Keys not listed here follow in alphabetical order.
+var recordKeyOrder = []string{
+	"degree", "title", "field", "institution", "organization", "year", "start", "end",
+}
+
+// Records returns v as a slice of records (tables) if it is an array of
+// tables, as produced by [[category.key]] sections or arrays of inline tables.
+func Records(v interface{}) ([]map[string]interface{}, bool) {
+	switch val := v.(type) {
+	case []map[string]interface{}:
+		return val, true
+	case []interface{}:
+		if len(val) == 0 {
+			return nil, false
+		}
+		recs := make([]map[string]interface{}, 0, len(val))
+		for _, item := range val {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			recs = append(recs, m)
+		}
+		return recs, true
+	default:
+		return nil, false
+	}
+}
+
+// RecordKeys returns the keys of a record in display order: well-known keys
+// first (see recordKeyOrder), then the remainder alphabetically.
+func RecordKeys(rec map[string]interface{}) []string {
+	keys := make([]string, 0, len(rec))
+	seen := make(map[string]bool, len(rec))
+	for _, k := range recordKeyOrder {
+		if _, ok := rec[k]; ok {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+	var rest []string
+	for k := range rec {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// FormatRecord renders a single record as a one-line summary. Degrees and
+// positions get a natural-language form; other records are rendered as
+// "key: value" pairs.
+//
+//	PhD in Statistics, SIUE (2020)
+//	Research Engineer, Acme (2019 – 2021)
+func FormatRecord(rec map[string]interface{}) string {
+	if _, ok := rec["degree"]; ok {
+		d := degreeFromRecord(rec)
+		s := d.Degree
+		if d.Field != "" {
+			s += " in " + d.Field
+		}
+		if d.Institution != "" {
+			s += ", " + d.Institution
+		}
+		if d.Year != "" {
+			s += " (" + d.Year + ")"
+		}
+		return s
+	}
+	if _, ok := rec["title"]; ok {
+		if _, ok := rec["organization"]; ok {
+			p := positionFromRecord(rec)
+			s := p.Title + ", " + p.Organization
+			switch {
+			case p.Start != "" && p.End != "":
+				s += " (" + p.Start + " – " + p.End + ")"
+			case p.Start != "":
+				s += " (" + p.Start + " – present)"
+			}
+			return s
+		}
+	}
+
+	parts := make([]string, 0, len(rec))
+	for _, k := range RecordKeys(rec) {
+		parts = append(parts, fmt.Sprintf("%s: %s", k, FormatValue(rec[k])))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Degrees returns the structured records stored at education.degrees. Plain
+// string entries (the older flat format) are returned with only Degree set.
+func (db *DB) Degrees() []Degree {
+	f, ok := db.GetField("education.degrees")
+	if !ok {
+		return nil
+	}
+	if recs, ok := Records(f.Value); ok {
+		out := make([]Degree, 0, len(recs))
+		for _, rec := range recs {
+			out = append(out, degreeFromRecord(rec))
+		}
+		return out
+	}
+	var out []Degree
+	for _, s := range Items(f.Value) {
+		out = append(out, Degree{Degree: s})
+	}
+	return out
+}
+
+// Positions returns the structured records stored at employment.positions.
+// Plain string entries are returned with only Title set.
+func (db *DB) Positions() []Position {
+	f, ok := db.GetField("employment.positions")
+	if !ok {
+		return nil
+	}
+	if recs, ok := Records(f.Value); ok {
+		out := make([]Position, 0, len(recs))
+		for _, rec := range recs {
+			out = append(out, positionFromRecord(rec))
+		}
+		return out
+	}
+	var out []Position
+	for _, s := range Items(f.Value) {
+		out = append(out, Position{Title: s})
+	}
+	return out
+}
+
+// degreeFromRecord maps a raw record onto a Degree.
+func degreeFromRecord(rec map[string]interface{}) Degree {
+	return Degree{
+		Degree:      recordString(rec, "degree"),
+		Field:       recordString(rec, "field"),
+		Institution: recordString(rec, "institution"),
+		Year:        recordString(rec, "year"),
+	}
+}
+
+// positionFromRecord maps a raw record onto a Position.
+func positionFromRecord(rec map[string]interface{}) Position {
+	return Position{
+		Title:        recordString(rec, "title"),
+		Organization: recordString(rec, "organization"),
+		Start:        recordString(rec, "start"),
+		End:          recordString(rec, "end"),
+	}
+}
+
+// recordString returns the formatted value of key in rec, or "" if absent.
+func recordString(rec map[string]interface{}, key string) string {
+	v, ok := rec[key]
+	if !ok {
+		return ""
+	}
+	return FormatValue(v)
+}
+
+// Items returns the elements of a scalar or array value as strings, so a
+// scalar yields one item and an absent (nil) value none.
+func Items(v interface{}) []string {
+	switch val := v.(type) {
+	case []interface{}:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			out = append(out, FormatValue(item))
+		}
+		return out
+	case []string:
+		return val
+	case nil:
+		return nil
+	default:
+		return []string{FormatValue(v)}
+	}
+}
@@ -0,0 +1,185 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func newRecordsDB() *DB {
+	return &DB{Categories: []Category{
+		{
+			Name: "education",
+			Fields: []Field{{
+				Key: "degrees", Category: "education",
+				Value: []map[string]interface{}{
+					{"degree": "PhD", "field": "Statistics", "institution": "SIUE", "year": int64(2020)},
+					{"degree": "BS", "institution": "UIUC"},
+				},
+			}},
+		},
+		{
+			Name: "employment",
+			Fields: []Field{{
+				Key: "positions", Category: "employment",
+				Value: []interface{}{
+					map[string]interface{}{"title": "Engineer", "organization": "Acme", "start": "2019", "end": "2021"},
+					map[string]interface{}{"title": "Researcher", "organization": "Lab", "start": "2021"},
+				},
+			}},
+		},
+	}}
+}
+
+func TestRecords(t *testing.T) {
+	if _, ok := Records([]map[string]interface{}{{"a": 1}}); !ok {
+		t.Error("expected []map to be records")
+	}
+	if _, ok := Records([]interface{}{map[string]interface{}{"a": 1}}); !ok {
+		t.Error("expected []interface{} of maps to be records")
+	}
+	if _, ok := Records([]interface{}{"a", map[string]interface{}{"a": 1}}); ok {
+		t.Error("mixed array should not be records")
+	}
+	if _, ok := Records([]interface{}{}); ok {
+		t.Error("empty array should not be records")
+	}
+	if _, ok := Records("x"); ok {
+		t.Error("string should not be records")
+	}
+}
+
+func TestRecordKeys_Order(t *testing.T) {
+	keys := RecordKeys(map[string]interface{}{"zeta": 1, "year": 2, "degree": 3, "alpha": 4})
+	want := []string{"degree", "year", "alpha", "zeta"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("RecordKeys = %v, want %v", keys, want)
+	}
+}
+
+func TestFormatRecord(t *testing.T) {
+	tests := []struct {
+		rec      map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"degree": "PhD", "field": "Statistics", "institution": "SIUE", "year": int64(2020)}, "PhD in Statistics, SIUE (2020)"},
+		{map[string]interface{}{"degree": "BS"}, "BS"},
+		{map[string]interface{}{"title": "Engineer", "organization": "Acme", "start": "2019", "end": "2021"}, "Engineer, Acme (2019 – 2021)"},
+		{map[string]interface{}{"title": "Engineer", "organization": "Acme", "start": "2019"}, "Engineer, Acme (2019 – present)"},
+		{map[string]interface{}{"b": "2", "a": "1"}, "a: 1, b: 2"},
+	}
+	for _, tt := range tests {
+		if got := FormatRecord(tt.rec); got != tt.expected {
+			t.Errorf("FormatRecord(%v) = %q, want %q", tt.rec, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatValue_Records(t *testing.T) {
+	db := newRecordsDB()
+	f, _ := db.GetField("education.degrees")
+	got := FormatValue(f.Value)
+	if got != "PhD in Statistics, SIUE (2020); BS, UIUC" {
+		t.Errorf("unexpected FormatValue for records: %q", got)
+	}
+	if InferType(f.Value) != "records" {
+		t.Errorf("expected records type, got %q", InferType(f.Value))
+	}
+}
+
+func TestDegrees(t *testing.T) {
+	degrees := newRecordsDB().Degrees()
+	if len(degrees) != 2 {
+		t.Fatalf("expected 2 degrees, got %d", len(degrees))
+	}
+	if degrees[0] != (Degree{Degree: "PhD", Field: "Statistics", Institution: "SIUE", Year: "2020"}) {
+		t.Errorf("unexpected first degree: %+v", degrees[0])
+	}
+}
+
+func TestDegrees_FlatStrings(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "education",
+		Fields: []Field{{Key: "degrees", Category: "education",
+			Value: []interface{}{"BS Computer Science (University, 2020)"}}},
+	}}}
+	degrees := db.Degrees()
+	if len(degrees) != 1 || degrees[0].Degree != "BS Computer Science (University, 2020)" {
+		t.Errorf("expected flat string to map to Degree, got %+v", degrees)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	positions := newRecordsDB().Positions()
+	if len(positions) != 2 {
+		t.Fatalf("expected 2 positions, got %d", len(positions))
+	}
+	if positions[1] != (Position{Title: "Researcher", Organization: "Lab", Start: "2021"}) {
+		t.Errorf("unexpected second position: %+v", positions[1])
+	}
+	if (&DB{}).Positions() != nil {
+		t.Error("expected nil positions for empty DB")
+	}
+}
+
+func TestFormatJSON_Records(t *testing.T) {
+	out, err := FormatJSON(newRecordsDB())
+	if err != nil {
+		t.Fatalf("FormatJSON error: %v", err)
+	}
+	var parsed map[string]map[string][]map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if parsed["education"]["degrees"][0]["institution"] != "SIUE" {
+		t.Errorf("expected nested record in JSON, got %s", out)
+	}
+}
+
+func TestFormatYAML_Records(t *testing.T) {
+	out := FormatYAML(newRecordsDB())
+	expected := `education:
+  degrees:
+    - degree: PhD
+      field: Statistics
+      institution: SIUE
+      year: 2020
+    - degree: BS
+      institution: UIUC
+`
+	if !strings.HasPrefix(out, expected) {
+		t.Errorf("unexpected YAML:\n%s", out)
+	}
+}
+
+func TestFormatTOML_Records(t *testing.T) {
+	out := FormatTOML(newRecordsDB())
+	want := `[education]
+
+[[education.degrees]]
+degree = "PhD"
+field = "Statistics"
+institution = "SIUE"
+year = 2020
+
+[[education.degrees]]
+degree = "BS"
+institution = "UIUC"
+`
+	if !strings.HasPrefix(out, want) {
+		t.Errorf("expected [[education.degrees]] sections, got:\n%s", out)
+	}
+	var parsed map[string]interface{}
+	if _, err := toml.Decode(out, &parsed); err != nil {
+		t.Errorf("invalid TOML: %v\n%s", err, out)
+	}
+}
+
+func TestFormatTable_Records(t *testing.T) {
+	out := FormatTable(newRecordsDB().AllFields())
+	if !strings.Contains(out, "Engineer, Acme (2019 – 2021); Researcher, Lab (2021 – present)") {
+		t.Errorf("expected record summaries in table, got:\n%s", out)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // SchemaField describes a single field's schema metadata.
@@ -13,11 +14,15 @@ type SchemaField struct {
 	Type        string `json:"type"`
 	Description string `json:"description"`
 	Example     string `json:"example"`
+	Computed    bool   `json:"computed,omitempty"`
 }
 
 // InferType returns a human-readable type name for the given value.
 func InferType(v interface{}) string {
-	switch v.(type) {
+	if _, ok := Records(v); ok {
+		return "records"
+	}
+	switch val := v.(type) {
 	case string:
 		return "string"
 	case []interface{}:
@@ -30,6 +35,8 @@ func InferType(v interface{}) string {
 		return "float"
 	case bool:
 		return "boolean"
+	case time.Time:
+		return TimeKind(val)
 	default:
 		return "unknown"
 	}
@@ -49,6 +56,7 @@ func BuildSchema(db *DB) []SchemaField {
 				Type:        InferType(f.Value),
 				Description: f.Desc,
 				Example:     FormatValue(f.Value),
+				Computed:    f.Computed,
 			})
 		}
 	}
@@ -68,39 +76,38 @@ func FormatSchemaTable(entries []SchemaField) string {
 	exWidth := len("Example")
 
 	for _, e := range entries {
-		if len(e.Category) > catWidth {
-			catWidth = len(e.Category)
-		}
-		if len(e.Key) > keyWidth {
-			keyWidth = len(e.Key)
-		}
-		if len(e.Type) > typeWidth {
-			typeWidth = len(e.Type)
-		}
-		if len(e.Description) > descWidth {
-			descWidth = len(e.Description)
-		}
-		if len(e.Example) > exWidth {
-			exWidth = len(e.Example)
-		}
+		typeWidth = max(typeWidth, DisplayWidth(schemaType(e)))
+		catWidth = max(catWidth, DisplayWidth(e.Category))
+		keyWidth = max(keyWidth, DisplayWidth(e.Key))
+		descWidth = max(descWidth, DisplayWidth(e.Description))
+		exWidth = max(exWidth, DisplayWidth(e.Example))
 	}
 
 	var b strings.Builder
-	fmt.Fprintf(&b, "%-*s    %-*s    %-*s    %-*s    %s\n",
-		catWidth, "Category", keyWidth, "Key", typeWidth, "Type", descWidth, "Description", "Example")
-	fmt.Fprintf(&b, "%-*s    %-*s    %-*s    %-*s    %s\n",
-		catWidth, strings.Repeat("\u2500", catWidth),
-		keyWidth, strings.Repeat("\u2500", keyWidth),
-		typeWidth, strings.Repeat("\u2500", typeWidth),
-		descWidth, strings.Repeat("\u2500", descWidth),
+	fmt.Fprintf(&b, "%s    %s    %s    %s    %s\n",
+		PadRight("Category", catWidth), PadRight("Key", keyWidth), PadRight("Type", typeWidth), PadRight("Description", descWidth), "Example")
+	fmt.Fprintf(&b, "%s    %s    %s    %s    %s\n",
+		strings.Repeat("\u2500", catWidth),
+		strings.Repeat("\u2500", keyWidth),
+		strings.Repeat("\u2500", typeWidth),
+		strings.Repeat("\u2500", descWidth),
 		strings.Repeat("\u2500", exWidth))
 	for _, e := range entries {
-		fmt.Fprintf(&b, "%-*s    %-*s    %-*s    %-*s    %s\n",
-			catWidth, e.Category, keyWidth, e.Key, typeWidth, e.Type, descWidth, e.Description, e.Example)
+		fmt.Fprintf(&b, "%s    %s    %s    %s    %s\n",
+			PadRight(e.Category, catWidth), PadRight(e.Key, keyWidth), PadRight(schemaType(e), typeWidth), PadRight(e.Description, descWidth), e.Example)
 	}
 	return b.String()
 }
 
+// schemaType returns the Type column text for a schema entry, marking
+// computed fields so they stand out from stored values.
+func schemaType(e SchemaField) string {
+	if e.Computed {
+		return e.Type + " (computed)"
+	}
+	return e.Type
+}
+
 // FormatSchemaJSON serializes schema entries as a JSON array.
 func FormatSchemaJSON(entries []SchemaField) (string, error) {
 	data, err := json.MarshalIndent(entries, "", "  ")
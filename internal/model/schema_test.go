@@ -152,3 +152,44 @@ func TestBuildSchema_EmptyDB(t *testing.T) {
 		t.Errorf("expected 0 entries for empty DB, got %d", len(schema))
 	}
 }
+
+func TestBuildSchema_Computed(t *testing.T) {
+	db := newTestDB()
+	db.Categories[1].Fields = append(db.Categories[1].Fields, Field{
+		Key: "profile", Value: "https://github.com/{{web.github}}", Category: "web",
+	})
+	if err := db.Resolve(); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+
+	entries := BuildSchema(db)
+	var found bool
+	for _, e := range entries {
+		if e.Key == "profile" {
+			found = true
+			if !e.Computed {
+				t.Error("expected profile to be marked computed")
+			}
+			if e.Example != "https://github.com/queelius" {
+				t.Errorf("unexpected example %q", e.Example)
+			}
+		} else if e.Computed {
+			t.Errorf("field %s.%s should not be computed", e.Category, e.Key)
+		}
+	}
+	if !found {
+		t.Fatal("expected computed field in schema")
+	}
+
+	table := FormatSchemaTable(entries)
+	if !strings.Contains(table, "string (computed)") {
+		t.Errorf("expected computed marker in table, got:\n%s", table)
+	}
+	out, err := FormatSchemaJSON(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `"computed": true`) {
+		t.Errorf("expected computed flag in JSON, got:\n%s", out)
+	}
+}
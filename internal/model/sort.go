@@ -0,0 +1,96 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKeys lists the orderings accepted by FieldLess and SortFields.
+var SortKeys = []string{"key", "value", "category"}
+
+// FieldLess returns the ordering named by by:
+//
+//   - "key" orders by key, then category
+//   - "value" orders numbers numerically and everything else by its
+//     formatted text, case-insensitively, then by path
+//   - "category" orders by category, then key
+func FieldLess(by string) (func(a, b Field) bool, error) {
+	switch by {
+	case "key":
+		return func(a, b Field) bool {
+			if a.Key != b.Key {
+				return a.Key < b.Key
+			}
+			return a.Category < b.Category
+		}, nil
+	case "value":
+		return func(a, b Field) bool {
+			if c := compareValues(a.Value, b.Value); c != 0 {
+				return c < 0
+			}
+			return a.Category+"."+a.Key < b.Category+"."+b.Key
+		}, nil
+	case "category":
+		return func(a, b Field) bool {
+			if a.Category != b.Category {
+				return a.Category < b.Category
+			}
+			return a.Key < b.Key
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown sort key %q (want %s)", by, strings.Join(SortKeys, ", "))
+}
+
+// SortFields returns a copy of fields ordered by by (see FieldLess), or in
+// their original order when by is empty. reverse inverts the order.
+func SortFields(fields []Field, by string, reverse bool) ([]Field, error) {
+	out := append([]Field(nil), fields...)
+	if by != "" {
+		less, err := FieldLess(by)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(out, func(i, j int) bool {
+			if reverse {
+				return less(out[j], out[i])
+			}
+			return less(out[i], out[j])
+		})
+	} else if reverse {
+		for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+			out[i], out[j] = out[j], out[i]
+		}
+	}
+	return out, nil
+}
+
+// compareValues compares two field values, numerically when both are
+// numbers and otherwise by their case-folded FormatValue text.
+func compareValues(a, b interface{}) int {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(FormatValue(a)), strings.ToLower(FormatValue(b)))
+}
+
+// toFloat converts the numeric types produced by the TOML decoder.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
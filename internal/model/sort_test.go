@@ -0,0 +1,63 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func sortFixture() []Field {
+	return []Field{
+		{Category: "web", Key: "github", Value: "queelius"},
+		{Category: "academic", Key: "gpa", Value: 3.95},
+		{Category: "academic", Key: "year", Value: int64(10)},
+		{Category: "identity", Key: "name", Value: "Alex"},
+	}
+}
+
+func paths(fields []Field) string {
+	var out []string
+	for _, f := range fields {
+		out = append(out, f.Category+"."+f.Key)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestSortFields(t *testing.T) {
+	tests := []struct {
+		by      string
+		reverse bool
+		want    string
+	}{
+		{"", false, "web.github academic.gpa academic.year identity.name"},
+		{"", true, "identity.name academic.year academic.gpa web.github"},
+		{"key", false, "web.github academic.gpa identity.name academic.year"},
+		{"value", false, "academic.gpa academic.year identity.name web.github"},
+		{"category", false, "academic.gpa academic.year identity.name web.github"},
+		{"category", true, "web.github identity.name academic.year academic.gpa"},
+	}
+	for _, tt := range tests {
+		got, err := SortFields(sortFixture(), tt.by, tt.reverse)
+		if err != nil {
+			t.Fatalf("SortFields(%q): %v", tt.by, err)
+		}
+		if paths(got) != tt.want {
+			t.Errorf("SortFields(%q, %v) = %s, want %s", tt.by, tt.reverse, paths(got), tt.want)
+		}
+	}
+}
+
+func TestSortFields_DoesNotModifyInput(t *testing.T) {
+	in := sortFixture()
+	if _, err := SortFields(in, "key", false); err != nil {
+		t.Fatal(err)
+	}
+	if in[0].Key != "github" {
+		t.Error("SortFields modified its input")
+	}
+}
+
+func TestSortFields_UnknownKey(t *testing.T) {
+	if _, err := SortFields(sortFixture(), "size", false); err == nil {
+		t.Error("expected error for unknown sort key")
+	}
+}
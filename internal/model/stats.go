@@ -0,0 +1,136 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CategoryStats holds the per-category counts in Stats.
+type CategoryStats struct {
+	Name      string `json:"name"`
+	Fields    int    `json:"fields"`
+	Described int    `json:"described"`
+	Local     int    `json:"local"`
+}
+
+// Stats summarizes a database: how many fields it holds, of which types,
+// how many are described or computed, and how many come from the local
+// override file.
+type Stats struct {
+	Categories []CategoryStats `json:"categories"`
+	Fields     int             `json:"fields"`
+	Described  int             `json:"described"`
+	Computed   int             `json:"computed"`
+	Local      int             `json:"local"`
+	Overrides  int             `json:"overrides"`
+	Types      map[string]int  `json:"types"`
+}
+
+// ComputeStats gathers Stats for db. Fields whose Layer is "local" count as
+// local; those whose path also exists in global count as overrides. global
+// may be nil when no local layer is in play.
+func ComputeStats(db, global *DB) Stats {
+	s := Stats{Types: make(map[string]int)}
+	for _, cat := range db.Categories {
+		cs := CategoryStats{Name: cat.Name}
+		for _, f := range cat.Fields {
+			if IsDescKey(f.Key) {
+				continue
+			}
+			cs.Fields++
+			s.Types[statsType(f.Value)]++
+			if f.Desc != "" {
+				cs.Described++
+			}
+			if f.Computed {
+				s.Computed++
+			}
+			if f.Layer == "local" {
+				cs.Local++
+				if global != nil {
+					if _, ok := global.GetField(cat.Name + "." + f.Key); ok {
+						s.Overrides++
+					}
+				}
+			}
+		}
+		if cs.Fields == 0 {
+			continue
+		}
+		s.Categories = append(s.Categories, cs)
+		s.Fields += cs.Fields
+		s.Described += cs.Described
+		s.Local += cs.Local
+	}
+	return s
+}
+
+// statsType extends InferType with "table" for nested tables.
+func statsType(v interface{}) string {
+	if _, ok := v.(map[string]interface{}); ok {
+		return "table"
+	}
+	return InferType(v)
+}
+
+// FormatStatsTable renders Stats as a per-category table followed by
+// totals and the type distribution.
+//
+// Output example:
+//
+//	Category    Fields    Described    Local
+//	────────    ──────    ─────────    ─────
+//	identity    2         1            0
+//
+//	Fields:     8 (5 described, 0 computed)
+//	Local:      0 (0 overrides)
+//	Types:      array 2, float 1, string 5
+func FormatStatsTable(s Stats) string {
+	catWidth := len("Category")
+	for _, c := range s.Categories {
+		catWidth = max(catWidth, DisplayWidth(c.Name))
+	}
+	const (
+		fieldsWidth = len("Fields")
+		descWidth   = len("Described")
+	)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s    %-*s    %-*s    %s\n", PadRight("Category", catWidth), fieldsWidth, "Fields", descWidth, "Described", "Local")
+	fmt.Fprintf(&b, "%s    %s    %s    %s\n",
+		repeatRune('\u2500', catWidth),
+		repeatRune('\u2500', fieldsWidth),
+		repeatRune('\u2500', descWidth),
+		repeatRune('\u2500', len("Local")))
+	for _, c := range s.Categories {
+		fmt.Fprintf(&b, "%s    %-*d    %-*d    %d\n", PadRight(c.Name, catWidth), fieldsWidth, c.Fields, descWidth, c.Described, c.Local)
+	}
+
+	types := make([]string, 0, len(s.Types))
+	for t := range s.Types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for i, t := range types {
+		types[i] = fmt.Sprintf("%s %d", t, s.Types[t])
+	}
+
+	fmt.Fprintf(&b, "\nFields:     %d (%d described, %d computed)\n", s.Fields, s.Described, s.Computed)
+	fmt.Fprintf(&b, "Local:      %d (%d overrides)\n", s.Local, s.Overrides)
+	fmt.Fprintf(&b, "Types:      %s\n", strings.Join(types, ", "))
+	return b.String()
+}
+
+// FormatStatsJSON serializes Stats as a JSON object.
+func FormatStatsJSON(s Stats) (string, error) {
+	if s.Categories == nil {
+		s.Categories = []CategoryStats{}
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal stats to JSON: %w", err)
+	}
+	return string(data), nil
+}
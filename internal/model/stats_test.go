@@ -0,0 +1,61 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	global := &DB{Categories: []Category{
+		{Name: "identity", Fields: []Field{{Key: "name", Value: "Alex"}}},
+	}}
+	db := &DB{Categories: []Category{
+		{Name: "identity", Fields: []Field{
+			{Key: "aka", Value: []interface{}{"A"}, Desc: "Aliases"},
+			{Key: "name", Value: "Bob", Layer: "local"},
+			{Key: "name_desc", Value: "Full name"},
+		}},
+		{Name: "web", Fields: []Field{
+			{Key: "mastodon", Value: "@bob", Layer: "local"},
+			{Key: "profile", Value: map[string]interface{}{"url": "x"}, Computed: true},
+		}},
+	}}
+
+	s := ComputeStats(db, global)
+	if s.Fields != 4 || s.Described != 1 || s.Computed != 1 {
+		t.Errorf("fields/described/computed = %d/%d/%d, want 4/1/1", s.Fields, s.Described, s.Computed)
+	}
+	if s.Local != 2 || s.Overrides != 1 {
+		t.Errorf("local/overrides = %d/%d, want 2/1", s.Local, s.Overrides)
+	}
+	if s.Types["string"] != 2 || s.Types["array"] != 1 || s.Types["table"] != 1 {
+		t.Errorf("unexpected types: %v", s.Types)
+	}
+	if len(s.Categories) != 2 || s.Categories[0].Fields != 2 || s.Categories[1].Local != 1 {
+		t.Errorf("unexpected categories: %+v", s.Categories)
+	}
+}
+
+func TestFormatStats(t *testing.T) {
+	s := ComputeStats(newTestDB(), nil)
+
+	out := FormatStatsTable(s)
+	for _, want := range []string{"Category", "identity", "Fields:", "Types:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in table:\n%s", want, out)
+		}
+	}
+
+	js, err := FormatStatsJSON(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(js), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if parsed["fields"] != float64(s.Fields) {
+		t.Errorf("unexpected fields count: %v", parsed["fields"])
+	}
+}
@@ -0,0 +1,314 @@
+package model
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TemplateData returns the value passed to generator templates. Each category
+// is available by name as a map of its non-_desc fields, so templates can
+// write {{.identity.name}} or {{with .contact.email}}...{{end}}. In addition:
+//
+//   - .Author is the db.Author() record
+//   - .Links is the db.Links() list of web profiles
+//   - .Degrees and .Positions are the structured education/employment records
+func TemplateData(db *DB) map[string]interface{} {
+	data := map[string]interface{}{
+		"Author":    db.Author(),
+		"Links":     db.Links(),
+		"Degrees":   db.Degrees(),
+		"Positions": db.Positions(),
+	}
+	for _, cat := range db.Categories {
+		fields := map[string]interface{}{}
+		for _, f := range cat.Fields {
+			if !IsDescKey(f.Key) {
+				fields[f.Key] = f.Value
+			}
+		}
+		data[cat.Name] = fields
+	}
+	return data
+}
+
+// templateFuncs are the helper functions available to generator templates.
+var templateFuncs = template.FuncMap{
+	// value formats any field value for display (arrays are comma-joined).
+	"value": FormatValue,
+	// join formats each element of an array value and joins them with sep.
+	"join": func(sep string, v interface{}) string {
+		return strings.Join(Items(v), sep)
+	},
+	// items returns the elements of a scalar or array value as strings.
+	"items": Items,
+	// shield escapes s for use in a shields.io static badge path.
+	"shield": func(s string) string {
+		return strings.NewReplacer("-", "--", "_", "__", " ", "_").Replace(s)
+	},
+	// upper and lower change the case of a formatted value.
+	"upper": func(v interface{}) string { return strings.ToUpper(FormatValue(v)) },
+	"lower": func(v interface{}) string { return strings.ToLower(FormatValue(v)) },
+	// default returns def when v is missing or empty, so a pipeline such as
+	// {{.phone | default "n/a"}} always prints something.
+	"default": func(def, v interface{}) interface{} {
+		if v == nil || FormatValue(v) == "" {
+			return def
+		}
+		return v
+	},
+	// title upper-cases the first letter of s.
+	"title": func(s string) string {
+		r, n := utf8.DecodeRuneInString(s)
+		if n == 0 {
+			return s
+		}
+		return string(unicode.ToUpper(r)) + s[n:]
+	},
+}
+
+// RenderTemplate parses text as a Go template and executes it against
+// TemplateData(db). Templates whose name ends in ".html" use html/template,
+// so interpolated values are escaped for their HTML context; all others use
+// text/template.
+func RenderTemplate(db *DB, name, text string) (string, error) {
+	var b strings.Builder
+	if strings.HasSuffix(name, ".html") {
+		tmpl, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(templateFuncs)).Parse(text)
+		if err != nil {
+			return "", err
+		}
+		if err := tmpl.Execute(&b, TemplateData(db)); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	if err := tmpl.Execute(&b, TemplateData(db)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// FieldsTemplateData returns the value passed to --template for a list of
+// query results. Each field is available by key ({{.name}}), and the fields
+// of each category by category name ({{.contact.email}}). When the same key
+// appears in several categories, the first match wins.
+func FieldsTemplateData(fields []Field) map[string]interface{} {
+	data := map[string]interface{}{}
+	cats := map[string]map[string]interface{}{}
+	var order []string
+	for _, f := range fields {
+		if IsDescKey(f.Key) {
+			continue
+		}
+		if _, ok := data[f.Key]; !ok {
+			data[f.Key] = f.Value
+		}
+		if cats[f.Category] == nil {
+			cats[f.Category] = map[string]interface{}{}
+			order = append(order, f.Category)
+		}
+		cats[f.Category][f.Key] = f.Value
+	}
+	for _, name := range order {
+		if _, ok := data[name]; !ok {
+			data[name] = cats[name]
+		}
+	}
+	return data
+}
+
+// RenderFields executes text as a text/template against
+// FieldsTemplateData(fields). A key the template reads that matched no
+// field is the empty string, so it prints nothing and default or with
+// treat it as empty, instead of text/template printing "<no value>".
+func RenderFields(fields []Field, text string) (string, error) {
+	tmpl, err := template.New("--template").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	data := FieldsTemplateData(fields)
+	for _, t := range tmpl.Templates() {
+		walkTemplate(t.Root, nil, true, func(path []string) { fillMissing(data, path) })
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// walkTemplate calls read with the path from the data root of every field
+// a template node reads, as far as it can be known: inside {{with .a.b}}
+// fields are relative to a.b, and inside {{range}} or a with of anything
+// else only $-rooted fields such as $.a are reported. known is false
+// where dot is not a path from the root.
+func walkTemplate(node parse.Node, dot []string, known bool, read func([]string)) {
+	pipe := func(p *parse.PipeNode) {
+		if p != nil {
+			walkPipe(p, dot, known, read)
+		}
+	}
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			walkTemplate(c, dot, known, read)
+		}
+	case *parse.ActionNode:
+		pipe(n.Pipe)
+	case *parse.TemplateNode:
+		pipe(n.Pipe)
+	case *parse.IfNode:
+		pipe(n.Pipe)
+		walkTemplate(n.List, dot, known, read)
+		walkTemplate(n.ElseList, dot, known, read)
+	case *parse.WithNode:
+		pipe(n.Pipe)
+		inner, ok := pipePath(n.Pipe)
+		walkTemplate(n.List, append(append([]string(nil), dot...), inner...), known && ok, read)
+		walkTemplate(n.ElseList, dot, known, read)
+	case *parse.RangeNode:
+		pipe(n.Pipe)
+		walkTemplate(n.List, nil, false, read)
+		walkTemplate(n.ElseList, dot, known, read)
+	}
+}
+
+// walkPipe reports the fields read by the arguments of a pipeline.
+func walkPipe(p *parse.PipeNode, dot []string, known bool, read func([]string)) {
+	for _, cmd := range p.Cmds {
+		for _, arg := range cmd.Args {
+			switch a := arg.(type) {
+			case *parse.FieldNode:
+				if known {
+					read(append(append([]string(nil), dot...), a.Ident...))
+				}
+			case *parse.VariableNode:
+				if a.Ident[0] == "$" && len(a.Ident) > 1 {
+					read(a.Ident[1:])
+				}
+			case *parse.PipeNode:
+				walkPipe(a, dot, known, read)
+			}
+		}
+	}
+}
+
+// pipePath returns the field path a pipeline such as ".a.b" evaluates, and
+// false for any other pipeline.
+func pipePath(p *parse.PipeNode) ([]string, bool) {
+	if len(p.Decl) > 0 || len(p.Cmds) != 1 || len(p.Cmds[0].Args) != 1 {
+		return nil, false
+	}
+	f, ok := p.Cmds[0].Args[0].(*parse.FieldNode)
+	if !ok {
+		return nil, false
+	}
+	return f.Ident, true
+}
+
+// fillMissing sets path in data to the empty string when it is absent,
+// adding tables on the way. Paths that run into a non-table value are left
+// for the template to report.
+func fillMissing(data map[string]interface{}, path []string) {
+	for i, k := range path {
+		v, ok := data[k]
+		if i == len(path)-1 {
+			if !ok {
+				data[k] = ""
+			}
+			return
+		}
+		if !ok {
+			v = map[string]interface{}{}
+			data[k] = v
+		}
+		next, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		data = next
+	}
+}
+
+// HumansTemplate is the built-in template for `deets generate humans-txt`.
+// It renders the TEAM section of a humans.txt file (https://humanstxt.org).
+const HumansTemplate = `/* TEAM */
+{{- with .identity.name}}
+	Name: {{value .}}{{end}}
+{{- with .academic.title}}
+	Role: {{value .}}{{end}}
+{{- with .contact.email}}
+	Contact: {{value .}}{{end}}
+{{- range .Links}}
+	{{title .Network}}: {{or .URL .Username}}{{end}}
+{{- with .identity.location}}
+	Location: {{value .}}{{end}}
+`
+
+// SignatureTemplate is the built-in plain-text template for
+// `deets generate signature`.
+const SignatureTemplate = `-- 
+{{with .identity.name}}{{value .}}
+{{end}}
+{{- with .academic.title}}{{value .}}{{with $.academic.institution}}, {{value .}}{{end}}
+{{else}}{{with .academic.institution}}{{value .}}
+{{end}}{{end}}
+{{- with .contact.email}}{{value .}}
+{{end}}
+{{- range .Links}}{{with .URL}}{{.}}
+{{end}}{{end}}`
+
+// SignatureHTMLTemplate is the built-in HTML template for
+// `deets generate signature --html`.
+const SignatureHTMLTemplate = `<p>
+{{- with .identity.name}}
+  <strong>{{value .}}</strong><br>
+{{- end}}
+{{- with .academic.title}}
+  {{value .}}{{with $.academic.institution}}, {{value .}}{{end}}<br>
+{{- else}}{{with .academic.institution}}
+  {{value .}}<br>
+{{- end}}{{end}}
+{{- with .contact.email}}
+  <a href="mailto:{{value .}}">{{value .}}</a><br>
+{{- end}}
+{{- range .Links}}{{if .URL}}
+  <a href="{{.URL}}">{{.Network}}</a>
+{{- end}}{{end}}
+</p>
+`
+
+// ProfileReadmeTemplate is the built-in template for
+// `deets generate profile-readme`, a GitHub profile README in Markdown.
+const ProfileReadmeTemplate = `# {{with .identity.name}}{{value .}}{{else}}Hi there{{end}}
+{{with .identity.bio}}
+{{value .}}
+{{end}}
+{{- $badges := false}}
+{{- range .Links}}{{if and .URL (eq .Network "github" "mastodon")}}
+{{- if not $badges}}
+{{$badges = true}}{{end -}}
+[![{{title .Network}}](https://img.shields.io/badge/{{title .Network}}-{{shield (or .Username .Network)}}-informational?logo={{.Network}})]({{.URL}})
+{{end}}{{end}}
+{{- with .Author.ORCIDID}}
+{{- if not $badges}}
+{{$badges = true}}{{end -}}
+[![ORCID](https://img.shields.io/badge/ORCID-{{shield .}}-A6CE39?logo=orcid)]({{$.Author.ORCID}})
+{{end}}
+{{- with .academic.research_interests}}
+## Research interests
+
+{{range items .}}- {{.}}
+{{end}}{{end}}`
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	out, err := RenderTemplate(newTestDB(), "t", `{{.identity.name}} | {{join "/" .identity.aka}} | {{title "github"}} | {{value .academic.topics}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Alexander Towell | Alex Towell/Alex T | Github | statistics, machine learning"
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
+
+func TestRenderTemplate_Errors(t *testing.T) {
+	if _, err := RenderTemplate(newTestDB(), "t", "{{.identity.name"); err == nil {
+		t.Error("expected parse error")
+	}
+	if _, err := RenderTemplate(newTestDB(), "t", "{{nosuchfunc .}}"); err == nil {
+		t.Error("expected error for unknown function")
+	}
+}
+
+func TestTemplateData_ExcludesDesc(t *testing.T) {
+	data := TemplateData(newTestDB())
+	identity := data["identity"].(map[string]interface{})
+	if _, ok := identity["name_desc"]; ok {
+		t.Error("template data should not include _desc fields")
+	}
+	if len(data["Links"].([]Link)) != 2 {
+		t.Errorf("expected two links, got %v", data["Links"])
+	}
+}
+
+func TestHumansTemplate(t *testing.T) {
+	out, err := RenderTemplate(newTestDB(), "humans.txt", HumansTemplate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "/* TEAM */\n\tName: Alexander Towell\n\tGithub: https://github.com/queelius\n\tWebsite: https://example.com\n"
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+
+	out, err = RenderTemplate(&DB{}, "humans.txt", HumansTemplate)
+	if err != nil || !strings.HasPrefix(out, "/* TEAM */") {
+		t.Errorf("expected empty TEAM section for empty DB, got %q (%v)", out, err)
+	}
+}
+
+func newSignatureDB() *DB {
+	db := newTestDB()
+	db.Categories = append(db.Categories, Category{Name: "contact", Fields: []Field{
+		{Key: "email", Value: "alex@example.com", Category: "contact"},
+	}})
+	db.Categories[2].Fields = append(db.Categories[2].Fields,
+		Field{Key: "title", Value: "Researcher", Category: "academic"},
+		Field{Key: "institution", Value: "R&D Lab", Category: "academic"})
+	return db
+}
+
+func TestSignatureTemplate(t *testing.T) {
+	out, err := RenderTemplate(newSignatureDB(), "signature.txt", SignatureTemplate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "-- \nAlexander Towell\nResearcher, R&D Lab\nalex@example.com\nhttps://github.com/queelius\nhttps://example.com\n"
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
+
+func TestSignatureTemplate_InstitutionOnly(t *testing.T) {
+	db := &DB{Categories: []Category{{Name: "academic", Fields: []Field{
+		{Key: "institution", Value: "SIUE", Category: "academic"},
+	}}}}
+	out, err := RenderTemplate(db, "signature.txt", SignatureTemplate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "-- \nSIUE\n" {
+		t.Errorf("got %q", out)
+	}
+}
+
+func TestSignatureHTMLTemplate(t *testing.T) {
+	out, err := RenderTemplate(newSignatureDB(), "signature.html", SignatureHTMLTemplate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"<strong>Alexander Towell</strong><br>",
+		"Researcher, R&amp;D Lab<br>",
+		`<a href="mailto:alex@example.com">alex@example.com</a><br>`,
+		`<a href="https://github.com/queelius">github</a>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderTemplate_HTMLEscapes(t *testing.T) {
+	db := &DB{Categories: []Category{{Name: "identity", Fields: []Field{
+		{Key: "name", Value: "<b>Bob</b>", Category: "identity"},
+	}}}}
+	out, err := RenderTemplate(db, "x.html", "<p>{{.identity.name}}</p>")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "<p>&lt;b&gt;Bob&lt;/b&gt;</p>" {
+		t.Errorf("expected escaped HTML, got %q", out)
+	}
+	out, _ = RenderTemplate(db, "x.txt", "{{.identity.name}}")
+	if out != "<b>Bob</b>" {
+		t.Errorf("text templates should not escape, got %q", out)
+	}
+}
+
+func TestProfileReadmeTemplate(t *testing.T) {
+	db := newTestDB()
+	db.Categories[0].Fields = append(db.Categories[0].Fields,
+		Field{Key: "bio", Value: "Statistician.", Category: "identity"})
+	db.Categories[2].Fields = append(db.Categories[2].Fields,
+		Field{Key: "research_interests", Value: []interface{}{"reliability", "encrypted search"}, Category: "academic"})
+
+	out, err := RenderTemplate(db, "profile-readme.md", ProfileReadmeTemplate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# Alexander Towell
+
+Statistician.
+
+[![Github](https://img.shields.io/badge/Github-queelius-informational?logo=github)](https://github.com/queelius)
+[![ORCID](https://img.shields.io/badge/ORCID-0000--0001--2345--6789-A6CE39?logo=orcid)](https://orcid.org/0000-0001-2345-6789)
+
+## Research interests
+
+- reliability
+- encrypted search
+`
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestProfileReadmeTemplate_Empty(t *testing.T) {
+	out, err := RenderTemplate(&DB{}, "profile-readme.md", ProfileReadmeTemplate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "# Hi there\n" {
+		t.Errorf("got %q", out)
+	}
+}
+
+func TestRenderFields(t *testing.T) {
+	fields := newTestDB().Query("*.github")
+	fields = append(fields, newTestDB().Query("identity")...)
+
+	tests := map[string]string{
+		`{{.name}} <{{.github}}>`:                      "Alexander Towell <queelius>",
+		`{{.identity.name | upper}}`:                   "ALEXANDER TOWELL",
+		`{{join " / " .aka}}`:                          "Alex Towell / Alex T",
+		`{{.pronouns | default "n/a"}}`:                "n/a",
+		`{{.web.github | default "n/a"}}`:              "queelius",
+		`{{range $k, $v := .web}}{{$k}}={{$v}}{{end}}`: "github=queelius",
+		`{{.name}} {{.missing}}`:                       "Alexander Towell ",
+		`[{{.contact.email}}]`:                         "[]",
+		`{{with .identity}}{{.nickname}}{{end}}!`:      "!",
+		`{{with .pronouns}}{{.}}{{else}}none{{end}}`:   "none",
+		`{{range .aka}}{{$.missing}}{{end}}.`:          ".",
+	}
+	for text, want := range tests {
+		got, err := RenderFields(fields, text)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", text, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s = %q, want %q", text, got, want)
+		}
+	}
+
+	if _, err := RenderFields(fields, "{{.name"); err == nil {
+		t.Error("expected parse error")
+	}
+}
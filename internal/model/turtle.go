@@ -0,0 +1,114 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// FormatTurtle renders the database as an RDF Turtle document describing a
+// foaf:Person identified by <#me>:
+//
+//   - identity.name → foaf:name, identity.aka → foaf:nick
+//   - contact emails → foaf:mbox <mailto:...>, phones → foaf:phone <tel:...>
+//   - web.website → foaf:homepage, web.blog → foaf:weblog
+//   - other web.* links and academic.orcid → foaf:account, as an
+//     foaf:OnlineAccount when the username is known and as the profile IRI
+//     otherwise
+//
+// Each statement is made once.
+func FormatTurtle(db *DB) string {
+	var props []string
+	seen := make(map[string]bool)
+	add := func(pred, obj string) {
+		// A field such as work_email = "@contact.email" repeats a statement
+		// already made.
+		if p := pred + " " + obj; !seen[p] {
+			seen[p] = true
+			props = append(props, p)
+		}
+	}
+
+	if name := fieldString(db, "identity.name"); name != "" {
+		add("foaf:name", turtleString(name))
+	}
+	if f, ok := db.GetField("identity.aka"); ok {
+		for _, s := range Items(f.Value) {
+			add("foaf:nick", turtleString(s))
+		}
+	}
+	for _, s := range contactItems(db, "email") {
+		add("foaf:mbox", turtleIRI("mailto:"+s))
+	}
+	for _, s := range contactItems(db, "phone") {
+		add("foaf:phone", turtleIRI("tel:"+strings.ReplaceAll(s, " ", "")))
+	}
+
+	for _, l := range db.Links() {
+		switch {
+		case l.Network == "website" && l.URL != "":
+			add("foaf:homepage", turtleIRI(l.URL))
+		case l.Network == "blog" && l.URL != "":
+			add("foaf:weblog", turtleIRI(l.URL))
+		case l.Username != "" && l.URL != "":
+			add("foaf:account", turtleAccount(serviceHomepage(l.URL), l.Username))
+		case l.URL != "":
+			add("foaf:account", turtleIRI(l.URL))
+		}
+	}
+	if orcid := fieldString(db, "academic.orcid"); orcid != "" {
+		if isURL(orcid) {
+			add("foaf:account", turtleIRI(orcid))
+		} else {
+			add("foaf:account", turtleAccount("https://orcid.org/", orcid))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("@prefix foaf: <http://xmlns.com/foaf/0.1/> .\n\n")
+	b.WriteString("<#me> a foaf:Person")
+	for _, p := range props {
+		b.WriteString(" ;\n    ")
+		b.WriteString(p)
+	}
+	b.WriteString(" .\n")
+	return b.String()
+}
+
+// turtleAccount renders an anonymous foaf:OnlineAccount node.
+func turtleAccount(service, name string) string {
+	return fmt.Sprintf("[\n        a foaf:OnlineAccount ;\n        foaf:accountServiceHomepage %s ;\n        foaf:accountName %s\n    ]",
+		turtleIRI(service), turtleString(name))
+}
+
+// serviceHomepage returns the scheme and host of a profile URL, e.g.
+// "https://github.com/" for "https://github.com/queelius".
+func serviceHomepage(profile string) string {
+	u, err := url.Parse(profile)
+	if err != nil || u.Host == "" {
+		return profile
+	}
+	return u.Scheme + "://" + u.Host + "/"
+}
+
+// turtleString quotes s as a Turtle string literal.
+func turtleString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// turtleIRI wraps s in angle brackets, percent-encoding the characters that
+// are not allowed inside a Turtle IRI reference.
+func turtleIRI(s string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range s {
+		if r <= ' ' || strings.ContainsRune("<>\"{}|^`\\", r) {
+			fmt.Fprintf(&b, "%%%02X", r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatTurtle(t *testing.T) {
+	db := newTestDB()
+	db.Categories = append(db.Categories, Category{
+		Name: "contact",
+		Fields: []Field{
+			{Key: "email", Value: "alex@example.com", Category: "contact"},
+			{Key: "phone", Value: "+1 555 0100", Category: "contact"},
+		},
+	})
+	out := FormatTurtle(db)
+	if !strings.HasPrefix(out, "@prefix foaf: <http://xmlns.com/foaf/0.1/> .\n\n<#me> a foaf:Person ;\n") {
+		t.Errorf("unexpected header:\n%s", out)
+	}
+	for _, want := range []string{
+		`    foaf:name "Alexander Towell" ;`,
+		`    foaf:nick "Alex T" ;`,
+		"    foaf:mbox <mailto:alex@example.com> ;",
+		"    foaf:phone <tel:+15550100> ;",
+		"    foaf:homepage <https://example.com> ;",
+		"foaf:accountServiceHomepage <https://github.com/> ;\n        foaf:accountName \"queelius\"\n    ]",
+		"foaf:accountServiceHomepage <https://orcid.org/> ;\n        foaf:accountName \"0000-0001-2345-6789\"\n    ] .\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatTurtle_Escaping(t *testing.T) {
+	db := &DB{Categories: []Category{
+		{Name: "identity", Fields: []Field{{Key: "name", Value: `Ada "The Countess"`, Category: "identity"}}},
+		{Name: "web", Fields: []Field{{Key: "blog", Value: "https://example.com/a b", Category: "web"}}},
+	}}
+	out := FormatTurtle(db)
+	if !strings.Contains(out, `foaf:name "Ada \"The Countess\""`) {
+		t.Errorf("expected escaped literal, got:\n%s", out)
+	}
+	if !strings.Contains(out, "foaf:weblog <https://example.com/a%20b>") {
+		t.Errorf("expected encoded IRI, got:\n%s", out)
+	}
+}
+
+func TestFormatTurtle_Empty(t *testing.T) {
+	out := FormatTurtle(&DB{})
+	if !strings.HasSuffix(out, "<#me> a foaf:Person .\n") {
+		t.Errorf("expected bare Person, got:\n%s", out)
+	}
+}
+
+func TestFormatTurtle_RefNotRepeated(t *testing.T) {
+	db := &DB{Categories: []Category{
+		{Name: "contact", Fields: []Field{
+			{Key: "email", Value: "ada@example.com", Category: "contact"},
+			{Key: "public_email", Value: "@contact.email", Category: "contact"},
+		}},
+	}}
+	if err := db.Resolve(); err != nil {
+		t.Fatal(err)
+	}
+	out := FormatTurtle(db)
+	if n := strings.Count(out, "foaf:mbox"); n != 1 {
+		t.Errorf("expected one foaf:mbox, got %d:\n%s", n, out)
+	}
+}
@@ -0,0 +1,159 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tsIdent matches property names that need no quoting in TypeScript.
+var tsIdent = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsReserved lists the type names a generated interface must not take: the
+// module's own Deets, and global types that generated code or its users
+// rely on, which a same-named interface would shadow.
+var tsReserved = []string{
+	"Deets", "Array", "Boolean", "Date", "Error", "Function", "JSON", "Map",
+	"Math", "Number", "Object", "Partial", "Promise", "Record", "RegExp",
+	"Set", "String", "Symbol",
+}
+
+// FormatTypeScript generates a TypeScript module with an interface per
+// category, a Deets interface combining them, and a `deets` const holding the
+// current values. Arrays are typed as T[] (or a union for mixed arrays) and
+// records as arrays of object types; _desc fields are excluded, and field
+// descriptions become JSDoc comments.
+//
+// Interface names are unique: a category whose name maps to Deets, a global
+// type such as Array or Date, or another category's interface ("web-site"
+// and "web_site") is numbered, as in Date2 or WebSite2.
+func FormatTypeScript(db *DB) (string, error) {
+	names := goNamer{}
+	for _, name := range tsReserved {
+		names[name] = true
+	}
+	typeNames := make([]string, len(db.Categories))
+	for i, cat := range db.Categories {
+		typeNames[i] = names.name(cat.Name)
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by deets generate ts; DO NOT EDIT.\n")
+
+	for i, cat := range db.Categories {
+		fmt.Fprintf(&b, "\n/** The [%s] category. */\n", cat.Name)
+		fmt.Fprintf(&b, "export interface %s {\n", typeNames[i])
+		for _, f := range cat.Fields {
+			if IsDescKey(f.Key) {
+				continue
+			}
+			if f.Desc != "" {
+				fmt.Fprintf(&b, "  /** %s */\n", strings.ReplaceAll(f.Desc, "*/", "*\\/"))
+			}
+			fmt.Fprintf(&b, "  %s: %s;\n", tsKey(f.Key), tsType(f.Value))
+		}
+		b.WriteString("}\n")
+	}
+
+	b.WriteString("\nexport interface Deets {\n")
+	for i, cat := range db.Categories {
+		fmt.Fprintf(&b, "  %s: %s;\n", tsKey(cat.Name), typeNames[i])
+	}
+	b.WriteString("}\n")
+
+	b.WriteString("\nexport const deets: Deets = {\n")
+	for _, cat := range db.Categories {
+		fmt.Fprintf(&b, "  %s: {\n", tsKey(cat.Name))
+		for _, f := range cat.Fields {
+			if IsDescKey(f.Key) {
+				continue
+			}
+			data, err := json.Marshal(jsonValue(f.Value))
+			if err != nil {
+				return "", fmt.Errorf("marshal %s.%s: %w", cat.Name, f.Key, err)
+			}
+			fmt.Fprintf(&b, "    %s: %s,\n", tsKey(f.Key), data)
+		}
+		b.WriteString("  },\n")
+	}
+	b.WriteString("};\n")
+	return b.String(), nil
+}
+
+// tsKey returns name as a TypeScript property name, quoting it if needed.
+func tsKey(name string) string {
+	if tsIdent.MatchString(name) {
+		return name
+	}
+	data, _ := json.Marshal(name)
+	return string(data)
+}
+
+// tsType returns the TypeScript type of a field value.
+func tsType(v interface{}) string {
+	switch InferType(v) {
+	case "array":
+		var types []string
+		seen := map[string]bool{}
+		for _, item := range stringItemsRaw(v) {
+			t := tsScalarType(item)
+			if !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+		switch len(types) {
+		case 0:
+			return "string[]"
+		case 1:
+			return types[0] + "[]"
+		default:
+			return "(" + strings.Join(types, " | ") + ")[]"
+		}
+	case "records":
+		recs, _ := Records(v)
+		var keys []string
+		types := map[string]string{}
+		for _, rec := range recs {
+			for _, k := range RecordKeys(rec) {
+				t := tsScalarType(rec[k])
+				switch prev, ok := types[k]; {
+				case !ok:
+					keys = append(keys, k)
+					types[k] = t
+				case prev != t:
+					types[k] = "unknown"
+				}
+			}
+		}
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			opt := ""
+			for _, rec := range recs {
+				if _, ok := rec[k]; !ok {
+					opt = "?"
+					break
+				}
+			}
+			parts = append(parts, fmt.Sprintf("%s%s: %s", tsKey(k), opt, types[k]))
+		}
+		return "Array<{ " + strings.Join(parts, "; ") + " }>"
+	default:
+		return tsScalarType(v)
+	}
+}
+
+// tsScalarType maps a scalar value to its TypeScript type.
+func tsScalarType(v interface{}) string {
+	switch InferType(v) {
+	case "string", "date", "datetime", "time": // dates are ISO strings in JSON
+		return "string"
+	case "integer", "float":
+		return "number"
+	case "boolean":
+		return "boolean"
+	default:
+		return "unknown"
+	}
+}
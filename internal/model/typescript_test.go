@@ -0,0 +1,71 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatTypeScript(t *testing.T) {
+	db := newTestDB()
+	db.Categories = append(db.Categories, newRecordsDB().Categories[0])
+	out, err := FormatTypeScript(db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"// Code generated by deets generate ts; DO NOT EDIT.\n",
+		"export interface Identity {\n  /** Full legal name */\n  name: string;\n",
+		"  aka: string[];\n",
+		"  age: number;\n",
+		"  topics: string[];\n",
+		"  degrees: Array<{ degree: string; field?: string; institution: string; year?: number }>;\n",
+		"export interface Deets {\n  identity: Identity;\n  web: Web;\n",
+		"export const deets: Deets = {\n  identity: {\n    name: \"Alexander Towell\",\n    aka: [\"Alex Towell\",\"Alex T\"],\n    age: 35,\n  },\n",
+		"    gpa: 3.95,\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "_desc") {
+		t.Error("_desc fields should be excluded")
+	}
+}
+
+func TestTSKeyAndTypes(t *testing.T) {
+	if got := tsKey("web-site"); got != `"web-site"` {
+		t.Errorf("tsKey should quote invalid identifiers, got %s", got)
+	}
+	if got := tsKey("name"); got != "name" {
+		t.Errorf("tsKey(name) = %s", got)
+	}
+	if got := tsType([]interface{}{"a", int64(1)}); got != "(string | number)[]" {
+		t.Errorf("mixed array type = %s", got)
+	}
+	if got := tsType(true); got != "boolean" {
+		t.Errorf("bool type = %s", got)
+	}
+}
+
+func TestFormatTypeScript_InterfaceNameCollisions(t *testing.T) {
+	db := &DB{}
+	for _, name := range []string{"deets", "date", "array", "web-site", "web_site"} {
+		db.Categories = append(db.Categories, Category{Name: name, Fields: []Field{{Key: "x", Value: "y", Category: name}}})
+	}
+	out, err := FormatTypeScript(db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"export interface Deets2 {", "export interface Date2 {", "export interface Array2 {",
+		"export interface WebSite {", "export interface WebSite2 {",
+		"  deets: Deets2;", "  web_site: WebSite2;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "export interface Deets {") != 1 {
+		t.Errorf("expected a single Deets interface:\n%s", out)
+	}
+}
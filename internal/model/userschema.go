@@ -0,0 +1,156 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Schema holds the constraints declared in ~/.deets/schema.toml: which
+// categories and keys exist, and the type, pattern, and presence of each
+// key.
+//
+// Example:
+//
+//	strict = true   # reject categories and keys not declared below
+//
+//	[identity.name]
+//	type = "string"
+//	required = true
+//
+//	[contact.email]
+//	pattern = '^[^@\s]+@[^@\s]+$'
+//
+//	[cooking]       # a category with any keys
+type Schema struct {
+	Strict     bool
+	Categories []SchemaCategory
+}
+
+// SchemaCategory is one declared category. A category with no Keys allows
+// any key, even in a strict schema.
+type SchemaCategory struct {
+	Name string
+	Keys []SchemaKey
+}
+
+// SchemaKey constrains one key. Empty Type and nil Pattern leave the value
+// unconstrained.
+type SchemaKey struct {
+	Name     string
+	Type     string
+	Pattern  *regexp.Regexp
+	Required bool
+}
+
+// SchemaTypes lists the type names a SchemaKey may declare, as reported by
+// InferType, plus "table" for nested tables.
+var SchemaTypes = []string{"string", "integer", "float", "boolean", "date", "datetime", "time", "array", "records", "table"}
+
+// category returns the declared category name, or nil.
+func (s *Schema) category(name string) *SchemaCategory {
+	for i := range s.Categories {
+		if s.Categories[i].Name == name {
+			return &s.Categories[i]
+		}
+	}
+	return nil
+}
+
+// key returns the declared key of c, or nil.
+func (c *SchemaCategory) key(name string) *SchemaKey {
+	for i := range c.Keys {
+		if c.Keys[i].Name == name {
+			return &c.Keys[i]
+		}
+	}
+	return nil
+}
+
+// CheckField returns the reasons value may not be stored at category.key,
+// or nil if it may.
+func (s *Schema) CheckField(category, key string, value interface{}) []string {
+	if IsDescKey(key) {
+		return nil
+	}
+	c := s.category(category)
+	if c == nil {
+		if s.Strict {
+			return []string{fmt.Sprintf("category [%s] is not in the schema", category)}
+		}
+		return nil
+	}
+	k := c.key(key)
+	if k == nil {
+		if s.Strict && len(c.Keys) > 0 {
+			return []string{fmt.Sprintf("key %q is not in the schema for [%s]", key, category)}
+		}
+		return nil
+	}
+
+	var problems []string
+	if k.Type != "" {
+		if got := statsType(value); got != k.Type {
+			problems = append(problems, fmt.Sprintf("type is %s, schema requires %s", got, k.Type))
+		}
+	}
+	if k.Pattern != nil {
+		var values []string
+		switch val := value.(type) {
+		case string:
+			values = []string{val}
+		case []interface{}:
+			for _, item := range val {
+				if s, ok := item.(string); ok {
+					values = append(values, s)
+				}
+			}
+		case []string:
+			values = val
+		}
+		for _, v := range values {
+			if !k.Pattern.MatchString(v) {
+				problems = append(problems, fmt.Sprintf("%q does not match %s", v, k.Pattern))
+			}
+		}
+	}
+	return problems
+}
+
+// Check returns every violation of s in db: fields the schema rejects and,
+// after them, required fields that are missing.
+func (s *Schema) Check(db *DB) []ValidationFailure {
+	var failures []ValidationFailure
+	for _, cat := range db.Categories {
+		if s.Strict && s.category(cat.Name) == nil {
+			failures = append(failures, ValidationFailure{
+				Validator: "schema",
+				Path:      cat.Name,
+				Message:   fmt.Sprintf("category [%s] is not in the schema", cat.Name),
+			})
+			continue
+		}
+		for _, f := range cat.Fields {
+			for _, msg := range s.CheckField(cat.Name, f.Key, f.Value) {
+				failures = append(failures, ValidationFailure{
+					Validator: "schema",
+					Path:      cat.Name + "." + f.Key,
+					Value:     FormatValue(f.Value),
+					Message:   msg,
+				})
+			}
+		}
+	}
+	for _, c := range s.Categories {
+		for _, k := range c.Keys {
+			path := c.Name + "." + k.Name
+			if _, ok := db.GetField(path); k.Required && !ok {
+				failures = append(failures, ValidationFailure{
+					Validator: "schema",
+					Path:      path,
+					Message:   "required field is missing",
+				})
+			}
+		}
+	}
+	return failures
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func testSchema() *Schema {
+	return &Schema{
+		Strict: true,
+		Categories: []SchemaCategory{
+			{Name: "identity", Keys: []SchemaKey{
+				{Name: "name", Type: "string", Required: true},
+				{Name: "aka", Type: "array", Pattern: regexp.MustCompile(`^[A-Z]`)},
+				{Name: "birthday", Type: "date", Required: true},
+			}},
+			{Name: "cooking"},
+		},
+	}
+}
+
+func TestSchema_CheckField(t *testing.T) {
+	s := testSchema()
+	cases := []struct {
+		cat, key string
+		value    interface{}
+		want     string // substring of the first problem, or "" for none
+	}{
+		{"identity", "name", "Alex", ""},
+		{"identity", "name", int64(3), "schema requires string"},
+		{"identity", "aka", []interface{}{"Alex", "lex"}, `"lex" does not match`},
+		{"identity", "name_desc", int64(3), ""},
+		{"identity", "nickname", "Al", "not in the schema for [identity]"},
+		{"cooking", "anything", "goes", ""},
+		{"hobbies", "chess", true, "category [hobbies] is not in the schema"},
+	}
+	for _, c := range cases {
+		problems := s.CheckField(c.cat, c.key, c.value)
+		switch {
+		case c.want == "" && len(problems) > 0:
+			t.Errorf("%s.%s: unexpected problems %v", c.cat, c.key, problems)
+		case c.want != "" && (len(problems) == 0 || !strings.Contains(problems[0], c.want)):
+			t.Errorf("%s.%s: problems %v, want %q", c.cat, c.key, problems, c.want)
+		}
+	}
+
+	s.Strict = false
+	if problems := s.CheckField("hobbies", "chess", true); problems != nil {
+		t.Errorf("non-strict schema rejected an undeclared category: %v", problems)
+	}
+}
+
+func TestSchema_Check(t *testing.T) {
+	db := &DB{Categories: []Category{
+		{Name: "hobbies", Fields: []Field{{Key: "chess", Value: true}, {Key: "go", Value: true}}},
+		{Name: "identity", Fields: []Field{{Key: "name", Value: int64(1)}}},
+	}}
+	var got []string
+	for _, f := range testSchema().Check(db) {
+		got = append(got, f.Path+": "+f.Message)
+	}
+	want := []string{
+		"hobbies: category [hobbies] is not in the schema",
+		"identity.name: type is integer, schema requires string",
+		"identity.birthday: required field is missing",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Check:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ValidationFailure is one value that failed a validator in Validate.
+type ValidationFailure struct {
+	Validator string `json:"validator"`
+	Path      string `json:"path"`
+	Value     string `json:"value"`
+	Message   string `json:"message"`
+}
+
+// Validator checks the string values of the keys it applies to, chosen by
+// category and key name.
+type Validator struct {
+	Name        string
+	Description string
+	Applies     func(category, key string) bool
+	Check       func(value string) string
+}
+
+var (
+	orcidID      = regexp.MustCompile(`^\d{4}-\d{4}-\d{4}-\d{3}[\dX]$`)
+	e164         = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+	phoneSpacing = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+)
+
+// Validators lists the built-in validators, in the order their failures
+// are reported for a value.
+var Validators = []Validator{
+	{
+		Name:        "email",
+		Description: "email and *_email keys parse as a bare address",
+		Applies: func(category, key string) bool {
+			return key == "email" || strings.HasSuffix(key, "_email")
+		},
+		Check: func(value string) string {
+			addr, err := mail.ParseAddress(value)
+			if err != nil || addr.Address != value {
+				return "not a valid email address"
+			}
+			return ""
+		},
+	},
+	{
+		Name:        "url",
+		Description: "URLs in [web] and *_url, website, homepage, and blog keys are well-formed http(s) URLs",
+		Applies: func(category, key string) bool {
+			return category == "web" || isURLKey(key)
+		},
+		Check: func(value string) string {
+			if !strings.ContainsAny(value, ":/") && !strings.HasPrefix(value, "www.") {
+				// [web] also holds bare usernames and handles.
+				return ""
+			}
+			u, err := url.Parse(value)
+			switch {
+			case err != nil || strings.ContainsAny(value, " \t"):
+				return "not a well-formed URL"
+			case u.Scheme != "http" && u.Scheme != "https":
+				return "URL must start with http:// or https://"
+			case u.Host == "":
+				return "URL has no host"
+			}
+			return ""
+		},
+	},
+	{
+		Name:        "orcid",
+		Description: "academic.orcid is a valid ORCID iD with a correct checksum",
+		Applies: func(category, key string) bool {
+			return category == "academic" && key == "orcid"
+		},
+		Check: func(value string) string {
+			id := strings.TrimPrefix(strings.TrimPrefix(value, "https://orcid.org/"), "http://orcid.org/")
+			if !orcidID.MatchString(id) {
+				return "not an ORCID iD of the form 0000-0000-0000-0000"
+			}
+			if want := orcidCheckDigit(id); id[len(id)-1] != want {
+				return fmt.Sprintf("checksum fails: last character should be %c", want)
+			}
+			return ""
+		},
+	},
+	{
+		Name:        "phone",
+		Description: "phone, mobile, fax, and *_phone keys are roughly E.164 (+ and 7 to 15 digits)",
+		Applies: func(category, key string) bool {
+			return key == "phone" || key == "mobile" || key == "fax" || strings.HasSuffix(key, "_phone")
+		},
+		Check: func(value string) string {
+			if !e164.MatchString(phoneSpacing.Replace(value)) {
+				return "not an international phone number such as +1 555 010 0000"
+			}
+			return ""
+		},
+	},
+}
+
+// orcidCheckDigit computes the ISO 7064 MOD 11-2 check character of an
+// ORCID iD from its first 15 digits.
+func orcidCheckDigit(id string) byte {
+	total := 0
+	for _, c := range strings.ReplaceAll(id, "-", "")[:15] {
+		total = (total + int(c-'0')) * 2
+	}
+	result := (12 - total%11) % 11
+	if result == 10 {
+		return 'X'
+	}
+	return byte('0' + result)
+}
+
+// Validate applies Validators to every string value of db, including the
+// items of arrays and the values inside tables and arrays of tables, and
+// returns the failures in the order of db. _desc keys are not validated.
+func Validate(db *DB) []ValidationFailure {
+	var failures []ValidationFailure
+	walkValues(db, func(category, path, key string, value interface{}, item bool) {
+		s, ok := value.(string)
+		if !ok || s == "" || IsDescKey(key) {
+			return
+		}
+		for _, v := range Validators {
+			if !v.Applies(category, key) {
+				continue
+			}
+			if msg := v.Check(s); msg != "" {
+				failures = append(failures, ValidationFailure{Validator: v.Name, Path: path, Value: s, Message: msg})
+			}
+		}
+	})
+	return failures
+}
+
+// FormatValidationTable renders failures as path, validator, value, and
+// message columns. A positive maxWidth cuts long values and messages short
+// to fit, as FormatTableFit does.
+func FormatValidationTable(failures []ValidationFailure, maxWidth int) string {
+	cols := []Column{{Header: "Path"}, {Header: "Validator"}, {Header: "Value", Shrink: true}, {Header: "Message", Shrink: true}}
+	rows := make([][]string, 0, len(failures))
+	for _, f := range failures {
+		rows = append(rows, []string{f.Path, f.Validator, f.Value, f.Message})
+	}
+	return FormatColumns(cols, rows, maxWidth)
+}
+
+// FormatValidationJSON renders failures as a JSON array.
+func FormatValidationJSON(failures []ValidationFailure) (string, error) {
+	if failures == nil {
+		failures = []ValidationFailure{}
+	}
+	data, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
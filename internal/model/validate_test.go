@@ -0,0 +1,75 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOrcidCheckDigit(t *testing.T) {
+	for _, id := range []string{"0000-0002-1825-0097", "0000-0001-5109-3700", "0000-0002-1694-233X"} {
+		if got := orcidCheckDigit(id); got != id[len(id)-1] {
+			t.Errorf("orcidCheckDigit(%s) = %c", id, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	db := &DB{Categories: []Category{
+		{Name: "contact", Fields: []Field{
+			{Key: "email", Value: "alex@example.com"},
+			{Key: "work_email", Value: "not an address"},
+			{Key: "email_desc", Value: "not validated"},
+			{Key: "phone", Value: "+1 (555) 010-0000"},
+			{Key: "mobile", Value: "555-0100"},
+		}},
+		{Name: "web", Fields: []Field{
+			{Key: "github", Value: "queelius"},
+			{Key: "website", Value: "https://example.com"},
+			{Key: "blog", Value: "ftp://example.com"},
+			{Key: "mirrors", Value: []interface{}{"https://a.example.com", "https://"}},
+			{Key: "site", Value: "htp:/bad"},
+			{Key: "mastodon", Value: "@alex@example.social"},
+		}},
+		{Name: "academic", Fields: []Field{
+			{Key: "orcid", Value: "https://orcid.org/0000-0002-1825-0098"},
+		}},
+		{Name: "work", Fields: []Field{
+			{Key: "jobs", Value: []map[string]interface{}{{"homepage": "www.example com"}}},
+		}},
+	}}
+
+	var got []string
+	for _, f := range Validate(db) {
+		got = append(got, f.Validator+" "+f.Path)
+	}
+	want := []string{
+		"email contact.work_email",
+		"phone contact.mobile",
+		"url web.blog",
+		"url web.mirrors",
+		"url web.site",
+		"orcid academic.orcid",
+		"url work.jobs[0].homepage",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Validate failures:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidate_ORCIDChecksumMessage(t *testing.T) {
+	db := &DB{Categories: []Category{
+		{Name: "academic", Fields: []Field{{Key: "orcid", Value: "0000-0002-1825-0098"}}},
+	}}
+	failures := Validate(db)
+	if len(failures) != 1 || !strings.Contains(failures[0].Message, "should be 7") {
+		t.Errorf("unexpected failures: %+v", failures)
+	}
+}
+
+func TestFormatValidationTable(t *testing.T) {
+	failures := []ValidationFailure{{Validator: "url", Path: "web.site", Value: "htp:/bad", Message: "URL must start with http:// or https://"}}
+	lines := strings.Split(FormatValidationTable(failures, 0), "\n")
+	if !strings.HasPrefix(lines[0], "Path") || !strings.Contains(lines[0], "Validator") || !strings.HasPrefix(lines[1], "───") {
+		t.Errorf("expected the shared table style, got:\n%s", strings.Join(lines, "\n"))
+	}
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatVCard renders the identity, contact, web, and academic fields of the
+// database as a vCard 4.0 (RFC 6350) card suitable for saving as a .vcf file:
+//
+//   - identity.name → FN and N, identity.aka → NICKNAME
+//   - contact keys containing "email" → EMAIL, "phone" → TEL
+//   - web.* links → URL, academic.orcid → URL (https://orcid.org/...)
+//   - academic.institution → ORG, academic.title → TITLE
+//
+// A property is written once per value. Lines are CRLF-terminated and
+// folded at 75 octets as the RFC requires.
+func FormatVCard(db *DB) string {
+	var lines []string
+	seen := make(map[string]bool)
+	add := func(name, value string) {
+		// A field such as work_email = "@contact.email" repeats a value
+		// already on the card.
+		line := name + ":" + value
+		if value != "" && !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+
+	name := fieldString(db, "identity.name")
+	lines = append(lines, "BEGIN:VCARD", "VERSION:4.0", "FN:"+vcardText(name))
+	if name != "" {
+		add("N", vcardName(name))
+	}
+	if f, ok := db.GetField("identity.aka"); ok {
+		var nicks []string
+		for _, s := range Items(f.Value) {
+			nicks = append(nicks, vcardText(s))
+		}
+		add("NICKNAME", strings.Join(nicks, ","))
+	}
+
+	for _, s := range contactItems(db, "email") {
+		add("EMAIL", vcardText(s))
+	}
+	for _, s := range contactItems(db, "phone") {
+		add("TEL", vcardText(s))
+	}
+
+	for _, l := range db.Links() {
+		add("URL", vcardText(l.URL))
+	}
+	if orcid := fieldString(db, "academic.orcid"); orcid != "" {
+		if !isURL(orcid) {
+			orcid = "https://orcid.org/" + orcid
+		}
+		add("URL", vcardText(orcid))
+	}
+	add("ORG", vcardText(fieldString(db, "academic.institution")))
+	add("TITLE", vcardText(fieldString(db, "academic.title")))
+	lines = append(lines, "END:VCARD")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(vcardFold(line))
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// contactItems returns every value of the [contact] fields whose key
+// contains kind (e.g. "email" matches email and work_email), in file order.
+func contactItems(db *DB, kind string) []string {
+	cat, ok := db.GetCategory("contact")
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, f := range cat.Fields {
+		if IsDescKey(f.Key) || !strings.Contains(f.Key, kind) {
+			continue
+		}
+		out = append(out, Items(f.Value)...)
+	}
+	return out
+}
+
+// fieldString returns the formatted value at path, or "" if it is absent.
+func fieldString(db *DB, path string) string {
+	f, ok := db.GetField(path)
+	if !ok {
+		return ""
+	}
+	return FormatValue(f.Value)
+}
+
+// vcardName builds the structured N property (family;given;additional;;)
+// from a display name, treating the last word as the family name.
+func vcardName(name string) string {
+	given, family := splitName(name)
+	var additional []string
+	if parts := strings.Fields(given); len(parts) > 1 {
+		given = parts[0]
+		for _, p := range parts[1:] {
+			additional = append(additional, vcardText(p))
+		}
+	}
+	return fmt.Sprintf("%s;%s;%s;;", vcardText(family), vcardText(given), strings.Join(additional, ","))
+}
+
+// vcardText escapes backslashes, commas, semicolons, and newlines in a
+// vCard text value.
+func vcardText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// vcardFold splits a content line longer than 75 octets into continuation
+// lines beginning with a single space, without breaking UTF-8 sequences.
+func vcardFold(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		n := len(string(r))
+		if width+n > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	db := &DB{Categories: []Category{{
+		Name: "web",
+		Fields: []Field{
+			{Key: "github", Value: "queelius", Category: "web"},
+			{Key: "github_desc", Value: "GitHub username", Category: "web"},
+			{Key: "blog", Value: "https://example.com", Category: "web"},
+			{Key: "mastodon", Value: "@alex@fosstodon.org", Category: "web"},
+			{Key: "twitter", Value: "@alex", Category: "web"},
+			{Key: "matrix", Value: "alex", Category: "web"},
+		},
+	}}}
+	links := db.Links()
+	want := []Link{
+		{Network: "github", Username: "queelius", URL: "https://github.com/queelius"},
+		{Network: "blog", URL: "https://example.com"},
+		{Network: "mastodon", Username: "alex@fosstodon.org", URL: "https://fosstodon.org/@alex"},
+		{Network: "twitter", Username: "alex", URL: "https://twitter.com/alex"},
+		{Network: "matrix", Username: "alex"},
+	}
+	if len(links) != len(want) {
+		t.Fatalf("expected %d links, got %d: %+v", len(want), len(links), links)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("link %d: expected %+v, got %+v", i, want[i], links[i])
+		}
+	}
+}
+
+func TestFormatVCard(t *testing.T) {
+	out := FormatVCard(newTestDB())
+	if !strings.HasPrefix(out, "BEGIN:VCARD\r\nVERSION:4.0\r\n") {
+		t.Errorf("expected vCard header, got:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "END:VCARD\r\n") {
+		t.Errorf("expected END:VCARD trailer, got:\n%s", out)
+	}
+	for _, want := range []string{
+		"FN:Alexander Towell\r\n",
+		"N:Towell;Alexander;;;\r\n",
+		"NICKNAME:Alex Towell,Alex T\r\n",
+		"URL:https://github.com/queelius\r\n",
+		"URL:https://example.com\r\n",
+		"URL:https://orcid.org/0000-0001-2345-6789\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatVCard_Contact(t *testing.T) {
+	db := &DB{Categories: []Category{
+		{Name: "identity", Fields: []Field{{Key: "name", Value: "Ada King, Countess", Category: "identity"}}},
+		{Name: "contact", Fields: []Field{
+			{Key: "email", Value: "ada@example.com", Category: "contact"},
+			{Key: "work_email", Value: []interface{}{"a@work.example", "b@work.example"}, Category: "contact"},
+			{Key: "phone", Value: "+1-555-0100", Category: "contact"},
+			{Key: "address", Value: "ignored", Category: "contact"},
+		}},
+		{Name: "academic", Fields: []Field{
+			{Key: "institution", Value: "SIUE; Dept. of Math", Category: "academic"},
+		}},
+	}}
+	out := FormatVCard(db)
+	for _, want := range []string{
+		`FN:Ada King\, Countess` + "\r\n",
+		"EMAIL:ada@example.com\r\n",
+		"EMAIL:a@work.example\r\nEMAIL:b@work.example\r\n",
+		"TEL:+1-555-0100\r\n",
+		`ORG:SIUE\; Dept. of Math` + "\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "ignored") {
+		t.Errorf("unmapped contact fields should be skipped:\n%s", out)
+	}
+}
+
+func TestFormatVCard_RefNotRepeated(t *testing.T) {
+	db := &DB{Categories: []Category{
+		{Name: "contact", Fields: []Field{
+			{Key: "email", Value: "ada@example.com", Category: "contact"},
+			{Key: "public_email", Value: "@contact.email", Category: "contact"},
+		}},
+		{Name: "web", Fields: []Field{
+			{Key: "website", Value: "https://ada.example", Category: "web"},
+			{Key: "blog", Value: "@web.website", Category: "web"},
+		}},
+	}}
+	if err := db.Resolve(); err != nil {
+		t.Fatal(err)
+	}
+	out := FormatVCard(db)
+	if n := strings.Count(out, "EMAIL:"); n != 1 {
+		t.Errorf("expected one EMAIL, got %d:\n%s", n, out)
+	}
+	if n := strings.Count(out, "URL:https://ada.example"); n != 1 {
+		t.Errorf("expected one URL, got %d:\n%s", n, out)
+	}
+}
+
+func TestVCardFold(t *testing.T) {
+	line := "NOTE:" + strings.Repeat("é", 60)
+	folded := vcardFold(line)
+	for _, l := range strings.Split(folded, "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("folded line exceeds 75 octets (%d): %q", len(l), l)
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != line {
+		t.Error("unfolding should restore the original line")
+	}
+}
+
+func TestVCardName(t *testing.T) {
+	tests := map[string]string{
+		"Cher":                      "Cher;;;;",
+		"Alexander Towell":          "Towell;Alexander;;;",
+		"John Ronald Reuel Tolkien": "Tolkien;John;Ronald,Reuel;;",
+	}
+	for in, want := range tests {
+		if got := vcardName(in); got != want {
+			t.Errorf("vcardName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
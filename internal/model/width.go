@@ -0,0 +1,26 @@
+package model
+
+import "github.com/mattn/go-runewidth"
+
+// DisplayWidth returns the number of terminal columns s occupies, as
+// measured by go-runewidth: two for East Asian wide and fullwidth
+// characters and for emoji, none for combining marks, zero-width joiners,
+// and control characters.
+func DisplayWidth(s string) int {
+	return runewidth.StringWidth(s)
+}
+
+// PadRight pads s with spaces to width columns as measured by
+// DisplayWidth. Strings already that wide are returned unchanged.
+func PadRight(s string, width int) string {
+	return runewidth.FillRight(s, width)
+}
+
+// Truncate shortens s to at most n columns as measured by DisplayWidth,
+// marking the cut with "…".
+func Truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return runewidth.Truncate(s, n, "…")
+}
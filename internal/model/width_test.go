@@ -0,0 +1,100 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	cases := map[string]int{
+		"":          0,
+		"abc":       3,
+		"café":      4,
+		"café":     4, // e + combining acute accent
+		"日本語":       6,
+		"한국":        4,
+		"ＡＢ":        4, // fullwidth
+		"👍":         2,
+		"👍🏽":        2, // with skin tone modifier
+		"a‍b":       2, // zero-width joiner
+		"tab\there": 7,
+	}
+	for s, want := range cases {
+		if got := DisplayWidth(s); got != want {
+			t.Errorf("DisplayWidth(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	if got := PadRight("日本", 6); got != "日本  " {
+		t.Errorf("PadRight = %q", got)
+	}
+	if got := PadRight("toolong", 3); got != "toolong" {
+		t.Errorf("PadRight should not cut, got %q", got)
+	}
+}
+
+func TestFormatTable_WideCharactersAlign(t *testing.T) {
+	fields := []Field{
+		{Category: "identity", Key: "name", Value: "山田太郎", Desc: "x"},
+		{Category: "identity", Key: "emoji", Value: "🎉 party", Desc: "y"},
+		{Category: "identity", Key: "accent", Value: "Zoë", Desc: "z"},
+	}
+	out := FormatTableWithDesc(fields)
+	// Every row's description column starts at the same display column.
+	var col int
+	for i, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if i == 1 {
+			continue // separator
+		}
+		idx := strings.LastIndex(line, "    ")
+		c := DisplayWidth(line[:idx])
+		if i == 0 {
+			col = c
+		} else if c != col {
+			t.Errorf("row %d misaligned (column %d, want %d):\n%s", i, c, col, out)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("日本語テキスト", 7); got != "日本語…" {
+		t.Errorf("Truncate = %q", got)
+	}
+	if got := Truncate("日本", 4); got != "日本" {
+		t.Errorf("Truncate should keep text that fits, got %q", got)
+	}
+	if got := Truncate("abcdef", 4); got != "abc…" {
+		t.Errorf("Truncate = %q", got)
+	}
+	if got := Truncate("abc", 0); got != "" {
+		t.Errorf("Truncate to 0 = %q", got)
+	}
+}
+
+func TestFormatTableFit(t *testing.T) {
+	fields := []Field{
+		{Category: "identity", Key: "bio", Value: strings.Repeat("word ", 40), Desc: "A long description of the biography field"},
+		{Category: "identity", Key: "name", Value: "Alex", Desc: "Full name"},
+	}
+	out := FormatTableFit(fields, true, false, 50)
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if w := DisplayWidth(line); w > 50 {
+			t.Errorf("line is %d columns wide, want at most 50: %q", w, line)
+		}
+	}
+	if !strings.Contains(out, "…") || !strings.Contains(out, "Alex") {
+		t.Errorf("expected cut values:\n%s", out)
+	}
+	// Columns stay aligned: every row's Description starts at the same column.
+	lines := strings.Split(out, "\n")
+	col := strings.Index(lines[0], "Description")
+	if !strings.HasPrefix(lines[3][col:], "Full name") {
+		t.Errorf("description column misaligned:\n%s", out)
+	}
+
+	if got := FormatTableFit(fields, true, false, 0); got != FormatTableWithDesc(fields) {
+		t.Errorf("maxWidth 0 should not cut:\n%s", got)
+	}
+}
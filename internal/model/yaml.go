@@ -0,0 +1,177 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatYAML formats the entire DB as a YAML document.
+//
+// Each category is a top-level mapping key, separated by a blank line.
+// Arrays of scalars use the flow sequence syntax, nested tables are block
+// mappings, arrays of tables are block sequences, and multi-line strings
+// are literal block scalars. Strings are unquoted unless they require
+// quoting. _desc fields are excluded. Output is built as a yaml.v3 node
+// tree, so key order is preserved and every value is escaped correctly.
+func FormatYAML(db *DB) string {
+	var docs []string
+	for _, cat := range db.Categories {
+		fields := &yaml.Node{Kind: yaml.MappingNode}
+		for _, f := range cat.Fields {
+			if !IsDescKey(f.Key) {
+				fields.Content = append(fields.Content, yamlString(f.Key), yamlNode(f.Value))
+			}
+		}
+		docs = append(docs, encodeYAML(&yaml.Node{
+			Kind:    yaml.MappingNode,
+			Content: []*yaml.Node{yamlString(cat.Name), fields},
+		}))
+	}
+	return strings.Join(docs, "\n")
+}
+
+// encodeYAML renders node with two-space indentation.
+func encodeYAML(node *yaml.Node) string {
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	// Encoding a node tree built by yamlNode cannot fail.
+	_ = enc.Encode(node)
+	_ = enc.Close()
+	return b.String()
+}
+
+// yamlScalar renders s as a single-line YAML scalar, for writers that lay
+// out a document by hand.
+func yamlScalar(s string) string {
+	node := yamlString(s)
+	if node.Style == yaml.LiteralStyle {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	return strings.TrimSuffix(encodeYAML(node), "\n")
+}
+
+// yamlNode converts a Go value to a YAML node.
+func yamlNode(v interface{}) *yaml.Node {
+	if recs, ok := Records(v); ok {
+		seq := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, rec := range recs {
+			seq.Content = append(seq.Content, yamlNode(rec))
+		}
+		return seq
+	}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		m := &yaml.Node{Kind: yaml.MappingNode}
+		if len(val) == 0 {
+			m.Style = yaml.FlowStyle
+		}
+		for _, k := range RecordKeys(val) {
+			if !IsDescKey(k) {
+				m.Content = append(m.Content, yamlString(k), yamlNode(val[k]))
+			}
+		}
+		return m
+	case string:
+		return yamlString(val)
+	case []interface{}:
+		items := make([]*yaml.Node, 0, len(val))
+		for _, item := range val {
+			items = append(items, yamlNode(item))
+		}
+		return yamlSequence(items)
+	case []string:
+		items := make([]*yaml.Node, 0, len(val))
+		for _, s := range val {
+			items = append(items, yamlString(s))
+		}
+		return yamlSequence(items)
+	case int64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: fmt.Sprint(val)}
+	case float64:
+		s := fmt.Sprint(val)
+		switch {
+		case math.IsInf(val, 1):
+			s = ".inf"
+		case math.IsInf(val, -1):
+			s = "-.inf"
+		case math.IsNaN(val):
+			s = ".nan"
+		case !strings.ContainsAny(s, ".eE"):
+			// YAML would read a bare "4" back as an integer.
+			s += ".0"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: s}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(val)}
+	case time.Time:
+		// Untagged, so dates and datetimes stay plain timestamps.
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: FormatTime(val)}
+	default:
+		return yamlString(fmt.Sprintf("%v", v))
+	}
+}
+
+// yamlSequence wraps items in a sequence, in flow style ([a, b]) when every
+// item is a scalar and as a block sequence otherwise.
+func yamlSequence(items []*yaml.Node) *yaml.Node {
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle, Content: items}
+	for _, item := range items {
+		if item.Kind != yaml.ScalarNode {
+			seq.Style = 0
+			return seq
+		}
+	}
+	for _, item := range items {
+		// Block scalars cannot appear inside a flow sequence.
+		if item.Style == yaml.LiteralStyle {
+			item.Style = yaml.DoubleQuotedStyle
+		}
+	}
+	return seq
+}
+
+// yamlString returns a string node: a literal block scalar when s spans
+// lines, double-quoted when yamlNeedsQuoting says so, and otherwise left
+// to the encoder, which quotes anything that would not read back as s.
+func yamlString(s string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+	switch {
+	case strings.Contains(s, "\n"):
+		node.Style = yaml.LiteralStyle
+	case yamlNeedsQuoting(s):
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	return node
+}
+
+// yamlNeedsQuoting reports whether a YAML string value should be quoted to
+// avoid ambiguity with YAML special values or characters, including the
+// YAML 1.1 booleans that older parsers still accept.
+func yamlNeedsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	// Values that YAML would interpret as special types.
+	lower := strings.ToLower(s)
+	switch lower {
+	case "true", "false", "yes", "no", "y", "n", "on", "off", "null", "~":
+		return true
+	}
+	// If it starts or ends with whitespace, or contains characters that
+	// could confuse a YAML parser.
+	if s[0] == ' ' || s[len(s)-1] == ' ' {
+		return true
+	}
+	for _, c := range s {
+		switch c {
+		case ':', '#', '[', ']', '{', '}', ',', '&', '*', '!', '|', '>', '\'', '"', '%', '@', '`':
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,545 @@
+// Package qr implements a small QR Code encoder (ISO/IEC 18004) for byte-mode
+// data, sufficient for encoding vCards and URLs. It selects the smallest
+// version that fits the data at the requested error correction level and the
+// mask pattern with the lowest penalty score.
+package qr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Level is an error correction level.
+type Level int
+
+// Error correction levels, recovering roughly 7%, 15%, 25%, and 30% of
+// codewords respectively.
+const (
+	Low Level = iota
+	Medium
+	Quartile
+	High
+)
+
+// formatBits returns the two-bit level indicator used in the format string.
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// ErrTooLong is returned when the data does not fit in a version 40 symbol.
+var ErrTooLong = errors.New("data too long for a QR code")
+
+// Code is an encoded QR symbol. Modules are addressed by (x, y) with the
+// origin at the top-left corner.
+type Code struct {
+	version  int
+	size     int
+	level    Level
+	modules  [][]bool
+	function [][]bool
+}
+
+// Version returns the symbol version (1–40).
+func (c *Code) Version() int { return c.version }
+
+// Size returns the width and height of the symbol in modules, excluding the
+// quiet zone.
+func (c *Code) Size() int { return c.size }
+
+// Dark reports whether the module at (x, y) is dark. Coordinates outside the
+// symbol are light.
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.size && y < c.size && c.modules[y][x]
+}
+
+// Encode encodes data in byte mode at the given error correction level.
+func Encode(data []byte, level Level) (*Code, error) {
+	if level < Low || level > High {
+		return nil, fmt.Errorf("invalid error correction level %d", level)
+	}
+
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if 4+charCountBits(v)+8*len(data) <= 8*dataCodewords(v, level) {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("%w (%d bytes)", ErrTooLong, len(data))
+	}
+
+	var bb bitBuffer
+	bb.append(0x4, 4) // byte mode
+	bb.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+	capacity := 8 * dataCodewords(version, level)
+	bb.append(0, min(4, capacity-len(bb)))
+	bb.append(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	codewords := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+
+	c := newCode(version, level)
+	c.drawFunctionPatterns()
+	c.drawCodewords(c.addECCAndInterleave(codewords))
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask) // XOR again to undo
+	}
+	c.applyMask(best)
+	c.drawFormatBits(best)
+	return c, nil
+}
+
+func newCode(version int, level Level) *Code {
+	size := version*4 + 17
+	c := &Code{version: version, size: size, level: level}
+	c.modules = make([][]bool, size)
+	c.function = make([][]bool, size)
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+		c.function[i] = make([]bool, size)
+	}
+	return c
+}
+
+// ---------------------------------------------------------------------------
+// Function patterns
+// ---------------------------------------------------------------------------
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.size-4, 3)
+	c.drawFinder(3, c.size-4)
+
+	pos := alignmentPositions(c.version)
+	n := len(pos)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue // overlaps a finder pattern
+			}
+			c.drawAlignment(pos[i], pos[j])
+		}
+	}
+
+	c.drawFormatBits(0) // reserve the area; rewritten once the mask is chosen
+	c.drawVersion()
+}
+
+// drawFinder draws a finder pattern and its separator centred on (x, y).
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= c.size || yy >= c.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignment draws a 5×5 alignment pattern centred on (x, y).
+func (c *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// formatInfo returns the 15-bit BCH-protected, masked format string.
+func formatInfo(level Level, mask int) int {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatInfo(c.level, mask)
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(bits, i))
+	}
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.size-15+i, bit(bits, i))
+	}
+	c.setFunction(8, c.size-8, true) // the dark module
+}
+
+// versionInfo returns the 18-bit BCH-protected version string.
+func versionInfo(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	return version<<12 | rem
+}
+
+func (c *Code) drawVersion() {
+	if c.version < 7 {
+		return
+	}
+	bits := versionInfo(c.version)
+	for i := 0; i < 18; i++ {
+		a, b := c.size-11+i%3, i/3
+		c.setFunction(a, b, bit(bits, i))
+		c.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Data placement and masking
+// ---------------------------------------------------------------------------
+
+// drawCodewords places data in the zigzag pattern, skipping function modules.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		for vert := 0; vert < c.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.size - 1 - vert // upward column
+				}
+				if !c.function[y][x] && i < len(data)*8 {
+					c.modules[y][x] = bit(int(data[i>>3]), 7-(i&7))
+					i++
+				}
+			}
+		}
+	}
+}
+
+// maskAt reports whether mask pattern m inverts the module at (x, y).
+func maskAt(m, x, y int) bool {
+	switch m {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// applyMask XORs the data modules with mask pattern m.
+func (c *Code) applyMask(m int) {
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if !c.function[y][x] && maskAt(m, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol using the four rules of ISO/IEC 18004 §7.8.3.
+// Lower is better.
+func (c *Code) penalty() int {
+	n := c.size
+	score := 0
+	at := func(x, y int, vertical bool) bool {
+		if vertical {
+			return c.modules[x][y]
+		}
+		return c.modules[y][x]
+	}
+
+	for _, vertical := range []bool{false, true} {
+		for y := 0; y < n; y++ {
+			// N1: runs of five or more same-coloured modules.
+			run := 1
+			for x := 1; x < n; x++ {
+				if at(x, y, vertical) == at(x-1, y, vertical) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += 3 + run - 5
+				}
+				run = 1
+			}
+			if run >= 5 {
+				score += 3 + run - 5
+			}
+
+			// N3: finder-like 1:1:3:1:1 patterns next to four light modules.
+			for x := 0; x+11 <= n; x++ {
+				if matchesFinder(func(i int) bool { return at(x+i, y, vertical) }) {
+					score += 40
+				}
+			}
+		}
+	}
+
+	// N2: 2×2 blocks of one colour.
+	for y := 0; y+1 < n; y++ {
+		for x := 0; x+1 < n; x++ {
+			v := c.modules[y][x]
+			if v == c.modules[y][x+1] && v == c.modules[y+1][x] && v == c.modules[y+1][x+1] {
+				score += 3
+			}
+		}
+	}
+
+	// N4: deviation of the dark proportion from 50%.
+	dark := 0
+	for _, row := range c.modules {
+		for _, m := range row {
+			if m {
+				dark++
+			}
+		}
+	}
+	percent := dark * 100 / (n * n)
+	score += 10 * (abs(percent-50) / 5)
+	return score
+}
+
+// matchesFinder reports whether the 11 modules returned by at form either
+// 1011101 0000 or 0000 1011101.
+func matchesFinder(at func(int) bool) bool {
+	const a = "10111010000"
+	const b = "00001011101"
+	matchA, matchB := true, true
+	for i := 0; i < 11; i++ {
+		v := at(i)
+		if v != (a[i] == '1') {
+			matchA = false
+		}
+		if v != (b[i] == '1') {
+			matchB = false
+		}
+	}
+	return matchA || matchB
+}
+
+// ---------------------------------------------------------------------------
+// Error correction
+// ---------------------------------------------------------------------------
+
+// addECCAndInterleave splits data into blocks, appends Reed-Solomon error
+// correction codewords to each, and interleaves the result.
+func (c *Code) addECCAndInterleave(data []byte) []byte {
+	numBlocks := numECCBlocks[c.level][c.version]
+	eccLen := eccCodewordsPerBlock[c.level][c.version]
+	raw := rawDataModules(c.version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	divisor := rsDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := range blocks {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		dat := append([]byte(nil), data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(dat, divisor)
+		if i < numShort {
+			dat = append(dat, 0) // placeholder so all blocks align
+		}
+		blocks[i] = append(dat, ecc...)
+	}
+
+	out := make([]byte, 0, raw)
+	for i := range blocks[0] {
+		for j, blk := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				out = append(out, blk[i])
+			}
+		}
+	}
+	return out
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of the given
+// degree, with roots α^0 … α^(degree-1), as coefficients from highest to
+// lowest power excluding the leading 1.
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder returns the error correction codewords for data.
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMul(d, factor)
+		}
+	}
+	return result
+}
+
+// gfMul multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMul(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+// ---------------------------------------------------------------------------
+// Version tables
+// ---------------------------------------------------------------------------
+
+// charCountBits is the width of the byte-mode character count field.
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// rawDataModules returns the number of modules available for data and error
+// correction in a symbol of the given version.
+func rawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// dataCodewords returns the number of 8-bit data codewords for a version and
+// level, after error correction is accounted for.
+func dataCodewords(version int, level Level) int {
+	return rawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numECCBlocks[level][version]
+}
+
+// alignmentPositions returns the centre coordinates of the alignment
+// patterns along each axis.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*4 + numAlign*2 + 1) / (numAlign*2 - 2) * 2
+	if version == 32 {
+		step = 26
+	}
+	size := version*4 + 17
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, size-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var numECCBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// ---------------------------------------------------------------------------
+// Helpers
+// ---------------------------------------------------------------------------
+
+// bitBuffer is an append-only sequence of bits.
+type bitBuffer []bool
+
+func (b *bitBuffer) append(val, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (val>>uint(i))&1 != 0)
+	}
+}
+
+func bit(x, i int) bool {
+	return (x>>uint(i))&1 != 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
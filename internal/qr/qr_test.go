@@ -0,0 +1,289 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestDataCodewords(t *testing.T) {
+	// Capacities from ISO/IEC 18004 Table 7.
+	tests := []struct {
+		version int
+		level   Level
+		want    int
+	}{
+		{1, Low, 19}, {1, Medium, 16}, {1, Quartile, 13}, {1, High, 9},
+		{5, Low, 108}, {5, Medium, 86}, {5, Quartile, 62}, {5, High, 46},
+		{10, Low, 274}, {10, Medium, 216}, {10, Quartile, 154}, {10, High, 122},
+		{20, Low, 861}, {20, Medium, 669}, {20, Quartile, 485}, {20, High, 385},
+		{40, Low, 2956}, {40, Medium, 2334}, {40, Quartile, 1666}, {40, High, 1276},
+	}
+	for _, tt := range tests {
+		if got := dataCodewords(tt.version, tt.level); got != tt.want {
+			t.Errorf("dataCodewords(%d, %d) = %d, want %d", tt.version, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestAlignmentPositions(t *testing.T) {
+	tests := map[int][]int{
+		1:  nil,
+		2:  {6, 18},
+		7:  {6, 22, 38},
+		32: {6, 34, 60, 86, 112, 138},
+		40: {6, 30, 58, 86, 114, 142, 170},
+	}
+	for v, want := range tests {
+		got := alignmentPositions(v)
+		if len(got) != len(want) {
+			t.Errorf("version %d: got %v, want %v", v, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("version %d: got %v, want %v", v, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestFormatInfo(t *testing.T) {
+	if got := formatInfo(Medium, 0); got != 0x5412 {
+		t.Errorf("formatInfo(M, 0) = %#x, want 0x5412", got)
+	}
+	if got := formatInfo(Low, 0); got != 0x77C4 {
+		t.Errorf("formatInfo(L, 0) = %#x, want 0x77c4", got)
+	}
+	if got := formatInfo(High, 7); got != 0x083B {
+		t.Errorf("formatInfo(H, 7) = %#x, want 0x083b", got)
+	}
+}
+
+func TestVersionInfo(t *testing.T) {
+	if got := versionInfo(7); got != 0x07C94 {
+		t.Errorf("versionInfo(7) = %#x, want 0x7c94", got)
+	}
+	if got := versionInfo(40); got != 0x28C69 {
+		t.Errorf("versionInfo(40) = %#x, want 0x28c69", got)
+	}
+}
+
+// gfPow returns α^n in GF(2^8).
+func gfPow(n int) byte {
+	r := byte(1)
+	for i := 0; i < n; i++ {
+		r = gfMul(r, 2)
+	}
+	return r
+}
+
+func TestReedSolomon_Syndromes(t *testing.T) {
+	data := []byte("deets reed-solomon test block")
+	for _, degree := range []int{7, 10, 18, 30} {
+		ecc := rsRemainder(data, rsDivisor(degree))
+		msg := append(append([]byte(nil), data...), ecc...)
+		for i := 0; i < degree; i++ {
+			// Evaluate the codeword polynomial at α^i with Horner's rule.
+			x, s := gfPow(i), byte(0)
+			for _, c := range msg {
+				s = gfMul(s, x) ^ c
+			}
+			if s != 0 {
+				t.Errorf("degree %d: syndrome %d = %#x, want 0", degree, i, s)
+			}
+		}
+	}
+}
+
+// readFormat decodes the level and mask from the first copy of the format
+// information around the top-left finder.
+func readFormat(c *Code) (Level, int) {
+	var bits int
+	for i := 0; i <= 5; i++ {
+		if c.modules[i][8] {
+			bits |= 1 << i
+		}
+	}
+	pos := [][2]int{{8, 7}, {8, 8}, {7, 8}}
+	for i, p := range pos {
+		if c.modules[p[1]][p[0]] {
+			bits |= 1 << (6 + i)
+		}
+	}
+	for i := 9; i < 15; i++ {
+		if c.modules[8][14-i] {
+			bits |= 1 << i
+		}
+	}
+	bits ^= 0x5412
+	levels := map[int]Level{1: Low, 0: Medium, 3: Quartile, 2: High}
+	return levels[bits>>13], (bits >> 10) & 7
+}
+
+// readCodewords extracts the codeword stream by walking the symbol in
+// two-module columns from the bottom-right corner, unmasking as it goes.
+func readCodewords(c *Code, mask int) []byte {
+	var bits []bool
+	upward := true
+	for x := c.size - 1; x > 0; x -= 2 {
+		if x == 6 {
+			x--
+		}
+		for k := 0; k < c.size; k++ {
+			y := k
+			if upward {
+				y = c.size - 1 - k
+			}
+			for _, xx := range []int{x, x - 1} {
+				if c.function[y][xx] {
+					continue
+				}
+				bits = append(bits, c.modules[y][xx] != maskAt(mask, xx, y))
+			}
+		}
+		upward = !upward
+	}
+	out := make([]byte, len(bits)/8)
+	for i := range out {
+		for j := 0; j < 8; j++ {
+			if bits[i*8+j] {
+				out[i] |= 1 << (7 - j)
+			}
+		}
+	}
+	return out
+}
+
+func TestEncode_RoundTripsCodewords(t *testing.T) {
+	for _, input := range []string{"https://example.com", strings.Repeat("BEGIN:VCARD ", 30)} {
+		c, err := Encode([]byte(input), Medium)
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		level, mask := readFormat(c)
+		if level != Medium {
+			t.Errorf("format info level = %d, want Medium", level)
+		}
+
+		got := readCodewords(c, mask)
+		ref := newCode(c.version, Medium)
+		ref.drawFunctionPatterns()
+		want := ref.addECCAndInterleave(encodeData(t, []byte(input), c.version, Medium))
+		if !bytes.Equal(got[:len(want)], want) {
+			t.Errorf("%d-byte input: codewords read back from the symbol differ from those encoded", len(input))
+		}
+	}
+}
+
+// encodeData rebuilds the padded data codewords for input, independently of
+// Encode's bit buffer.
+func encodeData(t *testing.T, input []byte, version int, level Level) []byte {
+	t.Helper()
+	var bb bitBuffer
+	bb.append(4, 4)
+	bb.append(len(input), charCountBits(version))
+	for _, b := range input {
+		bb.append(int(b), 8)
+	}
+	capacity := 8 * dataCodewords(version, level)
+	for i := 0; i < 4 && len(bb) < capacity; i++ {
+		bb = append(bb, false)
+	}
+	for len(bb)%8 != 0 {
+		bb = append(bb, false)
+	}
+	out := make([]byte, 0, capacity/8)
+	for i := 0; i < len(bb); i += 8 {
+		var v byte
+		for j := 0; j < 8; j++ {
+			if bb[i+j] {
+				v |= 1 << (7 - j)
+			}
+		}
+		out = append(out, v)
+	}
+	for pad := []byte{0xEC, 0x11}; len(out) < capacity/8; pad[0], pad[1] = pad[1], pad[0] {
+		out = append(out, pad[0])
+	}
+	return out
+}
+
+func TestEncode_VersionSelection(t *testing.T) {
+	c, err := Encode([]byte("hello"), Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Version() != 1 || c.Size() != 21 {
+		t.Errorf("expected version 1 (21×21), got %d (%d)", c.Version(), c.Size())
+	}
+
+	// 14 bytes is the most version 1-M holds in byte mode.
+	if c, _ := Encode(bytes.Repeat([]byte("x"), 14), Medium); c.Version() != 1 {
+		t.Errorf("14 bytes should fit version 1, got %d", c.Version())
+	}
+	if c, _ := Encode(bytes.Repeat([]byte("x"), 15), Medium); c.Version() != 2 {
+		t.Errorf("15 bytes should need version 2, got %d", c.Version())
+	}
+}
+
+func TestEncode_FinderPatterns(t *testing.T) {
+	c, err := Encode([]byte("finder"), Low)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, origin := range [][2]int{{0, 0}, {c.size - 7, 0}, {0, c.size - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				ring := max(abs(dx-3), abs(dy-3))
+				want := ring != 2
+				if got := c.Dark(origin[0]+dx, origin[1]+dy); got != want {
+					t.Fatalf("finder at %v: module (%d,%d) = %v, want %v", origin, dx, dy, got, want)
+				}
+			}
+		}
+	}
+	if !c.Dark(8, c.size-8) {
+		t.Error("dark module should be set")
+	}
+}
+
+func TestEncode_TooLong(t *testing.T) {
+	_, err := Encode(make([]byte, 2400), Medium)
+	if !errors.Is(err, ErrTooLong) {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	c, _ := Encode([]byte("hi"), Medium)
+	plain := c.Blocks(false)
+	lines := strings.Split(strings.TrimSuffix(plain, "\n"), "\n")
+	if want := (c.size + 2*QuietZone + 1) / 2; len(lines) != want {
+		t.Errorf("expected %d lines, got %d", want, len(lines))
+	}
+	if strings.Contains(plain, "\x1b[") {
+		t.Error("plain output should not contain escape sequences")
+	}
+	if !strings.Contains(c.Blocks(true), "\x1b[30;40m▀") {
+		t.Error("ANSI output should use explicit colours")
+	}
+}
+
+func TestPNG(t *testing.T) {
+	c, _ := Encode([]byte("hi"), Medium)
+	data, err := c.PNG(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("invalid PNG: %v", err)
+	}
+	if dim := (c.size + 2*QuietZone) * 4; img.Bounds().Dx() != dim {
+		t.Errorf("expected %dpx image, got %d", dim, img.Bounds().Dx())
+	}
+}
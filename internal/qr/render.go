@@ -0,0 +1,88 @@
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// QuietZone is the width, in modules, of the light border required around
+// a symbol.
+const QuietZone = 4
+
+// Blocks renders the symbol as text using Unicode half-block characters, two
+// module rows per line, including the quiet zone. With ansi set, every cell
+// is drawn with explicit black and white colours so the code scans on both
+// light and dark terminals; otherwise dark modules are drawn as blocks on the
+// default background, which suits light backgrounds and printing.
+func (c *Code) Blocks(ansi bool) string {
+	var b strings.Builder
+	lo, hi := -QuietZone, c.size+QuietZone
+	for y := lo; y < hi; y += 2 {
+		for x := lo; x < hi; x++ {
+			top, bottom := c.Dark(x, y), c.Dark(x, y+1)
+			if y+1 >= hi {
+				bottom = false
+			}
+			if ansi {
+				b.WriteString(ansiColor(top, bottom))
+				b.WriteString("▀")
+				continue
+			}
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		if ansi {
+			b.WriteString("\x1b[0m")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ansiColor returns the escape sequence that sets the foreground to the top
+// module's colour and the background to the bottom module's.
+func ansiColor(top, bottom bool) string {
+	fg, bg := "97", "107"
+	if top {
+		fg = "30"
+	}
+	if bottom {
+		bg = "40"
+	}
+	return "\x1b[" + fg + ";" + bg + "m"
+}
+
+// PNG encodes the symbol, including the quiet zone, as a grayscale PNG with
+// each module drawn as a scale×scale square.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	if scale < 1 {
+		scale = 1
+	}
+	dim := (c.size + 2*QuietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, dim, dim))
+	for py := 0; py < dim; py++ {
+		for px := 0; px < dim; px++ {
+			v := color.Gray{Y: 0xFF}
+			if c.Dark(px/scale-QuietZone, py/scale-QuietZone) {
+				v = color.Gray{Y: 0x00}
+			}
+			img.SetGray(px, py, v)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxBackups is the number of backups kept; older ones are deleted as new
+// ones are made.
+const MaxBackups = 50
+
+// backupTimeFormat names backup files so they sort oldest first.
+const backupTimeFormat = "20060102T150405.000000000Z"
+
+var (
+	// backupDir is where writes keep copies of files; "" disables backups.
+	backupDir string
+	// backedUp holds the files already backed up since EnableBackups, so a
+	// command that writes a file several times leaves one backup of it.
+	backedUp map[string]bool
+)
+
+// EnableBackups makes every write through this package first copy the file
+// into dir, once per file until EnableBackups is called again. An empty
+// dir disables backups.
+func EnableBackups(dir string) {
+	backupDir = dir
+	backedUp = make(map[string]bool)
+}
+
+// PauseBackups disables backups until the returned function is called, for
+// writes to scratch files.
+func PauseBackups() (resume func()) {
+	dir := backupDir
+	backupDir = ""
+	return func() { backupDir = dir }
+}
+
+// A Backup is a copy of a file taken before a write changed it.
+type Backup struct {
+	Path string    // the file that was backed up
+	File string    // the backup copy
+	Time time.Time // when the copy was taken
+}
+
+// backup copies path into the backup directory unless it has already been
+// backed up or does not exist yet.
+func backup(path string) error {
+	if backupDir == "" {
+		return nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if backedUp[abs] {
+		return nil
+	}
+	data, err := os.ReadFile(abs)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return err
+	}
+	// The file name holds the time and the escaped original path.
+	name := time.Now().UTC().Format(backupTimeFormat) + "_" + url.QueryEscape(abs)
+	if err := os.WriteFile(filepath.Join(backupDir, name), data, 0600); err != nil {
+		return err
+	}
+	backedUp[abs] = true
+	return pruneBackups(backupDir)
+}
+
+// pruneBackups deletes all but the newest MaxBackups backups in dir.
+func pruneBackups(dir string) error {
+	backups, err := Backups(dir)
+	if err != nil {
+		return err
+	}
+	for _, b := range backups[min(len(backups), MaxBackups):] {
+		if err := os.Remove(b.File); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backups lists the backups in dir, newest first. A missing dir has none.
+func Backups(dir string) ([]Backup, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var backups []Backup
+	for _, e := range entries {
+		stamp, escaped, ok := strings.Cut(e.Name(), "_")
+		if !ok || e.IsDir() {
+			continue
+		}
+		t, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		path, err := url.QueryUnescape(escaped)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Path: path, File: filepath.Join(dir, e.Name()), Time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Time.After(backups[j].Time) })
+	return backups, nil
+}
+
+// Undo restores the newest backup in dir over the file it was taken from
+// and deletes it, so calling Undo again goes one step further back. The
+// restore itself is not backed up.
+func Undo(dir string) (Backup, error) {
+	backups, err := Backups(dir)
+	if err != nil {
+		return Backup{}, err
+	}
+	if len(backups) == 0 {
+		return Backup{}, fmt.Errorf("no backups in %s", dir)
+	}
+	b := backups[0]
+	data, err := os.ReadFile(b.File)
+	if err != nil {
+		return Backup{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(b.Path), 0755); err != nil {
+		return Backup{}, err
+	}
+	unlock, err := lockFile(b.Path)
+	if err != nil {
+		return Backup{}, err
+	}
+	defer unlock()
+	if err := writeAtomic(b.Path, data); err != nil {
+		return Backup{}, fmt.Errorf("restoring %s: %w", b.Path, err)
+	}
+	return b, os.Remove(b.File)
+}
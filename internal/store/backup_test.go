@@ -0,0 +1,80 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupAndUndo(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backups")
+	EnableBackups(dir)
+	defer EnableBackups("")
+
+	path := writeTemp(t, "[contact]\nemail = \"a@b.c\"\n")
+	// Two writes in one session leave a single backup of the original.
+	if err := SetValue(path, "contact", "email", "x@y.z"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetValue(path, "contact", "phone", "555"); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := Backups(dir)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("expected one backup, got %v (err %v)", backups, err)
+	}
+	if backups[0].Path != path {
+		t.Errorf("backup path = %q, want %q", backups[0].Path, path)
+	}
+
+	// A new session backs up again.
+	EnableBackups(dir)
+	if err := RemoveValue(path, "contact", "phone"); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := Backups(dir); len(backups) != 2 {
+		t.Fatalf("expected two backups, got %d", len(backups))
+	}
+
+	if _, err := Undo(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTemp(t, path); got != "[contact]\nemail = \"x@y.z\"\nphone = \"555\"\n" {
+		t.Errorf("after first undo:\n%s", got)
+	}
+	if _, err := Undo(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTemp(t, path); got != "[contact]\nemail = \"a@b.c\"\n" {
+		t.Errorf("after second undo:\n%s", got)
+	}
+	if _, err := Undo(dir); err == nil {
+		t.Error("expected error with no backups left")
+	}
+}
+
+func TestBackup_PauseAndPrune(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backups")
+	EnableBackups(dir)
+	defer EnableBackups("")
+
+	path := writeTemp(t, "[a]\nk = 1\n")
+	resume := PauseBackups()
+	if err := SetValue(path, "a", "k", "2"); err != nil {
+		t.Fatal(err)
+	}
+	resume()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("paused writes should not be backed up")
+	}
+
+	for i := 0; i < MaxBackups+3; i++ {
+		EnableBackups(dir)
+		if err := SetValue(path, "a", "k", "3"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if backups, _ := Backups(dir); len(backups) != MaxBackups {
+		t.Errorf("expected %d backups kept, got %d", MaxBackups, len(backups))
+	}
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/queelius/deets/internal/model"
+)
+
+// ParseAssignments parses batch input of one "category.key = value" per
+// line. Blank lines and lines starting with "#" are skipped. A value that
+// is already a valid TOML literal (42, true, 1990-05-01, "quoted",
+// ["a", "b"]) is kept as written; anything else is stored as a string.
+func ParseAssignments(text string) ([]Assignment, error) {
+	var out []Assignment
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		a, err := ParseAssignment(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// ParseAssignment parses one "category.key = value" assignment, with the
+// value treated as in ParseAssignments.
+func ParseAssignment(s string) (Assignment, error) {
+	path, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return Assignment{}, fmt.Errorf("expected category.key = value")
+	}
+	cat, key, ok := strings.Cut(strings.TrimSpace(path), ".")
+	if !ok || cat == "" || key == "" {
+		return Assignment{}, fmt.Errorf("invalid path %q: expected category.key", strings.TrimSpace(path))
+	}
+	return Assignment{cat, key, valueLiteral(strings.TrimSpace(value))}, nil
+}
+
+// valueLiteral returns value unchanged when it parses as a TOML value and
+// as a quoted string otherwise.
+func valueLiteral(value string) string {
+	if isTOMLValue(value) {
+		return value
+	}
+	return model.TOMLString(value)
+}
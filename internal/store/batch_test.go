@@ -0,0 +1,77 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestParseAssignments(t *testing.T) {
+	got, err := ParseAssignments(`
+# provisioning
+identity.name = Alex Towell
+academic.year = 2021
+identity.birthday = 1990-05-01
+identity.aka = ["A", "B"]
+web.public=true
+identity.quoted = "kept"
+education.phd.school = MIT
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Assignment{
+		{"identity", "name", `"Alex Towell"`},
+		{"academic", "year", "2021"},
+		{"identity", "birthday", "1990-05-01"},
+		{"identity", "aka", `["A", "B"]`},
+		{"web", "public", "true"},
+		{"identity", "quoted", `"kept"`},
+		{"education", "phd.school", `"MIT"`},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d assignments, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("assignment %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseAssignments_Errors(t *testing.T) {
+	for _, in := range []string{"identity.name", "noperiod = x", ".key = x"} {
+		if _, err := ParseAssignments(in); err == nil {
+			t.Errorf("expected error for %q", in)
+		}
+	}
+}
+
+func TestSetLiterals(t *testing.T) {
+	path := writeTemp(t, "# mine\n[identity]\nname = \"Old\"\n")
+	err := SetLiterals(path, []Assignment{
+		{"identity", "name", `"New"`},
+		{"web", "github", `"queelius"`},
+		{"identity", "email", `"a@b.c"`},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# mine\n[identity]\nname = \"New\"\nemail = \"a@b.c\"\n\n[web]\ngithub = \"queelius\"\n"
+	if got := readTemp(t, path); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseAssignment(t *testing.T) {
+	a, err := ParseAssignment("identity.name=Alice Smith")
+	if err != nil {
+		t.Fatalf("ParseAssignment returned error: %v", err)
+	}
+	if a != (Assignment{"identity", "name", `"Alice Smith"`}) {
+		t.Errorf("ParseAssignment = %+v", a)
+	}
+	for _, s := range []string{"identity.name", "name=Alice", ".name=x"} {
+		if _, err := ParseAssignment(s); err == nil {
+			t.Errorf("ParseAssignment(%q) succeeded, want an error", s)
+		}
+	}
+}
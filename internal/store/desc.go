@@ -0,0 +1,46 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// OrphanDescs returns the "_desc" keys in the file at path whose described
+// key does not exist in the same table, as sorted "category.key_desc"
+// paths. LoadFile ignores such keys, so nothing ever reads them.
+func OrphanDescs(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var raw map[string]interface{}
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	var orphans []string
+	var walk func(table map[string]interface{}, prefix string)
+	walk = func(table map[string]interface{}, prefix string) {
+		for k, v := range table {
+			if sub, ok := v.(map[string]interface{}); ok {
+				walk(sub, prefix+k+".")
+				continue
+			}
+			base, ok := strings.CutSuffix(k, "_desc")
+			if _, exists := table[base]; ok && !exists {
+				orphans = append(orphans, prefix+k)
+			}
+		}
+	}
+	for name, v := range raw {
+		if cat, ok := v.(map[string]interface{}); ok {
+			walk(cat, name+".")
+		}
+	}
+	sort.Strings(orphans)
+	return orphans, nil
+}
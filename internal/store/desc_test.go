@@ -0,0 +1,30 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrphanDescs(t *testing.T) {
+	path := writeTemp(t, `[contact]
+email = "a@b.c"
+email_desc = "Primary email"
+phone_desc = "Mobile"
+
+[education.phd]
+field = "Statistics"
+field_desc = "Area of study"
+year_desc = "Year awarded"
+
+[web]
+github_desc = "GitHub username"
+`)
+	got, err := OrphanDescs(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"contact.phone_desc", "education.phd.year_desc", "web.github_desc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OrphanDescs = %v, want %v", got, want)
+	}
+}
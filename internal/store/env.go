@@ -0,0 +1,117 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/queelius/deets/internal/model"
+)
+
+// EnvLayer returns a Layer that overrides fields from environment
+// variables named as 'deets export --format env' names them, such as
+// DEETS_IDENTITY_NAME for identity.name or DEETS_EDUCATION_PHD_SCHOOL for
+// the school inside the education.phd table. lookup reports the value of a
+// variable, as os.LookupEnv does.
+//
+// Since those names cannot be mapped back to paths unambiguously, only
+// fields that were loaded can be overridden. A value keeps the type of the
+// field it replaces where it can: strings stay strings, arrays are split
+// on commas, and other values are read as TOML literals. Arrays of tables
+// are left alone. Overridden fields have layer "env" and the variable name
+// as their source.
+func EnvLayer(lookup func(name string) (string, bool)) Layer {
+	return func(db *model.DB) (*model.DB, error) {
+		overlay := &model.DB{}
+		for _, cat := range db.Categories {
+			var fields []model.Field
+			for _, f := range cat.Fields {
+				if model.IsDescKey(f.Key) {
+					continue
+				}
+				changed := false
+				for _, leaf := range model.Leaves(f) {
+					name := model.EnvName(cat.Name, leaf.Key)
+					s, ok := lookup(name)
+					if !ok {
+						continue
+					}
+					v, ok := envValue(leaf.Value, s)
+					if !ok {
+						continue
+					}
+					var sub []string
+					if leaf.Key != f.Key {
+						sub = strings.Split(strings.TrimPrefix(leaf.Key, f.Key+"."), ".")
+					}
+					f.Value = withLeaf(f.Value, sub, v)
+					f.Source = "$" + name
+					changed = true
+				}
+				if changed {
+					f.Layer = "env"
+					f.Line = 0
+					f.Computed = false
+					fields = append(fields, f)
+				}
+			}
+			if len(fields) > 0 {
+				overlay.Categories = append(overlay.Categories, model.Category{Name: cat.Name, Fields: fields})
+			}
+		}
+		return overlay, nil
+	}
+}
+
+// envValue converts the environment value s to replace current, reporting
+// false if current cannot be overridden from a single string.
+func envValue(current interface{}, s string) (interface{}, bool) {
+	if _, ok := model.Records(current); ok {
+		return nil, false
+	}
+	var literal string
+	switch val := current.(type) {
+	case string:
+		return s, true
+	case []interface{}, []string:
+		strs := true
+		if items, ok := val.([]interface{}); ok {
+			for _, item := range items {
+				if _, ok := item.(string); !ok {
+					strs = false
+				}
+			}
+		}
+		var parts []string
+		for _, part := range strings.Split(s, ",") {
+			part = strings.TrimSpace(part)
+			if strs {
+				parts = append(parts, model.TOMLString(part))
+			} else {
+				parts = append(parts, valueLiteral(part))
+			}
+		}
+		literal = "[" + strings.Join(parts, ", ") + "]"
+	default:
+		literal = valueLiteral(s)
+	}
+	var probe map[string]interface{}
+	if err := toml.Unmarshal([]byte("v = "+literal), &probe); err != nil {
+		return s, true
+	}
+	return probe["v"], true
+}
+
+// withLeaf returns v with the value at the sub-path path replaced by leaf,
+// copying the tables along the way so v itself is not modified.
+func withLeaf(v interface{}, path []string, leaf interface{}) interface{} {
+	if len(path) == 0 {
+		return leaf
+	}
+	m, _ := v.(map[string]interface{})
+	out := make(map[string]interface{}, len(m))
+	for k, val := range m {
+		out[k] = val
+	}
+	out[path[0]] = withLeaf(m[path[0]], path[1:], leaf)
+	return out
+}
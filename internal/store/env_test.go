@@ -0,0 +1,73 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEnvLayer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "me.toml")
+	content := `[identity]
+name = "Alice"
+name_desc = "Full name"
+aka = ["Al", "Ali"]
+signature = "{{identity.name}}"
+
+[academic]
+year = 2020
+
+[education.phd]
+school = "MIT"
+field = "CS"
+
+[[projects.list]]
+name = "deets"
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"DEETS_IDENTITY_NAME":        "Bob",
+		"DEETS_IDENTITY_AKA":         "B, Bobby",
+		"DEETS_ACADEMIC_YEAR":        "2021",
+		"DEETS_EDUCATION_PHD_SCHOOL": "Stanford",
+		"DEETS_PROJECTS_LIST":        "ignored",
+		"DEETS_IDENTITY_MISSING":     "not loaded",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	db, err := Load(path, "", EnvLayer(lookup))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	name, _ := db.GetField("identity.name")
+	if name.Value != "Bob" || name.Layer != "env" || name.Source != "$DEETS_IDENTITY_NAME" || name.Desc != "Full name" {
+		t.Errorf("identity.name = %+v, want Bob from env with its description", name)
+	}
+	if sig, _ := db.GetField("identity.signature"); sig.Value != "Bob" {
+		t.Errorf("signature = %v, want it to see the env override", sig.Value)
+	}
+	if aka, _ := db.GetField("identity.aka"); !reflect.DeepEqual(aka.Value, []interface{}{"B", "Bobby"}) {
+		t.Errorf("identity.aka = %#v, want a split array", aka.Value)
+	}
+	if year, _ := db.GetField("academic.year"); year.Value != int64(2021) {
+		t.Errorf("academic.year = %#v, want int64 2021", year.Value)
+	}
+	phd, _ := db.GetField("education.phd")
+	want := map[string]interface{}{"school": "Stanford", "field": "CS"}
+	if !reflect.DeepEqual(phd.Value, want) {
+		t.Errorf("education.phd = %#v, want %#v", phd.Value, want)
+	}
+	if list, _ := db.GetField("projects.list"); list.Layer != "global" {
+		t.Errorf("projects.list = %+v, want arrays of tables left alone", list)
+	}
+	if _, ok := db.GetField("identity.missing"); ok {
+		t.Error("env created a field that was not loaded")
+	}
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/queelius/deets/internal/model"
+)
+
+// Inline tables such as address = { city = "X" } cannot be extended with
+// dotted keys elsewhere in the file, so a key inside one is set or removed
+// by rewriting the whole inline table on its own line.
+
+// inlineTable finds the key line in lines[start:end] whose key is a proper
+// prefix of the dotted key and whose value is an inline table. It returns
+// the line index and the rest of the key inside the table, or -1. inValue
+// is continuation(lines).
+func inlineTable(lines []string, inValue []bool, start, end int, key string) (int, string) {
+	parts := strings.Split(key, ".")
+	for i := 1; i < len(parts); i++ {
+		idx := findKey(lines, inValue, start, end, strings.Join(parts[:i], "."))
+		if idx == -1 {
+			continue
+		}
+		if _, value := splitKeyLine(strings.TrimSpace(lines[idx])); strings.HasPrefix(value, "{") {
+			return idx, strings.Join(parts[i:], ".")
+		}
+	}
+	return -1, ""
+}
+
+// setInline sets (or, with remove, deletes) the dotted path inside the
+// inline table on line idx, keeping the order of the existing keys. New
+// keys go last, and intermediate tables are created as needed.
+func setInline(lines []string, idx int, path, literal string, remove bool) ([]string, error) {
+	key, value := splitKeyLine(strings.TrimSpace(lines[idx]))
+	var doc map[string]interface{}
+	md, err := toml.Decode("v = "+value, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", key, err)
+	}
+	table := doc["v"].(map[string]interface{})
+
+	// order maps each table's dotted path inside the value to its keys in
+	// file order.
+	order := map[string][]string{}
+	for _, k := range md.Keys() {
+		if len(k) > 1 {
+			parent := strings.Join(k[1:len(k)-1], ".")
+			order[parent] = append(order[parent], k[len(k)-1])
+		}
+	}
+
+	segs := strings.Split(path, ".")
+	m := table
+	for i, seg := range segs[:len(segs)-1] {
+		next, ok := m[seg].(map[string]interface{})
+		if !ok {
+			if remove || m[seg] != nil {
+				return nil, fmt.Errorf("%s.%s is not a table", key, strings.Join(segs[:i+1], "."))
+			}
+			next = map[string]interface{}{}
+			m[seg] = next
+			parent := strings.Join(segs[:i], ".")
+			order[parent] = append(order[parent], seg)
+		}
+		m = next
+	}
+
+	last := segs[len(segs)-1]
+	if remove {
+		if _, ok := m[last]; !ok {
+			return nil, fmt.Errorf("key %q not found in %s", path, key)
+		}
+		delete(m, last)
+	} else {
+		var val map[string]interface{}
+		if _, err := toml.Decode("v = "+literal, &val); err != nil {
+			return nil, err
+		}
+		if _, ok := m[last]; !ok {
+			parent := strings.Join(segs[:len(segs)-1], ".")
+			order[parent] = append(order[parent], last)
+		}
+		m[last] = val["v"]
+	}
+
+	lines[idx] = key + " = " + renderInline(table, "", order)
+	return lines, nil
+}
+
+// renderInline writes m as a one-line inline table, with the keys of each
+// table in the order recorded for its path.
+func renderInline(m map[string]interface{}, path string, order map[string][]string) string {
+	keys, known := order[path]
+	if !known {
+		// A table set as a whole value has no recorded order.
+		return model.FormatValueTOML(m)
+	}
+	var parts []string
+	for _, k := range keys {
+		v, ok := m[k]
+		if !ok {
+			continue
+		}
+		literal := model.FormatValueTOML(v)
+		if sub, ok := v.(map[string]interface{}); ok {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			literal = renderInline(sub, child, order)
+		}
+		name := k
+		if !isBareKey(k) {
+			name = model.TOMLString(k)
+		}
+		parts = append(parts, name+" = "+literal)
+	}
+	if len(parts) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
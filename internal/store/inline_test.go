@@ -0,0 +1,80 @@
+package store
+
+import (
+	"testing"
+)
+
+const addressTOML = `[contact]
+email = "a@b.c"
+address = { city = "X", country = "Y", geo = { lat = 1.5, lon = 2 } }
+address_desc = "Mailing address"
+`
+
+func TestLoadFile_InlineTable(t *testing.T) {
+	db, err := LoadFile(writeTemp(t, addressTOML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, ok := db.GetField("contact.address.city")
+	if !ok || f.Value != "X" {
+		t.Errorf("contact.address.city = %+v, %v", f, ok)
+	}
+	if f, ok := db.GetField("contact.address.geo.lat"); !ok || f.Value != 1.5 {
+		t.Errorf("contact.address.geo.lat = %+v, %v", f, ok)
+	}
+	if f, _ := db.GetField("contact.address"); f.Desc != "Mailing address" {
+		t.Errorf("expected the table's description, got %q", f.Desc)
+	}
+}
+
+func TestSetValue_InlineTable(t *testing.T) {
+	path := writeTemp(t, addressTOML)
+	if err := SetValue(path, "contact", "address.city", `Zürich "Old"`); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLiteral(path, "contact", "address.zip", "8001"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLiteral(path, "contact", "address.geo.alt", "400"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetValue(path, "contact", "address.po.box", "12"); err != nil {
+		t.Fatal(err)
+	}
+	want := `[contact]
+email = "a@b.c"
+address = { city = "Zürich \"Old\"", country = "Y", geo = { lat = 1.5, lon = 2, alt = 400 }, zip = 8001, po = { box = "12" } }
+address_desc = "Mailing address"
+`
+	if got := readTemp(t, path); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if _, err := LoadFile(path); err != nil {
+		t.Errorf("wrote invalid TOML: %v", err)
+	}
+
+	if err := SetValue(path, "contact", "address.city.name", "x"); err == nil {
+		t.Error("expected error setting a key inside a string")
+	}
+}
+
+func TestRemoveValue_InlineTable(t *testing.T) {
+	path := writeTemp(t, addressTOML)
+	if err := RemoveValue(path, "contact", "address.country"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveValue(path, "contact", "address.geo.lon"); err != nil {
+		t.Fatal(err)
+	}
+	want := `[contact]
+email = "a@b.c"
+address = { city = "X", geo = { lat = 1.5 } }
+address_desc = "Mailing address"
+`
+	if got := readTemp(t, path); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if err := RemoveValue(path, "contact", "address.missing"); err == nil {
+		t.Error("expected error for a missing key")
+	}
+}
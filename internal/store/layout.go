@@ -0,0 +1,355 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+)
+
+// A document is a me.toml split into the pieces that Format rearranges.
+// Comment lines travel with the key or header below them, so reordering
+// keeps each comment next to what it describes.
+type document struct {
+	// sections[0] holds the keys before the first header; its header is "".
+	sections []section
+}
+
+type section struct {
+	comments []string // comment lines directly above the header
+	header   string
+	entries  []entry
+	trailing []string // comment and blank lines after the last key
+}
+
+type entry struct {
+	lead  []string // comment and blank lines since the previous key
+	key   string
+	value []string // the text after "=", then any continuation lines verbatim
+}
+
+// parseDocument splits lines into sections and entries. Every line other
+// than headers, keys, and their values must be blank or a comment, as in
+// any valid TOML file.
+func parseDocument(lines []string) document {
+	doc := document{sections: []section{{}}}
+	var pending []string
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		cur := &doc.sections[len(doc.sections)-1]
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			pending = append(pending, trimmed)
+		case strings.HasPrefix(trimmed, "["):
+			// Only the comments with no blank line before the header are its own.
+			n := len(pending)
+			for n > 0 && pending[n-1] != "" {
+				n--
+			}
+			cur.trailing = append(cur.trailing, pending[:n]...)
+			doc.sections = append(doc.sections, section{comments: pending[n:], header: normalizeHeader(trimmed)})
+			pending = nil
+		default:
+			key, value := splitKeyLine(trimmed)
+			end := valueEnd(lines, i)
+			cur.entries = append(cur.entries, entry{
+				lead:  pending,
+				key:   key,
+				value: append([]string{value}, lines[i+1:end]...),
+			})
+			pending = nil
+			i = end - 1
+		}
+	}
+	last := &doc.sections[len(doc.sections)-1]
+	last.trailing = append(last.trailing, pending...)
+	return doc
+}
+
+// normalizeHeader removes stray spaces inside a [table] or [[array]]
+// header. Headers with a trailing comment are left as they are.
+func normalizeHeader(line string) string {
+	open, close := "[", "]"
+	if strings.HasPrefix(line, "[[") {
+		open, close = "[[", "]]"
+	}
+	if !strings.HasSuffix(line, close) {
+		return line
+	}
+	name := strings.TrimSpace(line[len(open) : len(line)-len(close)])
+	return open + name + close
+}
+
+// splitKeyLine splits "key = value" at the first "=" outside a quoted key.
+func splitKeyLine(line string) (key, value string) {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '=':
+			return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+	}
+	return line, ""
+}
+
+// keyOrder is the sort key for a key name: alphabetical, with each _desc
+// key directly after the key it describes.
+func keyOrder(key string) string {
+	if base, ok := strings.CutSuffix(key, "_desc"); ok {
+		return base + "\x01"
+	}
+	return key + "\x00"
+}
+
+// sortKeys orders the keys of every section by order(section, key),
+// keeping keys with equal order in file order.
+func (d document) sortKeys(order func(section, key string) string) {
+	for _, s := range d.sections {
+		name := sectionName(s.header)
+		sort.SliceStable(s.entries, func(i, j int) bool {
+			return order(name, s.entries[i].key) < order(name, s.entries[j].key)
+		})
+	}
+}
+
+// sortSections orders sections by order(category), where category is the
+// part of the section name before the first dot. Sub-tables and arrays of
+// tables follow their category's own section in file order, and keys
+// before the first header stay first.
+func (d document) sortSections(order func(category string) string) {
+	rank := func(s section) string {
+		category, _, sub := strings.Cut(sectionName(s.header), ".")
+		if sub {
+			return order(category) + "\x01"
+		}
+		return order(category) + "\x00"
+	}
+	rest := d.sections[1:]
+	sort.SliceStable(rest, func(i, j int) bool {
+		return rank(rest[i]) < rank(rest[j])
+	})
+}
+
+// sectionName returns the table name of a [table] or [[array]] header.
+func sectionName(header string) string {
+	name, _, _ := strings.Cut(header, "#")
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(name), "[]"))
+}
+
+// render writes the document in canonical style: one blank line between
+// sections, at most one blank line anywhere, no indentation, and "="
+// aligned within each run of keys not separated by blank lines or
+// comments. With sorted, blank lines between keys are dropped, since the
+// groups they marked no longer hold.
+func (d document) render(sorted bool) string {
+	var out []string
+	for _, s := range d.sections {
+		if s.header != "" {
+			if len(out) > 0 {
+				out = append(out, "")
+			}
+			out = append(out, collapseBlanks(s.comments)...)
+			out = append(out, s.header)
+		}
+
+		entries := s.entries
+
+		// A comment, or in file order a blank line, ends an alignment run.
+		breaksRun := func(e entry) bool {
+			if sorted {
+				return len(collapseBlanks(e.lead)) > 0
+			}
+			return len(e.lead) > 0
+		}
+		widths := make([]int, len(entries))
+		for start := 0; start < len(entries); {
+			end, width := start+1, utf8.RuneCountInString(entries[start].key)
+			for ; end < len(entries) && !breaksRun(entries[end]); end++ {
+				width = max(width, utf8.RuneCountInString(entries[end].key))
+			}
+			for i := start; i < end; i++ {
+				widths[i] = width
+			}
+			start = end
+		}
+
+		for i, e := range entries {
+			if i > 0 && !sorted && len(e.lead) > 0 && e.lead[0] == "" {
+				out = append(out, "")
+			}
+			out = append(out, collapseBlanks(e.lead)...)
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(e.key))
+			out = append(out, e.key+pad+" = "+e.value[0])
+			out = append(out, e.value[1:]...)
+		}
+
+		if trailing := collapseBlanks(s.trailing); len(trailing) > 0 {
+			if len(out) > 0 && s.trailing[0] == "" {
+				out = append(out, "")
+			}
+			out = append(out, trailing...)
+		}
+	}
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// collapseBlanks drops blank lines at either end of lines and merges runs
+// of blank lines into one.
+func collapseBlanks(lines []string) []string {
+	var out []string
+	for _, l := range lines {
+		if l == "" && (len(out) == 0 || out[len(out)-1] == "") {
+			continue
+		}
+		out = append(out, l)
+	}
+	if len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// Format returns content rewritten in canonical style, keeping every
+// comment. Keys stay in file order unless sortKeys is set. Content that is
+// not valid TOML is rejected rather than guessed at.
+func Format(content string, sortKeys bool) (string, error) {
+	var probe map[string]interface{}
+	if err := toml.Unmarshal([]byte(content), &probe); err != nil {
+		return "", err
+	}
+	doc := parseDocument(strings.Split(strings.TrimRight(content, "\n"), "\n"))
+	if sortKeys {
+		doc.sortKeys(func(_, key string) string { return keyOrder(key) })
+	}
+	return doc.render(sortKeys), nil
+}
+
+// FormatFile rewrites the file at path with Format and reports whether its
+// content changed. With check set, the file is only compared, not written.
+func FormatFile(path string, sortKeys, check bool) (bool, error) {
+	return rewriteFile(path, check, func(content string) (string, error) {
+		return Format(content, sortKeys)
+	})
+}
+
+// SortFile reorders the sections and keys of the file at path and writes
+// it in Format's style, reporting whether its content changed. by is
+// "alpha" for alphabetical order or "template" for the order of
+// DefaultTemplate, with anything the template lacks after it
+// alphabetically. Either way each _desc key follows the key it describes
+// and comments move with their keys.
+func SortFile(path, by string) (bool, error) {
+	categoryOrder, keyOrderIn, err := sortOrders(by)
+	if err != nil {
+		return false, err
+	}
+	return rewriteFile(path, false, func(content string) (string, error) {
+		if _, err := Format(content, false); err != nil {
+			return "", err
+		}
+		doc := parseDocument(strings.Split(strings.TrimRight(content, "\n"), "\n"))
+		doc.sortSections(categoryOrder)
+		doc.sortKeys(keyOrderIn)
+		return doc.render(true), nil
+	})
+}
+
+// sortOrders returns the category and key orders for SortFile.
+func sortOrders(by string) (func(string) string, func(string, string) string, error) {
+	switch by {
+	case "alpha":
+		return func(category string) string { return category },
+			func(_, key string) string { return keyOrder(key) }, nil
+	case "template":
+		// Known names sort by position, ahead of all others.
+		ranked := func(names []string, name string) string {
+			for i, n := range names {
+				if n == name {
+					return fmt.Sprintf("0%03d", i)
+				}
+			}
+			return "1" + name
+		}
+		keys := templateKeys()
+		return func(category string) string { return ranked(defaultCategoryOrder, category) },
+			func(section, key string) string {
+				base, desc := strings.CutSuffix(key, "_desc")
+				order := ranked(keys[section], base)
+				if desc {
+					return order + "\x01"
+				}
+				return order + "\x00"
+			}, nil
+	}
+	return nil, nil, fmt.Errorf("unknown order %q: expected alpha or template", by)
+}
+
+// rewriteFile replaces the content of the file at path with rewrite's
+// result and reports whether it changed. With check set, nothing is
+// written.
+func rewriteFile(path string, check bool, rewrite func(string) (string, error)) (bool, error) {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	rewritten, err := rewrite(string(data))
+	if err != nil {
+		return false, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if rewritten == string(data) {
+		return false, nil
+	}
+	if check {
+		return true, nil
+	}
+	return true, writeFile(path, []byte(rewritten))
+}
+
+// EmptyCategories returns, in file order, the [table] sections of the file
+// at path that hold nothing at all: no keys, no comments, and no
+// sub-tables. Sections holding only commented-out fields, as DefaultTemplate
+// writes them, are not empty.
+func EmptyCategories(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc := parseDocument(strings.Split(strings.TrimRight(string(data), "\n"), "\n"))
+
+	var empty []string
+	for _, s := range doc.sections[1:] {
+		name := sectionName(s.header)
+		if strings.HasPrefix(s.header, "[[") || len(s.entries) > 0 || len(collapseBlanks(s.trailing)) > 0 {
+			continue
+		}
+		hasSub := false
+		for _, other := range doc.sections[1:] {
+			if strings.HasPrefix(sectionName(other.header), name+".") {
+				hasSub = true
+				break
+			}
+		}
+		if !hasSub {
+			empty = append(empty, name)
+		}
+	}
+	return empty, nil
+}
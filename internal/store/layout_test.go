@@ -0,0 +1,285 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+const messyTOML = `# top comment
+
+
+  [ identity ]
+name="Alex"
+  aka = [
+  "Lex",
+]
+# how to address me
+pronouns =  "they/them"   # inline
+
+
+
+[web]
+github_desc = "GitHub username"
+blog = "https://example.com"
+github = "queelius"
+
+# end note
+`
+
+func TestFormat(t *testing.T) {
+	got, err := Format(messyTOML, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# top comment
+
+[identity]
+name = "Alex"
+aka  = [
+  "Lex",
+]
+# how to address me
+pronouns = "they/them"   # inline
+
+[web]
+github_desc = "GitHub username"
+blog        = "https://example.com"
+github      = "queelius"
+
+# end note
+`
+	if got != want {
+		t.Errorf("Format:\n%s\nwant:\n%s", got, want)
+	}
+	assertSameTOML(t, messyTOML, got)
+
+	if again, _ := Format(got, false); again != got {
+		t.Errorf("Format is not idempotent:\n%s", again)
+	}
+}
+
+func TestFormat_SortKeys(t *testing.T) {
+	got, err := Format(messyTOML, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# top comment
+
+[identity]
+aka  = [
+  "Lex",
+]
+name = "Alex"
+# how to address me
+pronouns = "they/them"   # inline
+
+[web]
+blog        = "https://example.com"
+github      = "queelius"
+github_desc = "GitHub username"
+
+# end note
+`
+	if got != want {
+		t.Errorf("Format sorted:\n%s\nwant:\n%s", got, want)
+	}
+	assertSameTOML(t, messyTOML, got)
+}
+
+func TestFormat_KeepsTemplateAndMultiline(t *testing.T) {
+	if got, _ := Format(DefaultTemplate, false); got != DefaultTemplate {
+		t.Errorf("the default template should already be formatted, got:\n%s", got)
+	}
+
+	content := "[identity]\nbio = \"\"\"\n  [not a header]\n# not a comment\"\"\"\nnym = \"A\"\n"
+	got, err := Format(content, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != content {
+		t.Errorf("multi-line string should be kept verbatim, got:\n%s", got)
+	}
+
+	if _, err := Format("[identity\nname = 1\n", false); err == nil {
+		t.Error("expected error for invalid TOML")
+	}
+}
+
+func TestFormatFile(t *testing.T) {
+	path := writeTemp(t, messyTOML)
+	changed, err := FormatFile(path, false, true)
+	if err != nil || !changed {
+		t.Fatalf("check: changed=%v err=%v, want true", changed, err)
+	}
+	if readTemp(t, path) != messyTOML {
+		t.Error("check should not write the file")
+	}
+
+	if changed, err := FormatFile(path, false, false); err != nil || !changed {
+		t.Fatalf("format: changed=%v err=%v, want true", changed, err)
+	}
+	if changed, err := FormatFile(path, false, true); err != nil || changed {
+		t.Errorf("formatted file: changed=%v err=%v, want false", changed, err)
+	}
+}
+
+// assertSameTOML fails unless a and b decode to the same data.
+func assertSameTOML(t *testing.T, a, b string) {
+	t.Helper()
+	var da, db map[string]interface{}
+	if _, err := toml.Decode(a, &da); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := toml.Decode(b, &db); err != nil {
+		t.Fatalf("formatted output is not valid TOML: %v", err)
+	}
+	if !reflect.DeepEqual(da, db) {
+		t.Errorf("formatting changed the data:\n%v\n%v", da, db)
+	}
+}
+
+const jumbledTOML = `# deets
+
+[web]
+blog = "https://example.com"
+github = "queelius"
+
+[[education.degrees]]
+degree = "PhD"
+
+# Hobbies and such
+[zeta]
+b = 2
+a = 1
+
+[identity]
+pronouns = "they/them"
+# legal name
+name = "Alex"
+name_desc = "Full legal name"
+aka = ["Lex"]
+nickname = "A"
+
+[education]
+institution = "SIUE"
+
+[[education.degrees]]
+year = 2015
+degree = "MS"
+`
+
+func TestSortFile_Alpha(t *testing.T) {
+	path := writeTemp(t, jumbledTOML)
+	changed, err := SortFile(path, "alpha")
+	if err != nil || !changed {
+		t.Fatalf("changed=%v err=%v, want true", changed, err)
+	}
+	want := `# deets
+
+[education]
+institution = "SIUE"
+
+[[education.degrees]]
+degree = "PhD"
+
+[[education.degrees]]
+degree = "MS"
+year   = 2015
+
+[identity]
+aka = ["Lex"]
+# legal name
+name      = "Alex"
+name_desc = "Full legal name"
+nickname  = "A"
+pronouns  = "they/them"
+
+[web]
+blog   = "https://example.com"
+github = "queelius"
+
+# Hobbies and such
+[zeta]
+a = 1
+b = 2
+`
+	got := readTemp(t, path)
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	assertSameTOML(t, jumbledTOML, got)
+
+	if changed, _ := SortFile(path, "alpha"); changed {
+		t.Error("sorting a sorted file should not change it")
+	}
+}
+
+func TestSortFile_Template(t *testing.T) {
+	path := writeTemp(t, jumbledTOML)
+	if _, err := SortFile(path, "template"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# deets
+
+[identity]
+# legal name
+name      = "Alex"
+name_desc = "Full legal name"
+aka       = ["Lex"]
+pronouns  = "they/them"
+nickname  = "A"
+
+[web]
+github = "queelius"
+blog   = "https://example.com"
+
+[education]
+institution = "SIUE"
+
+[[education.degrees]]
+degree = "PhD"
+
+[[education.degrees]]
+degree = "MS"
+year   = 2015
+
+# Hobbies and such
+[zeta]
+a = 1
+b = 2
+`
+	if got := readTemp(t, path); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := SortFile(path, "random"); err == nil {
+		t.Error("expected error for an unknown order")
+	}
+}
+
+func TestEmptyCategories(t *testing.T) {
+	path := writeTemp(t, `[identity]
+name = "Alex"
+
+[empty]
+
+[commented]
+# email = ""
+
+[parent]
+
+[parent.child]
+key = "v"
+
+[last]
+`)
+	got, err := EmptyCategories(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"empty", "last"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("EmptyCategories = %v, want %v", got, want)
+	}
+}
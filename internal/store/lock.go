@@ -0,0 +1,49 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LockTimeout is how long a write waits for another deets process to
+// finish with the same file.
+var LockTimeout = 10 * time.Second
+
+// staleLockAge is the age after which a lock is assumed to be left over
+// from a process that crashed, and is broken. No write holds a lock for
+// anywhere near this long.
+const staleLockAge = time.Minute
+
+// lockFile takes an advisory lock on path for a read-modify-write by
+// creating path.lock exclusively, which works the same on every platform.
+// The returned function releases it. Writers in this package hold the lock
+// from reading a file until its replacement is renamed into place, so
+// concurrent invocations cannot drop each other's edits.
+func lockFile(path string) (unlock func(), err error) {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	lock := path + ".lock"
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintln(f, os.Getpid())
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another deets process (remove %s if none is running)", path, lock)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
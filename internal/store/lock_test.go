@@ -0,0 +1,57 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLock_ConcurrentWritesKeepEveryEdit(t *testing.T) {
+	path := writeTemp(t, "[a]\n")
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- SetValue(path, "a", fmt.Sprintf("k%d", i), "v")
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("SetValue returned error: %v", err)
+		}
+	}
+	content := readTemp(t, path)
+	for i := 0; i < 20; i++ {
+		if !strings.Contains(content, fmt.Sprintf("k%d = ", i)) {
+			t.Errorf("edit k%d was lost:\n%s", i, content)
+		}
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Error("lock file should be removed after writing")
+	}
+}
+
+func TestLock_TimeoutAndStale(t *testing.T) {
+	path := writeTemp(t, "[a]\n")
+	defer func(d time.Duration) { LockTimeout = d }(LockTimeout)
+	LockTimeout = 50 * time.Millisecond
+
+	os.WriteFile(path+".lock", []byte("1\n"), 0644)
+	err := SetValue(path, "a", "k", "v")
+	if err == nil || !strings.Contains(err.Error(), "locked by another deets process") {
+		t.Fatalf("expected lock timeout, got %v", err)
+	}
+
+	old := time.Now().Add(-2 * staleLockAge)
+	os.Chtimes(path+".lock", old, old)
+	if err := SetValue(path, "a", "k", "v"); err != nil {
+		t.Errorf("a stale lock should be broken, got %v", err)
+	}
+}
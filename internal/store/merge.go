@@ -7,7 +7,9 @@ import (
 )
 
 // Merge merges a local override DB into a global DB and returns a new DB.
-// Local keys replace matching global keys within each category. Non-overlapping
+// Local keys replace matching global keys within each category, except that
+// a local table is merged into a global table key by key, so overriding
+// [education.phd] year keeps the other keys of the table. Non-overlapping
 // keys from both are preserved. Categories that exist only in local or only in
 // global are included. The result is sorted alphabetically by category and by
 // field key within each category.
@@ -67,8 +69,9 @@ func Merge(global, local *model.DB) *model.DB {
 }
 
 // mergeCategory merges fields from a local category into a global category.
-// Local fields override global fields with the same key. All other fields are
-// preserved and the result is sorted alphabetically by key.
+// Local fields override global fields with the same key, with tables merged
+// through mergeTables. All other fields are preserved and the result is
+// sorted alphabetically by key.
 func mergeCategory(global, local model.Category) model.Category {
 	// Build a map of global fields.
 	fieldMap := make(map[string]model.Field, len(global.Fields))
@@ -78,6 +81,14 @@ func mergeCategory(global, local model.Category) model.Category {
 
 	// Local fields override globals.
 	for _, f := range local.Fields {
+		if g, ok := fieldMap[f.Key]; ok {
+			gt, gOK := g.Value.(map[string]interface{})
+			lt, lOK := f.Value.(map[string]interface{})
+			if gOK && lOK {
+				f.Value = mergeTables(gt, lt)
+				f.Descs = mergeDescs(g.Descs, f.Descs)
+			}
+		}
 		fieldMap[f.Key] = f
 	}
 
@@ -94,3 +105,37 @@ func mergeCategory(global, local model.Category) model.Category {
 	}
 	return cat
 }
+
+// mergeTables returns the table global with the keys of local merged in,
+// recursing into tables present in both. Neither argument is modified.
+func mergeTables(global, local map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(global)+len(local))
+	for k, v := range global {
+		out[k] = v
+	}
+	for k, v := range local {
+		gt, gOK := out[k].(map[string]interface{})
+		lt, lOK := v.(map[string]interface{})
+		if gOK && lOK {
+			v = mergeTables(gt, lt)
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// mergeDescs returns the nested descriptions of global with those of local
+// taking precedence, or nil if there are none.
+func mergeDescs(global, local map[string]string) map[string]string {
+	if len(global) == 0 {
+		return local
+	}
+	out := make(map[string]string, len(global)+len(local))
+	for k, v := range global {
+		out[k] = v
+	}
+	for k, v := range local {
+		out[k] = v
+	}
+	return out
+}
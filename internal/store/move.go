@@ -0,0 +1,217 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenameCategory renames the [from] section of the TOML file at filePath to
+// [to], along with its sub-tables ([from.sub] and [[from.sub]]). Keys,
+// comments, and descriptions inside the sections are untouched. It returns
+// an error if from is not found or to already exists.
+func RenameCategory(filePath, from, to string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return renameCategory(filePath, from, to)
+}
+
+// renameCategory is RenameCategory for callers holding the lock.
+func renameCategory(filePath, from, to string) error {
+	lines, err := readLines(filePath)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i, line := range lines {
+		if inCategory(line, to) {
+			return fmt.Errorf("category %q already exists in %s", to, filePath)
+		}
+		if inCategory(line, from) {
+			found = true
+			lines[i] = renameHeader(line, from, to)
+		}
+	}
+	if !found {
+		return fmt.Errorf("category %q not found in %s", from, filePath)
+	}
+	return writeLines(filePath, lines)
+}
+
+// hasCategory reports whether lines contain a header for category or one of
+// its sub-tables.
+func hasCategory(lines []string, category string) bool {
+	for _, line := range lines {
+		if inCategory(line, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// renameHeader rewrites a [from...] or [[from...]] header line to use to,
+// keeping its indentation and any trailing comment.
+func renameHeader(line, from, to string) string {
+	open := strings.Index(line, "[")
+	name := strings.TrimLeft(line[open:], "[")
+	brackets := line[open : len(line)-len(name)]
+	return line[:open] + brackets + to + strings.TrimPrefix(name, from)
+}
+
+// MoveValue moves the key fromKey in category fromCat to toKey in category
+// toCat in the TOML file at filePath. The key's _desc companion and any
+// comment lines directly above either of them travel along. Renames within
+// a category happen in place; moves to another category append the lines to
+// that section, creating it if needed. A key that names a sub-table, such as
+// "phd" for [education.phd], is renamed like a category.
+//
+// It returns an error if the source key is not found or the destination key
+// already exists.
+func MoveValue(filePath, fromCat, fromKey, toCat, toKey string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	lines, err := readLines(filePath)
+	if err != nil {
+		return err
+	}
+	inValue := continuation(lines)
+	fromCat, fromKey = nestedSection(lines, inValue, fromCat, fromKey)
+	toCat, toKey = nestedSection(lines, inValue, toCat, toKey)
+
+	sectionIdx := findSection(lines, inValue, fromCat)
+	keyIdx := -1
+	if sectionIdx != -1 {
+		keyIdx = findKey(lines, inValue, sectionIdx+1, findNextSection(lines, inValue, sectionIdx), fromKey)
+	}
+	if keyIdx == -1 {
+		if table := fromCat + "." + fromKey; hasCategory(lines, table) {
+			return renameCategory(filePath, table, toCat+"."+toKey)
+		}
+		return fmt.Errorf("key %q not found in category %q in %s", fromKey, fromCat, filePath)
+	}
+
+	if dst := findSection(lines, inValue, toCat); dst != -1 {
+		if findKey(lines, inValue, dst+1, findNextSection(lines, inValue, dst), toKey) != -1 {
+			return fmt.Errorf("key %q already exists in category %q in %s", toKey, toCat, filePath)
+		}
+	}
+
+	if fromCat == toCat {
+		renameKeys(lines, inValue, sectionIdx, fromKey, toKey)
+		return writeLines(filePath, lines)
+	}
+
+	// Cut the key's block, then its description's, from the source section.
+	var block []string
+	for _, k := range []string{fromKey, fromKey + "_desc"} {
+		inValue := continuation(lines)
+		idx := findKey(lines, inValue, sectionIdx+1, findNextSection(lines, inValue, sectionIdx), k)
+		if idx == -1 {
+			continue
+		}
+		start, end := keyBlock(lines, sectionIdx, idx)
+		block = append(block, lines[start:end]...)
+		lines = append(lines[:start], lines[end:]...)
+	}
+	renameKeys(block, continuation(block), -1, fromKey, toKey)
+	lines = dropEmptySection(lines, sectionIdx)
+
+	inValue = continuation(lines)
+	dst := findSection(lines, inValue, toCat)
+	if dst == -1 {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, fmt.Sprintf("[%s]", toCat))
+		lines = append(lines, block...)
+		return writeLines(filePath, lines)
+	}
+
+	// Insert after the last non-blank line of the destination section.
+	insertAt := findNextSection(lines, inValue, dst)
+	for insertAt > dst+1 && strings.TrimSpace(lines[insertAt-1]) == "" {
+		insertAt--
+	}
+	lines = append(lines[:insertAt], append(block, lines[insertAt:]...)...)
+	return writeLines(filePath, lines)
+}
+
+// renameKeys renames the from and from_desc key lines to to and to_desc
+// within the section starting at sectionIdx, or within all of lines when
+// sectionIdx is -1. inValue is continuation(lines).
+func renameKeys(lines []string, inValue []bool, sectionIdx int, from, to string) {
+	start, end := 0, len(lines)
+	if sectionIdx != -1 {
+		start, end = sectionIdx+1, findNextSection(lines, inValue, sectionIdx)
+	}
+	for _, pair := range [][2]string{{from, to}, {from + "_desc", to + "_desc"}} {
+		if idx := findKey(lines, inValue, start, end, pair[0]); idx != -1 {
+			indent := lines[idx][:len(lines[idx])-len(strings.TrimLeft(lines[idx], " \t"))]
+			rest := strings.TrimPrefix(strings.TrimLeft(lines[idx], " \t"), pair[0])
+			lines[idx] = indent + pair[1] + rest
+		}
+	}
+}
+
+// keyBlock returns the line range [start, end) covering the key at keyIdx:
+// the comment lines directly above it, and every line of its value when an
+// array or multi-line string spans several lines.
+func keyBlock(lines []string, sectionIdx, keyIdx int) (int, int) {
+	start := keyIdx
+	for start > sectionIdx+1 && strings.HasPrefix(strings.TrimSpace(lines[start-1]), "#") {
+		start--
+	}
+	return start, valueEnd(lines, keyIdx)
+}
+
+// valueEnd returns the index just past the last line of the value that
+// starts on line keyIdx.
+func valueEnd(lines []string, keyIdx int) int {
+	value := strings.TrimSpace(lines[keyIdx][strings.Index(lines[keyIdx], "=")+1:])
+	for _, quote := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(value, quote) && strings.Count(value, quote) == 1 {
+			for i := keyIdx + 1; i < len(lines); i++ {
+				if strings.Contains(lines[i], quote) {
+					return i + 1
+				}
+			}
+			return len(lines)
+		}
+	}
+	if strings.HasPrefix(value, "[") {
+		depth := 0
+		for i := keyIdx; i < len(lines); i++ {
+			text := lines[i]
+			if i == keyIdx {
+				text = value
+			}
+			depth += strings.Count(text, "[") - strings.Count(text, "]")
+			if depth <= 0 {
+				return i + 1
+			}
+		}
+		return len(lines)
+	}
+	return keyIdx + 1
+}
+
+// dropEmptySection removes the section starting at sectionIdx when it holds
+// no keys any more, as RemoveValue does.
+func dropEmptySection(lines []string, sectionIdx int) []string {
+	next := findNextSection(lines, continuation(lines), sectionIdx)
+	for i := sectionIdx + 1; i < next; i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return lines
+		}
+	}
+	return append(lines[:sectionIdx], lines[next:]...)
+}
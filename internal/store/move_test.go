@@ -0,0 +1,158 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTemp writes content to a me.toml in a temp dir and returns its path.
+func writeTemp(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "me.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// readTemp returns the content of path.
+func readTemp(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMoveValue_RenameInPlace(t *testing.T) {
+	path := writeTemp(t, `[identity]
+# what friends call me
+nickname = "Al"
+nickname_desc = "Informal name"
+name = "Alice"
+`)
+	if err := MoveValue(path, "identity", "nickname", "identity", "aka"); err != nil {
+		t.Fatalf("MoveValue returned error: %v", err)
+	}
+	want := `[identity]
+# what friends call me
+aka = "Al"
+aka_desc = "Informal name"
+name = "Alice"
+`
+	if got := readTemp(t, path); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMoveValue_AcrossCategories(t *testing.T) {
+	path := writeTemp(t, `[contact]
+# primary address
+email = "a@example.com"
+email_desc = "Primary email"
+topics = [
+  "stats",
+  "ml",
+]
+
+[billing]
+name = "Alice"
+
+[web]
+github = "alice"
+`)
+	if err := MoveValue(path, "contact", "email", "billing", "email"); err != nil {
+		t.Fatalf("MoveValue returned error: %v", err)
+	}
+	if err := MoveValue(path, "contact", "topics", "academic", "topics"); err != nil {
+		t.Fatalf("MoveValue returned error: %v", err)
+	}
+	want := `[billing]
+name = "Alice"
+# primary address
+email = "a@example.com"
+email_desc = "Primary email"
+
+[web]
+github = "alice"
+
+[academic]
+topics = [
+  "stats",
+  "ml",
+]
+`
+	if got := readTemp(t, path); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	db, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("result no longer parses: %v", err)
+	}
+	if f, ok := db.GetField("billing.email"); !ok || f.Desc != "Primary email" {
+		t.Errorf("billing.email = %+v, %v", f, ok)
+	}
+}
+
+func TestMoveValue_SubTable(t *testing.T) {
+	path := writeTemp(t, `[education]
+field = "Statistics"
+
+[education.phd]
+institution = "SIUE"
+`)
+	if err := MoveValue(path, "education", "phd", "education", "doctorate"); err != nil {
+		t.Fatalf("MoveValue returned error: %v", err)
+	}
+	if got := readTemp(t, path); !strings.Contains(got, "[education.doctorate]\ninstitution") {
+		t.Errorf("expected renamed sub-table, got:\n%s", got)
+	}
+}
+
+func TestMoveValue_Errors(t *testing.T) {
+	path := writeTemp(t, `[identity]
+name = "Alice"
+aka = "Al"
+`)
+	if err := MoveValue(path, "identity", "missing", "identity", "x"); err == nil {
+		t.Error("expected error for missing source key")
+	}
+	if err := MoveValue(path, "identity", "aka", "identity", "name"); err == nil {
+		t.Error("expected error for existing destination key")
+	}
+}
+
+func TestRenameCategory(t *testing.T) {
+	path := writeTemp(t, `[work]
+role = "dev"
+
+[work.acme]
+years = 3
+
+[[work.history]]
+name = "old"
+
+[workshop]
+x = 1
+`)
+	if err := RenameCategory(path, "work", "career"); err != nil {
+		t.Fatalf("RenameCategory returned error: %v", err)
+	}
+	got := readTemp(t, path)
+	for _, want := range []string{"[career]\n", "[career.acme]\n", "[[career.history]]\n", "[workshop]\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+
+	if err := RenameCategory(path, "career", "workshop"); err == nil {
+		t.Error("expected error renaming onto an existing category")
+	}
+	if err := RenameCategory(path, "nonexistent", "x"); err == nil {
+		t.Error("expected error for missing category")
+	}
+}
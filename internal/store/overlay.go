@@ -0,0 +1,54 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/queelius/deets/internal/model"
+)
+
+// A Layer returns an overlay for Load and LoadReader to merge on top of the
+// database loaded so far, before computed fields are evaluated.
+type Layer func(db *model.DB) (*model.DB, error)
+
+// Static returns a Layer that merges overlay whatever was loaded.
+func Static(overlay *model.DB) Layer {
+	return func(*model.DB) (*model.DB, error) {
+		return overlay, nil
+	}
+}
+
+// Overlay builds an in-memory overlay from assignments, to be merged on top
+// of the loaded files through Static. Its fields carry the given layer and
+// source. A later assignment to the same key replaces an earlier one, and a
+// dotted key such as phd.year sets that one key of the table, as EnvLayer
+// does, so the rest of the table is kept when the overlay is merged. Keys
+// must be bare TOML keys without array indexes.
+func Overlay(assignments []Assignment, layer, source string) (*model.DB, error) {
+	raw := make(map[string]interface{})
+	for _, a := range assignments {
+		if err := checkAssignment(a); err != nil {
+			return nil, err
+		}
+		path := a.Category + "." + a.Key
+		if _, _, ok := model.SplitIndex(path); ok {
+			return nil, fmt.Errorf("invalid key %q: array items cannot be overridden", path)
+		}
+		var probe map[string]interface{}
+		if err := toml.Unmarshal([]byte("v = "+a.Literal), &probe); err != nil {
+			return nil, fmt.Errorf("invalid TOML value for %s: %s", path, a.Literal)
+		}
+		cat, _ := raw[a.Category].(map[string]interface{})
+		if cat == nil {
+			cat = make(map[string]interface{})
+			raw[a.Category] = cat
+		}
+		parts := strings.Split(a.Key, ".")
+		cat[parts[0]] = withLeaf(cat[parts[0]], parts[1:], probe["v"])
+	}
+
+	db := build(raw, source, nil)
+	setLayer(db, layer)
+	return db, nil
+}
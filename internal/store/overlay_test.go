@@ -0,0 +1,75 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	db, err := Overlay([]Assignment{
+		{"identity", "name", `"Bob"`},
+		{"identity", "age", "42"},
+		{"identity", "name", `"Carol"`},
+		{"education", "phd.school", `"MIT"`},
+	}, "set", "--set")
+	if err != nil {
+		t.Fatalf("Overlay returned error: %v", err)
+	}
+
+	f, ok := db.GetField("identity.name")
+	if !ok || f.Value != "Carol" {
+		t.Fatalf("identity.name = %+v, want the later assignment", f)
+	}
+	if f.Layer != "set" || f.Source != "--set" || f.Line != 0 {
+		t.Errorf("identity.name = %+v, want layer set, source --set, no line", f)
+	}
+	if f, _ := db.GetField("identity.age"); f.Value != int64(42) {
+		t.Errorf("identity.age = %#v, want int64 42", f.Value)
+	}
+	if f, _ := db.GetField("education.phd"); f.Value == nil {
+		t.Error("expected dotted key to build a nested table")
+	}
+}
+
+func TestOverlay_InvalidKeys(t *testing.T) {
+	for _, a := range []Assignment{
+		{"identity", "aka[0]", `"x"`},
+		{"identity", "bad key", `"x"`},
+		{"identity", "name", `"x"` + "\n[evil]"},
+	} {
+		if _, err := Overlay([]Assignment{a}, "set", "--set"); err == nil {
+			t.Errorf("Overlay(%+v) succeeded, want an error", a)
+		}
+	}
+}
+
+func TestLoad_Overlays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "me.toml")
+	content := `[identity]
+name = "Alice"
+signature = "{{identity.name}}"
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	first, _ := Overlay([]Assignment{{"identity", "name", `"Bob"`}}, "set", "--set")
+	second, _ := Overlay([]Assignment{{"identity", "name", `"Carol"`}}, "env", "environment")
+
+	db, err := Load(path, "", Static(first), Static(second))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if f, _ := db.GetField("identity.signature"); f.Value != "Carol" {
+		t.Errorf("signature = %v, want it to see the last overlay", f.Value)
+	}
+
+	db, err = LoadReader(strings.NewReader(content), "stdin", Static(first))
+	if err != nil {
+		t.Fatalf("LoadReader returned error: %v", err)
+	}
+	if f, _ := db.GetField("identity.name"); f.Value != "Bob" || f.Layer != "set" {
+		t.Errorf("identity.name = %+v, want the overlay", f)
+	}
+}
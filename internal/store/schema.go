@@ -0,0 +1,106 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/queelius/deets/internal/model"
+)
+
+// LoadSchema reads a schema file such as ~/.deets/schema.toml, keeping the
+// categories and keys in file order. A missing file is not an error and
+// yields a nil schema.
+func LoadSchema(path string) (*model.Schema, error) {
+	var raw map[string]interface{}
+	md, err := toml.DecodeFile(path, &raw)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	fail := func(format string, args ...interface{}) (*model.Schema, error) {
+		return nil, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...))
+	}
+
+	schema := &model.Schema{}
+	for _, k := range md.Keys() {
+		switch {
+		case len(k) == 1 && k[0] == "strict":
+			strict, ok := raw["strict"].(bool)
+			if !ok {
+				return fail("strict must be true or false")
+			}
+			schema.Strict = strict
+		case len(k) == 1:
+			if _, ok := raw[k[0]].(map[string]interface{}); !ok {
+				return fail("%s must be a [category] table", k[0])
+			}
+			if schemaCategory(schema, k[0]) == -1 {
+				schema.Categories = append(schema.Categories, model.SchemaCategory{Name: k[0]})
+			}
+		case len(k) == 2:
+			// [category.key] may come without a [category] header, or
+			// after the keys of another category.
+			i := schemaCategory(schema, k[0])
+			if i == -1 {
+				schema.Categories = append(schema.Categories, model.SchemaCategory{Name: k[0]})
+				i = len(schema.Categories) - 1
+			}
+			cat := &schema.Categories[i]
+			spec, ok := raw[k[0]].(map[string]interface{})[k[1]].(map[string]interface{})
+			if !ok {
+				return fail("%s.%s must be a [category.key] table", k[0], k[1])
+			}
+			key, err := schemaKey(k[1], spec)
+			if err != nil {
+				return fail("%s.%s: %v", k[0], k[1], err)
+			}
+			cat.Keys = append(cat.Keys, key)
+		}
+	}
+	return schema, nil
+}
+
+// schemaCategory returns the index of the named category in s, or -1.
+func schemaCategory(s *model.Schema, name string) int {
+	return slices.IndexFunc(s.Categories, func(c model.SchemaCategory) bool { return c.Name == name })
+}
+
+// schemaKey builds the constraints of one key from its [category.key]
+// table.
+func schemaKey(name string, spec map[string]interface{}) (model.SchemaKey, error) {
+	key := model.SchemaKey{Name: name}
+	for field, v := range spec {
+		var ok bool
+		switch field {
+		case "type":
+			if key.Type, ok = v.(string); !ok || !slices.Contains(model.SchemaTypes, key.Type) {
+				return key, fmt.Errorf("type must be one of %s", strings.Join(model.SchemaTypes, ", "))
+			}
+		case "pattern":
+			s, isString := v.(string)
+			if !isString {
+				return key, fmt.Errorf("pattern must be a string")
+			}
+			re, err := regexp.Compile(s)
+			if err != nil {
+				return key, fmt.Errorf("invalid pattern: %w", err)
+			}
+			key.Pattern = re
+		case "required":
+			if key.Required, ok = v.(bool); !ok {
+				return key, fmt.Errorf("required must be true or false")
+			}
+		case "description":
+			// Documentation for the reader of the schema file.
+		default:
+			return key, fmt.Errorf("unknown field %q: use type, pattern, required, or description", field)
+		}
+	}
+	return key, nil
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSchema(t *testing.T) {
+	path := writeTemp(t, `strict = true
+
+[identity.name]
+type = "string"
+required = true
+description = "Full name"
+
+[identity.aka]
+type = "array"
+
+[contact.email]
+pattern = '^[^@ ]+@[^@ ]+$'
+
+[cooking]
+`)
+	s, err := LoadSchema(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Strict || len(s.Categories) != 3 {
+		t.Fatalf("unexpected schema: %+v", s)
+	}
+	identity := s.Categories[0]
+	if identity.Name != "identity" || len(identity.Keys) != 2 || identity.Keys[0].Name != "name" || !identity.Keys[0].Required {
+		t.Errorf("unexpected identity: %+v", identity)
+	}
+	if email := s.Categories[1].Keys[0]; email.Pattern == nil || !email.Pattern.MatchString("a@b") {
+		t.Errorf("unexpected email constraint: %+v", email)
+	}
+	if s.Categories[2].Name != "cooking" || len(s.Categories[2].Keys) != 0 {
+		t.Errorf("unexpected cooking: %+v", s.Categories[2])
+	}
+}
+
+func TestLoadSchema_Missing(t *testing.T) {
+	s, err := LoadSchema(filepath.Join(t.TempDir(), "schema.toml"))
+	if err != nil || s != nil {
+		t.Errorf("LoadSchema(missing) = %v, %v; want nil, nil", s, err)
+	}
+}
+
+func TestLoadSchema_Invalid(t *testing.T) {
+	for _, content := range []string{
+		"strict = \"yes\"\n",
+		"[identity.name]\ntype = \"text\"\n",
+		"[identity.name]\npattern = \"(\"\n",
+		"[identity.name]\nrequire = true\n",
+		"[identity]\nname = \"string\"\n",
+	} {
+		path := writeTemp(t, content)
+		if _, err := LoadSchema(path); err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("expected error naming the file for %q, got %v", content, err)
+		}
+	}
+}
+
+func TestLoadSchema_CategoryOrder(t *testing.T) {
+	s, err := LoadSchema(writeTemp(t, "[identity.name]\n[contact.email]\n[identity.aka]\n[identity]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Categories) != 2 || len(s.Categories[0].Keys) != 2 || s.Categories[1].Name != "contact" {
+		t.Errorf("unexpected categories: %+v", s.Categories)
+	}
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A Snapshot is a named set of file copies under a snapshot directory,
+// one subdirectory per snapshot. Each copy is named after the escaped
+// absolute path of the file it was taken from, so restoring needs no
+// other record.
+type Snapshot struct {
+	Name  string
+	Time  time.Time
+	Files []string // the files the snapshot holds copies of
+}
+
+// validSnapshotName rejects names that would escape the snapshot
+// directory or be hidden in it.
+func validSnapshotName(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return nil
+}
+
+// SaveSnapshot copies files into dir/name. Files that do not exist are
+// skipped, but at least one must. An existing snapshot of that name is
+// replaced only with force.
+func SaveSnapshot(dir, name string, files []string, force bool) (Snapshot, error) {
+	if err := validSnapshotName(name); err != nil {
+		return Snapshot{}, err
+	}
+	target := filepath.Join(dir, name)
+	if _, err := os.Stat(target); err == nil {
+		if !force {
+			return Snapshot{}, fmt.Errorf("snapshot %q already exists", name)
+		}
+		if err := os.RemoveAll(target); err != nil {
+			return Snapshot{}, err
+		}
+	}
+
+	snap := Snapshot{Name: name, Time: time.Now()}
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		data, err := os.ReadFile(abs)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return Snapshot{}, err
+		}
+		if err := os.MkdirAll(target, 0755); err != nil {
+			return Snapshot{}, err
+		}
+		if err := os.WriteFile(filepath.Join(target, url.QueryEscape(abs)), data, 0600); err != nil {
+			return Snapshot{}, err
+		}
+		snap.Files = append(snap.Files, abs)
+	}
+	if len(snap.Files) == 0 {
+		return Snapshot{}, fmt.Errorf("nothing to snapshot: none of %s exist", strings.Join(files, ", "))
+	}
+	return snap, nil
+}
+
+// Snapshots lists the snapshots in dir, oldest first. A missing dir has
+// none.
+func Snapshots(dir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snaps []Snapshot
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		snap, err := loadSnapshot(dir, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, snap)
+	}
+	sort.SliceStable(snaps, func(i, j int) bool { return snaps[i].Time.Before(snaps[j].Time) })
+	return snaps, nil
+}
+
+// loadSnapshot reads the snapshot dir/name.
+func loadSnapshot(dir, name string) (Snapshot, error) {
+	target := filepath.Join(dir, name)
+	info, err := os.Stat(target)
+	if os.IsNotExist(err) {
+		return Snapshot{}, fmt.Errorf("snapshot %q not found", name)
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+	entries, err := os.ReadDir(target)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snap := Snapshot{Name: name, Time: info.ModTime()}
+	for _, e := range entries {
+		if path, err := url.QueryUnescape(e.Name()); err == nil && !e.IsDir() {
+			snap.Files = append(snap.Files, path)
+		}
+	}
+	return snap, nil
+}
+
+// RestoreSnapshot writes every copy in the snapshot dir/name back over
+// the file it was taken from, through WriteFile so the current content is
+// backed up first.
+func RestoreSnapshot(dir, name string) (Snapshot, error) {
+	if err := validSnapshotName(name); err != nil {
+		return Snapshot{}, err
+	}
+	snap, err := loadSnapshot(dir, name)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	for _, path := range snap.Files {
+		data, err := os.ReadFile(filepath.Join(dir, name, url.QueryEscape(path)))
+		if err != nil {
+			return Snapshot{}, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return Snapshot{}, err
+		}
+		if err := WriteFile(path, data); err != nil {
+			return Snapshot{}, fmt.Errorf("restoring %s: %w", path, err)
+		}
+	}
+	return snap, nil
+}
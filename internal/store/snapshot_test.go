@@ -0,0 +1,51 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshots(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	global := writeTemp(t, "[contact]\nemail = \"a@b.c\"\n")
+	missing := filepath.Join(t.TempDir(), "none.toml")
+
+	snap, err := SaveSnapshot(dir, "before", []string{global, missing}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snap.Files) != 1 || snap.Files[0] != global {
+		t.Errorf("expected only the existing file saved, got %v", snap.Files)
+	}
+	if _, err := SaveSnapshot(dir, "before", []string{global}, false); err == nil {
+		t.Error("expected error saving over an existing snapshot")
+	}
+	if _, err := SaveSnapshot(dir, "before", []string{global}, true); err != nil {
+		t.Errorf("--force should replace the snapshot: %v", err)
+	}
+	for _, name := range []string{"", "../x", ".hidden"} {
+		if _, err := SaveSnapshot(dir, name, []string{global}, false); err == nil {
+			t.Errorf("expected error for name %q", name)
+		}
+	}
+	if _, err := SaveSnapshot(dir, "empty", []string{missing}, false); err == nil {
+		t.Error("expected error when no file exists")
+	}
+
+	os.WriteFile(global, []byte("[contact]\nemail = \"changed\"\n"), 0644)
+	if _, err := RestoreSnapshot(dir, "before"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := readTemp(t, global); got != "[contact]\nemail = \"a@b.c\"\n" {
+		t.Errorf("expected the snapshot restored, got:\n%s", got)
+	}
+	if _, err := RestoreSnapshot(dir, "nope"); err == nil {
+		t.Error("expected error for an unknown snapshot")
+	}
+
+	snaps, err := Snapshots(dir)
+	if err != nil || len(snaps) != 1 || snaps[0].Name != "before" {
+		t.Errorf("Snapshots = %+v (err %v)", snaps, err)
+	}
+}
@@ -4,6 +4,7 @@ package store
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -16,18 +17,47 @@ import (
 // Each top-level key in the TOML is treated as a category name whose value is
 // a map of field keys to values. Keys ending in "_desc" are treated as
 // descriptions for their companion field (e.g., "email_desc" describes "email").
+// Nested tables such as [education.phd] are kept as table values; their
+// "_desc" keys are moved into the field's Descs.
 func LoadFile(path string) (*model.DB, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading %s: %w", path, err)
 	}
+	return parse(data, path)
+}
 
+// LoadReader reads a TOML document from r, as Load does for a single file
+// with no local override. name stands in for the path in field sources and
+// error messages.
+func LoadReader(r io.Reader, name string, layers ...Layer) (*model.DB, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	db, err := parse(data, name)
+	if err != nil {
+		return nil, err
+	}
+	setLayer(db, "global")
+	return resolve(db, layers)
+}
+
+// parse builds a *model.DB from the TOML document data, read from path.
+func parse(data []byte, path string) (*model.DB, error) {
 	var raw map[string]interface{}
 	if err := toml.Unmarshal(data, &raw); err != nil {
 		return nil, fmt.Errorf("parsing %s: %w", path, err)
 	}
+	return build(raw, path, strings.Split(string(data), "\n")), nil
+}
 
+// build turns the decoded document raw, read from path, into a *model.DB.
+// lines holds the text of the document for the fields' line numbers, or is
+// nil when there is no document to point into.
+func build(raw map[string]interface{}, path string, lines []string) *model.DB {
 	db := &model.DB{}
+	index := keyLines(lines)
 
 	// Collect and sort category names alphabetically.
 	catNames := make([]string, 0, len(raw))
@@ -58,6 +88,15 @@ func LoadFile(path string) (*model.DB, error) {
 				Key:      key,
 				Value:    catMap[key],
 				Category: catName,
+				Source:   path,
+				Line:     index[catName+"."+key],
+			}
+			if table, ok := f.Value.(map[string]interface{}); ok {
+				f.Descs = make(map[string]string)
+				f.Value = stripDescs(table, "", f.Descs)
+				if len(f.Descs) == 0 {
+					f.Descs = nil
+				}
 			}
 
 			// Look for a companion _desc key in the TOML data.
@@ -85,25 +124,121 @@ func LoadFile(path string) (*model.DB, error) {
 		}
 	}
 
-	return db, nil
+	return db
+}
+
+// stripDescs returns a copy of the nested table m without its "_desc" keys,
+// recording each description in descs under its dotted sub-path.
+func stripDescs(m map[string]interface{}, prefix string, descs map[string]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if strings.HasSuffix(k, "_desc") {
+			if s, ok := v.(string); ok {
+				descs[prefix+strings.TrimSuffix(k, "_desc")] = s
+			}
+			continue
+		}
+		if table, ok := v.(map[string]interface{}); ok {
+			v = stripDescs(table, prefix+k+".", descs)
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// keyLines indexes, in one pass over lines, the 1-based line on which each
+// category.key is defined, either as a "key = value" line in the
+// [category] section or as a [category.key] or [[category.key]] table
+// header. The first definition wins; text inside multi-line values is
+// skipped.
+func keyLines(lines []string) map[string]int {
+	index := make(map[string]int)
+	add := func(path string, i int) {
+		if _, ok := index[path]; !ok {
+			index[path] = i + 1
+		}
+	}
+	inValue := continuation(lines)
+	section := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inValue[i] || trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		case strings.HasPrefix(trimmed, "[[") && strings.HasSuffix(trimmed, "]]"):
+			section = strings.TrimSpace(trimmed[2 : len(trimmed)-2])
+			add(section, i)
+		case strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+			section = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			add(section, i)
+		case section != "":
+			if key, _ := splitKeyLine(trimmed); key != trimmed {
+				add(section+"."+key, i)
+			}
+		}
+	}
+	return index
+}
+
+// setLayer tags every field in db with the given layer name.
+func setLayer(db *model.DB, layer string) {
+	for i := range db.Categories {
+		for j := range db.Categories[i].Fields {
+			db.Categories[i].Fields[j].Layer = layer
+		}
+	}
 }
 
 // Load reads the global TOML file and optionally merges it with a local
 // override file. If localPath is empty, only the global file is loaded.
-func Load(globalPath, localPath string) (*model.DB, error) {
-	global, err := LoadFile(globalPath)
-	if err != nil {
-		return nil, err
+// The overlay of each layer is then merged on top in order. Computed
+// fields are evaluated after merging so templates see local overrides and
+// layers.
+func Load(globalPath, localPath string, layers ...Layer) (*model.DB, error) {
+	sources := []Source{{Path: globalPath, Layer: "global"}}
+	if localPath != "" {
+		sources = append(sources, Source{Path: localPath, Layer: "local"})
 	}
+	return LoadSources(sources, layers...)
+}
 
-	if localPath == "" {
-		return global, nil
+// Source is a file for LoadSources and the layer its fields belong to.
+type Source struct {
+	Path  string
+	Layer string
+}
+
+// LoadSources reads each source in order, merging every file over the ones
+// before it, then merges the overlay of each layer and evaluates computed
+// fields as Load does.
+func LoadSources(sources []Source, layers ...Layer) (*model.DB, error) {
+	db := &model.DB{}
+	for i, src := range sources {
+		next, err := LoadFile(src.Path)
+		if err != nil {
+			return nil, err
+		}
+		setLayer(next, src.Layer)
+		if i == 0 {
+			db = next
+		} else {
+			db = Merge(db, next)
+		}
 	}
+	return resolve(db, layers)
+}
 
-	local, err := LoadFile(localPath)
-	if err != nil {
+// resolve merges the overlay of each layer onto db in order and evaluates
+// computed fields.
+func resolve(db *model.DB, layers []Layer) (*model.DB, error) {
+	for _, layer := range layers {
+		overlay, err := layer(db)
+		if err != nil {
+			return nil, err
+		}
+		db = Merge(db, overlay)
+	}
+	if err := db.Resolve(); err != nil {
 		return nil, err
 	}
-
-	return Merge(global, local), nil
+	return db, nil
 }
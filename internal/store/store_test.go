@@ -3,6 +3,8 @@ package store
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -402,6 +404,47 @@ github = "bob"
 	}
 }
 
+func TestLoad_MergesNestedTables(t *testing.T) {
+	dir := t.TempDir()
+	globalPath := filepath.Join(dir, "global.toml")
+	localPath := filepath.Join(dir, "local.toml")
+
+	globalContent := `[education.phd]
+school = "MIT"
+school_desc = "Granting institution"
+year = 2019
+`
+	localContent := `[education.phd]
+year = 2020
+`
+	if err := os.WriteFile(globalPath, []byte(globalContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(localPath, []byte(localContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	set, err := Overlay([]Assignment{{"education", "phd.field", `"CS"`}}, "set", "--set")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := Load(globalPath, localPath, Static(set))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	f, ok := db.GetField("education.phd")
+	if !ok {
+		t.Fatal("expected education.phd")
+	}
+	want := map[string]interface{}{"school": "MIT", "year": int64(2020), "field": "CS"}
+	if !reflect.DeepEqual(f.Value, want) {
+		t.Errorf("education.phd = %#v, want %#v", f.Value, want)
+	}
+	if f.Descs["school"] != "Granting institution" {
+		t.Errorf("Descs = %v, want the global description kept", f.Descs)
+	}
+}
+
 func TestLoad_MissingGlobal(t *testing.T) {
 	_, err := Load("/nonexistent/global.toml", "")
 	if err == nil {
@@ -449,3 +492,212 @@ name = "Alice"
 		t.Fatal("expected error for missing local file, got nil")
 	}
 }
+
+func TestLoad_ResolvesTemplatesAfterMerge(t *testing.T) {
+	dir := t.TempDir()
+	globalPath := filepath.Join(dir, "global.toml")
+	localPath := filepath.Join(dir, "local.toml")
+
+	globalContent := `[identity]
+name = "Alice"
+signature = "{{identity.name}} <{{contact.email}}>"
+
+[contact]
+email = "alice@example.com"
+`
+	localContent := `[contact]
+email = "alice@work.example"
+`
+	if err := os.WriteFile(globalPath, []byte(globalContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(localPath, []byte(localContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := Load(globalPath, localPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	f, ok := db.GetField("identity.signature")
+	if !ok {
+		t.Fatal("expected identity.signature")
+	}
+	if f.Value != "Alice <alice@work.example>" {
+		t.Errorf("expected template to see local override, got %q", f.Value)
+	}
+	if !f.Computed {
+		t.Error("expected signature to be computed")
+	}
+}
+
+func TestLoadReader(t *testing.T) {
+	content := `[identity]
+name = "Alice"
+name_desc = "Full name"
+signature = "{{identity.name}}"
+`
+	db, err := LoadReader(strings.NewReader(content), "stdin")
+	if err != nil {
+		t.Fatalf("LoadReader returned error: %v", err)
+	}
+	f, ok := db.GetField("identity.name")
+	if !ok {
+		t.Fatal("expected identity.name")
+	}
+	if f.Source != "stdin" || f.Layer != "global" || f.Desc != "Full name" {
+		t.Errorf("identity.name = %+v, want source stdin, layer global, and its description", f)
+	}
+	if sig, _ := db.GetField("identity.signature"); sig.Value != "Alice" || !sig.Computed {
+		t.Errorf("expected signature to be resolved, got %+v", sig)
+	}
+
+	_, err = LoadReader(strings.NewReader("[identity\n"), "stdin")
+	if err == nil || !strings.Contains(err.Error(), "parsing stdin") {
+		t.Errorf("expected parse error naming stdin, got %v", err)
+	}
+}
+
+func TestLoad_TemplateCycle(t *testing.T) {
+	dir := t.TempDir()
+	globalPath := filepath.Join(dir, "global.toml")
+	content := `[x]
+a = "{{x.b}}"
+b = "{{x.a}}"
+`
+	if err := os.WriteFile(globalPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(globalPath, ""); err == nil {
+		t.Fatal("expected error for template cycle")
+	}
+}
+
+func TestLoadFile_ArrayOfTables(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "me.toml")
+	content := `[education]
+field = "Statistics"
+
+[[education.degrees]]
+degree = "PhD"
+institution = "SIUE"
+year = 2020
+
+[[education.degrees]]
+degree = "BS"
+institution = "UIUC"
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+	degrees := db.Degrees()
+	if len(degrees) != 2 {
+		t.Fatalf("expected 2 degrees, got %d", len(degrees))
+	}
+	if degrees[0].Institution != "SIUE" || degrees[0].Year != "2020" {
+		t.Errorf("unexpected first degree: %+v", degrees[0])
+	}
+}
+
+func TestLoadFile_NestedTables(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "me.toml")
+	content := `[education]
+field = "Statistics"
+
+[education.phd]
+institution = "SIUE"
+institution_desc = "Where the PhD was earned"
+
+[education.phd.advisor]
+name = "Bob"
+name_desc = "Dissertation advisor"
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+	phd, ok := db.GetField("education.phd")
+	if !ok {
+		t.Fatal("expected education.phd")
+	}
+	table := phd.Value.(map[string]interface{})
+	if _, ok := table["institution_desc"]; ok {
+		t.Error("nested _desc key should be stripped from the table")
+	}
+
+	f, ok := db.GetField("education.phd.institution")
+	if !ok || f.Value != "SIUE" || f.Desc != "Where the PhD was earned" {
+		t.Errorf("education.phd.institution = %+v, %v", f, ok)
+	}
+	f, ok = db.GetField("education.phd.advisor.name")
+	if !ok || f.Desc != "Dissertation advisor" {
+		t.Errorf("education.phd.advisor.name = %+v, %v", f, ok)
+	}
+}
+
+func TestLoad_Provenance(t *testing.T) {
+	dir := t.TempDir()
+	globalPath := filepath.Join(dir, "global.toml")
+	localPath := filepath.Join(dir, "local.toml")
+
+	globalContent := `[identity]
+name = "Alice"
+bio = """
+[education.phd]
+pronouns = "not a key"
+"""
+pronouns = "she/her"
+
+[education.phd]
+institution = "SIUE"
+`
+	localContent := `# overrides
+[identity]
+name = "Bob"
+`
+	if err := os.WriteFile(globalPath, []byte(globalContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(localPath, []byte(localContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := Load(globalPath, localPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		layer  string
+		source string
+		line   int
+	}{
+		{"identity.name", "local", localPath, 3},
+		{"identity.pronouns", "global", globalPath, 7},
+		{"education.phd", "global", globalPath, 9},
+		{"education.phd.institution", "global", globalPath, 9},
+	}
+	for _, tt := range tests {
+		f, ok := db.GetField(tt.path)
+		if !ok {
+			t.Errorf("%s: not found", tt.path)
+			continue
+		}
+		if f.Layer != tt.layer || f.Source != tt.source || f.Line != tt.line {
+			t.Errorf("%s: got %s %s:%d, want %s %s:%d", tt.path,
+				f.Layer, f.Source, f.Line, tt.layer, tt.source, tt.line)
+		}
+	}
+}
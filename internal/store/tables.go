@@ -0,0 +1,140 @@
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Arrays of tables are written as repeated [[category.name]] sections, one
+// per element. Keys address an element by index, as in
+// "degrees[1].institution", and the line editors below work on the
+// element's own section.
+
+// splitElement splits a key that addresses an element of an array of
+// tables, such as "degrees[1].institution", into "degrees", 1, and
+// "institution". rest is empty when the key names the whole element. It
+// reports false for keys without an index.
+func splitElement(key string) (name string, index int, rest string, ok bool) {
+	open := strings.Index(key, "[")
+	if open <= 0 {
+		return "", 0, "", false
+	}
+	end := strings.Index(key[open:], "]")
+	if end == -1 {
+		return "", 0, "", false
+	}
+	end += open
+	index, err := strconv.Atoi(key[open+1 : end])
+	if err != nil {
+		return "", 0, "", false
+	}
+	rest = key[end+1:]
+	if rest != "" && !strings.HasPrefix(rest, ".") {
+		return "", 0, "", false
+	}
+	return key[:open], index, strings.TrimPrefix(rest, "."), true
+}
+
+// arrayTables returns the line indices of the [[table]] headers in lines,
+// one per element of the array. inValue is continuation(lines).
+func arrayTables(lines []string, inValue []bool, table string) []int {
+	target := fmt.Sprintf("[[%s]]", table)
+	var idx []int
+	for i, line := range lines {
+		if !inValue[i] && strings.TrimSpace(line) == target {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// element resolves index, which counts from the end when negative, to the
+// header line of that element of the array of tables. It returns -1 if
+// there is no such element.
+func element(headers []int, index int) int {
+	if index < 0 {
+		index += len(headers)
+	}
+	if index < 0 || index >= len(headers) {
+		return -1
+	}
+	return headers[index]
+}
+
+// setElement sets rest to literal within element index of the array of
+// tables category.name. An index one past the last element appends a new
+// element holding just that key.
+func setElement(lines []string, category, name string, index int, rest, literal string) ([]string, error) {
+	table := category + "." + name
+	inValue := continuation(lines)
+	sectionIdx := findSection(lines, inValue, category)
+	if sectionIdx != -1 && findKey(lines, inValue, sectionIdx+1, findNextSection(lines, inValue, sectionIdx), name) != -1 {
+		return nil, fmt.Errorf("%s is an inline array; rewrite it as [[%s]] sections to edit its elements", table, table)
+	}
+	newLine := fmt.Sprintf("%s = %s", rest, literal)
+
+	headers := arrayTables(lines, inValue, table)
+	if h := element(headers, index); h != -1 {
+		return setInSection(lines, inValue, h, rest, newLine), nil
+	}
+	if index != len(headers) {
+		return nil, fmt.Errorf("%s has no element %d (it has %d)", table, index, len(headers))
+	}
+
+	// Append the new element after the last one, or after the category's
+	// own section when the array is new.
+	var at int
+	switch {
+	case len(headers) > 0:
+		at = sectionEnd(lines, inValue, headers[len(headers)-1])
+	case sectionIdx != -1:
+		at = sectionEnd(lines, inValue, sectionIdx)
+	default:
+		at = len(lines)
+		for at > 0 && strings.TrimSpace(lines[at-1]) == "" {
+			at--
+		}
+	}
+	block := []string{fmt.Sprintf("[[%s]]", table), newLine}
+	if at > 0 {
+		block = append([]string{""}, block...)
+	}
+	return append(lines[:at], append(block, lines[at:]...)...), nil
+}
+
+// removeElement removes rest from element index of the array of tables
+// category.name, or the whole element when rest is empty.
+func removeElement(lines []string, category, name string, index int, rest string) ([]string, error) {
+	table := category + "." + name
+	inValue := continuation(lines)
+	h := element(arrayTables(lines, inValue, table), index)
+	if h == -1 {
+		return nil, fmt.Errorf("element %d of %s not found", index, table)
+	}
+	next := findNextSection(lines, inValue, h)
+
+	if rest == "" {
+		lines = append(lines[:h], lines[next:]...)
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		return lines, nil
+	}
+
+	keyIdx := findKey(lines, inValue, h+1, next, rest)
+	if keyIdx == -1 {
+		return nil, fmt.Errorf("key %q not found in element %d of %s", rest, index, table)
+	}
+	return append(lines[:keyIdx], lines[valueEnd(lines, keyIdx):]...), nil
+}
+
+// sectionEnd returns the index just past the last non-blank line of the
+// section whose header is at sectionIdx. inValue is continuation(lines).
+func sectionEnd(lines []string, inValue []bool, sectionIdx int) int {
+	end := findNextSection(lines, inValue, sectionIdx)
+	for end > sectionIdx+1 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return end
+}
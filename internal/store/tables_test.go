@@ -0,0 +1,154 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+const degreesTOML = `[education]
+field = "Statistics"
+
+[[education.degrees]]
+degree = "PhD"
+year = 2020
+
+[[education.degrees]]
+degree = "BS"
+
+[web]
+github = "q"
+`
+
+func TestSetValue_ArrayOfTablesElement(t *testing.T) {
+	path := writeTemp(t, degreesTOML)
+	if err := SetValue(path, "education", "degrees[1].institution", "UIUC"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLiteral(path, "education", "degrees[-1].year", "2012"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetValue(path, "education", "degrees[0].degree", "PhD (Statistics)"); err != nil {
+		t.Fatal(err)
+	}
+	want := `[education]
+field = "Statistics"
+
+[[education.degrees]]
+degree = "PhD (Statistics)"
+year = 2020
+
+[[education.degrees]]
+degree = "BS"
+institution = "UIUC"
+year = 2012
+
+[web]
+github = "q"
+`
+	if got := readTemp(t, path); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetValue_AppendsArrayOfTablesElement(t *testing.T) {
+	path := writeTemp(t, degreesTOML)
+	if err := SetValue(path, "education", "degrees[2].degree", "MS"); err != nil {
+		t.Fatal(err)
+	}
+	db, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, ok := db.GetField("education.degrees[2].degree")
+	if !ok || f.Value != "MS" {
+		t.Errorf("expected appended element, got %+v\n%s", f, readTemp(t, path))
+	}
+	if !strings.Contains(readTemp(t, path), "degree = \"BS\"\n\n[[education.degrees]]\ndegree = \"MS\"\n\n[web]") {
+		t.Errorf("expected new section after the last element:\n%s", readTemp(t, path))
+	}
+
+	if err := SetValue(path, "education", "degrees[7].degree", "MA"); err == nil {
+		t.Error("expected error for an index past the end")
+	}
+}
+
+func TestSetValue_NewArrayOfTables(t *testing.T) {
+	path := writeTemp(t, "[identity]\nname = \"Alex\"\n")
+	if err := SetValue(path, "employment", "positions[0].title", "Engineer"); err != nil {
+		t.Fatal(err)
+	}
+	want := "[identity]\nname = \"Alex\"\n\n[[employment.positions]]\ntitle = \"Engineer\"\n"
+	if got := readTemp(t, path); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetValue_RejectsWholeArrayOfTables(t *testing.T) {
+	path := writeTemp(t, degreesTOML)
+	if err := SetValue(path, "education", "degrees", `["PhD"]`); err == nil {
+		t.Error("expected error replacing [[education.degrees]] with an inline array")
+	}
+	if err := SetValue(path, "education", "degrees[0]", "x"); err == nil {
+		t.Error("expected error setting a whole element")
+	}
+	if got := readTemp(t, path); got != degreesTOML {
+		t.Errorf("file changed:\n%s", got)
+	}
+
+	inline := writeTemp(t, "[education]\ndegrees = [{ degree = \"PhD\" }]\n")
+	if err := SetValue(inline, "education", "degrees[0].year", "2020"); err == nil {
+		t.Error("expected error editing an element of an inline array")
+	}
+}
+
+func TestRemoveValue_ArrayOfTables(t *testing.T) {
+	path := writeTemp(t, degreesTOML)
+	if err := RemoveValue(path, "education", "degrees[0].year"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveValue(path, "education", "degrees[1]"); err != nil {
+		t.Fatal(err)
+	}
+	want := `[education]
+field = "Statistics"
+
+[[education.degrees]]
+degree = "PhD"
+
+[web]
+github = "q"
+`
+	if got := readTemp(t, path); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if err := RemoveValue(path, "education", "degrees[3]"); err == nil {
+		t.Error("expected error for a missing element")
+	}
+	if err := RemoveValue(path, "education", "degrees[0].missing"); err == nil {
+		t.Error("expected error for a missing key")
+	}
+}
+
+func TestSplitElement(t *testing.T) {
+	cases := []struct {
+		key   string
+		name  string
+		index int
+		rest  string
+		ok    bool
+	}{
+		{"degrees[1].institution", "degrees", 1, "institution", true},
+		{"degrees[-1]", "degrees", -1, "", true},
+		{"phd.courses[0].name", "phd.courses", 0, "name", true},
+		{"degrees", "", 0, "", false},
+		{"degrees[x].a", "", 0, "", false},
+		{"degrees[1]_desc", "", 0, "", false},
+	}
+	for _, c := range cases {
+		name, index, rest, ok := splitElement(c.key)
+		if name != c.name || index != c.index || rest != c.rest || ok != c.ok {
+			t.Errorf("splitElement(%q) = %q, %d, %q, %v", c.key, name, index, rest, ok)
+		}
+	}
+}
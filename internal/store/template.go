@@ -1,5 +1,10 @@
 package store
 
+import (
+	"sort"
+	"strings"
+)
+
 // DefaultTemplate is the default me.toml content for `deets init`.
 const DefaultTemplate = `# deets — Personal metadata
 # Edit this file to add your personal details.
@@ -31,10 +36,20 @@ const DefaultTemplate = `# deets — Personal metadata
 # research_interests = ["topic1", "topic2"]
 
 [education]
-# degrees = ["BS Computer Science (University, 2020)"]
-# degrees_desc = "Completed degrees with institution and year"
 # field = "Computer Science"
 # institution = "University of..."
+#
+# [[education.degrees]]
+# degree = "BS"
+# field = "Computer Science"
+# institution = "University of..."
+# year = 2020
+
+# [[employment.positions]]
+# title = "Software Engineer"
+# organization = "Example Corp"
+# start = "2020"
+# end = "2023"
 `
 
 // LocalTemplate is the minimal template for local overrides.
@@ -76,4 +91,69 @@ var DefaultDescriptions = map[string]map[string]string{
 		"field":       "Primary field of study",
 		"institution": "Degree-granting institution",
 	},
+	"employment": {
+		"positions": "Employment history with organization and dates",
+	},
+}
+
+// DefaultField is a well-known field from DefaultDescriptions.
+type DefaultField struct {
+	Category string
+	Key      string
+	Desc     string
+	// Kind is "string", "list" for arrays of strings, or "records" for
+	// arrays of tables such as [[education.degrees]].
+	Kind string
+}
+
+// defaultCategoryOrder is the order of categories in DefaultTemplate.
+var defaultCategoryOrder = []string{"identity", "contact", "web", "academic", "education", "employment"}
+
+// templateKeys returns, for each table in DefaultTemplate, the keys it
+// shows (commented out) in order, leaving out _desc keys.
+func templateKeys() map[string][]string {
+	keys := make(map[string][]string)
+	section := ""
+	for _, line := range strings.Split(DefaultTemplate, "\n") {
+		text := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		if strings.HasPrefix(text, "[") {
+			section = strings.Trim(text, "[]")
+			continue
+		}
+		key, _, ok := strings.Cut(text, " = ")
+		if !ok || section == "" || strings.ContainsAny(key, " #") || strings.HasSuffix(key, "_desc") {
+			continue
+		}
+		keys[section] = append(keys[section], key)
+	}
+	return keys
+}
+
+// defaultFieldKinds lists the well-known fields that are not plain strings.
+var defaultFieldKinds = map[string]string{
+	"identity.aka":                "list",
+	"academic.research_interests": "list",
+	"education.degrees":           "records",
+	"employment.positions":        "records",
+}
+
+// DefaultFields returns the fields of DefaultDescriptions with categories in
+// template order and keys sorted within each category.
+func DefaultFields() []DefaultField {
+	var fields []DefaultField
+	for _, cat := range defaultCategoryOrder {
+		keys := make([]string, 0, len(DefaultDescriptions[cat]))
+		for k := range DefaultDescriptions[cat] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			kind := defaultFieldKinds[cat+"."+k]
+			if kind == "" {
+				kind = "string"
+			}
+			fields = append(fields, DefaultField{cat, k, DefaultDescriptions[cat][k], kind})
+		}
+	}
+	return fields
 }
@@ -216,3 +216,17 @@ func TestDefaultDescriptions_EducationCategoryContents(t *testing.T) {
 		t.Errorf("expected education.institution = 'Degree-granting institution', got %q", education["institution"])
 	}
 }
+
+func TestDefaultFields_CoversDescriptions(t *testing.T) {
+	fields := DefaultFields()
+	count := 0
+	for _, keys := range DefaultDescriptions {
+		count += len(keys)
+	}
+	if len(fields) != count {
+		t.Fatalf("DefaultFields returned %d fields, DefaultDescriptions has %d (missing category order?)", len(fields), count)
+	}
+	if fields[0].Category != "identity" || fields[0].Key != "aka" || fields[0].Kind != "list" {
+		t.Errorf("first field = %+v, want identity.aka list", fields[0])
+	}
+}
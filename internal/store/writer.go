@@ -3,82 +3,195 @@ package store
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/queelius/deets/internal/model"
 )
 
 // SetValue sets a value for the given key within the specified category in the
 // TOML file at filePath. If the file does not exist it is created. If the
 // category or key does not exist it is appended. Existing lines, comments, and
 // formatting are preserved.
+//
+// A dotted key such as "phd.institution" is written into an existing
+// [category.phd] sub-table when there is one, into the inline table when
+// the category holds phd = { ... }, and as a TOML dotted key under
+// [category] otherwise. A key such as "degrees[1].year" is written
+// into the second [[category.degrees]] section; the index one past the
+// last section appends a new one.
 func SetValue(filePath, category, key, value string) error {
-	lines, err := readLines(filePath)
+	return SetLiteral(filePath, category, key, formatValue(value))
+}
+
+// SetLiteral is like SetValue but writes literal, which must already be a
+// TOML value such as 42, true, "text", or {a = 1}, without quoting it.
+func SetLiteral(filePath, category, key, literal string) error {
+	return SetLiterals(filePath, []Assignment{{category, key, literal}})
+}
+
+// Assignment is one key to set by SetLiterals. Literal is a TOML value
+// literal, as for SetLiteral.
+type Assignment struct {
+	Category string
+	Key      string
+	Literal  string
+}
+
+// SetLiterals applies each assignment in order as SetLiteral would, reading
+// and writing the file only once. Nothing is written if any category or
+// key is not a bare TOML key or any literal is not exactly one TOML value,
+// so a value can never smuggle extra keys or tables into the file.
+func SetLiterals(filePath string, assignments []Assignment) error {
+	for _, a := range assignments {
+		if err := checkAssignment(a); err != nil {
+			return err
+		}
+	}
+
+	unlock, err := lockFile(filePath)
 	if err != nil {
-		if !os.IsNotExist(err) {
+		return err
+	}
+	defer unlock()
+
+	lines, err := readLines(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, a := range assignments {
+		if lines, err = setLine(lines, a.Category, a.Key, a.Literal); err != nil {
 			return err
 		}
-		// File does not exist — create with section and key.
-		lines = []string{
-			fmt.Sprintf("[%s]", category),
-			fmt.Sprintf("%s = %s", key, formatValue(value)),
+	}
+	return writeLines(filePath, lines)
+}
+
+// setLine sets key in category to literal within lines and returns the
+// updated lines. Keys such as "degrees[1].year" address an element of an
+// array of tables (see setElement).
+func setLine(lines []string, category, key, literal string) ([]string, error) {
+	if name, index, rest, ok := splitElement(key); ok {
+		if rest == "" {
+			return nil, fmt.Errorf("cannot set %s.%s as a whole; set its keys, as in %s.%s.key", category, key, category, key)
 		}
-		return writeLines(filePath, lines)
+		return setElement(lines, category, name, index, rest, literal)
 	}
+	inValue := continuation(lines)
+	if len(arrayTables(lines, inValue, category+"."+key)) > 0 {
+		return nil, fmt.Errorf("%s.%s is an array of tables; set or remove its elements as %s.%s[N].key", category, key, category, key)
+	}
+
+	category, key = nestedSection(lines, inValue, category, key)
+	newLine := fmt.Sprintf("%s = %s", key, literal)
 
-	formatted := formatValue(value)
-	sectionIdx := findSection(lines, category)
+	sectionIdx := findSection(lines, inValue, category)
 
 	if sectionIdx == -1 {
 		// Category does not exist — append it.
 		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
 			lines = append(lines, "")
 		}
-		lines = append(lines, fmt.Sprintf("[%s]", category))
-		lines = append(lines, fmt.Sprintf("%s = %s", key, formatted))
-		return writeLines(filePath, lines)
+		return append(lines, fmt.Sprintf("[%s]", category), newLine), nil
+	}
+	if idx, rest := inlineTable(lines, inValue, sectionIdx+1, findNextSection(lines, inValue, sectionIdx), key); idx != -1 {
+		return setInline(lines, idx, rest, literal, false)
 	}
+	return setInSection(lines, inValue, sectionIdx, key, newLine), nil
+}
 
-	// Category exists — look for the key within it.
-	nextSection := findNextSection(lines, sectionIdx)
-	keyIdx := findKey(lines, sectionIdx+1, nextSection, key)
+// setInSection replaces key's line in the section whose header is at
+// sectionIdx with newLine, or adds newLine to the section if key is not
+// there yet. inValue is continuation(lines).
+func setInSection(lines []string, inValue []bool, sectionIdx int, key, newLine string) []string {
+	nextSection := findNextSection(lines, inValue, sectionIdx)
+	keyIdx := findKey(lines, inValue, sectionIdx+1, nextSection, key)
 
 	if keyIdx != -1 {
-		// Key exists — replace the line.
-		lines[keyIdx] = fmt.Sprintf("%s = %s", key, formatted)
-	} else {
-		// Key does not exist — insert before the next section (or EOF).
-		insertAt := nextSection
-		newLine := fmt.Sprintf("%s = %s", key, formatted)
-		lines = append(lines[:insertAt], append([]string{newLine}, lines[insertAt:]...)...)
+		// Key exists — replace its line, or all lines of a multi-line value.
+		end := valueEnd(lines, keyIdx)
+		lines = append(lines[:keyIdx+1], lines[end:]...)
+		lines[keyIdx] = newLine
+		return lines
 	}
+	// Key does not exist — insert after the section's last line, ahead of
+	// the blank lines that separate it from the next section (or EOF).
+	insertAt := sectionEnd(lines, inValue, sectionIdx)
+	return append(lines[:insertAt], append([]string{newLine}, lines[insertAt:]...)...)
+}
 
-	return writeLines(filePath, lines)
+// AddCategory appends a [category] section holding body, which may be all
+// comments, to the TOML file at filePath, creating the file if needed.
+// Returns an error if the category already exists.
+func AddCategory(filePath, category string, body []string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	lines, err := readLines(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if findSection(lines, continuation(lines), category) != -1 {
+		return fmt.Errorf("category %q already exists in %s", category, filePath)
+	}
+	if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+		lines = append(lines, "")
+	}
+	lines = append(lines, fmt.Sprintf("[%s]", category))
+	return writeLines(filePath, append(lines, body...))
 }
 
 // RemoveValue removes a key from the specified category in the TOML file at
 // filePath. If the category becomes empty (no keys left), the section header
-// is also removed. Returns an error if the key is not found.
+// is also removed. Returns an error if the key is not found. Dotted keys
+// are resolved against sub-tables and inline tables as in SetValue, and
+// "name[i]" removes element i of the [[category.name]] array of tables.
 func RemoveValue(filePath, category, key string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	lines, err := readLines(filePath)
 	if err != nil {
 		return err
 	}
+	if name, index, rest, ok := splitElement(key); ok {
+		if lines, err = removeElement(lines, category, name, index, rest); err != nil {
+			return fmt.Errorf("%w in %s", err, filePath)
+		}
+		return writeLines(filePath, lines)
+	}
+	inValue := continuation(lines)
+	category, key = nestedSection(lines, inValue, category, key)
 
-	sectionIdx := findSection(lines, category)
+	sectionIdx := findSection(lines, inValue, category)
 	if sectionIdx == -1 {
 		return fmt.Errorf("category %q not found in %s", category, filePath)
 	}
 
-	nextSection := findNextSection(lines, sectionIdx)
-	keyIdx := findKey(lines, sectionIdx+1, nextSection, key)
+	nextSection := findNextSection(lines, inValue, sectionIdx)
+	if idx, rest := inlineTable(lines, inValue, sectionIdx+1, nextSection, key); idx != -1 {
+		if lines, err = setInline(lines, idx, rest, "", true); err != nil {
+			return fmt.Errorf("%w in %s", err, filePath)
+		}
+		return writeLines(filePath, lines)
+	}
+	keyIdx := findKey(lines, inValue, sectionIdx+1, nextSection, key)
 	if keyIdx == -1 {
 		return fmt.Errorf("key %q not found in category %q in %s", key, category, filePath)
 	}
 
-	// Remove the key line.
-	lines = append(lines[:keyIdx], lines[keyIdx+1:]...)
+	// Remove the key line, and the rest of a multi-line value.
+	lines = append(lines[:keyIdx], lines[valueEnd(lines, keyIdx):]...)
 
 	// Check if the category is now empty (no non-blank, non-comment, non-section lines).
-	nextSection = findNextSection(lines, sectionIdx)
+	nextSection = findNextSection(lines, continuation(lines), sectionIdx)
 	empty := true
 	for i := sectionIdx + 1; i < nextSection; i++ {
 		trimmed := strings.TrimSpace(lines[i])
@@ -97,25 +210,52 @@ func RemoveValue(filePath, category, key string) error {
 }
 
 // RemoveCategory removes an entire category (header and all lines until the
-// next section or EOF) from the TOML file at filePath. Returns an error if
-// the category is not found.
+// next section or EOF) from the TOML file at filePath, together with its
+// sub-tables such as [category.sub] and [[category.sub]]. Returns an error
+// if the category is not found.
 func RemoveCategory(filePath, category string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	lines, err := readLines(filePath)
 	if err != nil {
 		return err
 	}
 
-	sectionIdx := findSection(lines, category)
-	if sectionIdx == -1 {
+	found := false
+	for i := 0; i < len(lines); {
+		if !inCategory(lines[i], category) {
+			i++
+			continue
+		}
+		found = true
+		next := findNextSection(lines, continuation(lines), i)
+		lines = append(lines[:i], lines[next:]...)
+	}
+	if !found {
 		return fmt.Errorf("category %q not found in %s", category, filePath)
 	}
 
-	nextSection := findNextSection(lines, sectionIdx)
-	lines = append(lines[:sectionIdx], lines[nextSection:]...)
-
 	return writeLines(filePath, lines)
 }
 
+// inCategory reports whether line is the header of category or of one of
+// its sub-tables.
+func inCategory(line, category string) bool {
+	trimmed := strings.TrimSpace(line)
+	name := strings.TrimSuffix(strings.TrimPrefix(trimmed, "["), "]")
+	if strings.HasPrefix(trimmed, "[[") {
+		name = strings.TrimSuffix(strings.TrimPrefix(trimmed, "[["), "]]")
+	}
+	if name == trimmed {
+		return false
+	}
+	return name == category || strings.HasPrefix(name, category+".")
+}
+
 // readLines reads the file at path and returns its content split into lines.
 func readLines(path string) ([]string, error) {
 	data, err := os.ReadFile(path)
@@ -131,31 +271,105 @@ func readLines(path string) ([]string, error) {
 	return strings.Split(content, "\n"), nil
 }
 
-// writeLines writes the given lines back to the file at path with 0644
-// permissions. A trailing newline is appended.
+// writeLines writes the given lines back to the file at path with
+// writeFile. A trailing newline is appended. The caller holds the lock.
 func writeLines(path string, lines []string) error {
 	content := strings.Join(lines, "\n") + "\n"
-	return os.WriteFile(path, []byte(content), 0644)
+	return writeFile(path, []byte(content))
+}
+
+// WriteFile atomically replaces the content of path with data, backing up
+// its previous content first when backups are enabled.
+func WriteFile(path string, data []byte) error {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeFile(path, data)
+}
+
+// writeFile is WriteFile for callers already holding the lock on path.
+func writeFile(path string, data []byte) error {
+	if err := backup(path); err != nil {
+		return fmt.Errorf("backing up %s: %w", path, err)
+	}
+	return writeAtomic(path, data)
+}
+
+// writeAtomic writes data to a temporary file in path's directory, syncs
+// it, and renames it over path, so a crash leaves either the old content
+// or the new, never a truncated file. An existing file's mode is kept; a
+// new file gets 0644. A symlink is followed, so a me.toml linked from a
+// dotfiles repository stays a link.
+func writeAtomic(path string, data []byte) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Once renamed, the temporary name no longer exists and Remove is a no-op.
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
 
 // findSection returns the line index of the [category] header in lines,
-// or -1 if the section is not found.
-func findSection(lines []string, category string) int {
+// or -1 if the section is not found. inValue is continuation(lines), as it
+// is for the other find helpers, computed once by the caller.
+func findSection(lines []string, inValue []bool, category string) int {
 	target := fmt.Sprintf("[%s]", category)
 	for i, line := range lines {
-		if strings.TrimSpace(line) == target {
+		if !inValue[i] && strings.TrimSpace(line) == target {
 			return i
 		}
 	}
 	return -1
 }
 
+// nestedSection maps a dotted key onto the deepest existing sub-table
+// header: with a [education.phd] section present, ("education",
+// "phd.institution") becomes ("education.phd", "institution"). Keys with no
+// matching sub-table are returned unchanged.
+func nestedSection(lines []string, inValue []bool, category, key string) (string, string) {
+	parts := strings.Split(key, ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		section := category + "." + strings.Join(parts[:i], ".")
+		if findSection(lines, inValue, section) != -1 {
+			return section, strings.Join(parts[i:], ".")
+		}
+	}
+	return category, key
+}
+
 // findNextSection returns the line index of the next [section] header after
 // afterLine, or len(lines) if no subsequent section is found.
-func findNextSection(lines []string, afterLine int) int {
+func findNextSection(lines []string, inValue []bool, afterLine int) int {
 	for i := afterLine + 1; i < len(lines); i++ {
 		trimmed := strings.TrimSpace(lines[i])
-		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+		if !inValue[i] && strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
 			return i
 		}
 	}
@@ -165,8 +379,11 @@ func findNextSection(lines []string, afterLine int) int {
 // findKey searches for a line matching "key = " (with optional whitespace)
 // between indices start (inclusive) and end (exclusive). Returns the line
 // index or -1 if not found.
-func findKey(lines []string, start, end int, key string) int {
+func findKey(lines []string, inValue []bool, start, end int, key string) int {
 	for i := start; i < end; i++ {
+		if inValue[i] {
+			continue
+		}
 		trimmed := strings.TrimSpace(lines[i])
 		// Match "key = ..." or "key=..."
 		if strings.HasPrefix(trimmed, key) {
@@ -180,16 +397,73 @@ func findKey(lines []string, start, end int, key string) int {
 	return -1
 }
 
-// formatValue formats a value for TOML output. If the value starts with "[",
-// it is treated as an array literal and written as-is. If it starts with a
-// double quote, it is assumed to be already quoted. Otherwise, the value is
-// wrapped in double quotes.
+// continuation marks the lines that carry on a value begun on an earlier
+// line, such as the body of a """ string or a multi-line array. It walks
+// keys the way parseDocument does, so text inside a value that looks like a
+// header or a key is never mistaken for one.
+func continuation(lines []string) []bool {
+	inValue := make([]bool, len(lines))
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "[") {
+			continue
+		}
+		end := valueEnd(lines, i)
+		for j := i + 1; j < end; j++ {
+			inValue[j] = true
+		}
+		i = end - 1
+	}
+	return inValue
+}
+
+// formatValue formats a value for TOML output. A value starting with "["
+// or a double quote that is already a complete TOML array or string is
+// written as-is. Anything else is encoded as a string, as a multi-line """
+// string when it contains newlines.
 func formatValue(value string) string {
-	if strings.HasPrefix(value, "[") {
+	if (strings.HasPrefix(value, "[") || strings.HasPrefix(value, "\"")) && isTOMLValue(value) {
 		return value
 	}
-	if strings.HasPrefix(value, "\"") {
-		return value
+	return model.TOMLString(value)
+}
+
+// isTOMLValue reports whether literal is exactly one TOML value, with
+// nothing after it that would add keys or tables.
+func isTOMLValue(literal string) bool {
+	var probe map[string]interface{}
+	if strings.TrimSpace(literal) == "" || toml.Unmarshal([]byte("v = "+literal), &probe) != nil {
+		return false
+	}
+	_, ok := probe["v"]
+	return ok && len(probe) == 1
+}
+
+// checkAssignment rejects an assignment that would not write exactly the
+// one key it names.
+func checkAssignment(a Assignment) error {
+	path := a.Category + "." + a.Key
+	for _, part := range strings.Split(path, ".") {
+		part, _, _ = model.SplitIndex(part)
+		if !isBareKey(part) {
+			return fmt.Errorf("invalid key %q: use letters, digits, '_' and '-' separated by '.'", path)
+		}
+	}
+	if !isTOMLValue(a.Literal) {
+		return fmt.Errorf("invalid TOML value for %s: %s", path, a.Literal)
+	}
+	return nil
+}
+
+// isBareKey reports whether s can be written as an unquoted TOML key.
+func isBareKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
 	}
-	return fmt.Sprintf("%q", value)
+	return true
 }
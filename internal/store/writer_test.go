@@ -403,6 +403,148 @@ func TestRemoveValue_FileNotFound(t *testing.T) {
 	}
 }
 
+func TestSetLiteral_WritesVerbatim(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "me.toml")
+	if err := os.WriteFile(path, []byte("[academic]\ngpa = \"old\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetLiteral(path, "academic", "gpa", "3.95"); err != nil {
+		t.Fatalf("SetLiteral returned error: %v", err)
+	}
+	if err := SetLiteral(path, "academic", "advisor", `{name = "Bob"}`); err != nil {
+		t.Fatalf("SetLiteral returned error: %v", err)
+	}
+
+	db, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("result does not parse: %v", err)
+	}
+	if f, _ := db.GetField("academic.gpa"); f.Value != 3.95 {
+		t.Errorf("gpa = %#v, want float 3.95", f.Value)
+	}
+	if f, _ := db.GetField("academic.advisor.name"); f.Value != "Bob" {
+		t.Errorf("advisor.name = %#v, want Bob", f.Value)
+	}
+}
+
+func TestSetValue_MultiLine(t *testing.T) {
+	path := writeTemp(t, "[identity]\nname = \"Alex\"\n")
+	bio := "Line one.\nShe said \"hi\" and \\ left.\nEnds with a quote\""
+
+	if err := SetValue(path, "identity", "bio", bio); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+	if !strings.Contains(readTemp(t, path), "bio = \"\"\"\nLine one.\n") {
+		t.Errorf("expected a multi-line block, got:\n%s", readTemp(t, path))
+	}
+	db, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("result does not parse: %v\n%s", err, readTemp(t, path))
+	}
+	if f, _ := db.GetField("identity.bio"); f.Value != bio {
+		t.Errorf("bio = %q, want %q", f.Value, bio)
+	}
+
+	// Replacing and removing take out every line of the block.
+	if err := SetValue(path, "identity", "bio", "short\nbio"); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+	if err := SetValue(path, "identity", "email", "a@b.c"); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+	if db, err = LoadFile(path); err != nil {
+		t.Fatalf("result does not parse: %v\n%s", err, readTemp(t, path))
+	}
+	if f, _ := db.GetField("identity.bio"); f.Value != "short\nbio" {
+		t.Errorf("bio = %q after replace", f.Value)
+	}
+	if err := RemoveValue(path, "identity", "bio"); err != nil {
+		t.Fatalf("RemoveValue returned error: %v", err)
+	}
+	if got, want := readTemp(t, path), "[identity]\nname = \"Alex\"\nemail = \"a@b.c\"\n"; got != want {
+		t.Errorf("after remove got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetValue_MultiLineBodyLooksLikeKey(t *testing.T) {
+	path := writeTemp(t, "[identity]\nname = \"Alex\"\n")
+	bio := "hi\nphone = 5\nbye"
+	if err := SetValue(path, "identity", "bio", bio); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+	if err := SetValue(path, "identity", "phone", "7"); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+
+	db, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("result does not parse: %v\n%s", err, readTemp(t, path))
+	}
+	if f, _ := db.GetField("identity.bio"); f.Value != bio {
+		t.Errorf("bio = %q, want %q", f.Value, bio)
+	}
+	if f, ok := db.GetField("identity.phone"); !ok || f.Value != "7" {
+		t.Errorf("phone = %#v, want \"7\"\n%s", f.Value, readTemp(t, path))
+	}
+}
+
+func TestSetValue_MultiLineBodyLooksLikeHeader(t *testing.T) {
+	path := writeTemp(t, "[identity]\nname = \"Alex\"\n")
+	bio := "hi\n[work]\nbye"
+	if err := SetValue(path, "identity", "bio", bio); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+	if err := SetValue(path, "identity", "email", "a@b.c"); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+	if err := SetValue(path, "work", "title", "Engineer"); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+
+	db, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("result does not parse: %v\n%s", err, readTemp(t, path))
+	}
+	want := map[string]string{
+		"identity.bio":   bio,
+		"identity.email": "a@b.c",
+		"work.title":     "Engineer",
+	}
+	for p, v := range want {
+		if f, _ := db.GetField(p); f.Value != v {
+			t.Errorf("%s = %#v, want %q\n%s", p, f.Value, v, readTemp(t, path))
+		}
+	}
+	if _, ok := db.GetField("identity.title"); ok {
+		t.Errorf("work.title landed in [identity]:\n%s", readTemp(t, path))
+	}
+}
+
+// --- AddCategory tests ---
+
+func TestAddCategory(t *testing.T) {
+	path := writeTemp(t, "[identity]\nname = \"Alice\"\n")
+	if err := AddCategory(path, "cooking", []string{"# cuisine = \"\""}); err != nil {
+		t.Fatalf("AddCategory returned error: %v", err)
+	}
+	if got, want := readTemp(t, path), "[identity]\nname = \"Alice\"\n\n[cooking]\n# cuisine = \"\"\n"; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if err := AddCategory(path, "identity", nil); err == nil {
+		t.Error("expected error for an existing category")
+	}
+
+	fresh := filepath.Join(t.TempDir(), "me.toml")
+	if err := AddCategory(fresh, "web", nil); err != nil {
+		t.Fatalf("AddCategory on a new file returned error: %v", err)
+	}
+	if got := readTemp(t, fresh); got != "[web]\n" {
+		t.Errorf("got %q for a new file", got)
+	}
+}
+
 // --- RemoveCategory tests ---
 
 func TestRemoveCategory_RemoveExisting(t *testing.T) {
@@ -497,6 +639,48 @@ name = "Alice"
 	}
 }
 
+func TestRemoveCategory_SubTables(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "me.toml")
+
+	initial := `[education]
+field = "Statistics"
+
+[education.phd]
+institution = "SIUE"
+
+[[education.degrees]]
+title = "BS"
+
+[educational]
+kept = true
+`
+	if err := os.WriteFile(path, []byte(initial), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveCategory(path, "education"); err != nil {
+		t.Fatalf("RemoveCategory returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := strings.TrimSpace(string(data))
+	if content != "[educational]\nkept = true" {
+		t.Errorf("expected only [educational] to remain, got:\n%s", content)
+	}
+
+	// A category made only of sub-tables is still found.
+	if err := os.WriteFile(path, []byte("[work.acme]\nrole = \"dev\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveCategory(path, "work"); err != nil {
+		t.Errorf("RemoveCategory on sub-table-only category: %v", err)
+	}
+}
+
 // --- formatValue tests ---
 
 func TestFormatValue_PlainString(t *testing.T) {
@@ -524,18 +708,19 @@ func TestFormatValue_AlreadyQuoted(t *testing.T) {
 
 func TestFindSection(t *testing.T) {
 	lines := []string{"[identity]", "name = \"Alice\"", "", "[contact]", "email = \"a@b.com\""}
+	inValue := continuation(lines)
 
-	idx := findSection(lines, "identity")
+	idx := findSection(lines, inValue, "identity")
 	if idx != 0 {
 		t.Errorf("expected index 0 for [identity], got %d", idx)
 	}
 
-	idx = findSection(lines, "contact")
+	idx = findSection(lines, inValue, "contact")
 	if idx != 3 {
 		t.Errorf("expected index 3 for [contact], got %d", idx)
 	}
 
-	idx = findSection(lines, "nonexistent")
+	idx = findSection(lines, inValue, "nonexistent")
 	if idx != -1 {
 		t.Errorf("expected -1 for nonexistent section, got %d", idx)
 	}
@@ -543,14 +728,15 @@ func TestFindSection(t *testing.T) {
 
 func TestFindNextSection(t *testing.T) {
 	lines := []string{"[identity]", "name = \"Alice\"", "", "[contact]", "email = \"a@b.com\""}
+	inValue := continuation(lines)
 
-	idx := findNextSection(lines, 0)
+	idx := findNextSection(lines, inValue, 0)
 	if idx != 3 {
 		t.Errorf("expected next section at 3, got %d", idx)
 	}
 
 	// After last section, should return len(lines).
-	idx = findNextSection(lines, 3)
+	idx = findNextSection(lines, inValue, 3)
 	if idx != len(lines) {
 		t.Errorf("expected len(lines) = %d, got %d", len(lines), idx)
 	}
@@ -558,18 +744,19 @@ func TestFindNextSection(t *testing.T) {
 
 func TestFindKey(t *testing.T) {
 	lines := []string{"[identity]", "name = \"Alice\"", "pronouns = \"she/her\""}
+	inValue := continuation(lines)
 
-	idx := findKey(lines, 1, 3, "name")
+	idx := findKey(lines, inValue, 1, 3, "name")
 	if idx != 1 {
 		t.Errorf("expected index 1 for 'name', got %d", idx)
 	}
 
-	idx = findKey(lines, 1, 3, "pronouns")
+	idx = findKey(lines, inValue, 1, 3, "pronouns")
 	if idx != 2 {
 		t.Errorf("expected index 2 for 'pronouns', got %d", idx)
 	}
 
-	idx = findKey(lines, 1, 3, "nonexistent")
+	idx = findKey(lines, inValue, 1, 3, "nonexistent")
 	if idx != -1 {
 		t.Errorf("expected -1 for nonexistent key, got %d", idx)
 	}
@@ -632,3 +819,148 @@ func TestWriteLines_AppendsNewline(t *testing.T) {
 		t.Errorf("expected %q, got %q", expected, content)
 	}
 }
+
+func TestSetValue_NestedSection(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "me.toml")
+
+	initial := `[education]
+field = "Statistics"
+
+[education.phd]
+institution = "SIUE"
+`
+	if err := os.WriteFile(path, []byte(initial), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetValue(path, "education", "phd.year", "2020"); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+	if err := SetValue(path, "education", "msc.year", "2015"); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !strings.Contains(content, "[education.phd]\ninstitution = \"SIUE\"\nyear = \"2020\"\n") {
+		t.Errorf("expected year in [education.phd], got:\n%s", content)
+	}
+	if i := strings.Index(content, `msc.year = "2015"`); i == -1 || i > strings.Index(content, "[education.phd]") {
+		t.Errorf("expected dotted key under [education], got:\n%s", content)
+	}
+
+	if err := RemoveValue(path, "education", "phd.institution"); err != nil {
+		t.Fatalf("RemoveValue returned error: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "SIUE") {
+		t.Errorf("expected institution removed, got:\n%s", data)
+	}
+}
+
+// --- WriteFile tests ---
+
+func TestWriteFile_AtomicKeepsMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "me.toml")
+	if err := os.WriteFile(path, []byte("[a]\nk = 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetValue(path, "a", "k", "2"); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary files left, got %d entries", len(entries))
+	}
+
+	fresh := filepath.Join(dir, "new.toml")
+	if err := WriteFile(fresh, []byte("[b]\n")); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(fresh); info.Mode().Perm() != 0644 {
+		t.Errorf("new file mode = %v, want 0644", info.Mode().Perm())
+	}
+}
+
+func TestWriteFile_FollowsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles.toml")
+	link := filepath.Join(dir, "me.toml")
+	os.WriteFile(target, []byte("[a]\nk = 1\n"), 0644)
+	if err := os.Symlink(target, link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if err := SetValue(link, "a", "k", "2"); err != nil {
+		t.Fatalf("SetValue returned error: %v", err)
+	}
+	if info, _ := os.Lstat(link); info.Mode()&os.ModeSymlink == 0 {
+		t.Error("the link should not be replaced by a regular file")
+	}
+	if got := readTemp(t, target); got != "[a]\nk = \"2\"\n" {
+		t.Errorf("target = %q", got)
+	}
+}
+
+func TestWriteFile_MissingDirectory(t *testing.T) {
+	err := WriteFile(filepath.Join(t.TempDir(), "no", "such", "me.toml"), []byte("x"))
+	if err == nil {
+		t.Fatal("expected error for a missing directory")
+	}
+}
+
+func TestSetValue_CannotInjectTOML(t *testing.T) {
+	values := []string{
+		`a"]` + "\n[evil]\nx = 1",
+		`"unterminated`,
+		`"quoted" = 1`,
+		`[1]` + "\nevil = true",
+		"bell\a",
+	}
+	for _, value := range values {
+		path := filepath.Join(t.TempDir(), "me.toml")
+		if err := SetValue(path, "identity", "name", value); err != nil {
+			t.Fatalf("SetValue(%q) returned error: %v", value, err)
+		}
+		db, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("SetValue(%q) wrote invalid TOML: %v", value, err)
+		}
+		if len(db.Categories) != 1 || len(db.Categories[0].Fields) != 1 {
+			t.Fatalf("SetValue(%q) wrote extra keys: %+v", value, db.Categories)
+		}
+		if got := db.Categories[0].Fields[0].Value; got != value {
+			t.Errorf("SetValue(%q) read back %q", value, got)
+		}
+	}
+}
+
+func TestSetLiterals_RejectsInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "me.toml")
+	bad := []Assignment{
+		{"identity", "name", "1\nevil = true"},
+		{"identity", "name", `"open`},
+		{"identity", "name", ""},
+		{"identity]\n[evil", "name", `"x"`},
+		{"identity", "na me", `"x"`},
+	}
+	for _, a := range bad {
+		if err := SetLiterals(path, []Assignment{{"identity", "ok", "1"}, a}); err == nil {
+			t.Errorf("SetLiterals accepted %+v", a)
+		}
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected nothing written after rejected assignments")
+	}
+}
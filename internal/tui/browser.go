@@ -0,0 +1,450 @@
+// Package tui implements deets' interactive terminal browser: a category
+// bar, a fuzzy-filtered field list, the selected field's description, and
+// inline editing. The Browser type holds all state and renders plain text,
+// so it can be driven and inspected without a terminal; Run connects it to
+// one.
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/queelius/deets/internal/model"
+)
+
+type mode int
+
+const (
+	browsing mode = iota
+	filtering
+	editing
+)
+
+// Browser is the state of the interactive browser.
+type Browser struct {
+	// Save writes input as the new value of f. It is expected to interpret
+	// input the way EditText presented it and to return an error, shown
+	// in the status line, when input is not acceptable.
+	Save func(f model.Field, input string) error
+	// Load rereads the database after a successful save.
+	Load func() (*model.DB, error)
+	// Raw returns f as written in the file Save writes to, so a template
+	// is edited rather than the value it resolves to. When it is nil, or
+	// reports false, f is edited as loaded.
+	Raw func(f model.Field) (model.Field, bool)
+
+	db      *model.DB
+	cats    []string // category names; index 0 ("") is all categories
+	cat     int
+	filter  []rune
+	visible []model.Field
+	cursor  int
+	offset  int
+	mode    mode
+	input   []rune
+	edit    model.Field // the field being edited, as Raw returned it
+	status  string
+	width   int
+	height  int
+}
+
+// NewBrowser returns a browser over db sized for an 80x24 terminal.
+func NewBrowser(db *model.DB) *Browser {
+	b := &Browser{width: 80, height: 24}
+	b.SetDB(db)
+	return b
+}
+
+// SetDB replaces the database being browsed, keeping the selected
+// category and filter where possible.
+func (b *Browser) SetDB(db *model.DB) {
+	current := ""
+	if b.cat < len(b.cats) {
+		current = b.cats[b.cat]
+	}
+	b.db = db
+	b.cats = []string{""}
+	b.cat = 0
+	for _, c := range db.Categories {
+		b.cats = append(b.cats, c.Name)
+		if c.Name == current {
+			b.cat = len(b.cats) - 1
+		}
+	}
+	b.refresh()
+}
+
+// Resize sets the screen size used by View.
+func (b *Browser) Resize(width, height int) {
+	if width > 0 {
+		b.width = width
+	}
+	if height > 0 {
+		b.height = height
+	}
+	b.scroll()
+}
+
+// Selected returns the field under the cursor.
+func (b *Browser) Selected() (model.Field, bool) {
+	if b.cursor < len(b.visible) {
+		return b.visible[b.cursor], true
+	}
+	return model.Field{}, false
+}
+
+// EditText returns the text a field is edited as: strings as they are,
+// anything else as a TOML literal.
+func EditText(f model.Field) string {
+	if s, ok := f.Value.(string); ok {
+		return s
+	}
+	return model.FormatValueTOML(f.Value)
+}
+
+// refresh recomputes the visible fields from the category and filter.
+func (b *Browser) refresh() {
+	var fields []model.Field
+	for _, c := range b.db.Categories {
+		if b.cat == 0 || c.Name == b.cats[b.cat] {
+			for _, f := range model.FlattenFields(c.Fields) {
+				if !model.IsDescKey(f.Key) {
+					fields = append(fields, f)
+				}
+			}
+		}
+	}
+
+	if len(b.filter) > 0 {
+		query := string(b.filter)
+		scores := make(map[string]int)
+		var matched []model.Field
+		for _, f := range fields {
+			best, ok := 0, false
+			for _, s := range []string{f.Category + "." + f.Key, model.FormatValue(f.Value), f.Desc} {
+				if score, hit := model.FuzzyScore(query, s); hit && (!ok || score > best) {
+					best, ok = score, true
+				}
+			}
+			if ok {
+				scores[f.Category+"."+f.Key] = best
+				matched = append(matched, f)
+			}
+		}
+		sort.SliceStable(matched, func(i, j int) bool {
+			return scores[matched[i].Category+"."+matched[i].Key] > scores[matched[j].Category+"."+matched[j].Key]
+		})
+		fields = matched
+	}
+
+	b.visible = fields
+	if b.cursor >= len(fields) {
+		b.cursor = len(fields) - 1
+	}
+	if b.cursor < 0 {
+		b.cursor = 0
+	}
+	b.scroll()
+}
+
+// listHeight is the number of field rows that fit on screen.
+func (b *Browser) listHeight() int {
+	// Category bar, filter line, separator, description, status line.
+	if h := b.height - 5; h > 0 {
+		return h
+	}
+	return 1
+}
+
+// scroll keeps the cursor inside the visible window.
+func (b *Browser) scroll() {
+	h := b.listHeight()
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+h {
+		b.offset = b.cursor - h + 1
+	}
+	if b.offset < 0 {
+		b.offset = 0
+	}
+}
+
+func (b *Browser) move(delta int) {
+	b.cursor += delta
+	if b.cursor >= len(b.visible) {
+		b.cursor = len(b.visible) - 1
+	}
+	if b.cursor < 0 {
+		b.cursor = 0
+	}
+	b.scroll()
+}
+
+func (b *Browser) switchCategory(delta int) {
+	b.cat = (b.cat + delta + len(b.cats)) % len(b.cats)
+	b.cursor, b.offset = 0, 0
+	b.refresh()
+}
+
+// HandleKey applies one key press and reports whether the user quit.
+func (b *Browser) HandleKey(k Key) bool {
+	if k.Code == KeyCtrlC {
+		return true
+	}
+	switch b.mode {
+	case filtering:
+		b.handleFilterKey(k)
+	case editing:
+		b.handleEditKey(k)
+	default:
+		return b.handleBrowseKey(k)
+	}
+	return false
+}
+
+func (b *Browser) handleBrowseKey(k Key) bool {
+	b.status = ""
+	switch k.Code {
+	case KeyUp:
+		b.move(-1)
+	case KeyDown:
+		b.move(1)
+	case KeyPgUp:
+		b.move(-b.listHeight())
+	case KeyPgDn:
+		b.move(b.listHeight())
+	case KeyHome:
+		b.move(-len(b.visible))
+	case KeyEnd:
+		b.move(len(b.visible))
+	case KeyLeft:
+		b.switchCategory(-1)
+	case KeyRight, KeyTab:
+		b.switchCategory(1)
+	case KeyEnter:
+		b.startEdit()
+	case KeyEsc:
+		b.filter = nil
+		b.refresh()
+	case KeyRune:
+		switch k.Rune {
+		case 'q':
+			return true
+		case 'k':
+			b.move(-1)
+		case 'j':
+			b.move(1)
+		case 'g':
+			b.move(-len(b.visible))
+		case 'G':
+			b.move(len(b.visible))
+		case 'h':
+			b.switchCategory(-1)
+		case 'l':
+			b.switchCategory(1)
+		case '/':
+			b.mode = filtering
+		case 'e':
+			b.startEdit()
+		}
+	}
+	return false
+}
+
+func (b *Browser) handleFilterKey(k Key) {
+	switch k.Code {
+	case KeyEnter:
+		b.mode = browsing
+	case KeyEsc:
+		b.filter = nil
+		b.mode = browsing
+	case KeyBackspace:
+		if len(b.filter) > 0 {
+			b.filter = b.filter[:len(b.filter)-1]
+		}
+	case KeyCtrlU:
+		b.filter = nil
+	case KeyUp:
+		b.move(-1)
+		return
+	case KeyDown:
+		b.move(1)
+		return
+	case KeyRune:
+		b.filter = append(b.filter, k.Rune)
+	default:
+		return
+	}
+	b.cursor, b.offset = 0, 0
+	b.refresh()
+}
+
+func (b *Browser) startEdit() {
+	f, ok := b.Selected()
+	if !ok {
+		return
+	}
+	if b.Save == nil {
+		b.status = "read-only"
+		return
+	}
+	if b.Raw != nil {
+		if raw, ok := b.Raw(f); ok {
+			f = raw
+		}
+	}
+	text := EditText(f)
+	if strings.Contains(text, "\n") {
+		b.status = fmt.Sprintf("multi-line value: use 'deets edit %s.%s'", f.Category, f.Key)
+		return
+	}
+	b.edit = f
+	b.input = []rune(text)
+	b.mode = editing
+}
+
+func (b *Browser) handleEditKey(k Key) {
+	b.status = ""
+	switch k.Code {
+	case KeyEsc:
+		b.mode = browsing
+		b.status = "edit cancelled"
+	case KeyBackspace:
+		if len(b.input) > 0 {
+			b.input = b.input[:len(b.input)-1]
+		}
+	case KeyCtrlU:
+		b.input = nil
+	case KeyRune:
+		b.input = append(b.input, k.Rune)
+	case KeyEnter:
+		b.commitEdit()
+	}
+}
+
+// commitEdit saves the edited value and reloads the database.
+func (b *Browser) commitEdit() {
+	f := b.edit
+	path := f.Category + "." + f.Key
+	if err := b.Save(f, string(b.input)); err != nil {
+		b.status = "error: " + err.Error()
+		return
+	}
+	b.mode = browsing
+	b.status = "saved " + path
+	if b.Load == nil {
+		return
+	}
+	db, err := b.Load()
+	if err != nil {
+		b.status = "saved " + path + ", but reloading failed: " + err.Error()
+		return
+	}
+	b.SetDB(db)
+	// Keep the edited field selected.
+	for i, v := range b.visible {
+		if v.Category+"."+v.Key == path {
+			b.cursor = i
+			b.scroll()
+			break
+		}
+	}
+}
+
+// View renders the screen as height lines of at most width characters.
+// The selected row is marked with reverse video.
+func (b *Browser) View() string {
+	const reverse, reset = "\x1b[7m", "\x1b[0m"
+	var lines []string
+
+	// Category bar, the current category in brackets.
+	var bar strings.Builder
+	for i, c := range b.cats {
+		name := c
+		if name == "" {
+			name = "All"
+		}
+		if i == b.cat {
+			bar.WriteString("[" + name + "]")
+		} else {
+			bar.WriteString(" " + name + " ")
+		}
+	}
+	lines = append(lines, model.Truncate(bar.String(), b.width))
+
+	// Filter line.
+	switch {
+	case b.mode == filtering:
+		lines = append(lines, model.Truncate("/"+string(b.filter)+"█", b.width))
+	case len(b.filter) > 0:
+		lines = append(lines, model.Truncate(fmt.Sprintf("filter: %s  (%d matches, esc clears)", string(b.filter), len(b.visible)), b.width))
+	default:
+		lines = append(lines, "")
+	}
+
+	// Field list.
+	keyWidth := 0
+	for _, f := range b.visible {
+		keyWidth = max(keyWidth, model.DisplayWidth(b.label(f)))
+	}
+	if keyWidth > b.width/3 {
+		keyWidth = b.width / 3
+	}
+	h := b.listHeight()
+	for row := 0; row < h; row++ {
+		i := b.offset + row
+		if i >= len(b.visible) {
+			lines = append(lines, "")
+			continue
+		}
+		f := b.visible[i]
+		value := strings.ReplaceAll(model.FormatValue(f.Value), "\n", "⏎")
+		line := model.Truncate(" "+model.PadRight(model.Truncate(b.label(f), keyWidth), keyWidth)+"  "+value, b.width)
+		if i == b.cursor {
+			line = reverse + line + reset
+		}
+		lines = append(lines, line)
+	}
+	if len(b.visible) == 0 {
+		lines[2] = " (no fields)"
+	}
+
+	// Description of the selected field.
+	lines = append(lines, strings.Repeat("─", b.width))
+	if f, ok := b.Selected(); ok {
+		desc := f.Desc
+		if desc == "" {
+			desc = "(no description)"
+		}
+		lines = append(lines, model.Truncate(fmt.Sprintf("%s.%s: %s", f.Category, f.Key, desc), b.width))
+	} else {
+		lines = append(lines, "")
+	}
+
+	// Status or edit line.
+	switch {
+	case b.mode == editing:
+		line := "edit: " + string(b.input) + "█"
+		if b.status != "" {
+			line += "  " + b.status
+		}
+		lines = append(lines, model.Truncate(line, b.width))
+	case b.status != "":
+		lines = append(lines, model.Truncate(b.status, b.width))
+	default:
+		lines = append(lines, model.Truncate("↑↓ move  ←→ category  / filter  enter edit  q quit", b.width))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// label is how a field is named in the list: its key within a category,
+// its full path when all categories are shown.
+func (b *Browser) label(f model.Field) string {
+	if b.cat == 0 {
+		return f.Category + "." + f.Key
+	}
+	return f.Key
+}
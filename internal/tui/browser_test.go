@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/queelius/deets/internal/model"
+)
+
+func testDB() *model.DB {
+	return &model.DB{Categories: []model.Category{
+		{Name: "identity", Fields: []model.Field{
+			{Category: "identity", Key: "name", Value: "Alex Towell", Desc: "Full legal name"},
+			{Category: "identity", Key: "aka", Value: []interface{}{"Alex T"}},
+		}},
+		{Name: "web", Fields: []model.Field{
+			{Category: "web", Key: "github", Value: "queelius", Desc: "GitHub username"},
+			{Category: "web", Key: "mastodon", Value: "@alex@example.social"},
+		}},
+	}}
+}
+
+func typeText(b *Browser, s string) {
+	for _, r := range s {
+		b.HandleKey(Key{Code: KeyRune, Rune: r})
+	}
+}
+
+func TestBrowser_NavigateAndDescribe(t *testing.T) {
+	b := NewBrowser(testDB())
+	if f, _ := b.Selected(); f.Key != "name" {
+		t.Fatalf("expected first field selected, got %s", f.Key)
+	}
+	b.HandleKey(Key{Code: KeyDown})
+	b.HandleKey(Key{Code: KeyDown})
+	view := b.View()
+	if !strings.Contains(view, "web.github: GitHub username") {
+		t.Errorf("expected description of the selected field:\n%s", view)
+	}
+	if !strings.Contains(view, "[All]") {
+		t.Errorf("expected All category selected:\n%s", view)
+	}
+
+	b.HandleKey(Key{Code: KeyRight})
+	if f, _ := b.Selected(); f.Category != "identity" || len(b.visible) != 2 {
+		t.Errorf("expected identity fields only, got %v", b.visible)
+	}
+	if !strings.Contains(b.View(), "[identity]") {
+		t.Error("expected identity highlighted in the category bar")
+	}
+	if b.HandleKey(Key{Code: KeyRune, Rune: 'q'}) != true {
+		t.Error("q should quit")
+	}
+}
+
+func TestBrowser_Filter(t *testing.T) {
+	b := NewBrowser(testDB())
+	typeText(b, "/mstdn")
+	if len(b.visible) != 1 || b.visible[0].Key != "mastodon" {
+		t.Fatalf("expected fuzzy match on mastodon, got %v", b.visible)
+	}
+	b.HandleKey(Key{Code: KeyEnter})
+	if !strings.Contains(b.View(), "filter: mstdn") {
+		t.Errorf("expected filter shown:\n%s", b.View())
+	}
+	b.HandleKey(Key{Code: KeyEsc})
+	if len(b.visible) != 4 {
+		t.Errorf("esc should clear the filter, got %d fields", len(b.visible))
+	}
+}
+
+func TestBrowser_Edit(t *testing.T) {
+	b := NewBrowser(testDB())
+	var saved string
+	b.Save = func(f model.Field, input string) error {
+		if input == "bad" {
+			return errors.New("rejected")
+		}
+		saved = f.Category + "." + f.Key + "=" + input
+		return nil
+	}
+	b.Load = func() (*model.DB, error) {
+		db := testDB()
+		db.Categories[0].Fields[0].Value = "Alexander"
+		return db, nil
+	}
+
+	b.HandleKey(Key{Code: KeyEnter})
+	b.HandleKey(Key{Code: KeyCtrlU})
+	typeText(b, "bad")
+	b.HandleKey(Key{Code: KeyEnter})
+	if !strings.Contains(b.View(), "error: rejected") || b.mode != editing {
+		t.Fatalf("expected to stay in edit mode with the error:\n%s", b.View())
+	}
+
+	b.HandleKey(Key{Code: KeyCtrlU})
+	typeText(b, "Alexander")
+	b.HandleKey(Key{Code: KeyEnter})
+	if saved != "identity.name=Alexander" {
+		t.Errorf("saved = %q", saved)
+	}
+	if view := b.View(); !strings.Contains(view, "saved identity.name") || !strings.Contains(view, "Alexander") {
+		t.Errorf("expected reloaded value and status:\n%s", view)
+	}
+
+	// Non-string values are edited as TOML literals.
+	b.HandleKey(Key{Code: KeyDown})
+	b.HandleKey(Key{Code: KeyEnter})
+	if string(b.input) != `["Alex T"]` {
+		t.Errorf("expected array literal in the edit line, got %q", string(b.input))
+	}
+}
+
+func TestBrowser_EditRaw(t *testing.T) {
+	b := NewBrowser(testDB())
+	var saved model.Field
+	b.Save = func(f model.Field, input string) error {
+		saved = f
+		return nil
+	}
+	b.Raw = func(f model.Field) (model.Field, bool) {
+		f.Value = "{{identity.first}} Towell"
+		return f, true
+	}
+
+	b.HandleKey(Key{Code: KeyEnter})
+	if string(b.input) != "{{identity.first}} Towell" {
+		t.Errorf("expected the raw template in the edit line, got %q", string(b.input))
+	}
+	b.HandleKey(Key{Code: KeyEnter})
+	if saved.Key != "name" || saved.Value != "{{identity.first}} Towell" {
+		t.Errorf("expected Save to get the raw field, got %+v", saved)
+	}
+}
+
+func TestBrowser_ViewFitsScreen(t *testing.T) {
+	b := NewBrowser(testDB())
+	b.Resize(30, 8)
+	lines := strings.Split(b.View(), "\n")
+	if len(lines) != 8 {
+		t.Errorf("expected 8 lines, got %d", len(lines))
+	}
+	for _, l := range lines {
+		l = strings.NewReplacer("\x1b[7m", "", "\x1b[0m", "").Replace(l)
+		if n := len([]rune(l)); n > 30 {
+			t.Errorf("line wider than screen (%d): %q", n, l)
+		}
+	}
+}
+
+func TestKeysFromMsg(t *testing.T) {
+	msgs := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("aé")},
+		{Type: tea.KeyUp},
+		{Type: tea.KeyPgDown},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyBackspace},
+		{Type: tea.KeySpace, Runes: []rune{' '}},
+		{Type: tea.KeyCtrlC},
+		{Type: tea.KeyF1},
+	}
+	want := []Key{
+		{Code: KeyRune, Rune: 'a'},
+		{Code: KeyRune, Rune: 'é'},
+		{Code: KeyUp},
+		{Code: KeyPgDn},
+		{Code: KeyEnter},
+		{Code: KeyBackspace},
+		{Code: KeyRune, Rune: ' '},
+		{Code: KeyCtrlC},
+		{Code: KeyUnknown},
+	}
+	var got []Key
+	for _, m := range msgs {
+		got = append(got, keysFromMsg(m)...)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d keys, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i] != w {
+			t.Errorf("key %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+}
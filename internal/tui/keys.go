@@ -0,0 +1,72 @@
+package tui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// KeyCode identifies a key press. Printable characters are KeyRune with
+// the character in Key.Rune.
+type KeyCode int
+
+// Keys the browser understands.
+const (
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPgUp
+	KeyPgDn
+	KeyEnter
+	KeyEsc
+	KeyTab
+	KeyBackspace
+	KeyCtrlC
+	KeyCtrlU
+	KeyUnknown
+)
+
+// Key is one decoded key press.
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// teaKeys maps the Bubble Tea key types the browser handles to its own.
+var teaKeys = map[tea.KeyType]KeyCode{
+	tea.KeyUp:        KeyUp,
+	tea.KeyDown:      KeyDown,
+	tea.KeyLeft:      KeyLeft,
+	tea.KeyRight:     KeyRight,
+	tea.KeyHome:      KeyHome,
+	tea.KeyEnd:       KeyEnd,
+	tea.KeyPgUp:      KeyPgUp,
+	tea.KeyPgDown:    KeyPgDn,
+	tea.KeyEnter:     KeyEnter,
+	tea.KeyEsc:       KeyEsc,
+	tea.KeyTab:       KeyTab,
+	tea.KeyBackspace: KeyBackspace,
+	tea.KeyCtrlH:     KeyBackspace,
+	tea.KeyCtrlC:     KeyCtrlC,
+	tea.KeyCtrlU:     KeyCtrlU,
+}
+
+// keysFromMsg translates a Bubble Tea key message into the browser's key
+// presses. Typed or pasted text arrives as one message holding several
+// runes, which become one KeyRune each.
+func keysFromMsg(msg tea.KeyMsg) []Key {
+	switch {
+	case msg.Type == tea.KeyRunes && !msg.Alt:
+		keys := make([]Key, len(msg.Runes))
+		for i, r := range msg.Runes {
+			keys[i] = Key{Code: KeyRune, Rune: r}
+		}
+		return keys
+	case msg.Type == tea.KeySpace:
+		return []Key{{Code: KeyRune, Rune: ' '}}
+	}
+	if code, ok := teaKeys[msg.Type]; ok {
+		return []Key{{Code: code}}
+	}
+	return []Key{{Code: KeyUnknown}}
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// Run shows b full screen until the user quits. Bubble Tea puts the
+// terminal in raw mode, switches to the alternate screen, decodes keys and
+// resizes, and restores the terminal on return, on Unix terminals and the
+// Windows console alike.
+func Run(b *Browser) error {
+	_, err := tea.NewProgram(program{b}, tea.WithAltScreen()).Run()
+	return err
+}
+
+// program adapts a Browser to Bubble Tea's model interface.
+type program struct {
+	b *Browser
+}
+
+func (p program) Init() tea.Cmd {
+	return nil
+}
+
+func (p program) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.b.Resize(msg.Width, msg.Height)
+	case tea.KeyMsg:
+		for _, k := range keysFromMsg(msg) {
+			if p.b.HandleKey(k) {
+				return p, tea.Quit
+			}
+		}
+	}
+	return p, nil
+}
+
+func (p program) View() string {
+	return p.b.View()
+}
+
+// TermSize returns the width and height of the terminal f, or zeros when
+// unknown.
+func TermSize(f *os.File) (width, height int) {
+	width, height, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0, 0
+	}
+	return width, height
+}